@@ -0,0 +1,72 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+func checkBaseAligned[T any](t *testing.T, name string, n uintptr, align uintptr) {
+	t.Helper()
+	opts := []Option{WithBaseAlignment(0)}
+	if align != 64 {
+		opts = []Option{WithBaseAlignment(align)}
+	}
+	for i := 0; i < 20; i++ {
+		a := NewAtomicArena[T](n, opts...)
+		if addr := uintptr(unsafe.Pointer(&a.raw[0])); addr%align != 0 {
+			t.Fatalf("%s x%d: base %#x not %d-aligned", name, n, addr, align)
+		}
+		if a.Cap() != n || uintptr(len(a.raw)) != n {
+			t.Fatalf("%s: capacity changed to %d", name, a.Cap())
+		}
+	}
+}
+
+// TestBaseAlignment checks the first element is aligned across element types and sizes
+func TestBaseAlignment(t *testing.T) {
+	for _, n := range []uintptr{1, 3, 100, 10_000} {
+		checkBaseAligned[byte](t, "byte", n, 64)
+		checkBaseAligned[[3]byte](t, "[3]byte", n, 64)
+		checkBaseAligned[[7]byte](t, "[7]byte", n, 64)
+		checkBaseAligned[uint16](t, "uint16", n, 64)
+		checkBaseAligned[float32](t, "float32", n, 64)
+		checkBaseAligned[float64](t, "float64", n, 64)
+		checkBaseAligned[padded](t, "padded", n, 64)
+		checkBaseAligned[[5]float64](t, "[5]float64", n, 64)
+		checkBaseAligned[*int](t, "*int", n, 64)
+		checkBaseAligned[string](t, "string", n, 64)
+		checkBaseAligned[float64](t, "float64", n, 256)
+		checkBaseAligned[byte](t, "byte", n, 4096)
+	}
+}
+
+// TestBaseAlignmentArithmetic ensures Alloc and Reserve are unchanged by the shift
+func TestBaseAlignmentArithmetic(t *testing.T) {
+	a := NewAtomicArena[[3]byte](10, WithBaseAlignment(64))
+	p, _ := a.Alloc([3]byte{1, 2, 3})
+	if p != &a.raw[0] {
+		t.Fatal("first Alloc not at the aligned base")
+	}
+	seg, err := a.Reserve(9)
+	if err != nil || &seg[0] != &a.raw[1] {
+		t.Fatalf("Reserve = %v", err)
+	}
+	if _, err := a.Alloc([3]byte{}); err == nil {
+		t.Fatal("expected a full arena")
+	}
+	c := a.Clone()
+	if uintptr(unsafe.Pointer(&c.raw[0]))%64 != 0 {
+		t.Fatal("clone lost the base alignment")
+	}
+}
+
+// TestBaseAlignmentInvalid ensures non-power-of-two alignments are rejected
+func TestBaseAlignmentInvalid(t *testing.T) {
+	if _, err := New[int](8, WithBaseAlignment(48)); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions, got %v", err)
+	}
+	if _, err := NewMmapArena[int](8, WithBaseAlignment(uintptr(pageSize)*2)); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions for an mmap arena, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package atomicarena
+
+// AllocMany reserves n slots in one step and fills each with a copy of
+// template, returning the segment. The fill copies the first slot into the
+// second, those two into the next two and so on, so it takes log2(n) bulk
+// copies rather than n stores. Unlike Reserve, every slot is published in
+// the pointer mirror, like Alloc, and counts as written only once filled.
+// On a full arena it returns a *CapacityError and allocates nothing.
+func (a *AtomicArena[T]) AllocMany(n uintptr, template T) ([]T, error) {
+	return a.allocMany(n, false, func(seg []T) {
+		if len(seg) == 0 {
+			return
+		}
+		seg[0] = template
+		for k := 1; k < len(seg); k *= 2 {
+			copy(seg[k:], seg[:k])
+		}
+	})
+}
+
+// AllocManyFunc is AllocMany that calls init(i, p) to initialize each slot
+// instead, where i counts from 0 within the segment and p points into the
+// arena. Slots start out zeroed or holding stale values, as for Reserve,
+// unless the arena was built WithZeroOnAlloc.
+func (a *AtomicArena[T]) AllocManyFunc(n uintptr, init func(i uintptr, p *T)) ([]T, error) {
+	return a.allocMany(n, a.opts.zeroOnAlloc, func(seg []T) {
+		for i := range seg {
+			init(uintptr(i), &seg[i])
+		}
+	})
+}
+
+// allocMany reserves n slots, fills them, publishes them in the mirror and
+// only then marks them written. With zero set, stale slots are cleared
+// before fill sees them.
+func (a *AtomicArena[T]) allocMany(n uintptr, zero bool, fill func(seg []T)) ([]T, error) {
+	start, err := a.reserve(n)
+	if err != nil {
+		return nil, a.allocErr(err, start, n)
+	}
+	seg := a.raw[start : start+n : start+n]
+	if zero {
+		a.zeroStale(start, seg)
+	}
+	fill(seg)
+	a.publish(start, start+n)
+	a.commit(n)
+	if a.prof != nil {
+		a.prof.sample(n)
+	}
+	return seg, nil
+}
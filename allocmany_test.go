@@ -0,0 +1,91 @@
+package atomicarena
+
+import "testing"
+
+// conn is a default-configured connection used as an AllocMany template.
+type conn struct {
+	ID      int
+	Addr    string
+	Retries int
+}
+
+// TestAllocMany covers n = 0, n = 1, odd sizes and the mirror
+func TestAllocMany(t *testing.T) {
+	a := NewAtomicArena[conn](64)
+	tmpl := conn{Addr: "localhost:80", Retries: 3}
+	for _, n := range []uintptr{0, 1, 2, 7, 32} {
+		before := a.Len()
+		seg, err := a.AllocMany(n, tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uintptr(len(seg)) != n || cap(seg) != len(seg) || a.Len() != before+n {
+			t.Fatalf("n=%d: expected a clipped segment of %d, got %d/%d", n, n, len(seg), cap(seg))
+		}
+		for i := range seg {
+			if seg[i] != tmpl {
+				t.Fatalf("n=%d: expected slot %d to equal the template, got %+v", n, i, seg[i])
+			}
+			if p, ok := a.Get(before + uintptr(i)); !ok || p != &seg[i] {
+				t.Fatalf("n=%d: expected slot %d published in the mirror", n, i)
+			}
+		}
+	}
+	if got := a.Committed(); got != a.Len() {
+		t.Fatalf("expected every slot committed, got %d of %d", got, a.Len())
+	}
+}
+
+// TestAllocManyFunc initializes each element from its index
+func TestAllocManyFunc(t *testing.T) {
+	a := NewAtomicArena[conn](8)
+	a.Alloc(conn{ID: -1})
+	seg, err := a.AllocManyFunc(5, func(i uintptr, p *conn) { p.ID = int(i) * 10 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range seg {
+		if c.ID != i*10 {
+			t.Fatalf("expected ID %d at %d, got %d", i*10, i, c.ID)
+		}
+	}
+}
+
+// TestAllocManyFull ensures a request beyond the remaining space allocates nothing
+func TestAllocManyFull(t *testing.T) {
+	a := NewAtomicArena[conn](10)
+	a.AllocMany(4, conn{})
+	calls := 0
+	_, err := a.AllocManyFunc(7, func(uintptr, *conn) { calls++ })
+	if ce := capacityErr(t, err); ce.Requested != 7 || ce.Available != 6 {
+		t.Fatalf("unexpected error %+v", *ce)
+	}
+	if _, err := a.AllocMany(7, conn{}); err == nil {
+		t.Fatalf("expected AllocMany to fail")
+	}
+	if calls != 0 || a.Len() != 4 {
+		t.Fatalf("expected nothing allocated, got len %d and %d init calls", a.Len(), calls)
+	}
+}
+
+// BenchmarkAllocMany compares AllocMany with a loop of Alloc for 10,000 elements
+func BenchmarkAllocMany(b *testing.B) {
+	const n = 10000
+	tmpl := conn{Addr: "localhost:80", Retries: 3}
+	b.Run("AllocMany", func(b *testing.B) {
+		a := NewAtomicArena[conn](n)
+		for i := 0; i < b.N; i++ {
+			a.Reset(false)
+			a.AllocMany(n, tmpl)
+		}
+	})
+	b.Run("Alloc", func(b *testing.B) {
+		a := NewAtomicArena[conn](n)
+		for i := 0; i < b.N; i++ {
+			a.Reset(false)
+			for j := 0; j < n; j++ {
+				a.Alloc(tmpl)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package atomicarena
+
+// appendChunk is the number of slots AppendFrom and AppendSeq reserve at a time.
+const appendChunk = 64
+
+// AppendFrom appends values produced by next until it reports false, filling
+// slots reserved appendChunk at a time directly from the callback. It returns
+// the number of values appended. If the arena fills first it returns
+// ErrArenaFull; the value that did not fit has been consumed from next and is
+// discarded. Unused slots of the final chunk are handed back when no other
+// reservation followed it, and tombstoned otherwise.
+func (a *AtomicArena[T]) AppendFrom(next func() (T, bool)) (int, error) {
+	p := appender[T]{a: a}
+	defer p.flush()
+	for {
+		v, ok := next()
+		if !ok {
+			return p.total, nil
+		}
+		if err := p.add(v); err != nil {
+			return p.total, err
+		}
+	}
+}
+
+// appender fills a chunk of reserved slots one value at a time.
+type appender[T any] struct {
+	a       *AtomicArena[T]
+	start   uintptr // first slot of the current chunk
+	k, used uintptr // size of the current chunk and slots filled in it
+	total   int
+}
+
+func (p *appender[T]) add(v T) error {
+	if p.used == p.k {
+		p.flush()
+		start, k, err := p.a.reserveUpTo(appendChunk)
+		if err != nil {
+			return err
+		}
+		p.start, p.k = start, k
+	}
+	i := p.start + p.used
+	p.a.raw[i] = v
+	p.a.publish(i, i+1)
+	p.used++
+	p.total++
+	return nil
+}
+
+// flush commits the current chunk.
+func (p *appender[T]) flush() {
+	if p.k > 0 {
+		p.a.commitPartial(p.start, p.k, p.used)
+	}
+	p.k, p.used = 0, 0
+}
+
+// reserveUpTo reserves between 1 and n slots, as many as are free.
+func (a *AtomicArena[T]) reserveUpTo(n uintptr) (start, k uintptr, err error) {
+	for {
+		// a zero k still asks for one slot so reserve reports why it failed
+		k = max(min(n, a.room()), 1)
+		start, err = a.reserve(k)
+		if err == ErrArenaFull && k > 1 {
+			// another allocator took the free slots first
+			continue
+		}
+		return start, k, err
+	}
+}
+
+// commitPartial marks the first used of the n slots reserved at start as
+// written. The rest are handed back if the reservation is still the last
+// one, and tombstoned otherwise.
+func (a *AtomicArena[T]) commitPartial(start, n, used uintptr) {
+	if used < n && !a.count.CompareAndSwap(start+n, start+used) {
+		for i := start + used; i < start+n; i++ {
+			a.markDead(i)
+		}
+		used = n
+	}
+	a.commit(used)
+}
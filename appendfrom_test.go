@@ -0,0 +1,97 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// counter returns a source yielding 0..n-1 and a pointer to the number of calls
+func counter(n int) (func() (int, bool), *int) {
+	calls := 0
+	return func() (int, bool) {
+		calls++
+		if calls > n {
+			return 0, false
+		}
+		return calls - 1, true
+	}, &calls
+}
+
+// TestAppendFromCutoff streams more items than capacity and checks the exact cut-off
+func TestAppendFromCutoff(t *testing.T) {
+	arena := NewAtomicArena[int](150)
+	next, calls := counter(1000)
+	n, err := arena.AppendFrom(next)
+	if !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if n != 150 || arena.Len() != 150 {
+		t.Fatalf("expected 150 appended, got %d (len %d)", n, arena.Len())
+	}
+	// item 150 was consumed and dropped; nothing after it was requested
+	if *calls != 151 {
+		t.Fatalf("expected 151 calls to next, got %d", *calls)
+	}
+	for i, v := range arena.Snapshot() {
+		if v != i {
+			t.Fatalf("index %d: expected %d, got %d", i, i, v)
+		}
+	}
+}
+
+// TestAppendFromPartialChunk ensures unused slots of the last chunk are handed back
+func TestAppendFromPartialChunk(t *testing.T) {
+	arena := NewAtomicArena[int](1000)
+	next, _ := counter(100)
+	n, err := arena.AppendFrom(next)
+	if err != nil || n != 100 {
+		t.Fatalf("AppendFrom = %d, %v", n, err)
+	}
+	if arena.Len() != 100 {
+		t.Fatalf("expected unused slots to be handed back, len %d", arena.Len())
+	}
+	if _, err := arena.Alloc(-1); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := arena.Get(100); *p != -1 {
+		t.Fatalf("expected Alloc to follow the appended values")
+	}
+	if err := arena.Reset(false); err != nil {
+		t.Fatalf("arena not quiescent after AppendFrom: %v", err)
+	}
+}
+
+// TestAppendFromInterleaved ensures a partial chunk behind another reservation is tombstoned
+func TestAppendFromInterleaved(t *testing.T) {
+	arena := NewAtomicArena[int](1000)
+	i := 0
+	n, err := arena.AppendFrom(func() (int, bool) {
+		i++
+		if i == 3 {
+			// another allocator reserves behind our chunk
+			arena.Alloc(-1)
+		}
+		return i, i <= 10
+	})
+	if err != nil || n != 10 {
+		t.Fatalf("AppendFrom = %d, %v", n, err)
+	}
+	if arena.Len() != appendChunk+1 {
+		t.Fatalf("expected the chunk to stay reserved, len %d", arena.Len())
+	}
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, -1}
+	if got := arena.Snapshot(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+// TestAppendFromFrozen ensures a frozen arena rejects the first value
+func TestAppendFromFrozen(t *testing.T) {
+	arena := NewAtomicArena[int](10)
+	arena.Freeze()
+	next, _ := counter(5)
+	if n, err := arena.AppendFrom(next); n != 0 || !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build go1.23
+
+package atomicarena
+
+import "iter"
+
+// AppendSeq appends the values of seq like AppendFrom, stopping the iteration
+// early if the arena fills.
+func (a *AtomicArena[T]) AppendSeq(seq iter.Seq[T]) (int, error) {
+	p := appender[T]{a: a}
+	defer p.flush()
+	var err error
+	for v := range seq {
+		if err = p.add(v); err != nil {
+			break
+		}
+	}
+	return p.total, err
+}
@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestAppendSeq streams an iterator past capacity and checks the cut-off
+func TestAppendSeq(t *testing.T) {
+	arena := NewAtomicArena[int](100)
+	yielded := 0
+	seq := func(yield func(int) bool) {
+		for i := 0; i < 1000; i++ {
+			yielded++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	n, err := arena.AppendSeq(seq)
+	if !errors.Is(err, ErrArenaFull) || n != 100 {
+		t.Fatalf("AppendSeq = %d, %v", n, err)
+	}
+	if yielded != 101 {
+		t.Fatalf("expected iteration to stop after 101 values, got %d", yielded)
+	}
+	arena.Reset(false)
+	n, err = arena.AppendSeq(slices.Values([]int{7, 8, 9}))
+	if err != nil || n != 3 || !slices.Equal(arena.Snapshot(), []int{7, 8, 9}) {
+		t.Fatalf("AppendSeq = %d, %v, %v", n, err, arena.Snapshot())
+	}
+}
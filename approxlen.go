@@ -0,0 +1,65 @@
+package atomicarena
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// lenRefreshEvery is how many slots a group reserves between refreshes of
+// its ArenaGroup's cached length.
+const lenRefreshEvery = 256
+
+// lenCache holds an ArenaGroup's TotalLen as of its last refresh. A group
+// refreshes it whenever its count crosses a multiple of lenRefreshEvery and
+// after every Reset. Refreshes are serialized, and each samples every group
+// no earlier than the one before it did, so the cached value only falls
+// when a group's count did.
+type lenCache struct {
+	approx  atomic.Uintptr
+	pending atomic.Bool // a refresh was requested since the running one started
+	mu      sync.Mutex  // held by the running refresh
+	sum     func() uintptr
+}
+
+// note requests a refresh if reserving [start, start+n) crossed a multiple
+// of lenRefreshEvery.
+func (c *lenCache) note(start, n uintptr) {
+	if start/lenRefreshEvery != (start+n)/lenRefreshEvery {
+		c.refresh()
+	}
+}
+
+// refresh recomputes the cached length, or leaves that to the refresh
+// already running, which repeats until no request is pending.
+func (c *lenCache) refresh() {
+	c.pending.Store(true)
+	for c.pending.Load() && c.mu.TryLock() {
+		for c.pending.Swap(false) {
+			c.approx.Store(c.sum())
+		}
+		c.mu.Unlock()
+	}
+}
+
+// ApproxLen returns the number of slots allocated across all groups as of
+// the last refresh, with a single atomic load. It is meant for monitoring
+// loops that would otherwise call TotalLen, which visits every group.
+//
+// Groups refresh it every 256 slots they reserve, so once allocations pause
+// it trails TotalLen by less than 256 slots per group. It never exceeds the
+// total capacity, and only decreases after a group is reset, or once a
+// later refresh sees a group that Compact, Unreserve or TryShrinkTo shrank.
+// RefreshLen brings it up to date at once.
+func (g *ArenaGroup[T]) ApproxLen() uintptr {
+	return g.lenc.approx.Load()
+}
+
+// RefreshLen recomputes the value ApproxLen returns from TotalLen and
+// returns it.
+func (g *ArenaGroup[T]) RefreshLen() uintptr {
+	g.lenc.mu.Lock()
+	defer g.lenc.mu.Unlock()
+	n := g.lenc.sum()
+	g.lenc.approx.Store(n)
+	return n
+}
@@ -0,0 +1,93 @@
+package atomicarena
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestApproxLenBoundedStaleness keeps ApproxLen monotonic, within capacity and close to TotalLen under load
+func TestApproxLenBoundedStaleness(t *testing.T) {
+	const groups, per = 4, 10_000
+	g := NewArenaGroup[int](groups, per)
+	var wg sync.WaitGroup
+	var stop atomic.Bool
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var last uintptr
+		for !stop.Load() {
+			n := g.ApproxLen()
+			if n < last || n > groups*per {
+				t.Errorf("expected ApproxLen to grow within capacity, got %d after %d", n, last)
+				return
+			}
+			last = n
+		}
+	}()
+	var producers sync.WaitGroup
+	for i := 0; i < groups; i++ {
+		producers.Add(1)
+		go func(a *AtomicArena[int]) {
+			defer producers.Done()
+			for j := 0; j < per-7; j++ {
+				if j%100 == 0 {
+					a.AppendSlice([]int{j, j})
+					j++
+					continue
+				}
+				a.Alloc(j)
+			}
+		}(g.Group(i))
+	}
+	producers.Wait()
+	stop.Store(true)
+	wg.Wait()
+
+	total, approx := g.TotalLen(), g.ApproxLen()
+	if approx > total || total-approx >= groups*lenRefreshEvery {
+		t.Fatalf("expected ApproxLen within %d of %d, got %d", groups*lenRefreshEvery, total, approx)
+	}
+	if n := g.RefreshLen(); n != total || g.ApproxLen() != total {
+		t.Fatalf("expected RefreshLen to return %d, got %d", total, n)
+	}
+}
+
+// TestApproxLenReset drops the cached length when a group is reset
+func TestApproxLenReset(t *testing.T) {
+	g := NewArenaGroup[int](2, lenRefreshEvery*2)
+	g.Group(0).AppendSlice(make([]int, lenRefreshEvery))
+	g.Group(1).AppendSlice(make([]int, lenRefreshEvery+1))
+	if n := g.ApproxLen(); n != 2*lenRefreshEvery+1 {
+		t.Fatalf("expected a refresh at the boundary, got %d", n)
+	}
+	g.Group(1).Reset(false)
+	if n := g.ApproxLen(); n != lenRefreshEvery {
+		t.Fatalf("expected Reset to refresh the cached length, got %d", n)
+	}
+	g.Group(0).Alloc(1)
+	if n := g.ApproxLen(); n != lenRefreshEvery {
+		t.Fatalf("expected no refresh between boundaries, got %d", n)
+	}
+	if err := g.ResetAll(false); err != nil || g.ApproxLen() != 0 {
+		t.Fatalf("expected 0 after ResetAll, got %d (%v)", g.ApproxLen(), err)
+	}
+}
+
+// BenchmarkApproxLen reads the cached length with one atomic load
+func BenchmarkApproxLen(b *testing.B) {
+	g := NewArenaGroup[int](64, 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = g.ApproxLen()
+	}
+}
+
+// BenchmarkTotalLen sums the length of every group, for comparison
+func BenchmarkTotalLen(b *testing.B) {
+	g := NewArenaGroup[int](64, 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = g.TotalLen()
+	}
+}
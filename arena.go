@@ -1,8 +1,12 @@
 package atomicarena
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"runtime/trace"
+	"sync"
 	"sync/atomic"
 	"unsafe"
 )
@@ -14,95 +18,681 @@ import (
 type AtomicArena[T any] struct {
 	raw      []T                 // contiguous storage for objects
 	ptrs     []atomic.Pointer[T] // atomic pointers into raw, for tests and visibility
-	maxElems uintptr             // maximum number of elements
-	count    atomic.Uintptr      // number of elements allocated so far
+	dead     []atomic.Uint64     // tombstone bitmap, one bit per slot
+	maxElems atomic.Uintptr      // maximum number of elements; raised by Grow, lowered by Truncate
+	count    atomic.Uintptr      // number of elements reserved so far, plus state flags
+	done     atomic.Uintptr      // number of reserved elements whose writes have completed
+	epoch    atomic.Uint64       // incremented by every Reset and Compact
+	opts     options             // construction-time configuration
+	pointers bool                // T contains pointers, ruling out the byte-level fast paths
+	word     bool                // T is word-sized and there is no mirror; see allocWord
+	zero     zeroer[T]           // clears runs of slots; see zeroerFor
+	regID    uint64              // Arenas registry entry, or 0 if the arena is unnamed
+	dirty    atomic.Uintptr      // slots from here on have been zero since storage was last cleared
+	cleared  atomic.Uint64       // stale slots zeroing allocations cleared
+	pristine atomic.Uint64       // pristine slots zeroing allocations handed out without clearing
+	prof     *allocProfile       // sampled allocation stacks, nil unless profiling
+	ops      *opLog              // recent operations, nil unless WithOpLog
+	dtor     func(*T) error      // releases an element's resources; nil if none
+	refs     []pinCount          // outstanding references, striped; nil unless ref counting
+	leak     leakCheck           // reports the arena if it is collected unclosed
+	marks    []watermark         // utilization thresholds, lowest first; nil if none
+	markAt   atomic.Uintptr      // lowest armed threshold, ^0 if none
+	softCap  atomic.Uintptr      // limit for ordinary allocations; maxElems unless WithSoftCap
+	softFull atomic.Uint64       // allocations refused at the soft cap
+	full     atomic.Uint64       // allocations refused with ErrArenaFull, softFull included
+	retired  atomic.Uint64       // slots given back by drops of the count; see countDropped
+	peak     atomic.Uintptr      // highest count seen by a drop of the count
+	retries  atomic.Uint64       // reservation CASes lost to other reservations
+
+	trims   atomic.Uint64              // times the count dropped; see countDropped
+	hint    atomic.Pointer[commitHint] // last prefix found by Committed
+	waiters commitWaiters              // WaitForCommitted callers
+	unpub   atomic.Uintptr             // Reserve'd slots not yet passed to PublishRange
+
+	ttl     *ttlStamps                // per-slot reservation times; nil unless WithTTL
+	lenc    *lenCache                 // cached length of the ArenaGroup this arena belongs to, if any
+	discard func(lo, hi uintptr) bool // zeroes slots by dropping their pages; nil unless mapped
+	huge    bool                      // storage is backed by huge pages, set by NewMmapArena
+
+	budget      *Budget      // budget the storage was reserved from, if any
+	budgetBytes uintptr      // bytes reserved from budget
+	closed      atomic.Bool  // Close has run
+	pins        atomic.Int32 // regions pinned by PinnedRegion and not yet unpinned
 }
 
+// The top bit of count marks the arena as frozen and the next one marks a
+// reset in progress; the remaining bits hold the number of reserved slots.
+// Keeping them in one word lets a single CAS reserve slots and observe the
+// arena's state at the same time.
+const (
+	frozenBit = uintptr(1) << (unsafe.Sizeof(uintptr(0))*8 - 1)
+	busyBit   = frozenBit >> 1
+	countMask = busyBit - 1
+	flagsMask = frozenBit | busyBit
+)
+
 // NewAtomicArena creates a new AtomicArena that can hold up to maxElems elements of type T.
 // It pre-allocates both the raw buffer and the pointer slice.
-func NewAtomicArena[T any](maxElems uintptr) *AtomicArena[T] {
-	raw := make([]T, maxElems)
-	ptrs := make([]atomic.Pointer[T], maxElems)
-	return &AtomicArena[T]{
+// It is New with a panic in place of the error; use New or
+// NewAtomicArenaChecked to handle an invalid configuration.
+func NewAtomicArena[T any](maxElems uintptr, opts ...Option) *AtomicArena[T] {
+	a, err := New[T](maxElems, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// New creates an arena that can hold up to maxElems elements of type T,
+// configured by opts. It returns ErrInvalidOptions if the options conflict
+// and ErrTooLarge if the storage cannot be allocated. A zero capacity is
+// accepted and yields an arena on which every allocation fails.
+//
+// A zero-sized T, such as struct{}, gets a specialized arena: every element
+// shares one address, so there is no pointer mirror, Alloc and AppendSlice
+// only bump the count, Get returns that shared address for every allocated
+// index, and Free and Reset skip zeroing. Use NewAtomicArenaChecked to reject
+// zero-sized types instead.
+func New[T any](maxElems uintptr, opts ...Option) (*AtomicArena[T], error) {
+	o := buildOptions(opts)
+	if err := o.validate(false); err != nil {
+		return nil, err
+	}
+	if err := validateElem[T](o); err != nil {
+		return nil, err
+	}
+	return newArena[T](maxElems, o)
+}
+
+// newArena allocates heap storage for an arena with validated options.
+func newArena[T any](maxElems uintptr, o options) (a *AtomicArena[T], err error) {
+	if zeroSized[T]() {
+		// a mirror would record the same address in every slot
+		o.noMirror = true
+	}
+	per := slotBytes[T]()
+	if per > 0 && maxElems > ^uintptr(0)/per {
+		return nil, fmt.Errorf("%w: %s overflows uintptr", ErrTooLarge, describeRequest[T](maxElems))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			a, err = nil, fmt.Errorf("%w: requested %s: %v", ErrTooLarge, describeRequest[T](maxElems), r)
+		}
+	}()
+	raw, err := alignedSlice[T](maxElems, o.baseAlign)
+	if err != nil {
+		return nil, err
+	}
+	var ptrs []atomic.Pointer[T]
+	if !o.noMirror {
+		ptrs = make([]atomic.Pointer[T], maxElems)
+	}
+	a = newAtomicArena(raw, ptrs, o)
+	if o.prefault {
+		a.Prefault()
+	}
+	return a, nil
+}
+
+// newAtomicArena builds an arena over caller-provided storage, which must be
+// zeroed and have equal lengths. ptrs is nil when the mirror is disabled.
+func newAtomicArena[T any](raw []T, ptrs []atomic.Pointer[T], o options) *AtomicArena[T] {
+	maxElems := uintptr(len(raw))
+	a := &AtomicArena[T]{
 		raw:      raw,
 		ptrs:     ptrs,
-		maxElems: maxElems,
+		dead:     make([]atomic.Uint64, (maxElems+63)/64),
+		opts:     o,
+		pointers: hasPointers[T](),
+		word:     ptrs == nil && wordSized[T](),
+		zero:     zeroerFor[T](),
+		prof:     newAllocProfile[T](o.profileRate),
+		ops:      newOpLog(o),
+		dtor:     destructorFor[T](o),
+		marks:    newWatermarks(o.watermarks, maxElems),
+		ttl:      newTTLStamps(o, maxElems),
+	}
+	a.maxElems.Store(maxElems)
+	a.softCap.Store(o.softCapFor(maxElems))
+	a.markAt.Store(a.lowestArmed())
+	a.waiters.wantAt.Store(^uintptr(0))
+	if o.refCounting {
+		a.refs = newStripes()
+	}
+	if o.name != "" {
+		a.regID = trackArena(a)
+	}
+	if o.leakLogf != nil {
+		a.leak = watchLeak(a, newLeakReport(a.leakLabel(), o.leakLogf))
+	}
+	return a
+}
+
+// reserve claims n consecutive slots and returns the index of the first one.
+// The CAS loop never lets count overshoot the soft cap, so a failed
+// reservation has nothing to roll back. On ErrArenaFull it returns the count
+// it observed, from which allocErr reports the free space.
+func (a *AtomicArena[T]) reserve(n uintptr) (uintptr, error) {
+	return a.reserveWithin(n, a.softCap.Load(), nil, false)
+}
+
+// reserveWithin is reserve with an explicit limit: the soft cap for ordinary
+// allocations or maxElems for priority ones. If place is set, each attempt
+// calls it with the first free slot, and it returns how many slots to skip
+// before the run, which are claimed in the same CAS, or an error that ends
+// the reservation; the index returned is then that of the first skipped
+// slot. If once is set, a lost CAS or a reset in progress fails with
+// ErrContended instead of being retried.
+func (a *AtomicArena[T]) reserveWithin(n, limit uintptr, place func(start uintptr) (uintptr, error), once bool) (uintptr, error) {
+	var retries uint64 // CASes lost to other reservations
+	for {
+		c := a.count.Load()
+		if c&flagsMask != 0 {
+			if c&frozenBit != 0 {
+				a.noteRetries(retries)
+				return 0, a.frozenErr()
+			}
+			if once {
+				return 0, ErrContended
+			}
+			// a reset is rewinding the arena; wait for it to finish
+			runtime.Gosched()
+			continue
+		}
+		start := c & countMask
+		var pad uintptr
+		if place != nil {
+			var err error
+			if pad, err = place(start); err != nil {
+				a.noteRetries(retries)
+				return start, err
+			}
+		}
+		if start > limit || pad > limit-start || n > limit-start-pad {
+			a.noteRetries(retries)
+			return start, ErrArenaFull
+		}
+		if a.count.CompareAndSwap(c, c+pad+n) {
+			a.noteRetries(retries)
+			a.claimed(start, pad+n)
+			return start, nil
+		}
+		if once {
+			return start, ErrContended
+		}
+		retries++
+	}
+}
+
+// noteRetries adds the CASes a reservation lost before it returned to
+// Stats.ReservationRetries. Uncontended reservations lose none and skip the
+// shared counter.
+func (a *AtomicArena[T]) noteRetries(n uint64) {
+	if n > 0 {
+		a.retries.Add(n)
+	}
+}
+
+// claimed does the bookkeeping for slots [start, start+n) that were just
+// reserved: sanitizer state, watermarks, WithTTL stamps and an ArenaGroup's
+// cached length.
+func (a *AtomicArena[T]) claimed(start, n uintptr) {
+	a.unpoisonSlots(start, start+n)
+	a.crossed(start + n)
+	if a.ttl != nil {
+		a.ttl.stamp(start, n)
+	}
+	if a.lenc != nil {
+		a.lenc.note(start, n)
 	}
 }
 
 // Alloc atomically reserves one slot and stores obj in the pre-allocated buffer.
 // Returns a pointer to the stored object, or error if full.
 func (a *AtomicArena[T]) Alloc(obj T) (*T, error) {
-	idx := a.count.Add(1) - 1
-	if idx >= a.maxElems {
-		// revert count
-		a.count.Add(^uintptr(0))
-		return nil, fmt.Errorf("arena full: max elements %d exceeded", a.maxElems)
+	_, p, err := a.alloc(obj)
+	if err == nil && a.prof != nil {
+		a.prof.sample(1)
+	}
+	return p, err
+}
+
+// AllocIndexed is Alloc that also returns the index of the slot obj was stored
+// in, for building compact references such as uint32 offsets instead of
+// pointers; Get(idx) returns the same pointer. An index names a slot, not a
+// value: after Reset the same index refers to the same slot, which later
+// allocations reuse, so an index is only meaningful within the Epoch it was
+// obtained in.
+func (a *AtomicArena[T]) AllocIndexed(obj T) (uintptr, *T, error) {
+	idx, p, err := a.alloc(obj)
+	if err == nil && a.prof != nil {
+		a.prof.sample(1)
+	}
+	return idx, p, err
+}
+
+// alloc implements Alloc and AllocIndexed. Sampling for the allocation profile
+// is left to them so recorded stacks start at their caller.
+func (a *AtomicArena[T]) alloc(obj T) (uintptr, *T, error) {
+	return a.allocWithin(obj, a.softCap.Load())
+}
+
+// allocWithin implements alloc and AllocPriority, reserving below limit.
+func (a *AtomicArena[T]) allocWithin(obj T, limit uintptr) (uintptr, *T, error) {
+	if a.word {
+		return a.allocWord(obj, limit)
+	}
+	idx, err := a.reserveWithin(1, limit, nil, false)
+	if err != nil {
+		return 0, nil, a.allocErr(err, idx, 1)
 	}
+	return idx, a.store(idx, obj), nil
+}
+
+// store fills the freshly reserved slot idx with obj, publishes it and marks
+// it written.
+func (a *AtomicArena[T]) store(idx uintptr, obj T) *T {
 	// place object in raw buffer and publish pointer
-	a.raw[idx] = obj
-	a.ptrs[idx].Store(&a.raw[idx])
-	return &a.raw[idx], nil
+	p := &a.raw[idx]
+	*p = obj
+	if a.ptrs != nil {
+		a.ptrs[idx].Store(p)
+	}
+	if a.ops != nil {
+		a.ops.record(OpAlloc, idx, 1, false)
+	}
+	a.commit(1)
+	return p
 }
 
 var ErrArenaFull = errors.New("atomicarena: arena full")
 
+// CapacityError reports an allocation that did not fit in the arena. It is
+// returned by Alloc, Reserve and AppendSlice, and wraps ErrArenaFull.
+type CapacityError struct {
+	Name      string  // arena name set by WithName; empty if unnamed
+	Requested uintptr // slots the allocation needed
+	Available uintptr // slots that were free when it failed
+	Capacity  uintptr // total slots in the arena, or the soft cap if Soft
+	Soft      bool    // refused at the soft cap set by WithSoftCap
+}
+
+// Error renders the failure like `atomicarena "packets": need 128 slots, 17 available of 4096`.
+func (e *CapacityError) Error() string {
+	prefix, unit := "atomicarena", "slots"
+	if e.Name != "" {
+		prefix = fmt.Sprintf("atomicarena %q", e.Name)
+	}
+	if e.Requested == 1 {
+		unit = "slot"
+	}
+	limit := ""
+	if e.Soft {
+		limit = " below the soft cap"
+	}
+	return fmt.Sprintf("%s: need %d %s, %d available of %d%s", prefix, e.Requested, unit, e.Available, e.Capacity, limit)
+}
+
+// Unwrap returns ErrArenaFull, so errors.Is(err, ErrArenaFull) holds.
+func (e *CapacityError) Unwrap() error { return ErrArenaFull }
+
+// allocErr turns a failed reservation of n slots into the error returned to
+// callers: ErrArenaFull becomes a *CapacityError, computed from the count
+// reserve observed at start, and other errors are prefixed with the arena's
+// name, if it has one. Capacity failures are also recorded in the execution
+// trace when tracing.
+func (a *AtomicArena[T]) allocErr(err error, start, n uintptr) error {
+	if err == ErrArenaFull {
+		a.full.Add(1)
+		if a.opts.tracing && trace.IsEnabled() {
+			a.traceFull()
+		}
+		soft, hard := a.softCap.Load(), a.maxElems.Load()
+		if n <= hard-start {
+			// it would have fit, so the soft cap refused it
+			a.softFull.Add(1)
+			return &CapacityError{Name: a.opts.name, Requested: n, Available: soft - min(start, soft), Capacity: soft, Soft: true}
+		}
+		return &CapacityError{Name: a.opts.name, Requested: n, Available: hard - start, Capacity: hard}
+	}
+	if a.opts.name == "" {
+		return err
+	}
+	return fmt.Errorf("arena %q: %w", a.opts.name, err)
+}
+
 // Reserve atomically reserves n slots and returns a slice view of length n.
 // Caller may write directly into the returned slice. No copying of data is performed.
+// The segment's slots are not published in the pointer mirror, and Committed
+// does not count them, until they are filled and passed to PublishRange;
+// Reset and the other operations that wait for in-flight writes do not wait
+// for that.
 func (a *AtomicArena[T]) Reserve(n uintptr) ([]T, error) {
-	if n == 0 {
-		return a.raw[:0], nil
+	_, seg, err := a.reserveSeg(n, a.opts.zeroOnReserve)
+	if err == nil && a.prof != nil {
+		a.prof.sample(n)
 	}
-	start := a.count.Add(n) - n
-	if start+n > a.maxElems {
-		// rollback
-		a.count.Add(^uintptr(n) + 1)
-		return nil, ErrArenaFull
+	return seg, err
+}
+
+// ReserveIndexed is Reserve that also returns the index of the segment's
+// first slot, so seg[i] is the slot at index start+i. Indices follow the
+// rules described at AllocIndexed.
+func (a *AtomicArena[T]) ReserveIndexed(n uintptr) (uintptr, []T, error) {
+	start, seg, err := a.reserveSeg(n, a.opts.zeroOnReserve)
+	if err == nil && a.prof != nil {
+		a.prof.sample(n)
+	}
+	return start, seg, err
+}
+
+// reserveSeg implements Reserve, ReserveIndexed and ReserveZeroed. If zero is
+// set, the segment's stale slots are cleared before they count as written.
+func (a *AtomicArena[T]) reserveSeg(n uintptr, zero bool) (uintptr, []T, error) {
+	start, err := a.reserve(n)
+	if err != nil {
+		return 0, nil, a.allocErr(err, start, n)
+	}
+	seg := a.raw[start : start+n]
+	if zero {
+		a.zeroStale(start, seg)
+	}
+	if a.ops != nil {
+		a.ops.record(OpReserve, start, n, false)
 	}
-	return a.raw[start : start+n], nil
+	// count the slots as unpublished before they count as done, so Committed
+	// never sees them as written
+	a.unpub.Add(n)
+	a.commit(n)
+	return start, seg, nil
 }
 
-// AppendSlice is now an alias for Reserve: it performs only an atomic reservation
-// and returns a slice segment of length len(objs). No copy is done.
-// To fill the arena, copy into the returned slice manually.
+// AppendSlice reserves len(objs) slots, copies objs into them and publishes
+// each slot in the pointer mirror before it counts as written. The returned
+// segment aliases the arena's storage.
 func (a *AtomicArena[T]) AppendSlice(objs []T) ([]T, error) {
+	_, seg, err := a.appendIndexed(objs)
+	return seg, err
+}
+
+// appendIndexed is AppendSlice that also returns the index of the segment's
+// first slot, for the callers that fill slots on the user's behalf.
+func (a *AtomicArena[T]) appendIndexed(objs []T) (uintptr, []T, error) {
+	if a.word {
+		start, seg, err := a.appendWord(objs)
+		if err == nil && a.prof != nil {
+			a.prof.sample(uintptr(len(objs)))
+		}
+		return start, seg, err
+	}
 	n := uintptr(len(objs))
 	// Reserve raw slots
-	seg, err := a.Reserve(n)
+	start, err := a.reserve(n)
 	if err != nil {
-		return nil, err
+		return 0, nil, a.allocErr(err, start, n)
 	}
+	seg := a.raw[start : start+n]
 	// Copy input values into reserved segment
 	copy(seg, objs)
-	return seg, nil
+	a.publish(start, start+n)
+	if a.ops != nil {
+		a.ops.record(OpReserve, start, n, false)
+	}
+	a.commit(n)
+	if a.prof != nil {
+		a.prof.sample(n)
+	}
+	return start, seg, nil
 }
 
-//go:linkname memclrNoHeapPointers runtime.memclrNoHeapPointers
-//go:nosplit
-func memclrNoHeapPointers(ptr unsafe.Pointer, n uintptr)
-
 // Reset clears all published pointers, allowing reuse of the arena.
-// It zeroes the ptrs slice via memclrNoHeapPointers and resets the allocation count.
-func (a *AtomicArena[T]) Reset(release bool) {
-	if release {
-		a.Free()
+// It zeroes the ptrs slice via clearMirror and resets the allocation count.
+// Reset waits for in-flight writes to finish before rewinding the count;
+// allocations that arrive while it runs wait for it rather than failing.
+// Under WithDestructor every live element is destroyed first, and any Close
+// errors are returned after the arena has been reset. Under WithRefCounting
+// Reset also waits for outstanding references to be released.
+// It returns ErrFrozen if the arena is frozen.
+func (a *AtomicArena[T]) Reset(release bool) error {
+	if a.refs != nil {
+		return a.ResetWhenIdle(context.Background(), release)
+	}
+	return a.reset(release)
+}
+
+// reset performs one reset attempt.
+func (a *AtomicArena[T]) reset(release bool) error {
+	if a.opts.tracing && trace.IsEnabled() {
+		return a.tracedReset(release)
+	}
+	_, _, err := a.rewind(release, nil)
+	return err
+}
+
+// rewind resets the arena once it is quiescent and reports how many slots it
+// released, along with any destructor errors. If idle is non-nil it is
+// called with the count word just before the reset is committed, and the
+// reset is abandoned if it returns false. Under WithRefCounting the reset is
+// also abandoned, with a *RefsError, while references are outstanding. The
+// busy bit holds off new reservations and references while the storage is
+// being zeroed.
+func (a *AtomicArena[T]) rewind(release bool, idle func(c uintptr) bool) (uintptr, bool, error) {
+	for {
+		c := a.count.Load()
+		if c&frozenBit != 0 {
+			return 0, false, a.frozenErr()
+		}
+		if c&busyBit != 0 {
+			// another reset is in progress
+			runtime.Gosched()
+			continue
+		}
+		n := c & countMask
+		if a.done.Load() != n {
+			// an Alloc or AppendSlice is still writing its slot
+			runtime.Gosched()
+			continue
+		}
+		if idle != nil && !idle(c) {
+			return 0, false, nil
+		}
+		if !a.count.CompareAndSwap(c, c|busyBit) {
+			continue
+		}
+		if a.refs != nil {
+			if refs := sumStripes(a.refs); refs != 0 {
+				a.count.Store(c)
+				return 0, false, &RefsError{Name: a.opts.name, Refs: refs}
+			}
+		}
+		var err error
+		if a.dtor != nil {
+			err = a.destroyLive(0, n)
+		}
+		a.clearTombstones(n)
+		if release {
+			a.zeroRange(0, n)
+		} else {
+			a.markStale(n)
+			if a.ptrs != nil {
+				// the values stay, but no slot is published any more
+				clearMirror(a.ptrs[:n])
+			}
+		}
+		a.done.Add(^n + 1)
+		a.unpub.Store(0)
+		if a.prof != nil {
+			a.prof.reset()
+		}
+		a.epoch.Add(1)
+		a.poisonSlots(0, n)
+		a.countDropped(n, 0)
+		if a.ops != nil {
+			// log before new allocations can, so none appears ahead of it
+			a.ops.record(OpReset, 0, n, release)
+		}
+		a.count.Store(0)
+		if a.lenc != nil {
+			a.lenc.refresh()
+		}
+		if a.waiters.wantAt.Load() != ^uintptr(0) {
+			// let waiters see the new epoch
+			a.wakeCommitted()
+		}
+		return n, true, err
 	}
-	a.count.Store(0)
 }
 
 // Free clears all published pointers and zeroes the raw storage.
-func (a *AtomicArena[T]) Free() {
-	old := a.count.Load()
-	if old > 0 {
-		// clear published pointers
-		ptr := unsafe.Pointer(&a.ptrs[0])
-		sz := unsafe.Sizeof(a.ptrs[0])
-		memclrNoHeapPointers(ptr, old*sz)
+// Under WithDestructor it first destroys the live elements, which then stay
+// tombstoned until the next Reset or Compact.
+// It returns ErrFrozen if the arena is frozen.
+func (a *AtomicArena[T]) Free() error {
+	if a.Frozen() {
+		return a.frozenErr()
+	}
+	if a.opts.tracing && trace.IsEnabled() {
+		return a.tracedFree()
+	}
+	_, err := a.free()
+	return err
+}
+
+// zeroRange clears published pointers and raw storage for slots [lo, hi).
+// Mapped storage hands whole pages back to the OS instead where it can.
+// Ranges larger than the parallel free threshold are split across goroutines.
+// Zero-sized elements have no storage to clear and no mirror, so there is
+// nothing to do for them.
+func (a *AtomicArena[T]) zeroRange(lo, hi uintptr) {
+	if hi <= lo || zeroSized[T]() {
+		return
+	}
+	defer a.markZeroed(lo, hi)
+	if a.discard != nil && a.discard(lo, hi) {
+		return
+	}
+	n := hi - lo
+	if n*unsafe.Sizeof(a.raw[0]) < a.opts.freeThreshold() {
+		a.zeroSerial(lo, hi)
+		return
+	}
+	workers := uintptr(runtime.GOMAXPROCS(0))
+	if workers > n {
+		workers = n
+	}
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := lo; start < hi; start += chunk {
+		end := min(start+chunk, hi)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.zeroSerial(start, end)
+		}()
+	}
+	wg.Wait()
+}
+
+func (a *AtomicArena[T]) zeroSerial(lo, hi uintptr) {
+	// clear published pointers
+	if a.ptrs != nil {
+		clearMirror(a.ptrs[lo:hi])
+	}
+
+	// **also** zero out raw storage:
+	a.zero.clear(a.raw[lo:hi])
+}
+
+// zeroer clears runs of slots. The arena calls it through the interface's
+// method table, like a function pointer, but unlike a func field it keeps
+// arenas comparable with reflect.DeepEqual.
+type zeroer[T any] interface {
+	clear(p []T)
+}
+
+// memclrZeroer clears pointer-free elements with a single memclr.
+type memclrZeroer[T any] struct{}
+
+func (memclrZeroer[T]) clear(p []T) { clearElems(p) }
+
+// typedZeroer clears elements holding pointers with the builtin clear.
+type typedZeroer[T any] struct{}
+
+func (typedZeroer[T]) clear(p []T) { clear(p) }
+
+// zeroerFor picks, once per arena, how runs of slots are cleared, so the
+// clearing paths need not branch on T: pointer-free elements are cleared
+// with a single memclr over their bytes, and elements holding pointers with
+// the builtin clear, which keeps the write barriers the garbage collector
+// needs.
+func zeroerFor[T any]() zeroer[T] {
+	if hasPointers[T]() {
+		return typedZeroer[T]{}
+	}
+	return memclrZeroer[T]{}
+}
+
+// FreeAsync zeroes the allocated storage like Free, but in the background.
+// The returned channel is closed once the storage is fully zeroed; the arena
+// must not be written to before then. On a frozen arena nothing is zeroed and
+// the channel is closed immediately.
+func (a *AtomicArena[T]) FreeAsync() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = a.Free()
+	}()
+	return done
+}
+
+// Len returns the number of slots currently allocated in the arena.
+func (a *AtomicArena[T]) Len() uintptr {
+	return a.count.Load() & countMask
+}
+
+// Epoch returns the number of times the arena has been reset or compacted.
+// Values derived from the arena's contents are stale once the epoch changes.
+func (a *AtomicArena[T]) Epoch() uint64 {
+	return a.epoch.Load()
+}
+
+// Cap returns the maximum number of elements the arena can hold.
+func (a *AtomicArena[T]) Cap() uintptr {
+	return a.maxElems.Load()
+}
+
+// Get returns a pointer to the element at index i, or false if i has not been allocated.
+// Tombstoned slots, and under WithTTL expired ones, are reported as missing.
+func (a *AtomicArena[T]) Get(i uintptr) (*T, bool) {
+	if i >= a.Len() || a.gone(i) {
+		return nil, false
+	}
+	return &a.raw[i], true
+}
+
+// Range calls fn for each allocated element in index order until fn returns false.
+// The number of elements is sampled once before iterating. Tombstoned slots are skipped.
+func (a *AtomicArena[T]) Range(fn func(i uintptr, v *T) bool) {
+	n := a.Len()
+	for i := uintptr(0); i < n; i++ {
+		if a.tombstoned(i) {
+			continue
+		}
+		if !fn(i, &a.raw[i]) {
+			return
+		}
+	}
+}
 
-		// **also** zero out raw storage:
-		var zero T
-		for i := uintptr(0); i < old; i++ {
-			a.raw[i] = zero
+// Snapshot returns a copy of the allocated elements, excluding tombstoned slots.
+func (a *AtomicArena[T]) Snapshot() []T {
+	n := a.Len()
+	out := make([]T, 0, n)
+	for i := uintptr(0); i < n; i++ {
+		if !a.tombstoned(i) {
+			out = append(out, a.raw[i])
 		}
 	}
+	return out
 }
@@ -0,0 +1,38 @@
+// Package arenahttp provides HTTP middleware that gives each request its own
+// arena, acquired from an atomicarena.ArenaPool and released when the handler
+// returns, and a handler exposing the registry of named arenas.
+package arenahttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// Middleware acquires an arena from pool for every request, stores it in the
+// request context (retrieve it with atomicarena.FromContext[T]) and releases
+// it back to the pool after next returns, including when next panics.
+// If the pool is exhausted the request fails with 503 Service Unavailable.
+func Middleware[T any](pool *atomicarena.ArenaPool[T], next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, err := pool.Acquire()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer pool.Release(a)
+		next.ServeHTTP(w, r.WithContext(atomicarena.WithArena(r.Context(), a)))
+	})
+}
+
+// HTTPHandler serves atomicarena.Arenas as a JSON array, for mounting on a
+// debug mux, e.g. mux.Handle("/debug/arenas", arenahttp.HTTPHandler()).
+func HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(atomicarena.Arenas()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package arenahttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// TestMiddlewareConcurrent sends concurrent requests and checks each handler
+// sees an empty, request-scoped arena.
+func TestMiddlewareConcurrent(t *testing.T) {
+	pool := atomicarena.NewArenaPool[int](8, 4, atomicarena.WithBlockingAcquire())
+	h := Middleware(pool, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, ok := atomicarena.FromContext[int](r.Context())
+		if !ok {
+			http.Error(w, "no arena", http.StatusInternalServerError)
+			return
+		}
+		if a.Len() != 0 {
+			http.Error(w, fmt.Sprintf("arena not reset: len %d", a.Len()), http.StatusInternalServerError)
+			return
+		}
+		for i := 0; i < 8; i++ {
+			_, _ = a.Alloc(i)
+		}
+		if r.URL.Query().Get("panic") != "" {
+			panic(http.ErrAbortHandler)
+		}
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				url := srv.URL
+				if (g+i)%5 == 0 {
+					url += "?panic=1"
+				}
+				resp, err := http.Get(url)
+				if err != nil {
+					continue // aborted by the panicking handler
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("status %d: %s", resp.StatusCode, body)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := pool.Outstanding(); n != 0 {
+		t.Fatalf("expected all arenas released, %d outstanding", n)
+	}
+}
+
+// TestHTTPHandler checks that named arenas are served as JSON
+func TestHTTPHandler(t *testing.T) {
+	a := atomicarena.NewAtomicArena[int](16, atomicarena.WithName("arenahttp-test"))
+	defer a.Close()
+	a.Alloc(1)
+
+	rec := httptest.NewRecorder()
+	HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/arenas", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", ct)
+	}
+	var infos []atomicarena.ArenaInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	for _, info := range infos {
+		if info.Name == "arenahttp-test" {
+			if info.Type != "int" || info.Len != 1 || info.Cap != 16 || info.Bytes == 0 {
+				t.Fatalf("unexpected entry %+v", info)
+			}
+			return
+		}
+	}
+	t.Fatalf("named arena missing from %s", rec.Body.String())
+}
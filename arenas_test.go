@@ -97,13 +97,13 @@ func BenchmarkReset(b *testing.B) {
 		s := s
 		b.Run(s.name, func(b *testing.B) {
 			// Each atomic.Pointer[T] is the size of an unsafe.Pointer
-			pointerSize := unsafe.Sizeof(atomic.Pointer[struct{}]{})
+			pointerSize := unsafe.Sizeof(atomic.Pointer[uintptr]{})
 			maxElems := s.totalBytes / pointerSize
-			arena := NewAtomicArena[struct{}](maxElems)
+			arena := NewAtomicArena[uintptr](maxElems)
 
 			// Prefill all slots so Reset has to clear them
 			for i := uintptr(0); i < maxElems; i++ {
-				arena.Alloc(struct{}{})
+				arena.Alloc(i)
 			}
 
 			b.ResetTimer()
@@ -339,7 +339,7 @@ func TestResetClearsValues(t *testing.T) {
 	// After reset, underlying storage should be zeroed
 	for i := uintptr(0); i < 3; i++ {
 		if arena.ptrs[i].Load() != nil {
-			t.Errorf("value at index %d not zero after reset: got %v", i, arena.ptrs[i])
+			t.Errorf("value at index %d not zero after reset: got %v", i, arena.ptrs[i].Load())
 		}
 	}
 }
@@ -416,7 +416,7 @@ func BenchmarkResetWithReaders(b *testing.B) {
 				return
 			default:
 				for i := 0; i < N; i++ {
-					_ = arena.ptrs[i].Load()
+					_ = arena.LoadPointer(uintptr(i))
 				}
 				runtime.Gosched()
 			}
@@ -0,0 +1,100 @@
+package atomicarena
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrStale is returned when the arena backing a view has been reset since
+// the view was created.
+var ErrStale = errors.New("atomicarena: arena was reset")
+
+// ArenaSlice is an append-only, ordered sequence stored in an arena. Unlike
+// a Go slice it never moves its elements, so pointers returned by Append and
+// At stay valid until the arena is reset. Storage is reserved in chunks, so
+// several ArenaSlices can share one arena, each owning its own slot ranges.
+//
+// An ArenaSlice is not safe for concurrent use; distinct ArenaSlices on the
+// same arena may be used from different goroutines.
+type ArenaSlice[T any] struct {
+	arena  *AtomicArena[T]
+	k      uintptr   // slots reserved per chunk
+	chunks [][]T     // reserved chunks in append order
+	offs   []uintptr // index of the first element of each chunk
+	base   uintptr   // arena index of the last chunk's first slot
+	short  bool      // some chunk is smaller than k
+	n      uintptr   // elements appended
+	epoch  uint64    // arena epoch the slice belongs to
+}
+
+// NewArenaSlice creates an empty ArenaSlice on a that reserves chunk slots
+// at a time. chunk is clamped to at least 1.
+func NewArenaSlice[T any](a *AtomicArena[T], chunk uintptr) *ArenaSlice[T] {
+	return &ArenaSlice[T]{arena: a, k: max(chunk, 1), epoch: a.Epoch()}
+}
+
+// Append stores v after the last element and returns a stable pointer to it.
+// Near the end of the arena a chunk smaller than the configured size is
+// reserved. It returns ErrStale once the arena has been reset.
+func (s *ArenaSlice[T]) Append(v T) (*T, error) {
+	if s.Stale() {
+		return nil, ErrStale
+	}
+	last := len(s.chunks) - 1
+	if last < 0 || s.n == s.offs[last]+uintptr(len(s.chunks[last])) {
+		start, k, err := s.arena.reserveUpTo(s.k)
+		if err != nil {
+			return nil, err
+		}
+		s.chunks = append(s.chunks, s.arena.raw[start:start+k])
+		s.arena.commit(k)
+		s.offs = append(s.offs, s.n)
+		s.base = start
+		s.short = s.short || k < s.k
+		last++
+	}
+	j := s.n - s.offs[last]
+	p := &s.chunks[last][j]
+	*p = v
+	s.arena.publish(s.base+j, s.base+j+1)
+	s.n++
+	return p, nil
+}
+
+// Len returns the number of appended elements, or zero if the slice is stale.
+func (s *ArenaSlice[T]) Len() uintptr {
+	if s.Stale() {
+		return 0
+	}
+	return s.n
+}
+
+// At returns a pointer to element i, or nil if i is out of range or the
+// slice is stale.
+func (s *ArenaSlice[T]) At(i uintptr) *T {
+	if i >= s.Len() {
+		return nil
+	}
+	c := i / s.k
+	if s.short {
+		c = uintptr(sort.Search(len(s.offs), func(j int) bool { return s.offs[j] > i })) - 1
+	}
+	return &s.chunks[c][i-s.offs[c]]
+}
+
+// Slice returns a copy of the appended elements in order.
+func (s *ArenaSlice[T]) Slice() []T {
+	n := s.Len()
+	out := make([]T, 0, n)
+	for _, c := range s.chunks {
+		// only the last chunk can be partly filled
+		take := min(uintptr(len(c)), n-uintptr(len(out)))
+		out = append(out, c[:take]...)
+	}
+	return out
+}
+
+// Stale reports whether the arena has been reset since the slice was created.
+func (s *ArenaSlice[T]) Stale() bool {
+	return s.arena.Epoch() != s.epoch
+}
@@ -0,0 +1,78 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestArenaSlicePointerStability ensures pointers survive thousands of appends
+func TestArenaSlicePointerStability(t *testing.T) {
+	arena := NewAtomicArena[int](10_000)
+	s := NewArenaSlice(arena, 64)
+	ptrs := make([]*int, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		p, err := s.Append(i)
+		if err != nil {
+			t.Fatalf("Append %d failed: %v", i, err)
+		}
+		ptrs = append(ptrs, p)
+	}
+	for i, p := range ptrs {
+		if *p != i || s.At(uintptr(i)) != p {
+			t.Fatalf("element %d moved or changed", i)
+		}
+	}
+	if s.Len() != 5000 || s.At(5000) != nil {
+		t.Fatalf("unexpected length %d", s.Len())
+	}
+}
+
+// TestArenaSliceInterleaved ensures two slices on one arena keep their own order
+func TestArenaSliceInterleaved(t *testing.T) {
+	arena := NewAtomicArena[int](1000)
+	a, b := NewArenaSlice(arena, 8), NewArenaSlice(arena, 8)
+	var wantA, wantB []int
+	for i := 0; i < 100; i++ {
+		a.Append(i)
+		wantA = append(wantA, i)
+		if i%3 == 0 {
+			b.Append(-i)
+			wantB = append(wantB, -i)
+		}
+	}
+	if !slices.Equal(a.Slice(), wantA) || !slices.Equal(b.Slice(), wantB) {
+		t.Fatalf("interleaved slices mixed up:\n%v\n%v", a.Slice(), b.Slice())
+	}
+	for i, v := range wantB {
+		if *b.At(uintptr(i)) != v {
+			t.Fatalf("b.At(%d) = %d, want %d", i, *b.At(uintptr(i)), v)
+		}
+	}
+}
+
+// TestArenaSliceShortChunk covers a short final chunk and a full arena
+func TestArenaSliceShortChunk(t *testing.T) {
+	arena := NewAtomicArena[int](20)
+	s := NewArenaSlice(arena, 8)
+	for i := 0; i < 20; i++ {
+		if _, err := s.Append(i); err != nil {
+			t.Fatalf("Append %d failed: %v", i, err)
+		}
+	}
+	if _, err := s.Append(20); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	for i := uintptr(0); i < 20; i++ {
+		if *s.At(i) != int(i) {
+			t.Fatalf("At(%d) = %d", i, *s.At(i))
+		}
+	}
+	arena.Reset(false)
+	if !s.Stale() || s.Len() != 0 {
+		t.Fatal("expected stale slice after Reset")
+	}
+	if _, err := s.Append(0); !errors.Is(err, ErrStale) {
+		t.Fatalf("expected ErrStale, got %v", err)
+	}
+}
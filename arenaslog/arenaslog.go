@@ -0,0 +1,322 @@
+// Package arenaslog provides a slog.Handler that batches records into
+// atomicarena arenas and flushes them to an inner handler when the batch
+// fills, when a timer fires, or on Close.
+package arenaslog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Raezil/atomicarena"
+)
+
+const (
+	// attrsPerRecord and bytesPerRecord size the attribute and byte arenas
+	// relative to the batch size.
+	attrsPerRecord = 8
+	bytesPerRecord = 256
+)
+
+// entry is the fixed-layout form of a slog.Record stored in the batch.
+type entry struct {
+	time  time.Time
+	level slog.Level
+	pc    uintptr
+	msg   []byte // in the byte arena
+	attrs []attr // in the attribute arena
+}
+
+// attr is a flattened attribute. Strings and values without a scalar
+// representation are copied into the byte arena.
+type attr struct {
+	key  []byte
+	kind slog.Kind
+	num  uint64
+	t    time.Time
+	str  []byte
+}
+
+// batch is the state shared by a BatchHandler and the handlers derived from it.
+type batch struct {
+	inner slog.Handler
+
+	// mu is held for reading while a record is stored and for writing while
+	// the batch is flushed, so flushes never observe a half-written record.
+	mu      sync.RWMutex
+	records *atomicarena.AtomicArena[entry]
+	attrs   *atomicarena.AtomicArena[attr]
+	bytes   *atomicarena.AtomicArena[byte]
+
+	clock     atomicarena.Clock // nil for the time package
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// BatchHandler is a slog.Handler that stores records in arenas and forwards
+// them to an inner handler in batches. Records are never dropped: a full batch
+// is flushed synchronously before the record that did not fit is stored.
+type BatchHandler struct {
+	b      *batch
+	pre    []slog.Attr // attributes from WithAttrs, keys already qualified
+	prefix string      // group qualification from WithGroup, like "a.b."
+}
+
+// Option configures a BatchHandler.
+type Option func(*batch)
+
+// WithClock drives the flush timer from c instead of the time package, so
+// tests can fire it with atomicarenatest.FakeClock.Advance. A nil c selects
+// the time package.
+func WithClock(c atomicarena.Clock) Option {
+	return func(b *batch) { b.clock = c }
+}
+
+// NewBatchHandler creates a BatchHandler buffering up to batchSize records and
+// flushing them to inner when the batch is full or every flushEvery (if positive).
+// batchSize is clamped to at least 1. Call Close to stop the timer and flush
+// what remains.
+func NewBatchHandler(inner slog.Handler, batchSize int, flushEvery time.Duration, opts ...Option) *BatchHandler {
+	n := uintptr(max(batchSize, 1))
+	b := &batch{
+		inner:   inner,
+		records: atomicarena.NewAtomicArena[entry](n),
+		attrs:   atomicarena.NewAtomicArena[attr](n * attrsPerRecord),
+		bytes:   atomicarena.NewAtomicArena[byte](n * bytesPerRecord),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if flushEvery > 0 {
+		go b.run(flushEvery)
+	} else {
+		close(b.stopped)
+	}
+	return &BatchHandler{b: b}
+}
+
+func (b *batch) run(every time.Duration) {
+	defer close(b.stopped)
+	var tick <-chan time.Time
+	if b.clock != nil {
+		ticker := b.clock.NewTicker(every)
+		defer ticker.Stop()
+		tick = ticker.C()
+	} else {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-tick:
+			_ = b.Flush()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// Enabled reports whether the inner handler handles records at level.
+func (h *BatchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.b.inner.Enabled(ctx, level)
+}
+
+// Handle stores r in the batch, flushing first if it does not fit.
+// Records too large for an empty batch are passed straight to the inner handler.
+func (h *BatchHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := h.flatten(r)
+	nbytes := uintptr(len(r.Message))
+	for i := range attrs {
+		nbytes += uintptr(len(attrs[i].Key))
+		if attrs[i].Value.Kind() == slog.KindString || attrs[i].Value.Kind() == slog.KindAny {
+			nbytes += uintptr(len(attrs[i].Value.String()))
+		}
+	}
+	if uintptr(len(attrs)) > h.b.attrs.Cap() || nbytes > h.b.bytes.Cap() {
+		rec := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		rec.AddAttrs(attrs...)
+		return h.b.inner.Handle(ctx, rec)
+	}
+	for {
+		h.b.mu.RLock()
+		err := h.b.store(r, attrs, nbytes)
+		h.b.mu.RUnlock()
+		if !errors.Is(err, atomicarena.ErrArenaFull) {
+			return err
+		}
+		if err := h.b.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// flatten returns the handler's accumulated attributes followed by the
+// record's, with groups expanded into dotted keys.
+func (h *BatchHandler) flatten(r slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(h.pre)+r.NumAttrs())
+	attrs = append(attrs, h.pre...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendFlat(attrs, h.prefix, a)
+		return true
+	})
+	return attrs
+}
+
+func appendFlat(dst []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			dst = appendFlat(dst, p, ga)
+		}
+		return dst
+	}
+	if a.Key == "" && v.Any() == nil {
+		return dst
+	}
+	return append(dst, slog.Attr{Key: prefix + a.Key, Value: v})
+}
+
+// store copies r into the arenas. It returns ErrArenaFull if any arena lacks room.
+func (b *batch) store(r slog.Record, attrs []slog.Attr, nbytes uintptr) error {
+	buf, err := b.bytes.Reserve(nbytes)
+	if err != nil {
+		return err
+	}
+	as, err := b.attrs.Reserve(uintptr(len(attrs)))
+	if err != nil {
+		return err
+	}
+	rec, err := b.records.Reserve(1)
+	if err != nil {
+		return err
+	}
+
+	put := func(s string) []byte {
+		n := copy(buf, s)
+		out := buf[:n:n]
+		buf = buf[n:]
+		return out
+	}
+	e := entry{time: r.Time, level: r.Level, pc: r.PC, msg: put(r.Message), attrs: as}
+	for i, a := range attrs {
+		as[i] = attr{key: put(a.Key), kind: a.Value.Kind()}
+		switch a.Value.Kind() {
+		case slog.KindInt64:
+			as[i].num = uint64(a.Value.Int64())
+		case slog.KindUint64:
+			as[i].num = a.Value.Uint64()
+		case slog.KindFloat64:
+			as[i].num = math.Float64bits(a.Value.Float64())
+		case slog.KindBool:
+			if a.Value.Bool() {
+				as[i].num = 1
+			}
+		case slog.KindDuration:
+			as[i].num = uint64(a.Value.Duration())
+		case slog.KindTime:
+			as[i].t = a.Value.Time()
+		default:
+			as[i].kind = slog.KindString
+			as[i].str = put(a.Value.String())
+		}
+	}
+	rec[0] = e
+	return nil
+}
+
+// Flush forwards all buffered records to the inner handler and resets the batch.
+func (h *BatchHandler) Flush() error {
+	return h.b.Flush()
+}
+
+func (b *batch) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked()
+}
+
+func (b *batch) flushLocked() error {
+	var errs []error
+	b.records.Range(func(_ uintptr, e *entry) bool {
+		rec := slog.NewRecord(e.time, e.level, string(e.msg), e.pc)
+		for i := range e.attrs {
+			rec.AddAttrs(e.attrs[i].toSlog())
+		}
+		if err := b.inner.Handle(context.Background(), rec); err != nil {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	b.records.Reset(false)
+	b.attrs.Reset(false)
+	b.bytes.Reset(false)
+	return errors.Join(errs...)
+}
+
+func (a *attr) toSlog() slog.Attr {
+	key := string(a.key)
+	switch a.kind {
+	case slog.KindInt64:
+		return slog.Int64(key, int64(a.num))
+	case slog.KindUint64:
+		return slog.Uint64(key, a.num)
+	case slog.KindFloat64:
+		return slog.Float64(key, math.Float64frombits(a.num))
+	case slog.KindBool:
+		return slog.Bool(key, a.num != 0)
+	case slog.KindDuration:
+		return slog.Duration(key, time.Duration(a.num))
+	case slog.KindTime:
+		return slog.Time(key, a.t)
+	default:
+		return slog.String(key, string(a.str))
+	}
+}
+
+// Close stops the flush timer and flushes any buffered records.
+// The handler must not be used afterwards.
+func (h *BatchHandler) Close() error {
+	var err error
+	h.b.closeOnce.Do(func() {
+		select {
+		case <-h.b.stopped:
+		default:
+			close(h.b.stop)
+			<-h.b.stopped
+		}
+		err = h.b.Flush()
+	})
+	return err
+}
+
+// WithAttrs returns a handler sharing this handler's batch that adds attrs to every record.
+func (h *BatchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.pre = append([]slog.Attr(nil), h.pre...)
+	for _, a := range attrs {
+		h2.pre = appendFlat(h2.pre, h.prefix, a)
+	}
+	return &h2
+}
+
+// WithGroup returns a handler sharing this handler's batch that qualifies
+// subsequent attribute keys with name.
+func (h *BatchHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
@@ -0,0 +1,160 @@
+package arenaslog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Raezil/atomicarena/atomicarenatest"
+)
+
+// recorder is an inner handler that keeps every record it receives.
+type recorder struct {
+	mu      sync.Mutex
+	recs    []slog.Record
+	handled chan struct{} // if set, receives once per record
+}
+
+func (r *recorder) Enabled(context.Context, slog.Level) bool { return true }
+func (r *recorder) Handle(_ context.Context, rec slog.Record) error {
+	r.mu.Lock()
+	r.recs = append(r.recs, rec.Clone())
+	r.mu.Unlock()
+	if r.handled != nil {
+		r.handled <- struct{}{}
+	}
+	return nil
+}
+func (r *recorder) WithAttrs([]slog.Attr) slog.Handler { return r }
+func (r *recorder) WithGroup(string) slog.Handler      { return r }
+
+func (r *recorder) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.recs)
+}
+
+func attrMap(rec slog.Record) map[string]slog.Value {
+	m := make(map[string]slog.Value)
+	rec.Attrs(func(a slog.Attr) bool { m[a.Key] = a.Value; return true })
+	return m
+}
+
+// TestBatchHandlerConcurrent logs from many goroutines and checks every record
+// arrives exactly once, in per-goroutine order, with its attributes intact.
+func TestBatchHandlerConcurrent(t *testing.T) {
+	const (
+		loggers = 8
+		perG    = 500
+	)
+	inner := &recorder{}
+	h := NewBatchHandler(inner, 64, 0)
+	logger := slog.New(h).With("app", "test")
+
+	var wg sync.WaitGroup
+	wg.Add(loggers)
+	for g := 0; g < loggers; g++ {
+		go func(g int) {
+			defer wg.Done()
+			l := logger.WithGroup("req")
+			for i := 0; i < perG; i++ {
+				l.Info(fmt.Sprintf("msg %d", i), "g", g, "i", i, "ok", i%2 == 0, "d", time.Duration(i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(inner.recs) != loggers*perG {
+		t.Fatalf("expected %d records, got %d", loggers*perG, len(inner.recs))
+	}
+	next := make([]int64, loggers)
+	for _, rec := range inner.recs {
+		m := attrMap(rec)
+		g, i := m["req.g"].Int64(), m["req.i"].Int64()
+		if i != next[g] {
+			t.Fatalf("logger %d: expected record %d, got %d", g, next[g], i)
+		}
+		next[g]++
+		if rec.Message != fmt.Sprintf("msg %d", i) {
+			t.Fatalf("unexpected message %q", rec.Message)
+		}
+		if m["app"].String() != "test" || m["req.ok"].Bool() != (i%2 == 0) || m["req.d"].Duration() != time.Duration(i) {
+			t.Fatalf("attributes not preserved: %v", m)
+		}
+	}
+}
+
+// TestBatchHandlerCopiesValues ensures attribute values are captured at log time
+func TestBatchHandlerCopiesValues(t *testing.T) {
+	inner := &recorder{}
+	h := NewBatchHandler(inner, 4, 0)
+	buf := bytes.NewBufferString("before")
+	slog.New(h).Info("hello", "buf", buf)
+	buf.Reset()
+	buf.WriteString("after!")
+	_ = h.Close()
+	if got := attrMap(inner.recs[0])["buf"].String(); got != "before" {
+		t.Fatalf("expected attribute captured at log time, got %q", got)
+	}
+}
+
+// TestBatchHandlerFlushes covers flush on full batch, on the timer, and oversized records
+func TestBatchHandlerFlushes(t *testing.T) {
+	inner := &recorder{handled: make(chan struct{}, 8)}
+	clock := atomicarenatest.NewFakeClock(time.Unix(0, 0))
+	h := NewBatchHandler(inner, 2, 5*time.Millisecond, WithClock(clock))
+	defer h.Close()
+	clock.BlockUntil(1)
+	l := slog.New(h)
+	l.Info("a")
+	l.Info("b")
+	l.Info("c") // does not fit: a and b are flushed first
+	if inner.len() != 2 {
+		t.Fatalf("expected full batch to flush, got %d records", inner.len())
+	}
+	<-inner.handled
+	<-inner.handled
+	clock.Advance(4 * time.Millisecond)
+	if inner.len() != 2 {
+		t.Fatalf("expected no timer flush before the interval, got %d records", inner.len())
+	}
+	clock.Advance(time.Millisecond)
+	select {
+	case <-inner.handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the timer to flush")
+	}
+	if inner.len() != 3 {
+		t.Fatalf("expected timer flush, got %d records", inner.len())
+	}
+
+	big := string(make([]byte, 2*bytesPerRecord+1))
+	l.Info(big)
+	if inner.len() != 4 {
+		t.Fatalf("expected oversized record to bypass the batch, got %d records", inner.len())
+	}
+}
+
+// TestBatchHandlerSmallBatch clamps a batch size below one to a single record
+func TestBatchHandlerSmallBatch(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		inner := &recorder{}
+		h := NewBatchHandler(inner, size, 0)
+		l := slog.New(h)
+		l.Info("a")
+		l.Info("b")
+		if inner.len() != 1 {
+			t.Fatalf("size %d: expected the first record flushed by the second, got %d records", size, inner.len())
+		}
+		if err := h.Close(); err != nil || inner.len() != 2 {
+			t.Fatalf("size %d: expected Close to flush the last record, got %d records, %v", size, inner.len(), err)
+		}
+	}
+}
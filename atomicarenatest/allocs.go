@@ -0,0 +1,24 @@
+package atomicarenatest
+
+import "testing"
+
+// AllocRuns is the number of times AssertZeroAllocs and AssertAllocsAtMost
+// call fn. A large count keeps a rare allocation from being averaged away.
+const AllocRuns = 1000
+
+// AssertZeroAllocs fails t unless fn performs no heap allocations, averaged
+// over AllocRuns calls with testing.AllocsPerRun. fn is also called once
+// beforehand to warm up, so it must tolerate AllocRuns+1 calls.
+func AssertZeroAllocs(t testing.TB, fn func()) {
+	t.Helper()
+	AssertAllocsAtMost(t, 0, fn)
+}
+
+// AssertAllocsAtMost is AssertZeroAllocs for operations that are allowed a
+// known number of allocations per call, such as building an error value.
+func AssertAllocsAtMost(t testing.TB, limit float64, fn func()) {
+	t.Helper()
+	if got := testing.AllocsPerRun(AllocRuns, fn); got > limit {
+		t.Errorf("expected at most %v heap allocations per call, got %v", limit, got)
+	}
+}
@@ -0,0 +1,145 @@
+// Package atomicarenatest provides test helpers for code that allocates from
+// atomicarena arenas. Its TrackedArena records every pointer it hands out so
+// a test can assert none are held past their lifetime; keeping it in its own
+// package means production builds never carry the tracking table.
+package atomicarenatest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// stackDepth is the maximum number of frames recorded per allocation.
+const stackDepth = 32
+
+// TrackedArena wraps an AtomicArena and tracks the pointers returned by Alloc
+// until they are handed back with Release. Pointers still outstanding when
+// the arena is Reset are kept as leaks, together with their allocation
+// stacks, for AssertEmptyOutstanding to report. It is safe for concurrent use.
+type TrackedArena[T any] struct {
+	arena *atomicarena.AtomicArena[T]
+
+	mu          sync.Mutex
+	outstanding map[*T][]uintptr // pointer → allocation stack
+	leaked      []leak
+}
+
+// leak is a pointer that was not released before a Reset.
+type leak struct {
+	epoch uint64
+	stack []uintptr
+}
+
+// NewTrackedArena creates a tracked arena holding up to maxElems elements.
+func NewTrackedArena[T any](maxElems uintptr, opts ...atomicarena.Option) *TrackedArena[T] {
+	return &TrackedArena[T]{
+		arena:       atomicarena.NewAtomicArena[T](maxElems, opts...),
+		outstanding: make(map[*T][]uintptr),
+	}
+}
+
+// Arena returns the underlying arena. Pointers obtained from it directly are
+// not tracked.
+func (a *TrackedArena[T]) Arena() *atomicarena.AtomicArena[T] {
+	return a.arena
+}
+
+// Alloc allocates obj like AtomicArena.Alloc and records the returned
+// pointer with the caller's stack.
+func (a *TrackedArena[T]) Alloc(obj T) (*T, error) {
+	p, err := a.arena.Alloc(obj)
+	if err != nil {
+		return nil, err
+	}
+	stk := make([]uintptr, stackDepth)
+	// skip runtime.Callers and Alloc
+	stk = stk[:runtime.Callers(2, stk)]
+	a.mu.Lock()
+	a.outstanding[p] = stk
+	a.mu.Unlock()
+	return p, nil
+}
+
+// Release marks p as no longer held. It reports false if p is not an
+// outstanding pointer of this arena, for example because it was released
+// twice or survived a Reset.
+func (a *TrackedArena[T]) Release(p *T) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.outstanding[p]; !ok {
+		return false
+	}
+	delete(a.outstanding, p)
+	return true
+}
+
+// Outstanding returns the number of pointers allocated and not yet released
+// since the last Reset.
+func (a *TrackedArena[T]) Outstanding() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.outstanding)
+}
+
+// Reset resets the underlying arena. Pointers still outstanding are recorded
+// as leaks and stop being tracked.
+func (a *TrackedArena[T]) Reset(release bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	epoch := a.arena.Epoch()
+	if err := a.arena.Reset(release); err != nil {
+		return err
+	}
+	for p, stk := range a.outstanding {
+		a.leaked = append(a.leaked, leak{epoch: epoch, stack: stk})
+		delete(a.outstanding, p)
+	}
+	return nil
+}
+
+// AssertEmptyOutstanding fails t if any pointer leaked past a Reset or is
+// still outstanding, listing the allocation site of each one. Leaks already
+// reported are not reported again.
+func (a *TrackedArena[T]) AssertEmptyOutstanding(t testing.TB) {
+	t.Helper()
+	a.mu.Lock()
+	leaks := a.leaked
+	a.leaked = nil
+	var live [][]uintptr
+	for _, stk := range a.outstanding {
+		live = append(live, stk)
+	}
+	a.mu.Unlock()
+	if len(leaks) == 0 && len(live) == 0 {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d arena pointer(s) not released", len(leaks)+len(live))
+	for _, l := range leaks {
+		fmt.Fprintf(&b, "\nleaked past Reset of epoch %d, allocated at:\n%s", l.epoch, formatStack(l.stack))
+	}
+	for _, stk := range live {
+		fmt.Fprintf(&b, "\nstill outstanding, allocated at:\n%s", formatStack(stk))
+	}
+	t.Error(b.String())
+}
+
+// formatStack renders a stack like a goroutine trace: one "function\n\tfile:line"
+// pair per frame.
+func formatStack(stk []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(stk)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,130 @@
+package atomicarenatest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recorder is a testing.TB that captures failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	msgs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+type request struct {
+	ID   int
+	Body string
+}
+
+//go:noinline
+func leakyHandler(a *TrackedArena[request]) *request {
+	p, _ := a.Alloc(request{ID: 1, Body: "kept"})
+	return p
+}
+
+//go:noinline
+func tidyHandler(a *TrackedArena[request]) {
+	p, _ := a.Alloc(request{ID: 2, Body: "released"})
+	a.Release(p)
+}
+
+// TestLeakReported deliberately leaks a pointer past Reset
+func TestLeakReported(t *testing.T) {
+	a := NewTrackedArena[request](8)
+	tidyHandler(a)
+	leakyHandler(a)
+	if n := a.Outstanding(); n != 1 {
+		t.Fatalf("expected 1 outstanding pointer, got %d", n)
+	}
+	if err := a.Reset(true); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{TB: t}
+	a.AssertEmptyOutstanding(r)
+	if len(r.msgs) != 1 {
+		t.Fatalf("expected one failure, got %q", r.msgs)
+	}
+	msg := r.msgs[0]
+	if !strings.Contains(msg, "atomicarenatest.leakyHandler") || !strings.Contains(msg, "leaked past Reset") {
+		t.Fatalf("expected the leaking function in the failure, got:\n%s", msg)
+	}
+	if strings.Contains(msg, "tidyHandler") {
+		t.Fatalf("released pointers must not be reported:\n%s", msg)
+	}
+
+	// the leak was reported once and the arena is clean again
+	r.msgs = nil
+	a.AssertEmptyOutstanding(r)
+	if len(r.msgs) != 0 {
+		t.Fatalf("expected no repeated report, got %q", r.msgs)
+	}
+}
+
+// TestOutstandingReported fails on pointers held without a Reset
+func TestOutstandingReported(t *testing.T) {
+	a := NewTrackedArena[request](8)
+	p := leakyHandler(a)
+	r := &recorder{TB: t}
+	a.AssertEmptyOutstanding(r)
+	if len(r.msgs) != 1 || !strings.Contains(r.msgs[0], "still outstanding") || !strings.Contains(r.msgs[0], "leakyHandler") {
+		t.Fatalf("expected an outstanding-pointer failure, got %q", r.msgs)
+	}
+	if !a.Release(p) || a.Release(p) {
+		t.Fatalf("expected the first Release to succeed and the second to fail")
+	}
+	a.AssertEmptyOutstanding(t)
+}
+
+// TestTrackedConcurrent allocates and releases from several goroutines; run with -race
+func TestTrackedConcurrent(t *testing.T) {
+	a := NewTrackedArena[request](1024)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				p, err := a.Alloc(request{ID: i})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				a.Release(p)
+			}
+		}()
+	}
+	wg.Wait()
+	a.AssertEmptyOutstanding(t)
+	if a.Arena().Len() != 800 {
+		t.Fatalf("expected 800 allocations, got %d", a.Arena().Len())
+	}
+}
+
+var sink []byte
+
+// TestAssertZeroAllocs reports an allocating function and accepts the rest
+func TestAssertZeroAllocs(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertZeroAllocs(r, func() {})
+	AssertAllocsAtMost(r, 1, func() { sink = make([]byte, 64) })
+	if len(r.msgs) != 0 {
+		t.Fatalf("expected no failures, got %q", r.msgs)
+	}
+	AssertZeroAllocs(r, func() { sink = make([]byte, 64) })
+	if len(r.msgs) != 1 || !strings.Contains(r.msgs[0], "at most 0 heap allocations per call, got 1") {
+		t.Fatalf("expected one allocation to be reported, got %q", r.msgs)
+	}
+}
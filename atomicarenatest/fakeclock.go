@@ -0,0 +1,137 @@
+package atomicarenatest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// FakeClock is an atomicarena.Clock whose time only moves when Advance is
+// called, for testing time-based features without sleeping. Pass it to an
+// arena with atomicarena.WithClock. It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// fakeTimer is a pending After channel, or a ticker when period is set.
+type fakeTimer struct {
+	clock  *FakeClock
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+var _ atomicarena.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock reading start.
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.cond.L = &c.mu
+	return c
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the clock's time once Advance has
+// moved it d past now. A d of zero or less fires at once.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- c.now
+		return t.ch
+	}
+	c.add(t)
+	return t.ch
+}
+
+// NewTicker returns a ticker that ticks each time Advance crosses a multiple
+// of d. Like time.Ticker it holds at most one undelivered tick. It panics if
+// d is not positive.
+func (c *FakeClock) NewTicker(d time.Duration) atomicarena.Ticker {
+	if d <= 0 {
+		panic("atomicarenatest: non-positive interval for NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.add(t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires every timer and ticker that
+// has come due, in time order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for len(c.waiters) > 0 && !c.waiters[0].at.After(end) {
+		t := c.waiters[0]
+		c.waiters = c.waiters[1:]
+		c.now = t.at
+		select {
+		case t.ch <- t.at:
+		default:
+			// a ticker whose last tick has not been received
+		}
+		if t.period > 0 {
+			t.at = t.at.Add(t.period)
+			c.add(t)
+		}
+	}
+	c.now = end
+}
+
+// Waiters returns the number of pending After channels and tickers.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n After channels and tickers are pending,
+// so a test can advance the clock only once the code under test is waiting
+// on it.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// add inserts t in firing order. c.mu must be held.
+func (c *FakeClock) add(t *fakeTimer) {
+	i := sort.Search(len(c.waiters), func(i int) bool { return c.waiters[i].at.After(t.at) })
+	c.waiters = append(c.waiters, nil)
+	copy(c.waiters[i+1:], c.waiters[i:])
+	c.waiters[i] = t
+	c.cond.Broadcast()
+}
+
+// C returns the ticker's channel.
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+// Stop removes the ticker from its clock. No tick is delivered afterwards.
+func (t *fakeTimer) Stop() {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package atomicarenatest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// TestFakeClockAfter fires timers only once Advance reaches them
+func TestFakeClockAfter(t *testing.T) {
+	start := time.Unix(100, 0)
+	c := NewFakeClock(start)
+	late, soon := c.After(2*time.Second), c.After(time.Second)
+	select {
+	case <-c.After(0):
+	default:
+		t.Fatalf("expected After(0) to fire at once")
+	}
+	c.Advance(999 * time.Millisecond)
+	select {
+	case <-soon:
+		t.Fatalf("timer fired early")
+	default:
+	}
+	c.Advance(time.Millisecond)
+	if at := <-soon; !at.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected the timer to fire at +1s, got %v", at)
+	}
+	c.Advance(5 * time.Second)
+	if at := <-late; !at.Equal(start.Add(2*time.Second)) || !c.Now().Equal(start.Add(6*time.Second)) {
+		t.Fatalf("expected +2s and a clock at +6s, got %v and %v", at, c.Now())
+	}
+	if c.Waiters() != 0 {
+		t.Fatalf("expected no pending timers, got %d", c.Waiters())
+	}
+}
+
+// TestFakeClockTicker delivers one tick per period and drops unread ones
+func TestFakeClockTicker(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	tk := c.NewTicker(time.Second)
+	c.Advance(time.Second)
+	<-tk.C()
+	c.Advance(3 * time.Second)
+	<-tk.C()
+	select {
+	case <-tk.C():
+		t.Fatalf("expected unread ticks to be dropped")
+	default:
+	}
+	tk.Stop()
+	c.Advance(time.Hour)
+	select {
+	case <-tk.C():
+		t.Fatalf("expected no tick after Stop")
+	default:
+	}
+	if c.Waiters() != 0 {
+		t.Fatalf("expected Stop to unregister the ticker")
+	}
+}
+
+// TestFakeClockResetWhenIdle drives the ResetWhenIdle backoff from another
+// goroutine
+func TestFakeClockResetWhenIdle(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	a := atomicarena.NewAtomicArena[int](4, atomicarena.WithRefCounting(), atomicarena.WithClock(c))
+	a.Alloc(1)
+	ref := a.Acquire()
+	done := make(chan error)
+	go func() { done <- a.ResetWhenIdle(context.Background(), false) }()
+	c.BlockUntil(1)
+	c.Advance(time.Microsecond)
+	c.BlockUntil(1)
+	a.ReleaseRef(ref)
+	c.Advance(time.Millisecond)
+	if err := <-done; err != nil || a.Len() != 0 {
+		t.Fatalf("expected the reset once the reference was released, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.Alloc(2)
+	ref = a.Acquire()
+	go func() { done <- a.ResetWhenIdle(ctx, false) }()
+	c.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) || a.Len() != 1 {
+		t.Fatalf("expected context.Canceled with the arena untouched, got %v", err)
+	}
+	a.ReleaseRef(ref)
+}
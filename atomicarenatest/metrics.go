@@ -0,0 +1,75 @@
+package atomicarenatest
+
+import (
+	"testing"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// StatsProvider is an arena whose Stats can be sampled, such as an
+// AtomicArena or an MmapArena.
+type StatsProvider interface {
+	Stats() atomicarena.Stats
+}
+
+// Baseline holds the Stats of a set of arenas taken by Snapshot, which
+// Report measures the rest of a benchmark against.
+type Baseline struct {
+	arenas []StatsProvider
+	base   []atomicarena.Stats
+}
+
+// Snapshot records the Stats of arenas. Take it right after b.ResetTimer
+// so setup allocations are left out of the metrics.
+func Snapshot(arenas ...StatsProvider) *Baseline {
+	s := &Baseline{arenas: arenas, base: make([]atomicarena.Stats, len(arenas))}
+	for i, a := range arenas {
+		s.base[i] = a.Stats()
+	}
+	return s
+}
+
+// Report adds the arenas' activity since the snapshot to b's results as
+// custom metrics, summed over the arenas:
+//
+//   - arena-allocs/op: slots the arenas handed out per iteration
+//   - spills/op: allocations refused with ErrArenaFull per iteration, the
+//     ones a caller has to serve from the heap or another arena instead
+//   - peak-util-%: the highest Len of the arenas as a share of their
+//     capacity, 0 to 100
+//
+// The peak is the highest since the arenas were created, not since the
+// snapshot, so it also covers the benchmark's setup.
+func (s *Baseline) Report(b *testing.B) {
+	b.Helper()
+	var allocs, spills uint64
+	var peak, capacity uintptr
+	for i, a := range s.arenas {
+		d := a.Stats().Sub(s.base[i])
+		allocs += d.Allocated
+		spills += d.Rejected
+		peak += d.PeakLen
+		capacity += d.Cap
+	}
+	if b.N > 0 {
+		b.ReportMetric(float64(allocs)/float64(b.N), "arena-allocs/op")
+		b.ReportMetric(float64(spills)/float64(b.N), "spills/op")
+	}
+	if capacity > 0 {
+		b.ReportMetric(100*float64(peak)/float64(capacity), "peak-util-%")
+	}
+}
+
+// ReportMetrics takes a snapshot of arenas and reports it with Report when
+// the benchmark function returns. Call it right after b.ResetTimer:
+//
+//	b.ResetTimer()
+//	atomicarenatest.ReportMetrics(b, arena)
+//	for i := 0; i < b.N; i++ {
+//		...
+//	}
+func ReportMetrics(b *testing.B, arenas ...StatsProvider) {
+	b.Helper()
+	s := Snapshot(arenas...)
+	b.Cleanup(func() { s.Report(b) })
+}
@@ -0,0 +1,50 @@
+package atomicarenatest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// TestSnapshotReport runs a benchmark that fills its arena and checks the metrics it reports
+func TestSnapshotReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs a full benchmark")
+	}
+	r := testing.Benchmark(func(b *testing.B) {
+		a := atomicarena.NewAtomicArena[int](64)
+		a.Reserve(16)
+		b.ResetTimer()
+		s := Snapshot(a)
+		for i := 0; i < b.N; i++ {
+			if _, err := a.Alloc(i); err != nil {
+				a.Reset(false)
+			}
+		}
+		s.Report(b)
+	})
+	if r.N == 0 {
+		t.Fatalf("expected the benchmark to run")
+	}
+	allocs, spills := r.Extra["arena-allocs/op"], r.Extra["spills/op"]
+	if allocs <= 0 || spills < 0 || math.Abs(allocs+spills-1) > 1e-9 {
+		t.Fatalf("expected every iteration to allocate or spill, got %v allocs/op and %v spills/op", allocs, spills)
+	}
+	// the 16 slots reserved in setup leave room for 48 iterations
+	if peak := r.Extra["peak-util-%"]; r.N > 48 && peak != 100 {
+		t.Fatalf("expected the arena to fill up, got peak utilization %v%%", peak)
+	}
+}
+
+// BenchmarkReportMetrics allocates from an arena that spills once it is full
+func BenchmarkReportMetrics(b *testing.B) {
+	a := atomicarena.NewAtomicArena[int](1 << 10)
+	b.ResetTimer()
+	ReportMetrics(b, a)
+	for i := 0; i < b.N; i++ {
+		if _, err := a.Alloc(i); err != nil {
+			a.Reset(false)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package atomicarenatest
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// ErrDiverged is returned by ReplayOps when the arena it replays into ends
+// up in a different state from the one the records describe.
+var ErrDiverged = errors.New("atomicarenatest: replay diverged")
+
+// ReplayOps re-applies operations recorded by an arena built WithOpLog to a,
+// one at a time, so an interleaving captured from a failing run can be
+// reproduced deterministically in a test. Resets and frees are replayed in
+// Seq order; the allocations between two of them are replayed in the order
+// they claimed slots, which concurrent writers may have logged differently.
+// Allocations store the zero value, since the log does not keep values, and
+// must land on the recorded slots; resets and frees must find the recorded
+// number of slots allocated. a should be fresh, with at least the capacity
+// of the recorded arena, and records should start from a fresh arena too,
+// so a log whose ring has wrapped replays only from a Reset onward. Drain
+// records are skipped, as the reset a Drain performs is logged on its own.
+// ReplayOps stops at the first record that fails or diverges, returning an
+// error naming it that wraps ErrDiverged or the arena's error.
+func ReplayOps[T any](records []atomicarena.OpRecord, a *atomicarena.AtomicArena[T]) error {
+	for len(records) > 0 {
+		// the run of allocations up to the next reset, free or drain
+		n := slices.IndexFunc(records, func(r atomicarena.OpRecord) bool {
+			return r.Op != atomicarena.OpAlloc && r.Op != atomicarena.OpReserve
+		})
+		if n < 0 {
+			n = len(records)
+		}
+		run := slices.Clone(records[:n])
+		slices.SortStableFunc(run, func(x, y atomicarena.OpRecord) int {
+			return cmp.Compare(x.Index, y.Index)
+		})
+		for _, r := range run {
+			if err := replayAlloc(r, a); err != nil {
+				return fmt.Errorf("replaying record %d (%v): %w", r.Seq, r.Op, err)
+			}
+		}
+		if n == len(records) {
+			break
+		}
+		if r := records[n]; r.Op != atomicarena.OpDrain {
+			if err := replayRelease(r, a); err != nil {
+				return fmt.Errorf("replaying record %d (%v): %w", r.Seq, r.Op, err)
+			}
+		}
+		records = records[n+1:]
+	}
+	return nil
+}
+
+// replayAlloc applies an Alloc or Reserve record to a.
+func replayAlloc[T any](r atomicarena.OpRecord, a *atomicarena.AtomicArena[T]) error {
+	if r.Op == atomicarena.OpAlloc {
+		var zero T
+		idx, _, err := a.AllocIndexed(zero)
+		if err == nil && idx != r.Index {
+			err = fmt.Errorf("%w: allocated slot %d, recorded %d", ErrDiverged, idx, r.Index)
+		}
+		return err
+	}
+	start, _, err := a.ReserveIndexed(r.Len)
+	if err == nil && start != r.Index {
+		err = fmt.Errorf("%w: reserved from slot %d, recorded %d", ErrDiverged, start, r.Index)
+	}
+	return err
+}
+
+// replayRelease applies a Reset or Free record to a.
+func replayRelease[T any](r atomicarena.OpRecord, a *atomicarena.AtomicArena[T]) error {
+	if r.Op != atomicarena.OpReset && r.Op != atomicarena.OpFree {
+		return fmt.Errorf("%w: unknown operation %v", ErrDiverged, r.Op)
+	}
+	if n := a.Len(); n != r.Len {
+		return fmt.Errorf("%w: %d slots allocated, recorded %d", ErrDiverged, n, r.Len)
+	}
+	if r.Op == atomicarena.OpReset {
+		return a.Reset(r.Release)
+	}
+	return a.Free()
+}
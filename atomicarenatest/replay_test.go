@@ -0,0 +1,68 @@
+package atomicarenatest
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Raezil/atomicarena"
+)
+
+// TestReplayOps reproduces a recorded concurrent sequence on a fresh arena
+func TestReplayOps(t *testing.T) {
+	a := atomicarena.NewAtomicArena[int](256, atomicarena.WithOpLog(256))
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 8 {
+				if (w+i)%3 == 0 {
+					a.Reserve(uintptr(i + 1))
+				} else {
+					a.Alloc(i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	a.Reset(true)
+	a.Alloc(1)
+	a.Free()
+
+	records := a.OpLog()
+	if len(records) != 4*8+3 {
+		t.Fatalf("expected %d records, got %d", 4*8+3, len(records))
+	}
+	// writers on other CPUs may log their allocations out of slot order
+	shuffled := slices.Clone(records)
+	slices.Reverse(shuffled[:4*8])
+	for _, recs := range [][]atomicarena.OpRecord{records, shuffled} {
+		fresh := atomicarena.NewAtomicArena[int](256)
+		if err := ReplayOps(recs, fresh); err != nil {
+			t.Fatalf("ReplayOps failed: %v", err)
+		}
+		if fresh.Len() != a.Len() || fresh.Epoch() != a.Epoch() {
+			t.Fatalf("expected the replay to end at len %d in epoch %d, got %d in %d", a.Len(), a.Epoch(), fresh.Len(), fresh.Epoch())
+		}
+	}
+}
+
+// TestReplayOpsDiverged stops at the first record the arena cannot match
+func TestReplayOpsDiverged(t *testing.T) {
+	a := atomicarena.NewAtomicArena[int](8, atomicarena.WithOpLog(8))
+	a.Alloc(1)
+	a.Reserve(2)
+	a.Reset(false)
+	used := atomicarena.NewAtomicArena[int](8)
+	used.Alloc(0)
+	err := ReplayOps(a.OpLog(), used)
+	if !errors.Is(err, ErrDiverged) || used.Len() != 2 {
+		t.Fatalf("expected the first allocation to diverge, got %v at len %d", err, used.Len())
+	}
+	small := atomicarena.NewAtomicArena[int](2)
+	if err := ReplayOps(a.OpLog(), small); !errors.Is(err, atomicarena.ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull replaying into a small arena, got %v", err)
+	}
+}
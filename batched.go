@@ -0,0 +1,199 @@
+package atomicarena
+
+import (
+	"context"
+	"errors"
+	"runtime/trace"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchedArena amortizes the shared reservation counter of an AtomicArena:
+// each allocating goroutine takes its own Batch, which reserves k slots at a
+// time and serves Allocs from that chunk without touching the counter.
+//
+// Len reports reserved slots, including the unused tail of open chunks;
+// Committed reports slots actually holding values. Reset and Drain must not
+// run concurrently with Batch.Alloc or Batch.Flush.
+type BatchedArena[T any] struct {
+	arena *AtomicArena[T]
+	k     uintptr
+
+	mu      sync.Mutex
+	batches map[*Batch[T]]struct{}
+	retired uintptr // values served by closed batches since the last Reset
+}
+
+// Batch is one goroutine's handle on a BatchedArena. A Batch must not be
+// used by more than one goroutine at a time.
+type Batch[T any] struct {
+	b      *BatchedArena[T]
+	seg    []T            // current chunk
+	base   uintptr        // slot index of seg[0]
+	next   uintptr        // next unused index in seg
+	epoch  uint64         // arena epoch the chunk was reserved in
+	served atomic.Uintptr // values stored since the last Reset
+}
+
+// NewBatchedArena creates a BatchedArena of maxElems slots whose batches
+// reserve k slots at a time. k is clamped to at least 1.
+func NewBatchedArena[T any](maxElems, k uintptr, opts ...Option) *BatchedArena[T] {
+	return &BatchedArena[T]{
+		arena:   NewAtomicArena[T](maxElems, opts...),
+		k:       max(k, 1),
+		batches: make(map[*Batch[T]]struct{}),
+	}
+}
+
+// NewBatch returns a new handle for the calling goroutine.
+func (b *BatchedArena[T]) NewBatch() *Batch[T] {
+	c := &Batch[T]{b: b}
+	b.mu.Lock()
+	b.batches[c] = struct{}{}
+	b.mu.Unlock()
+	return c
+}
+
+// Alloc stores obj in the batch's chunk, reserving a new chunk from the
+// shared arena when the current one is used up. Near the end of the arena a
+// short chunk covering the remaining slots is reserved instead.
+func (c *Batch[T]) Alloc(obj T) (*T, error) {
+	a := c.b.arena
+	if c.next == uintptr(len(c.seg)) || c.epoch != a.Epoch() {
+		if err := c.refill(); err != nil {
+			return nil, err
+		}
+	}
+	p := &c.seg[c.next]
+	*p = obj
+	a.publish(c.base+c.next, c.base+c.next+1)
+	a.published(1)
+	c.next++
+	c.served.Add(1)
+	return p, nil
+}
+
+func (c *Batch[T]) refill() error {
+	a := c.b.arena
+	c.Flush()
+	epoch := a.Epoch()
+	base, seg, err := a.ReserveIndexed(c.b.k)
+	if errors.Is(err, ErrArenaFull) {
+		if rest := a.Cap() - a.Len(); rest > 0 && rest < c.b.k {
+			base, seg, err = a.ReserveIndexed(rest)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	c.seg, c.base, c.next, c.epoch = seg, base, 0, epoch
+	return nil
+}
+
+// Flush returns the unused tail of the batch's chunk. If the chunk is still
+// the last reservation in the arena the slots are handed back to the shared
+// counter; otherwise they are tombstoned so Get, Range and Drain skip them.
+func (c *Batch[T]) Flush() {
+	a := c.b.arena
+	end := c.base + uintptr(len(c.seg))
+	unused := uintptr(len(c.seg)) - c.next
+	if unused > 0 && c.epoch == a.Epoch() {
+		if a.count.CompareAndSwap(end, end-unused) {
+			a.done.Add(^unused + 1)
+			a.dropUnpublished(unused, end-unused)
+		} else {
+			// the unused slots never held values, so no destructor runs
+			for i := c.base + c.next; i < end; i++ {
+				a.markDead(i)
+			}
+			// publish the dead zero values so Committed can pass them
+			a.publish(c.base+c.next, end)
+			a.published(unused)
+		}
+	}
+	c.seg, c.next = nil, 0
+}
+
+// Close flushes the batch and detaches it from the arena. Its values stay
+// committed; the batch must not be used afterwards.
+func (c *Batch[T]) Close() {
+	c.Flush()
+	c.b.mu.Lock()
+	delete(c.b.batches, c)
+	c.b.retired += c.served.Load()
+	c.b.mu.Unlock()
+}
+
+// Len returns the number of reserved slots, including unused chunk tails.
+func (b *BatchedArena[T]) Len() uintptr {
+	return b.arena.Len()
+}
+
+// Cap returns the maximum number of elements the arena can hold.
+func (b *BatchedArena[T]) Cap() uintptr {
+	return b.arena.Cap()
+}
+
+// Committed returns the number of values stored by all batches.
+func (b *BatchedArena[T]) Committed() uintptr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := b.retired
+	for c := range b.batches {
+		n += c.served.Load()
+	}
+	return n
+}
+
+// Get returns a pointer to the element at index i. Slots in an unflushed
+// chunk that have not been served yet read as zero values.
+func (b *BatchedArena[T]) Get(i uintptr) (*T, bool) {
+	return b.arena.Get(i)
+}
+
+// Reset flushes every batch and rewinds the arena, zeroing it if release is set.
+func (b *BatchedArena[T]) Reset(release bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.resetLocked(release)
+}
+
+func (b *BatchedArena[T]) resetLocked(release bool) error {
+	for c := range b.batches {
+		c.Flush()
+	}
+	// destructor errors are reported after the arena has been reset
+	err := b.arena.Reset(release)
+	if errors.Is(err, ErrFrozen) || errors.Is(err, ErrClosed) {
+		return err
+	}
+	for c := range b.batches {
+		c.served.Store(0)
+	}
+	b.retired = 0
+	return err
+}
+
+// Drain flushes every batch, returns a copy of all committed values in slot
+// order and resets the arena.
+func (b *BatchedArena[T]) Drain() ([]T, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.arena.opts.tracing && trace.IsEnabled() {
+		defer trace.StartRegion(context.Background(), regionDrain).End()
+	}
+	for c := range b.batches {
+		c.Flush()
+	}
+	out := b.arena.Snapshot()
+	if ops := b.arena.ops; ops != nil {
+		ops.record(OpDrain, 0, uintptr(len(out)), false)
+	}
+	if err := b.resetLocked(false); err != nil {
+		if errors.Is(err, ErrFrozen) || errors.Is(err, ErrClosed) {
+			return nil, err
+		}
+		return out, err
+	}
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package atomicarena
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestBatchedNoDoubleServe ensures concurrent batches never hand out the same slot
+func TestBatchedNoDoubleServe(t *testing.T) {
+	const workers, per = 8, 1000
+	b := NewBatchedArena[int](workers*per+workers*16, 16)
+	ptrs := make([][]*int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := b.NewBatch()
+			for i := 0; i < per; i++ {
+				p, err := c.Alloc(w*per + i)
+				if err != nil {
+					t.Errorf("Alloc failed: %v", err)
+					return
+				}
+				ptrs[w] = append(ptrs[w], p)
+			}
+		}()
+	}
+	wg.Wait()
+	seen := make(map[*int]bool)
+	for w := range ptrs {
+		for i, p := range ptrs[w] {
+			if seen[p] {
+				t.Fatalf("slot served twice")
+			}
+			seen[p] = true
+			if *p != w*per+i {
+				t.Fatalf("value overwritten: got %d, want %d", *p, w*per+i)
+			}
+		}
+	}
+	if c := b.Committed(); c != workers*per {
+		t.Fatalf("expected %d committed, got %d", workers*per, c)
+	}
+	if b.Len() < b.Committed() {
+		t.Fatalf("reserved %d below committed %d", b.Len(), b.Committed())
+	}
+}
+
+// TestBatchedDrain ensures Drain returns every committed value and nothing else
+func TestBatchedDrain(t *testing.T) {
+	b := NewBatchedArena[int](10_000, 64)
+	const workers, per = 4, 250
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := b.NewBatch()
+			defer c.Close()
+			for i := 0; i < per; i++ {
+				if _, err := c.Alloc(w*per + i + 1); err != nil {
+					t.Errorf("Alloc failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if c := b.Committed(); c != workers*per {
+		t.Fatalf("expected %d committed after Close, got %d", workers*per, c)
+	}
+	got, err := b.Drain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	if len(got) != workers*per {
+		t.Fatalf("expected %d values, got %d", workers*per, len(got))
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Fatalf("missing or extra value at %d: %d", i, v)
+		}
+	}
+	if b.Len() != 0 || b.Committed() != 0 {
+		t.Fatalf("expected empty arena after Drain, len %d committed %d", b.Len(), b.Committed())
+	}
+}
+
+// TestBatchedFlush covers tail give-back and tombstoning of unused slots
+func TestBatchedFlush(t *testing.T) {
+	b := NewBatchedArena[int](100, 10)
+	x, y := b.NewBatch(), b.NewBatch()
+	x.Alloc(1)
+	y.Alloc(2)
+	if b.Len() != 20 {
+		t.Fatalf("expected 20 reserved, got %d", b.Len())
+	}
+	// y's chunk is last, so its tail goes back to the counter
+	y.Flush()
+	if b.Len() != 11 {
+		t.Fatalf("expected 11 reserved after tail flush, got %d", b.Len())
+	}
+	// x's chunk is not, so its tail is tombstoned
+	x.Flush()
+	if b.Len() != 11 || b.arena.Alive(5) {
+		t.Fatalf("expected x's tail to be tombstoned, len %d", b.Len())
+	}
+	if got := b.arena.Snapshot(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("unexpected contents %v", got)
+	}
+	// a chunk reserved before Reset is not reused after it
+	x.Alloc(3)
+	if err := b.Reset(false); err != nil {
+		t.Fatal(err)
+	}
+	x.Alloc(4)
+	if p, _ := b.Get(0); *p != 4 || b.Committed() != 1 {
+		t.Fatalf("expected a fresh chunk after Reset")
+	}
+}
+
+// TestBatchedShortChunk ensures the last slots are served when fewer than k remain
+func TestBatchedShortChunk(t *testing.T) {
+	b := NewBatchedArena[int](25, 10)
+	c := b.NewBatch()
+	for i := 0; i < 25; i++ {
+		if _, err := c.Alloc(i); err != nil {
+			t.Fatalf("Alloc %d failed: %v", i, err)
+		}
+	}
+	if _, err := c.Alloc(25); err == nil {
+		t.Fatal("expected a full arena")
+	}
+}
+
+// BenchmarkBatchedParallel compares batched and direct allocation across goroutines
+func BenchmarkBatchedParallel(b *testing.B) {
+	b.Run("AtomicArena", func(b *testing.B) {
+		a := NewAtomicArena[int](uintptr(b.N))
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				a.Alloc(1)
+			}
+		})
+	})
+	b.Run("BatchedArena", func(b *testing.B) {
+		a := NewBatchedArena[int](uintptr(b.N)+1<<16, 256)
+		b.RunParallel(func(pb *testing.PB) {
+			c := a.NewBatch()
+			defer c.Close()
+			for pb.Next() {
+				c.Alloc(1)
+			}
+		})
+	})
+}
@@ -0,0 +1,191 @@
+package atomicarena
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// binaryChunk is the number of bytes ReadBinaryInto and WriteBinaryFrom
+// move at a time.
+const binaryChunk = 64 << 10
+
+// ErrPartialRecord is returned by ReadBinaryInto when the input ends in the
+// middle of a record.
+var ErrPartialRecord = errors.New("atomicarena: input ends in a partial record")
+
+// ReadBinaryInto reads fixed-size records from r until EOF, decoding them in
+// order with the encoding/binary rules and storing them in a. T must have a
+// fixed binary size (see binary.Size). Records are read straight into
+// reserved segments about 64 KiB at a time, and each segment counts as
+// written once it has been filled. When T's memory layout is exactly its
+// binary encoding and order is the native byte order, the bytes are copied
+// into the segment as they are; otherwise each chunk is decoded with
+// binary.Read. It returns the number of records stored. If the arena fills
+// first it returns a *CapacityError wrapping ErrArenaFull; if r ends inside
+// a record it returns ErrPartialRecord; other errors from r are returned as
+// they are. The records stored before an error stay in the arena. Once the
+// arena is full one more byte is read, to tell input that ended exactly at
+// the capacity from input that did not fit.
+func ReadBinaryInto[T any](r io.Reader, order binary.ByteOrder, a *AtomicArena[T]) (int, error) {
+	size, err := binarySize[T]()
+	if err != nil {
+		return 0, err
+	}
+	direct := rawBinary[T](order)
+	var buf []byte
+	if !direct {
+		buf = make([]byte, max(binaryChunk/size, 1)*size)
+	}
+	total := 0
+	for {
+		start, k, err := a.reserveUpTo(uintptr(max(binaryChunk/size, 1)))
+		if err != nil {
+			if errors.Is(err, ErrArenaFull) && probeEOF(r) {
+				// the input ended exactly at the capacity
+				return total, nil
+			}
+			return total, a.allocErr(err, start, 1)
+		}
+		seg := a.raw[start : start+k : start+k]
+		var dst []byte
+		if direct {
+			dst = elemBytes(seg, k)
+		} else {
+			dst = buf[:int(k)*size]
+		}
+		m, rerr := io.ReadFull(r, dst)
+		used := uintptr(m / size)
+		if !direct && used > 0 {
+			// cannot fail: the bytes are all there and T has a fixed size
+			binary.Read(bytes.NewReader(dst[:int(used)*size]), order, seg[:used])
+		}
+		if direct && m%size != 0 {
+			// the partial record must not be handed out by a later Reserve
+			clear(seg[used : used+1])
+		}
+		a.publish(start, start+used)
+		a.commitPartial(start, k, used)
+		total += int(used)
+		switch {
+		case rerr == nil:
+			continue
+		case m%size != 0:
+			return total, fmt.Errorf("%w: %d of %d bytes", ErrPartialRecord, m%size, size)
+		case rerr == io.EOF || rerr == io.ErrUnexpectedEOF:
+			return total, nil
+		default:
+			return total, rerr
+		}
+	}
+}
+
+// WriteBinaryFrom writes the committed elements of a to w as fixed-size
+// records in the given byte order, skipping tombstoned slots, and returns how
+// many it wrote. It is the inverse of ReadBinaryInto and uses the same fast
+// path for types whose memory layout is their binary encoding. Elements are
+// written in chunks of about 64 KiB. Like Dump, it returns ErrNotQuiescent if
+// writes stay in flight on an arena without a pointer mirror.
+func WriteBinaryFrom[T any](w io.Writer, order binary.ByteOrder, a *AtomicArena[T]) (int, error) {
+	size, err := binarySize[T]()
+	if err != nil {
+		return 0, err
+	}
+	n, ok := a.committedPrefix(^uintptr(0))
+	if !ok {
+		return 0, ErrNotQuiescent
+	}
+	direct := rawBinary[T](order)
+	chunk := uintptr(max(binaryChunk/size, 1))
+	var buf bytes.Buffer
+	written := 0
+	for i := uintptr(0); i < n; {
+		if a.tombstoned(i) {
+			i++
+			continue
+		}
+		// a run of live slots, at most one chunk long
+		j := i + 1
+		for j < n && j-i < chunk && !a.tombstoned(j) {
+			j++
+		}
+		seg := a.raw[i:j]
+		if direct {
+			_, err = w.Write(elemBytes(seg, j-i))
+		} else {
+			buf.Reset()
+			binary.Write(&buf, order, seg)
+			_, err = w.Write(buf.Bytes())
+		}
+		if err != nil {
+			return written, err
+		}
+		written += int(j - i)
+		i = j
+	}
+	return written, nil
+}
+
+// binarySize returns the encoded size of a T, or an error if T has no fixed
+// binary encoding.
+func binarySize[T any]() (int, error) {
+	var zero T
+	size := binary.Size(zero)
+	if size <= 0 {
+		return 0, fmt.Errorf("atomicarena: %s has no fixed-size binary encoding", reflect.TypeFor[T]())
+	}
+	return size, nil
+}
+
+// rawBinary reports whether T's bytes in memory are exactly its binary
+// encoding in order: order is native, T has no padding and every field is a
+// fixed-size number.
+func rawBinary[T any](order binary.ByteOrder) bool {
+	var probe [2]byte
+	binary.NativeEndian.PutUint16(probe[:], 1)
+	if order.Uint16(probe[:]) != 1 {
+		return false
+	}
+	var zero T
+	return binary.Size(zero) == int(unsafe.Sizeof(zero)) && rawNumbers(reflect.TypeFor[T]())
+}
+
+// rawNumbers reports whether t is built only from fixed-size numbers, so any
+// bit pattern is a valid value. Bools and blank fields are left to
+// encoding/binary, which normalizes and skips them.
+func rawNumbers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return rawNumbers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.Name == "_" || !rawNumbers(f.Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// probeEOF reports whether r has no more data, consuming at most one byte.
+func probeEOF(r io.Reader) bool {
+	var b [1]byte
+	for {
+		n, err := r.Read(b[:])
+		if n > 0 {
+			return false
+		}
+		if err != nil {
+			return err == io.EOF
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package atomicarena
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"testing/iotest"
+)
+
+// packedRecord has no padding and only numeric fields, so it takes the
+// direct copy path in native byte order.
+type packedRecord struct {
+	ID    uint32
+	Flags uint16
+	Kind  int16
+	Value float64
+}
+
+// paddedRecord has padding after ID, so it is always decoded field by field.
+type paddedRecord struct {
+	ID    uint32
+	Value float64
+	Ok    bool
+}
+
+func packedRecords(n int) []packedRecord {
+	out := make([]packedRecord, n)
+	for i := range out {
+		out[i] = packedRecord{ID: uint32(i), Flags: uint16(i * 3), Kind: int16(-i), Value: float64(i) / 4}
+	}
+	return out
+}
+
+// TestRawBinary checks which types and orders take the direct copy path
+func TestRawBinary(t *testing.T) {
+	other := nonNative()
+	for name, got := range map[string]bool{
+		"packed native":   rawBinary[packedRecord](binary.NativeEndian),
+		"[4]int32 native": rawBinary[[4]int32](binary.NativeEndian),
+		"packed other":    !rawBinary[packedRecord](other),
+		"padded native":   !rawBinary[paddedRecord](binary.NativeEndian),
+		"bool native":     !rawBinary[bool](binary.NativeEndian),
+	} {
+		if !got {
+			t.Errorf("%s: unexpected rawBinary result", name)
+		}
+	}
+}
+
+// TestBinaryRoundTrip writes and reads records in both byte orders, through
+// the direct path and the binary.Read fallback
+func TestBinaryRoundTrip(t *testing.T) {
+	want := packedRecords(20000) // several chunks
+	padded := []paddedRecord{{1, 1.5, true}, {2, -2, false}, {3, 0, true}}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		src := NewAtomicArena[packedRecord](uintptr(len(want)))
+		src.AppendSlice(want)
+		var buf bytes.Buffer
+		if n, err := WriteBinaryFrom(&buf, order, src); err != nil || n != len(want) {
+			t.Fatalf("%v: expected %d records written, got %d, %v", order, len(want), n, err)
+		}
+		var std bytes.Buffer
+		binary.Write(&std, order, want)
+		if !bytes.Equal(buf.Bytes(), std.Bytes()) {
+			t.Fatalf("%v: expected the encoding/binary encoding", order)
+		}
+		dst := NewAtomicArena[packedRecord](uintptr(len(want)) + 1)
+		// one byte at a time exercises short reads inside a chunk
+		n, err := ReadBinaryInto(iotest.OneByteReader(&buf), order, dst)
+		if err != nil || n != len(want) || !slices.Equal(dst.Snapshot(), want) {
+			t.Fatalf("%v: expected %d records back, got %d, %v", order, len(want), n, err)
+		}
+		if dst.Committed() != uintptr(n) || dst.LoadPointer(uintptr(n-1)) == nil {
+			t.Fatalf("%v: expected the records committed and published", order)
+		}
+
+		p := NewAtomicArena[paddedRecord](4)
+		p.AppendSlice(padded)
+		buf.Reset()
+		if n, err := WriteBinaryFrom(&buf, order, p); err != nil || n != 3 || buf.Len() != 3*13 {
+			t.Fatalf("%v: expected 39 bytes for 3 padded records, got %d bytes, %v", order, buf.Len(), err)
+		}
+		q := NewAtomicArena[paddedRecord](4)
+		if n, err := ReadBinaryInto(&buf, order, q); err != nil || n != 3 || !slices.Equal(q.Snapshot(), padded) {
+			t.Fatalf("%v: expected the padded records back, got %v, %v", order, q.Snapshot(), err)
+		}
+	}
+}
+
+// TestBinaryEndianness pins the byte layout of a record in both orders
+func TestBinaryEndianness(t *testing.T) {
+	a := NewAtomicArena[[2]uint16](1)
+	a.Alloc([2]uint16{0x0102, 0x0304})
+	for order, want := range map[binary.ByteOrder][]byte{
+		binary.BigEndian:    {1, 2, 3, 4},
+		binary.LittleEndian: {2, 1, 4, 3},
+	} {
+		var buf bytes.Buffer
+		WriteBinaryFrom(&buf, order, a)
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("%v: expected % x, got % x", order, want, buf.Bytes())
+		}
+		b := NewAtomicArena[[2]uint16](1)
+		if _, err := ReadBinaryInto(bytes.NewReader(want), order, b); err != nil {
+			t.Fatal(err)
+		}
+		if p, _ := b.Get(0); *p != [2]uint16{0x0102, 0x0304} {
+			t.Fatalf("%v: expected 0102 0304, got %04x", order, *p)
+		}
+	}
+}
+
+// TestReadBinaryIntoShort reports the records loaded before a trailing
+// partial record, a full arena or a failing reader
+func TestReadBinaryIntoShort(t *testing.T) {
+	recs := packedRecords(5)
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.NativeEndian, recs)
+	data := buf.Bytes()
+
+	for _, order := range []binary.ByteOrder{binary.NativeEndian, binary.BigEndian} {
+		a := NewAtomicArena[packedRecord](8)
+		n, err := ReadBinaryInto(bytes.NewReader(data[:len(data)-3]), order, a)
+		if !errors.Is(err, ErrPartialRecord) || n != 4 || a.Len() != 4 {
+			t.Fatalf("%v: expected 4 records and ErrPartialRecord, got %d, %v, len %d", order, n, err, a.Len())
+		}
+		// the slot that received the partial record was cleared and given back
+		if seg, _ := a.ReserveZeroed(1); seg[0] != (packedRecord{}) {
+			t.Fatalf("%v: expected the partial record to be cleared, got %+v", order, seg[0])
+		}
+	}
+
+	full := NewAtomicArena[packedRecord](3)
+	n, err := ReadBinaryInto(bytes.NewReader(data), binary.NativeEndian, full)
+	if ce := capacityErr(t, err); ce.Available != 0 || n != 3 {
+		t.Fatalf("expected 3 records and a full arena, got %d, %+v", n, *ce)
+	}
+	exact := NewAtomicArena[packedRecord](5)
+	if n, err := ReadBinaryInto(bytes.NewReader(data), binary.NativeEndian, exact); err != nil || n != 5 {
+		t.Fatalf("expected input that exactly fills the arena to succeed, got %d, %v", n, err)
+	}
+
+	boom := errors.New("boom")
+	r := io.MultiReader(bytes.NewReader(data[:2*binary.Size(packedRecord{})]), iotest.ErrReader(boom))
+	b := NewAtomicArena[packedRecord](8)
+	if n, err := ReadBinaryInto(r, binary.NativeEndian, b); !errors.Is(err, boom) || n != 2 {
+		t.Fatalf("expected 2 records and the reader's error, got %d, %v", n, err)
+	}
+	if _, err := ReadBinaryInto(bytes.NewReader(data), binary.NativeEndian, NewAtomicArena[int](4)); err == nil {
+		t.Fatalf("expected int to be refused for lacking a fixed binary size")
+	}
+}
+
+// TestWriteBinaryFromTombstones skips tombstoned slots
+func TestWriteBinaryFromTombstones(t *testing.T) {
+	a := NewAtomicArena[packedRecord](8)
+	a.AppendSlice(packedRecords(5))
+	a.Tombstone(1)
+	a.Tombstone(3)
+	var buf bytes.Buffer
+	if n, err := WriteBinaryFrom(&buf, binary.NativeEndian, a); err != nil || n != 3 {
+		t.Fatalf("expected 3 live records, got %d, %v", n, err)
+	}
+	b := NewAtomicArena[packedRecord](8)
+	ReadBinaryInto(&buf, binary.NativeEndian, b)
+	if got := b.Snapshot(); len(got) != 3 || got[1].ID != 2 || got[2].ID != 4 {
+		t.Fatalf("expected records 0, 2 and 4, got %+v", got)
+	}
+}
+
+// BenchmarkReadBinaryInto loads a million records through the direct path
+// and through binary.Read
+func BenchmarkReadBinaryInto(b *testing.B) {
+	const n = 1 << 20
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.NativeEndian, packedRecords(n))
+	data := buf.Bytes()
+	a := NewAtomicArena[packedRecord](n)
+	for name, order := range map[string]binary.ByteOrder{"direct": binary.NativeEndian, "decode": nonNative()} {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				a.Reset(false)
+				if _, err := ReadBinaryInto(bytes.NewReader(data), order, a); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// nonNative returns the byte order that is not the platform's.
+func nonNative() binary.ByteOrder {
+	if binary.NativeEndian.Uint16([]byte{0, 1}) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
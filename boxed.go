@@ -0,0 +1,37 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNotImplemented is returned by AllocAs when a pointer to the arena's
+// element type does not implement the requested interface.
+var ErrNotImplemented = errors.New("atomicarena: element type does not implement interface")
+
+// AllocAs stores v in a and returns the stored element as the interface I.
+// An arena of interface values, such as NewAtomicArena[io.Reader], only holds
+// the interface headers: every value converted into one is still boxed on the
+// heap. AllocAs keeps the value itself in the arena instead and builds the
+// interface around a pointer to it, which needs no allocation, so I's
+// methods run on the arena-resident element:
+//
+//	buffers := atomicarena.NewAtomicArena[bytes.Buffer](n)
+//	w, err := atomicarena.AllocAs[io.Writer](buffers, bytes.Buffer{})
+//
+// *T must implement I, with pointer or value receivers; otherwise AllocAs
+// returns ErrNotImplemented without reserving a slot. The interface is valid
+// for as long as the pointer Alloc would have returned, so it must not be
+// used once the arena is reset. Other errors are those of Alloc.
+func AllocAs[I, T any](a *AtomicArena[T], v T) (I, error) {
+	var zero I
+	if _, ok := any((*T)(nil)).(I); !ok {
+		return zero, fmt.Errorf("%w: %v does not implement %v", ErrNotImplemented, reflect.TypeFor[*T](), reflect.TypeFor[I]())
+	}
+	p, err := a.Alloc(v)
+	if err != nil {
+		return zero, err
+	}
+	return any(p).(I), nil
+}
@@ -0,0 +1,48 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// hitCounter is a pointer-receiver fmt.Stringer that counts its calls.
+type hitCounter struct{ hits int }
+
+func (c *hitCounter) String() string {
+	c.hits++
+	return fmt.Sprintf("hit %d", c.hits)
+}
+
+// TestAllocAsDispatch calls methods through the interface on the element in the arena
+func TestAllocAsDispatch(t *testing.T) {
+	a := NewAtomicArena[hitCounter](2)
+	s, err := AllocAs[fmt.Stringer](a, hitCounter{})
+	if err != nil {
+		t.Fatalf("AllocAs failed: %v", err)
+	}
+	p, _ := a.Get(0)
+	if s.(*hitCounter) != p {
+		t.Fatalf("expected the interface to point at slot 0")
+	}
+	if got := s.String(); got != "hit 1" || p.hits != 1 {
+		t.Fatalf("expected the method to update the arena element, got %q and %d hits", got, p.hits)
+	}
+	if _, err := AllocAs[fmt.Stringer](a, hitCounter{}); err != nil {
+		t.Fatalf("AllocAs failed: %v", err)
+	}
+	if _, err := AllocAs[fmt.Stringer](a, hitCounter{}); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+}
+
+// TestAllocAsNotImplemented rejects an interface *T lacks without using a slot
+func TestAllocAsNotImplemented(t *testing.T) {
+	a := NewAtomicArena[int](1)
+	if _, err := AllocAs[fmt.Stringer](a, 1); !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("expected ErrNotImplemented, got %v", err)
+	}
+	if a.Len() != 0 {
+		t.Fatalf("expected no slot to be used, got %d", a.Len())
+	}
+}
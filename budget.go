@@ -0,0 +1,74 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrBudgetExceeded is returned when creating an arena would exceed its Budget.
+var ErrBudgetExceeded = errors.New("atomicarena: memory budget exceeded")
+
+// Budget caps the total element storage of the arenas created against it.
+// Reservations are made with a CAS loop, so concurrent constructions can never
+// push the total past the maximum.
+type Budget struct {
+	max  uintptr
+	used atomic.Uintptr
+}
+
+// NewBudget creates a Budget allowing up to maxBytes of element storage.
+func NewBudget(maxBytes uintptr) *Budget {
+	return &Budget{max: maxBytes}
+}
+
+// Used returns the number of bytes currently reserved.
+func (b *Budget) Used() uintptr {
+	return b.used.Load()
+}
+
+// Remaining returns the number of bytes still available.
+func (b *Budget) Remaining() uintptr {
+	return b.max - b.used.Load()
+}
+
+// reserve claims n bytes from the budget.
+func (b *Budget) reserve(n uintptr) error {
+	for {
+		used := b.used.Load()
+		if n > b.max-used {
+			return fmt.Errorf("%w: need %d bytes, %d of %d remaining", ErrBudgetExceeded, n, b.max-used, b.max)
+		}
+		if b.used.CompareAndSwap(used, used+n) {
+			return nil
+		}
+	}
+}
+
+// release returns n bytes to the budget.
+func (b *Budget) release(n uintptr) {
+	b.used.Add(^n + 1)
+}
+
+// NewAtomicArenaWithBudget creates an arena of n elements after reserving
+// n*sizeof(T) bytes from b. The reservation is returned to b by Close, or
+// at once if New rejects the options or the storage cannot be allocated.
+func NewAtomicArenaWithBudget[T any](n uintptr, b *Budget, opts ...Option) (*AtomicArena[T], error) {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if size != 0 && n > ^uintptr(0)/size {
+		return nil, fmt.Errorf("%w: %d elements of %d bytes overflow", ErrBudgetExceeded, n, size)
+	}
+	if err := b.reserve(n * size); err != nil {
+		return nil, err
+	}
+	a, err := New[T](n, opts...)
+	if err != nil {
+		b.release(n * size)
+		return nil, err
+	}
+	a.budget = b
+	a.budgetBytes = n * size
+	return a, nil
+}
@@ -0,0 +1,79 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestBudget covers reservation, exhaustion and release on Close
+func TestBudget(t *testing.T) {
+	b := NewBudget(100)
+	a, err := NewAtomicArenaWithBudget[int64](10, b)
+	if err != nil {
+		t.Fatalf("first arena failed: %v", err)
+	}
+	if b.Used() != 80 || b.Remaining() != 20 {
+		t.Fatalf("unexpected usage %d/%d", b.Used(), b.Remaining())
+	}
+	if _, err := NewAtomicArenaWithBudget[int64](3, b); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	_ = a.Close()
+	_ = a.Close() // idempotent
+	if b.Used() != 0 {
+		t.Fatalf("expected budget released, used %d", b.Used())
+	}
+	if _, err := NewAtomicArenaWithBudget[int64](^uintptr(0)/4, b); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected overflow to exceed budget, got %v", err)
+	}
+	if _, err := NewAtomicArenaWithBudget[int64](10, b, WithLocked()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions, got %v", err)
+	}
+	if b.Used() != 0 {
+		t.Fatalf("expected a failed construction to release its bytes, used %d", b.Used())
+	}
+}
+
+// TestBudgetConcurrent races constructions that together exceed the budget
+func TestBudgetConcurrent(t *testing.T) {
+	const (
+		maxBytes = 64 * 8 * 10
+		workers  = 50
+	)
+	b := NewBudget(maxBytes)
+	var wg sync.WaitGroup
+	var created atomic.Int64
+	var peak atomic.Uintptr
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				a, err := NewAtomicArenaWithBudget[int64](64, b)
+				if used := b.Used(); used > peak.Load() {
+					peak.Store(used)
+				}
+				if err != nil {
+					continue
+				}
+				created.Add(1)
+				if b.Used() > maxBytes {
+					t.Errorf("budget overcommitted: %d", b.Used())
+				}
+				_ = a.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > maxBytes {
+		t.Fatalf("peak usage %d exceeds %d", peak.Load(), maxBytes)
+	}
+	if b.Used() != 0 {
+		t.Fatalf("expected all reservations released, used %d", b.Used())
+	}
+	if created.Load() == 0 {
+		t.Fatal("no arena was ever created")
+	}
+}
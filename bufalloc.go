@@ -0,0 +1,39 @@
+package atomicarena
+
+// BufAllocator is the scratch-buffer provider that serialization libraries
+// and message builders can be handed instead of allocating from the heap.
+// ByteArena implements it. Buffers returned by AllocBytes belong to the
+// allocator: they stay valid until the next Reset, and must not be used,
+// retained or handed out after it.
+type BufAllocator interface {
+	AllocBytes(n int) ([]byte, error)
+	Reset()
+}
+
+var _ BufAllocator = (*ByteArena)(nil)
+
+// GrowerFunc returns a grow callback for libraries that enlarge their buffer
+// through a func(buf []byte, n int) []byte hook. The callback returns buf
+// itself if it already has room for n more bytes. Otherwise it allocates a
+// buffer of at least twice buf's capacity from the arena, copies buf into
+// it and returns it with buf's length. The old buffer is left in the arena
+// until Reset. If the arena cannot hold the larger buffer, the callback
+// falls back to growing on the heap, so the builder keeps working; such a
+// buffer outlives Reset like any heap memory. Buffers from the arena die at
+// Reset, so the builder's output must be consumed or copied before then.
+func (b *ByteArena) GrowerFunc() func(buf []byte, n int) []byte {
+	return func(buf []byte, n int) []byte {
+		if n < 0 {
+			panic(ErrNegativeSize)
+		}
+		if cap(buf)-len(buf) >= n {
+			return buf
+		}
+		size := max(2*cap(buf), len(buf)+n)
+		nb, err := b.AllocBytes(size)
+		if err != nil {
+			nb = make([]byte, size)
+		}
+		return nb[:copy(nb, buf)]
+	}
+}
@@ -0,0 +1,82 @@
+package atomicarena
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// msgBuilder mimics a flatbuffers-style builder: it appends fields to one
+// buffer and grows it only through the pluggable callback.
+type msgBuilder struct {
+	buf  []byte
+	grow func([]byte, int) []byte
+}
+
+func (m *msgBuilder) reserve(n int) []byte {
+	m.buf = m.grow(m.buf, n)
+	m.buf = m.buf[:len(m.buf)+n]
+	return m.buf[len(m.buf)-n:]
+}
+
+func (m *msgBuilder) uint32(v uint32) {
+	binary.LittleEndian.PutUint32(m.reserve(4), v)
+}
+
+func (m *msgBuilder) string(s string) {
+	m.uint32(uint32(len(s)))
+	copy(m.reserve(len(s)), s)
+}
+
+// buildMessage writes a small message with a repeated field.
+func buildMessage(m *msgBuilder) []byte {
+	m.buf = m.buf[:0]
+	m.uint32(0xCAFE)
+	for i := 0; i < 16; i++ {
+		m.string("telemetry-point")
+		m.uint32(uint32(i))
+	}
+	return m.buf
+}
+
+// TestGrowerFuncZeroAllocs builds messages with arena memory only
+func TestGrowerFuncZeroAllocs(t *testing.T) {
+	var _ BufAllocator = NewByteArena(0)
+	b := NewByteArena(4096)
+	m := &msgBuilder{grow: b.GrowerFunc()}
+	want := append([]byte(nil), buildMessage(m)...)
+	if len(want) != 4+16*(4+15+4) {
+		t.Fatalf("unexpected message length %d", len(want))
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Reset()
+		m.buf = nil
+		if got := buildMessage(m); string(got) != string(want) {
+			t.Fatalf("expected the same message on every run")
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected 0 heap allocations per message, got %v", allocs)
+	}
+	// the final buffer lives in the arena
+	if _, ok := b.arena.indexOf(&m.buf[0]); !ok {
+		t.Fatalf("expected the message to be stored in the arena")
+	}
+}
+
+// TestGrowerFuncFallback keeps growing on the heap once the arena is full
+func TestGrowerFuncFallback(t *testing.T) {
+	b := NewByteArena(64)
+	grow := b.GrowerFunc()
+	buf := grow(nil, 40)
+	if cap(buf) != 40 || len(buf) != 0 || b.Len() != 40 {
+		t.Fatalf("expected a 40-byte arena buffer, got cap %d, arena len %d", cap(buf), b.Len())
+	}
+	buf = append(buf, "0123456789"...)
+	if same := grow(buf, 30); &same[:1][0] != &buf[:1][0] {
+		t.Fatalf("expected a buffer with room to be returned as is")
+	}
+	big := grow(buf, 31)
+	if string(big) != "0123456789" || cap(big) < 80 || b.Len() != 40 {
+		t.Fatalf("expected a heap buffer holding the contents, got %q, cap %d, arena len %d", big, cap(big), b.Len())
+	}
+}
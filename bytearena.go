@@ -0,0 +1,161 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+)
+
+var (
+	// ErrNegativeSize is returned when a byte count passed to ByteArena is negative.
+	ErrNegativeSize = errors.New("atomicarena: negative size")
+	// ErrBadAlignment is returned for an alignment that is not a positive power of two.
+	ErrBadAlignment = errors.New("atomicarena: alignment must be a power of two")
+)
+
+// ByteArena is a lock-free bump allocator for variable-length byte buffers,
+// built on an AtomicArena[byte] without a pointer mirror. Buffers are
+// returned with their capacity clipped, so appending to one never writes
+// into its neighbour. The storage starts on a page boundary, so alignments
+// requested from AllocBytesAligned hold for absolute addresses. Every buffer
+// dies at Reset.
+type ByteArena struct {
+	arena   *AtomicArena[byte]
+	padding atomic.Uintptr // bytes skipped to align allocations since the last Reset
+	sizes   *sizeHistogram // request sizes, set by WithSizeHistogram
+}
+
+// NewByteArena creates a ByteArena holding up to size bytes, configured by
+// opts. The pointer mirror is always disabled. Like NewAtomicArena it panics
+// if the options are invalid.
+func NewByteArena(size uintptr, opts ...Option) *ByteArena {
+	o := buildOptions(opts)
+	if err := o.validate(false); err != nil {
+		panic(err)
+	}
+	o.noMirror = true
+	page := uintptr(pageSize)
+	buf := make([]byte, size+page)
+	off := (page - uintptr(unsafe.Pointer(unsafe.SliceData(buf)))%page) % page
+	raw := buf[off : off+size : off+size]
+	return &ByteArena{arena: newAtomicArena[byte](raw, nil, o), sizes: newSizeHistogram(o.sizeBuckets)}
+}
+
+// AllocBytes returns a buffer of n bytes from the arena, or a *CapacityError
+// wrapping ErrArenaFull. Storage used for the first time reads as zero, but
+// Reset keeps the old contents, so after one a buffer holds the bytes of
+// earlier buffers unless the arena was built WithZeroOnReserve or
+// WithZeroOnAlloc.
+func (b *ByteArena) AllocBytes(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, ErrNegativeSize
+	}
+	if b.sizes != nil {
+		b.sizes.record(n)
+	}
+	seg, err := b.arena.Reserve(uintptr(n))
+	if err != nil {
+		return nil, err
+	}
+	return seg[:n:n], nil
+}
+
+// AllocBytesAligned returns a buffer of n bytes whose address is a multiple
+// of align, which must be a power of two. The bytes skipped to reach the
+// boundary are claimed in the same atomic step and reported by Stats. It
+// fails like AllocBytes, with a *CapacityError, ErrFrozen or ErrClosed.
+func (b *ByteArena) AllocBytesAligned(n, align int) ([]byte, error) {
+	if n < 0 {
+		return nil, ErrNegativeSize
+	}
+	if align <= 0 || align&(align-1) != 0 {
+		return nil, fmt.Errorf("%w: %d", ErrBadAlignment, align)
+	}
+	if b.sizes != nil {
+		b.sizes.record(n)
+	}
+	a := b.arena
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(a.raw)))
+	mask := uintptr(align) - 1
+	var pad uintptr // bytes skipped to reach the boundary, for the last start seen
+	start, err := a.reserveWithin(uintptr(n), a.softCap.Load(), func(start uintptr) (uintptr, error) {
+		pad = (-(base + start)) & mask
+		return pad, nil
+	}, false)
+	if err != nil {
+		return nil, a.allocErr(err, start, pad+uintptr(n))
+	}
+	lo := start + pad
+	seg := a.raw[lo : lo+uintptr(n) : lo+uintptr(n)]
+	if a.opts.zeroOnReserve {
+		a.zeroStale(lo, seg)
+	}
+	if a.ops != nil {
+		a.ops.record(OpReserve, start, pad+uintptr(n), false)
+	}
+	a.commit(pad + uintptr(n))
+	b.padding.Add(pad)
+	if a.prof != nil {
+		a.prof.sample(uintptr(n))
+	}
+	return seg, nil
+}
+
+// CopyBytes returns a copy of p stored in the arena.
+func (b *ByteArena) CopyBytes(p []byte) ([]byte, error) {
+	dst, err := b.AllocBytes(len(p))
+	if err != nil {
+		return nil, err
+	}
+	copy(dst, p)
+	return dst, nil
+}
+
+// CopyString returns a copy of s whose bytes are stored in the arena.
+func (b *ByteArena) CopyString(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	dst, err := b.AllocBytes(len(s))
+	if err != nil {
+		return "", err
+	}
+	copy(dst, s)
+	return unsafe.String(&dst[0], len(dst)), nil
+}
+
+// Len returns the number of bytes allocated.
+func (b *ByteArena) Len() uintptr {
+	return b.arena.Len()
+}
+
+// Cap returns the arena's size in bytes.
+func (b *ByteArena) Cap() uintptr {
+	return b.arena.Cap()
+}
+
+// Epoch returns the number of times the arena has been reset.
+func (b *ByteArena) Epoch() uint64 {
+	return b.arena.Epoch()
+}
+
+// ByteArenaStats summarizes a ByteArena.
+type ByteArenaStats struct {
+	Stats
+	Padding   uintptr       // bytes lost to alignment padding since the last Reset
+	Histogram []BucketCount // request sizes, as reported by Histogram; nil unless WithSizeHistogram
+}
+
+// Stats returns a summary of the arena's current state. Len includes padding.
+func (b *ByteArena) Stats() ByteArenaStats {
+	return ByteArenaStats{Stats: b.arena.Stats(), Padding: b.padding.Load(), Histogram: b.Histogram()}
+}
+
+// Reset makes the whole arena available again. Buffers handed out before it
+// must no longer be used.
+func (b *ByteArena) Reset() {
+	if b.arena.Reset(false) == nil {
+		b.padding.Store(0)
+	}
+}
@@ -0,0 +1,144 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// TestByteArena covers allocation, clipping, copies and Reset
+func TestByteArena(t *testing.T) {
+	b := NewByteArena(16)
+	x, err := b.AllocBytes(4)
+	if err != nil || len(x) != 4 || cap(x) != 4 {
+		t.Fatalf("AllocBytes = %d/%d, %v", len(x), cap(x), err)
+	}
+	y, _ := b.CopyBytes([]byte("abcd"))
+	// appending to x must not clobber y
+	_ = append(x, 'z')
+	if string(y) != "abcd" {
+		t.Fatalf("neighbouring buffer overwritten: %q", y)
+	}
+	s, err := b.CopyString("hello")
+	if err != nil || s != "hello" || b.Len() != 13 {
+		t.Fatalf("CopyString = %q, %v (len %d)", s, err, b.Len())
+	}
+	if _, err := b.AllocBytes(4); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if _, err := b.AllocBytes(-1); !errors.Is(err, ErrNegativeSize) {
+		t.Fatalf("expected ErrNegativeSize, got %v", err)
+	}
+	b.Reset()
+	if b.Len() != 0 || b.Epoch() != 1 {
+		t.Fatalf("Reset failed: len %d epoch %d", b.Len(), b.Epoch())
+	}
+}
+
+// TestByteArenaConcurrent ensures concurrent buffers never overlap
+func TestByteArenaConcurrent(t *testing.T) {
+	b := NewByteArena(64 << 10)
+	var wg sync.WaitGroup
+	bufs := make([][]byte, 8)
+	for w := range bufs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				p, err := b.AllocBytes(8)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for j := range p {
+					p[j] = byte(w)
+				}
+				bufs[w] = append(bufs[w], p...)
+			}
+		}()
+	}
+	wg.Wait()
+	for w, buf := range bufs {
+		for _, c := range buf {
+			if c != byte(w) {
+				t.Fatalf("buffer of worker %d overwritten", w)
+			}
+		}
+	}
+}
+
+// TestByteArenaAligned checks absolute alignment across concurrent allocations
+func TestByteArenaAligned(t *testing.T) {
+	b := NewByteArena(1 << 20)
+	if uintptr(unsafe.Pointer(unsafe.SliceData(b.arena.raw)))%uintptr(pageSize) != 0 {
+		t.Fatal("storage is not page-aligned")
+	}
+	aligns := []int{1, 8, 64, 4096}
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				align := aligns[(w+i)%len(aligns)]
+				p, err := b.AllocBytesAligned(1+i%100, align)
+				if err != nil {
+					if errors.Is(err, ErrArenaFull) {
+						return
+					}
+					t.Error(err)
+					return
+				}
+				if addr := uintptr(unsafe.Pointer(&p[0])); addr%uintptr(align) != 0 {
+					t.Errorf("buffer at %#x not %d-aligned", addr, align)
+				}
+				b.AllocBytes(i % 7) // knock the offset off alignment
+			}
+		}()
+	}
+	wg.Wait()
+	if st := b.Stats(); st.Padding == 0 || st.Padding > st.Len {
+		t.Fatalf("unexpected padding accounting %+v", st)
+	}
+}
+
+// TestByteArenaAlignedErrors covers bad alignments, padding at the end of the arena and the errors of an ordinary reservation
+func TestByteArenaAlignedErrors(t *testing.T) {
+	b := NewByteArena(128)
+	for _, align := range []int{0, -8, 3, 48} {
+		if _, err := b.AllocBytesAligned(1, align); !errors.Is(err, ErrBadAlignment) {
+			t.Fatalf("align %d: expected ErrBadAlignment, got %v", align, err)
+		}
+	}
+	b.AllocBytes(1)
+	if _, err := b.AllocBytesAligned(8, 64); err != nil || b.Stats().Padding != 63 || b.Len() != 72 {
+		t.Fatalf("AllocBytesAligned = %v, %+v", err, b.Stats())
+	}
+	// only 56 bytes remain and reaching the next 64-byte boundary needs all of them
+	if _, err := b.AllocBytesAligned(1, 64); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	b.Reset()
+	if b.Stats().Padding != 0 {
+		t.Fatal("padding not cleared by Reset")
+	}
+	var ce *CapacityError
+	if _, err := NewByteArena(8).AllocBytesAligned(16, 8); !errors.As(err, &ce) || ce.Requested != 16 {
+		t.Fatalf("expected a *CapacityError for 16 bytes, got %v", err)
+	}
+	logged := NewByteArena(128, WithOpLog(4))
+	logged.AllocBytes(1)
+	logged.AllocBytesAligned(8, 64)
+	if ops := logged.arena.OpLog(); len(ops) != 2 || ops[1].Op != OpReserve || ops[1].Index != 1 || ops[1].Len != 71 {
+		t.Fatalf("expected the aligned reservation with its padding logged, got %+v", ops)
+	}
+	logged.arena.Freeze()
+	if _, err := logged.AllocBytesAligned(1, 8); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+	logged.arena.Close()
+	if _, err := logged.AllocBytesAligned(1, 8); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
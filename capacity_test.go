@@ -0,0 +1,92 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+// capacityErr extracts the *CapacityError from err.
+func capacityErr(t *testing.T, err error) *CapacityError {
+	t.Helper()
+	var ce *CapacityError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected a *CapacityError, got %v", err)
+	}
+	if !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected %v to wrap ErrArenaFull", err)
+	}
+	return ce
+}
+
+// TestCapacityErrorAlloc checks the fields for a single Alloc
+func TestCapacityErrorAlloc(t *testing.T) {
+	a := NewAtomicArena[int](2, WithName("packets"))
+	a.Alloc(1)
+	a.Alloc(2)
+	_, err := a.Alloc(3)
+	want := CapacityError{Name: "packets", Requested: 1, Available: 0, Capacity: 2}
+	if ce := capacityErr(t, err); *ce != want {
+		t.Fatalf("expected %+v, got %+v", want, *ce)
+	}
+	if got := err.Error(); got != `atomicarena "packets": need 1 slot, 0 available of 2` {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
+
+// TestCapacityErrorBulk checks the fields for bulk Reserve and AppendSlice
+func TestCapacityErrorBulk(t *testing.T) {
+	a := NewAtomicArena[byte](4096)
+	if _, err := a.Reserve(4096 - 17); err != nil {
+		t.Fatal(err)
+	}
+	_, err := a.Reserve(128)
+	want := CapacityError{Requested: 128, Available: 17, Capacity: 4096}
+	if ce := capacityErr(t, err); *ce != want {
+		t.Fatalf("expected %+v, got %+v", want, *ce)
+	}
+	if got := err.Error(); got != "atomicarena: need 128 slots, 17 available of 4096" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	_, err = a.AppendSlice(make([]byte, 18))
+	if ce := capacityErr(t, err); ce.Requested != 18 || ce.Available != 17 {
+		t.Fatalf("unexpected AppendSlice error %+v", *ce)
+	}
+	// the failed requests reserved nothing
+	if a.Len() != 4096-17 {
+		t.Fatalf("expected len %d, got %d", 4096-17, a.Len())
+	}
+}
+
+// TestCapacityErrorZero covers the n == 0 edge case: an empty request always fits
+func TestCapacityErrorZero(t *testing.T) {
+	a := NewAtomicArena[int](1)
+	a.Alloc(1)
+	if seg, err := a.Reserve(0); err != nil || len(seg) != 0 {
+		t.Fatalf("expected Reserve(0) on a full arena to succeed, got %d, %v", len(seg), err)
+	}
+	if _, err := a.AppendSlice(nil); err != nil {
+		t.Fatalf("expected an empty AppendSlice on a full arena to succeed, got %v", err)
+	}
+	z := NewAtomicArena[int](0)
+	_, err := z.Alloc(1)
+	want := CapacityError{Requested: 1, Available: 0, Capacity: 0}
+	if ce := capacityErr(t, err); *ce != want {
+		t.Fatalf("expected %+v, got %+v", want, *ce)
+	}
+}
+
+// TestCapacityErrorAllocators ensures every Allocator reports the same error
+func TestCapacityErrorAllocators(t *testing.T) {
+	for name, a := range map[string]Allocator[int]{
+		"AtomicArena": NewAtomicArena[int](3),
+		"Arena":       NewArena[int](3),
+		"MutexArena":  NewMutexArena[int](3),
+	} {
+		a.Alloc(1)
+		_, err := a.Reserve(5)
+		want := CapacityError{Requested: 5, Available: 2, Capacity: 3}
+		if ce := capacityErr(t, err); *ce != want {
+			t.Errorf("%s: expected %+v, got %+v", name, want, *ce)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrZeroCapacity is returned by NewAtomicArenaChecked for a zero maxElems.
+	ErrZeroCapacity = errors.New("atomicarena: zero capacity")
+	// ErrTooLarge is returned when an arena's storage cannot be allocated.
+	ErrTooLarge = errors.New("atomicarena: arena too large")
+	// ErrZeroSizedType is returned by NewAtomicArenaChecked for a zero-sized
+	// element type, whose elements would all share one address.
+	ErrZeroSizedType = errors.New("atomicarena: zero-sized element type")
+)
+
+// NewAtomicArenaChecked is like NewAtomicArena but reports invalid or
+// unsatisfiable capacities as errors instead of producing an unusable arena
+// or panicking: zero capacity, zero-sized element types, sizes that overflow
+// uintptr, and allocations the runtime refuses.
+func NewAtomicArenaChecked[T any](maxElems uintptr, opts ...Option) (*AtomicArena[T], error) {
+	if maxElems == 0 {
+		return nil, ErrZeroCapacity
+	}
+	if zeroSized[T]() {
+		return nil, fmt.Errorf("%w: every %s would share one address", ErrZeroSizedType, reflect.TypeFor[T]())
+	}
+	return New[T](maxElems, opts...)
+}
+
+// describeRequest renders an allocation request like "96GiB for 1.5e+09 elements of main.Foo".
+func describeRequest[T any](maxElems uintptr) string {
+	per := float64(slotBytes[T]())
+	return fmt.Sprintf("%s for %.3g elements of %s", formatBytes(per*float64(maxElems)), float64(maxElems), reflect.TypeFor[T]())
+}
+
+// formatBytes renders n bytes using binary units.
+func formatBytes(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.3g%s", n, units[i])
+}
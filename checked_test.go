@@ -0,0 +1,74 @@
+package atomicarena
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+type checkedFoo struct{ A, B, C int64 }
+
+// TestCheckedZero ensures zero capacity is rejected
+func TestCheckedZero(t *testing.T) {
+	if _, err := NewAtomicArenaChecked[int](0); !errors.Is(err, ErrZeroCapacity) {
+		t.Fatalf("expected ErrZeroCapacity, got %v", err)
+	}
+	// the unchecked constructor keeps accepting zero
+	if a := NewAtomicArena[int](0); a.Cap() != 0 {
+		t.Fatalf("expected zero capacity, got %d", a.Cap())
+	}
+}
+
+// TestCheckedOverflow covers the overflow boundary and runtime-refused sizes
+func TestCheckedOverflow(t *testing.T) {
+	per := unsafe.Sizeof(checkedFoo{}) + unsafe.Sizeof(atomic.Pointer[checkedFoo]{})
+	limit := ^uintptr(0) / per
+
+	_, err := NewAtomicArenaChecked[checkedFoo](limit + 1)
+	if !errors.Is(err, ErrTooLarge) || !strings.Contains(err.Error(), "overflows") {
+		t.Fatalf("expected overflow error, got %v", err)
+	}
+	if unsafe.Sizeof(uintptr(0)) < 8 {
+		// on 32-bit the boundary request is within reach of the allocator,
+		// which would abort the process rather than refuse it
+		return
+	}
+	// at the boundary the size fits in uintptr but the runtime refuses it
+	_, err = NewAtomicArenaChecked[checkedFoo](limit)
+	if !errors.Is(err, ErrTooLarge) || !strings.Contains(err.Error(), "atomicarena.checkedFoo") {
+		t.Fatalf("expected descriptive allocation error, got %v", err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected NewAtomicArena to panic")
+		} else if e, ok := r.(error); !ok || !errors.Is(e, ErrTooLarge) {
+			t.Fatalf("expected ErrTooLarge panic, got %v", r)
+		}
+	}()
+	NewAtomicArena[checkedFoo](limit)
+}
+
+// TestCheckedLarge ensures a reasonably large arena is created successfully
+func TestCheckedLarge(t *testing.T) {
+	a, err := NewAtomicArenaChecked[checkedFoo](1 << 20)
+	if err != nil {
+		t.Fatalf("NewAtomicArenaChecked failed: %v", err)
+	}
+	if a.Cap() != 1<<20 {
+		t.Fatalf("expected cap %d, got %d", 1<<20, a.Cap())
+	}
+}
+
+// TestDescribeRequest checks the human-readable size description
+func TestDescribeRequest(t *testing.T) {
+	got := describeRequest[checkedFoo](1_500_000_000)
+	want := formatBytes(1.5e9*float64(slotBytes[checkedFoo]())) + " for 1.5e+09 elements of atomicarena.checkedFoo"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := formatBytes(48e9); got != "44.7GiB" {
+		t.Fatalf("expected 44.7GiB, got %q", got)
+	}
+}
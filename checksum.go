@@ -0,0 +1,100 @@
+package atomicarena
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// Checksum hashes the committed elements into h, after resetting it, and
+// returns h.Sum64(). A nil h selects FNV-1a, whose result is the same in
+// every run and process; a hash/maphash.Hash with a fixed seed is faster
+// but only reproducible within a process. Pointer-free elements are hashed
+// as their raw bytes in native byte order, padding included. Elements with
+// strings or slices are walked field by field, hashing their contents with
+// length prefixes; pointers, maps, channels, funcs and interfaces are
+// rejected with ErrPointerType. Tombstoned slots are hashed like live ones.
+func (a *AtomicArena[T]) Checksum(h hash.Hash64) (uint64, error) {
+	if h == nil {
+		h = fnv.New64a()
+	}
+	h.Reset()
+	return a.ChecksumRange(h, 0, a.Committed())
+}
+
+// ChecksumRange hashes the committed slots [lo, hi) into h without resetting
+// it, so a caller can extend a previous Checksum with the suffix committed
+// since: Checksum over n slots followed by ChecksumRange(h, n, m) gives the
+// same result as Checksum over m slots. A nil h starts a fresh FNV-1a hash.
+// It returns ErrOutOfRange if hi exceeds the committed count or lo > hi.
+func (a *AtomicArena[T]) ChecksumRange(h hash.Hash64, lo, hi uintptr) (uint64, error) {
+	if h == nil {
+		h = fnv.New64a()
+	}
+	if n := a.Committed(); lo > hi || hi > n {
+		return 0, fmt.Errorf("%w: checksum [%d, %d), committed %d", ErrOutOfRange, lo, hi, n)
+	}
+	if !a.pointers {
+		h.Write(elemBytes(a.raw[lo:], hi-lo))
+		return h.Sum64(), nil
+	}
+	var buf []byte
+	for i := lo; i < hi; i++ {
+		var err error
+		if buf, err = appendValue(buf[:0], reflect.ValueOf(&a.raw[i]).Elem(), reflect.TypeFor[T]().String()); err != nil {
+			return 0, err
+		}
+		h.Write(buf)
+	}
+	return h.Sum64(), nil
+}
+
+// appendValue appends a deterministic encoding of v to buf. path names v
+// in errors.
+func appendValue(buf []byte, v reflect.Value, path string) ([]byte, error) {
+	var err error
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.LittleEndian.AppendUint64(buf, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v.Float())), nil
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(real(c)))
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(imag(c))), nil
+	case reflect.String:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		return append(buf, v.String()...), nil
+	case reflect.Slice:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return append(buf, v.Bytes()...), nil
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if buf, err = appendValue(buf, v.Index(i), path+"[]"); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if buf, err = appendValue(buf, v.Field(i), path+"."+v.Type().Field(i).Name); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("%w: cannot checksum %s (%s)", ErrPointerType, path, v.Type())
+}
@@ -0,0 +1,115 @@
+package atomicarena
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"hash/maphash"
+	"math/rand"
+	"testing"
+)
+
+// record is a pointerful element hashed by the reflection walk.
+type record struct {
+	ID    int
+	Tags  []string
+	Body  []byte
+	Score float64
+	flags [2]bool
+}
+
+// TestChecksumChanges flips every element in turn and expects a new checksum
+func TestChecksumChanges(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a := NewAtomicArena[position](64)
+	for i := 0; i < 64; i++ {
+		a.Alloc(position{rng.Float64(), rng.Float64()})
+	}
+	base, err := a.Checksum(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := a.Checksum(nil); again != base {
+		t.Fatalf("expected a stable checksum, got %x and %x", base, again)
+	}
+	for i := range a.raw {
+		old := a.raw[i]
+		a.raw[i].Y += 1
+		if sum, _ := a.Checksum(nil); sum == base {
+			t.Fatalf("expected a change to element %d to change the checksum", i)
+		}
+		a.raw[i] = old
+	}
+	if sum, _ := a.Checksum(nil); sum != base {
+		t.Fatalf("expected the original checksum after restoring, got %x", sum)
+	}
+	// the default is FNV-1a over the native-endian bytes, reproducible across runs
+	b := NewAtomicArena[uint32](4)
+	b.AppendSlice([]uint32{1, 2, 3})
+	want := fnv.New64a()
+	for _, v := range []uint32{1, 2, 3} {
+		want.Write(binary.NativeEndian.AppendUint32(nil, v))
+	}
+	if sum, _ := b.Checksum(nil); sum != want.Sum64() {
+		t.Fatalf("expected the FNV-1a checksum %#x, got %#x", want.Sum64(), sum)
+	}
+}
+
+// TestChecksumRange extends a checksum with newly committed suffixes
+func TestChecksumRange(t *testing.T) {
+	var h maphash.Hash
+	seed := maphash.MakeSeed()
+	h.SetSeed(seed)
+	a := NewAtomicArena[int64](16)
+	a.AppendSlice([]int64{1, 2, 3})
+	if _, err := a.Checksum(&h); err != nil {
+		t.Fatal(err)
+	}
+	a.AppendSlice([]int64{4, 5})
+	inc, err := a.ChecksumRange(&h, 3, a.Committed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fresh maphash.Hash
+	fresh.SetSeed(seed)
+	if full, _ := a.Checksum(&fresh); full != inc {
+		t.Fatalf("expected the incremental checksum %x to match the full one %x", inc, full)
+	}
+	for _, r := range [][2]uintptr{{4, 3}, {0, 6}} {
+		if _, err := a.ChecksumRange(nil, r[0], r[1]); !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("%v: expected ErrOutOfRange, got %v", r, err)
+		}
+	}
+}
+
+// TestChecksumPointers covers the reflection walk and its rejections
+func TestChecksumPointers(t *testing.T) {
+	a := NewAtomicArena[record](4)
+	a.Alloc(record{ID: 1, Tags: []string{"a", "bc"}, Body: []byte("x")})
+	a.Alloc(record{ID: 2, Score: 0.5})
+	base, err := a.Checksum(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, edit := range []func(r *record){
+		func(r *record) { r.Tags[1] = "b" },
+		func(r *record) { r.Tags = []string{"ab", "c"} },
+		func(r *record) { r.Body[0] = 'y' },
+		func(r *record) { r.flags[1] = true },
+	} {
+		c := NewAtomicArena[record](4)
+		x := a.raw[0]
+		x.Tags, x.Body = append([]string(nil), x.Tags...), append([]byte(nil), x.Body...)
+		edit(&x)
+		c.Alloc(x)
+		c.Alloc(a.raw[1])
+		if sum, _ := c.Checksum(nil); sum == base {
+			t.Fatalf("edit %d: expected the checksum to change", i)
+		}
+	}
+	p := NewAtomicArena[*int](1)
+	p.Alloc(new(int))
+	if _, err := p.Checksum(nil); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("expected ErrPointerType for *int, got %v", err)
+	}
+}
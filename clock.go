@@ -0,0 +1,58 @@
+package atomicarena
+
+import "time"
+
+// Clock is the time source of the arena's time-based features: the janitor,
+// AllocWithRetry and ResetWhenIdle. The default is the time package;
+// WithClock substitutes another, such as atomicarenatest.FakeClock, so tests
+// can drive time deterministically instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a ticker that delivers ticks every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker is the part of a *time.Ticker a Clock hands out. Like time.Ticker,
+// it drops ticks for slow receivers.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// WithClock makes the arena's time-based features use c. A nil c selects the
+// time package.
+func WithClock(c Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+// realClock is the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ t *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()               { t.t.Stop() }
+
+// timeSource returns the configured clock, or the time package.
+func (o *options) timeSource() Clock {
+	if o.clock == nil {
+		return realClock{}
+	}
+	return o.clock
+}
+
+// sleep waits for d on c. The time package sleeps without allocating a
+// timer channel, which keeps retry loops allocation-free.
+func sleep(c Clock, d time.Duration) {
+	if _, ok := c.(realClock); ok {
+		time.Sleep(d)
+		return
+	}
+	<-c.After(d)
+}
@@ -0,0 +1,50 @@
+package atomicarena
+
+import "errors"
+
+// ErrClosed is returned by operations on an arena that has been closed.
+var ErrClosed = errors.New("atomicarena: arena closed")
+
+// Close ends the arena's life. It freezes the arena, waits for in-flight
+// allocations to finish writing and then drops its storage, so the memory
+// can be collected even while the arena itself is still referenced.
+// Afterwards Alloc, Reserve, AppendSlice, Reset, Free and Tombstone return
+// ErrClosed, Get reports no element and Len is zero. Close also releases
+// the arena's budget reservation, if any, removes a named arena from the
+// Arenas registry and cancels the WithLeakWarning check. It is idempotent.
+//
+// Close may race with allocations, which either complete before it or fail,
+// but not with readers: pointers previously returned by the arena must not
+// be used after Close.
+func (a *AtomicArena[T]) Close() error {
+	if !a.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	a.Freeze()
+	a.count.Store(frozenBit)
+	a.done.Store(0)
+	a.dirty.Store(0)
+	a.raw, a.ptrs, a.dead = nil, nil, nil
+	if a.budget != nil {
+		a.budget.release(a.budgetBytes)
+	}
+	untrackArena(a.regID)
+	if a.opts.leakLogf != nil {
+		unwatchLeak(a)
+	}
+	return nil
+}
+
+// Closed reports whether Close has been called.
+func (a *AtomicArena[T]) Closed() bool {
+	return a.closed.Load()
+}
+
+// frozenErr is the error for a mutation refused because the frozen bit is
+// set, which Close also sets.
+func (a *AtomicArena[T]) frozenErr() error {
+	if a.closed.Load() {
+		return ErrClosed
+	}
+	return ErrFrozen
+}
@@ -0,0 +1,108 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestCloseInvalidates checks that every operation after Close errors
+// rather than panicking
+func TestCloseInvalidates(t *testing.T) {
+	b := NewBudget(1 << 10)
+	a, err := NewAtomicArenaWithBudget[int64](16, b, WithName("closing"), WithUnfreeze())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.AppendSlice([]int64{1, 2, 3})
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Closed() || a.Len() != 0 || a.raw != nil || a.ptrs != nil {
+		t.Fatalf("expected the storage to be dropped")
+	}
+	if b.Used() != 0 {
+		t.Fatalf("expected the budget to be returned, %d bytes still used", b.Used())
+	}
+	if _, ok := findArena("closing"); ok {
+		t.Fatalf("expected Close to unregister the arena")
+	}
+	if _, err := a.Alloc(4); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Alloc: expected ErrClosed, got %v", err)
+	}
+	if _, err := a.Reserve(1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Reserve: expected ErrClosed, got %v", err)
+	}
+	if _, err := a.AppendSlice([]int64{5}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("AppendSlice: expected ErrClosed, got %v", err)
+	}
+	for name, fn := range map[string]func() error{
+		"Reset":    func() error { return a.Reset(true) },
+		"Free":     a.Free,
+		"Unfreeze": a.Unfreeze,
+	} {
+		if err := fn(); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: expected ErrClosed, got %v", name, err)
+		}
+	}
+	if _, ok := a.Get(0); ok {
+		t.Fatalf("expected Get to report no element")
+	}
+	if err := a.Tombstone(0); err == nil {
+		t.Fatalf("expected Tombstone to fail")
+	}
+	if s := a.Snapshot(); len(s) != 0 {
+		t.Fatalf("expected an empty snapshot, got %v", s)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("expected a second Close to be a no-op, got %v", err)
+	}
+	if b.Used() != 0 {
+		t.Fatalf("double Close released the budget twice")
+	}
+}
+
+// TestCloseRacingAlloc closes an arena while writers allocate into it; every
+// allocation either succeeds or fails with ErrClosed. Run with -race.
+func TestCloseRacingAlloc(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		a := NewAtomicArena[[4]int64](1 << 12)
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				for i := int64(0); ; i++ {
+					var err error
+					if i%4 == 0 {
+						_, err = a.AppendSlice([][4]int64{{i}, {i}})
+					} else {
+						_, err = a.Alloc([4]int64{i, i, i, i})
+					}
+					if err != nil {
+						if !errors.Is(err, ErrClosed) && !errors.Is(err, ErrArenaFull) {
+							t.Errorf("unexpected error %v", err)
+						}
+						return
+					}
+				}
+			}()
+		}
+		close(start)
+		closes := make(chan error, 2)
+		for k := 0; k < 2; k++ {
+			go func() { closes <- a.Close() }()
+		}
+		for k := 0; k < 2; k++ {
+			if err := <-closes; err != nil {
+				t.Fatal(err)
+			}
+		}
+		wg.Wait()
+		if _, err := a.Alloc([4]int64{}); !errors.Is(err, ErrClosed) {
+			t.Fatalf("expected ErrClosed after Close, got %v", err)
+		}
+	}
+}
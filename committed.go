@@ -0,0 +1,160 @@
+package atomicarena
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// commitHint caches a committed prefix found by Committed. It is valid only
+// while the arena's trims counter still equals gen.
+type commitHint struct {
+	gen uint64
+	n   uintptr
+}
+
+// commitWaiters is how WaitForCommitted sleeps. Waiters publish the smallest
+// count they wait for in wantAt, which is ^0 while nobody waits, and writers
+// compare their new done count against it after every commit. Only a writer
+// that reaches it takes mu and closes ch, waking every waiter at once.
+type commitWaiters struct {
+	wantAt atomic.Uintptr
+	mu     sync.Mutex
+	ch     chan struct{}
+}
+
+// commit marks n written slots as done and wakes WaitForCommitted callers
+// once enough writes have landed. It replaces a plain done.Add(n) on the
+// allocation paths.
+func (a *AtomicArena[T]) commit(n uintptr) {
+	if a.done.Add(n) >= a.waiters.wantAt.Load() {
+		a.wakeCommitted()
+	}
+}
+
+// wakeCommitted wakes every WaitForCommitted caller so it rechecks.
+func (a *AtomicArena[T]) wakeCommitted() {
+	w := &a.waiters
+	w.mu.Lock()
+	if w.ch != nil {
+		close(w.ch)
+		w.ch = nil
+	}
+	w.wantAt.Store(^uintptr(0))
+	w.mu.Unlock()
+}
+
+// waitChan registers a waiter for n done writes and returns the channel
+// closed when a writer reaches it.
+func (a *AtomicArena[T]) waitChan(n uintptr) <-chan struct{} {
+	w := &a.waiters
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ch == nil {
+		w.ch = make(chan struct{})
+	}
+	if n < w.wantAt.Load() {
+		w.wantAt.Store(n)
+	}
+	return w.ch
+}
+
+// countDropped records that the count fell from old to n under exclusive
+// access, by Reset, a rollback or Compact: it re-arms watermarks above n,
+// invalidates the cached committed prefix and keeps the totals behind
+// Stats.Allocated and Stats.PeakLen, so the allocation paths need not.
+func (a *AtomicArena[T]) countDropped(old, n uintptr) {
+	a.armMarks(n)
+	a.trims.Add(1)
+	a.retired.Add(uint64(old - n))
+	if old > a.peak.Load() {
+		a.peak.Store(old)
+	}
+}
+
+// Committed returns how many leading slots are fully written, which a
+// consumer may read while producers keep allocating: every slot below the
+// result holds its final value. Len also counts slots whose writes are still
+// in flight, and Reserve'd slots not yet passed to PublishRange. When there
+// are none of either the two agree. Otherwise Committed walks the pointer
+// mirror from the last prefix it found, stopping at the first slot that is
+// not published. An arena built WithoutPointerMirror reports the last prefix
+// seen with no writes in flight and every reservation published or given
+// back, so there a reservation still open anywhere holds the whole prefix
+// back.
+//
+// Every edge from a write to Committed goes through sync/atomic, which the
+// race detector models, so under -race reading slots below the result is
+// clean while reading a slot at or beyond it, whose write may be in flight,
+// is still reported.
+func (a *AtomicArena[T]) Committed() uintptr {
+	g := a.trims.Load()
+	e, n := a.Epoch(), a.Len()
+	// done before unpub: a reservation counts itself unpublished first
+	if a.done.Load() == n && a.unpub.Load() == 0 && a.Len() == n && a.Epoch() == e {
+		if a.ptrs == nil {
+			a.advanceHint(g, n)
+		}
+		return n
+	}
+	from := uintptr(0)
+	if h := a.hint.Load(); h != nil && h.gen == g {
+		from = min(h.n, n)
+	}
+	if a.ptrs == nil {
+		return from
+	}
+	i := from
+	for i < n && a.ptrs[i].Load() != nil {
+		i++
+	}
+	if i > from {
+		a.advanceHint(g, i)
+	}
+	return i
+}
+
+// advanceHint caches n as the committed prefix for generation g unless a
+// larger one is cached already. A hint computed before a reset keeps its old
+// generation and is ignored.
+func (a *AtomicArena[T]) advanceHint(g uint64, n uintptr) {
+	old := a.hint.Load()
+	if old != nil && old.gen == g && old.n >= n {
+		return
+	}
+	a.hint.CompareAndSwap(old, &commitHint{gen: g, n: n})
+}
+
+// WaitForCommitted blocks until at least n slots are committed, as reported
+// by Committed, so a consumer can trail producers and treat the arena as a
+// single-producer or multi-producer, multi-consumer log. Waiting costs the
+// producers nothing until enough writes have landed to possibly satisfy the
+// smallest outstanding wait; then one of them wakes every waiter at once.
+// It returns ctx.Err() if ctx is done first, ErrStale if the arena is reset
+// or compacted while it waits, and ErrFrozen or ErrClosed if the arena stops
+// accepting writes before n slots are committed.
+func (a *AtomicArena[T]) WaitForCommitted(ctx context.Context, n uintptr) error {
+	e := a.Epoch()
+	for {
+		if a.Epoch() != e {
+			return ErrStale
+		}
+		if a.Committed() >= n {
+			return nil
+		}
+		if a.Frozen() {
+			return a.frozenErr()
+		}
+		ch := a.waitChan(n)
+		// recheck after registering: a writer that finished before the
+		// registration did not see it
+		if a.Epoch() != e || a.Committed() >= n || a.Frozen() {
+			continue
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
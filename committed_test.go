@@ -0,0 +1,316 @@
+package atomicarena
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// logEntry is an event in the log test: the producer and its sequence number.
+type logEntry struct {
+	Producer, Seq int
+}
+
+// TestWaitForCommittedLog has consumers trail several producers and checks
+// that each reads every entry exactly once, in index order; run with -race
+func TestWaitForCommittedLog(t *testing.T) {
+	const producers, perProducer = 4, 2000
+	const total = producers * perProducer
+	for _, opts := range [][]Option{nil, {WithoutPointerMirror()}} {
+		a := NewAtomicArena[logEntry](total, opts...)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		var consumers sync.WaitGroup
+		seen := make([][]logEntry, 2)
+		for c := range seen {
+			consumers.Add(1)
+			go func() {
+				defer consumers.Done()
+				for next := uintptr(0); next < total; {
+					if err := a.WaitForCommitted(ctx, next+1); err != nil {
+						t.Error(err)
+						return
+					}
+					for n := a.Committed(); next < n; next++ {
+						seen[c] = append(seen[c], a.raw[next])
+					}
+				}
+			}()
+		}
+		var wg sync.WaitGroup
+		for p := 0; p < producers; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perProducer; i++ {
+					if i%2 == 0 {
+						a.Alloc(logEntry{p, i})
+					} else {
+						a.AppendSlice([]logEntry{{p, i}})
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		consumers.Wait()
+		cancel()
+		for c, entries := range seen {
+			if len(entries) != total {
+				t.Fatalf("consumer %d: expected %d entries, got %d", c, total, len(entries))
+			}
+			next := make([]int, producers)
+			for i, e := range entries {
+				if e != a.raw[i] || e.Seq != next[e.Producer] {
+					t.Fatalf("consumer %d: entry %d is %+v, expected producer %d's entry %d", c, i, e, e.Producer, next[e.Producer])
+				}
+				next[e.Producer]++
+			}
+		}
+	}
+}
+
+// TestCommittedPending ensures a slot still being written bounds the prefix
+func TestCommittedPending(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	a.Alloc(1)
+	a.Alloc(2)
+	// simulate a writer that reserved slot 2 but has not finished
+	if _, err := a.reserve(1); err != nil {
+		t.Fatal(err)
+	}
+	a.Alloc(4)
+	if got := a.Committed(); got != 2 || a.Len() != 4 {
+		t.Fatalf("expected 2 committed of 4, got %d of %d", got, a.Len())
+	}
+	a.raw[2] = 3
+	a.ptrs[2].Store(&a.raw[2])
+	a.commit(1)
+	if got := a.Committed(); got != 4 {
+		t.Fatalf("expected 4 committed, got %d", got)
+	}
+}
+
+// TestCommittedReserve leaves Reserve'd slots out until PublishRange, with
+// and without a pointer mirror
+func TestCommittedReserve(t *testing.T) {
+	for name, opts := range map[string][]Option{"mirror": nil, "no mirror": {WithoutPointerMirror()}} {
+		a := NewAtomicArena[int](8, opts...)
+		a.Alloc(1)
+		if got := a.Committed(); got != 1 {
+			t.Fatalf("%s: expected 1 committed, got %d", name, got)
+		}
+		start, seg, err := a.ReserveIndexed(2)
+		if err != nil {
+			t.Fatalf("%s: ReserveIndexed failed: %v", name, err)
+		}
+		if got := a.Committed(); got != 1 {
+			t.Fatalf("%s: expected 1 committed before PublishRange, got %d", name, got)
+		}
+		woke := make(chan error, 1)
+		go func() { woke <- a.WaitForCommitted(context.Background(), 3) }()
+		select {
+		case err := <-woke:
+			t.Fatalf("%s: expected WaitForCommitted to wait for PublishRange, got %v", name, err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		seg[0], seg[1] = 2, 3
+		if err := a.PublishRange(start, start+2); err != nil {
+			t.Fatalf("%s: PublishRange failed: %v", name, err)
+		}
+		select {
+		case err := <-woke:
+			if err != nil {
+				t.Fatalf("%s: WaitForCommitted failed: %v", name, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: PublishRange did not wake the waiter", name)
+		}
+		if got := a.Committed(); got != 3 {
+			t.Fatalf("%s: expected 3 committed after PublishRange, got %d", name, got)
+		}
+		if name == "mirror" {
+			a.StorePointer(1, nil)
+			if got := a.Committed(); got != 1 {
+				t.Fatalf("expected StorePointer(nil) to hold the prefix at 1, got %d", got)
+			}
+			a.StorePointer(1, &seg[0])
+			if got := a.Committed(); got != 3 {
+				t.Fatalf("expected StorePointer to publish slot 1 again, got %d", got)
+			}
+		}
+		// an unpublished reservation does not outlive the Reset that drops it
+		a.Reserve(1)
+		a.Reset(false)
+		a.Alloc(4)
+		if got := a.Committed(); got != 1 {
+			t.Fatalf("%s: expected 1 committed after Reset, got %d", name, got)
+		}
+	}
+}
+
+// TestCommittedDiscardedReservations stops counting reservations that Unreserve, TryShrinkTo, Txn.Rollback, Compact or a batch flush discards
+func TestCommittedDiscardedReservations(t *testing.T) {
+	for name, drop := range map[string]func(a *AtomicArena[int], seg []int){
+		"Unreserve":   func(a *AtomicArena[int], seg []int) { a.Unreserve(seg) },
+		"TryShrinkTo": func(a *AtomicArena[int], _ []int) { a.TryShrinkTo(0) },
+		"Compact": func(a *AtomicArena[int], seg []int) {
+			for i := range seg {
+				a.Tombstone(uintptr(i))
+			}
+			a.Compact()
+		},
+	} {
+		a := NewAtomicArena[int](8, WithoutPointerMirror())
+		seg, err := a.Reserve(4)
+		if err != nil {
+			t.Fatalf("%s: Reserve failed: %v", name, err)
+		}
+		drop(a, seg)
+		a.Alloc(1)
+		a.Alloc(2)
+		if got := a.Committed(); got != 2 || a.Len() != 2 {
+			t.Fatalf("%s: expected 2 committed of 2, got %d of %d", name, got, a.Len())
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := a.WaitForCommitted(ctx, 1); err != nil {
+			t.Fatalf("%s: WaitForCommitted failed: %v", name, err)
+		}
+		cancel()
+	}
+
+	a := NewAtomicArena[int](8, WithoutPointerMirror())
+	tx := a.Begin()
+	a.Reserve(3)
+	tx.Alloc(9)
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	a.Alloc(1)
+	if got := a.Committed(); got != 1 {
+		t.Fatalf("expected 1 committed after Txn.Rollback, got %d", got)
+	}
+
+	b := NewBatchedArena[int](16, 4, WithoutPointerMirror())
+	first, second := b.NewBatch(), b.NewBatch()
+	first.Alloc(1)
+	second.Alloc(2)
+	// the first chunk's tail is tombstoned, the second's handed back
+	first.Flush()
+	second.Flush()
+	if got := b.arena.Committed(); got != b.Len() || got != 5 {
+		t.Fatalf("expected every flushed slot committed, got %d of %d", got, b.Len())
+	}
+}
+
+// TestWaitForCommittedErrors covers cancellation, Reset and Freeze
+func TestWaitForCommittedErrors(t *testing.T) {
+	a := NewAtomicArena[int](8, WithUnfreeze())
+	a.Alloc(1)
+	if err := a.WaitForCommitted(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := a.WaitForCommitted(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline, got %v", err)
+	}
+	for name, stop := range map[string]func(){
+		"Reset":  func() { a.Reset(false) },
+		"Freeze": a.Freeze,
+	} {
+		errc := make(chan error, 1)
+		go func() { errc <- a.WaitForCommitted(context.Background(), 5) }()
+		time.Sleep(5 * time.Millisecond)
+		stop()
+		select {
+		case err := <-errc:
+			if !errors.Is(err, ErrStale) && !errors.Is(err, ErrFrozen) {
+				t.Fatalf("%s: unexpected error %v", name, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s did not wake the waiter", name)
+		}
+		a.Unfreeze()
+	}
+}
+
+// TestCommittedReadsRaceFree reads every committed element while producers append; -race must stay quiet
+func TestCommittedReadsRaceFree(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithoutPointerMirror()}} {
+		const producers, per = 4, 2000
+		a := NewAtomicArena[[4]int](producers*per*2, opts...)
+		var wg sync.WaitGroup
+		for g := 0; g < producers; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < per; i++ {
+					v := [4]int{g, i, g, i}
+					switch i % 3 {
+					case 0:
+						a.Alloc(v)
+					case 1:
+						a.AppendSlice([][4]int{v, v})
+					default:
+						a.AllocManyFunc(1, func(_ uintptr, p *[4]int) { *p = v })
+					}
+				}
+			}(g)
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		for finished := false; !finished; {
+			select {
+			case <-done:
+				finished = true
+			default:
+			}
+			for i, n := uintptr(0), a.Committed(); i < n; i++ {
+				if p, _ := a.Get(i); p[0] != p[2] || p[1] != p[3] {
+					t.Fatalf("slot %d torn below Committed: %v", i, *p)
+				}
+			}
+		}
+	}
+}
+
+// TestReadPastCommittedFlagged reads a slot whose write is in flight in a child process and expects -race to report it
+func TestReadPastCommittedFlagged(t *testing.T) {
+	if !raceEnabled {
+		t.Skip("built without -race")
+	}
+	if os.Getenv("ATOMICARENA_RACE_CHILD") != "" {
+		a := NewAtomicArena[int](1)
+		var read atomic.Bool
+		go a.AllocManyFunc(1, func(_ uintptr, p *int) {
+			*p = 1
+			// hold the write in flight until the reader is done
+			for !read.Load() {
+				runtime.Gosched()
+			}
+		})
+		// spinning on the count orders nothing after the reservation itself
+		for a.Len() == 0 {
+			runtime.Gosched()
+		}
+		p, _ := a.Get(a.Committed())
+		println(*p)
+		read.Store(true)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestReadPastCommittedFlagged$")
+	cmd.Env = append(os.Environ(), "ATOMICARENA_RACE_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "WARNING: DATA RACE") {
+		t.Fatalf("expected the race detector to flag the read, got %v:\n%s", err, out)
+	}
+}
@@ -0,0 +1,18 @@
+package atomicarena
+
+import "context"
+
+// ctxKey is the context key for arenas of element type T. Every instantiation
+// is a distinct type, so arenas of different element types never collide.
+type ctxKey[T any] struct{}
+
+// WithArena returns a copy of ctx carrying a.
+func WithArena[T any](ctx context.Context, a *AtomicArena[T]) context.Context {
+	return context.WithValue(ctx, ctxKey[T]{}, a)
+}
+
+// FromContext returns the arena of element type T stored in ctx by WithArena.
+func FromContext[T any](ctx context.Context) (*AtomicArena[T], bool) {
+	a, ok := ctx.Value(ctxKey[T]{}).(*AtomicArena[T])
+	return a, ok && a != nil
+}
@@ -0,0 +1,23 @@
+package atomicarena
+
+import (
+	"context"
+	"testing"
+)
+
+// TestContextPerType ensures arenas of different element types don't collide
+func TestContextPerType(t *testing.T) {
+	ints := NewAtomicArena[int](1)
+	strs := NewAtomicArena[string](1)
+	ctx := WithArena(WithArena(context.Background(), ints), strs)
+
+	if a, ok := FromContext[int](ctx); !ok || a != ints {
+		t.Fatalf("expected int arena, got %v, %v", a, ok)
+	}
+	if a, ok := FromContext[string](ctx); !ok || a != strs {
+		t.Fatalf("expected string arena, got %v, %v", a, ok)
+	}
+	if _, ok := FromContext[float64](ctx); ok {
+		t.Fatal("expected no float64 arena")
+	}
+}
@@ -0,0 +1,47 @@
+package atomicarena
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// crossTargets are the platforms whose build files differ, each vetted by
+// TestCrossBuild: the mapping code is split by OS, by 32-bit Linux's mmap2
+// and by the architectures whose syscall package lacks MAP_HUGETLB.
+var crossTargets = []struct {
+	goos, goarch, tags string
+}{
+	{"linux", "amd64", ""},
+	{"linux", "amd64", "atomicarena_heapmmap"},
+	{"linux", "386", ""},
+	{"linux", "arm", ""},
+	{"linux", "arm64", ""},
+	{"linux", "mips", ""},
+	{"darwin", "arm64", ""},
+	{"windows", "amd64", ""},
+}
+
+// TestCrossBuild vets the module, tests included, for every cross target
+func TestCrossBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("cross builds are slow")
+	}
+	gotool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skipf("no go tool: %v", err)
+	}
+	for _, c := range crossTargets {
+		name := c.goos + "/" + c.goarch
+		if c.tags != "" {
+			name += "," + c.tags
+		}
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command(gotool, "vet", "-tags="+c.tags, "./...")
+			cmd.Env = append(os.Environ(), "GOOS="+c.goos, "GOARCH="+c.goarch, "CGO_ENABLED=0")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go vet failed: %v\n%s", err, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// ErrUnsupportedField is returned by DeepAppendSlice for element types with
+// pointers other than []byte and string.
+var ErrUnsupportedField = errors.New("atomicarena: unsupported field for deep copy")
+
+// deepField locates a []byte or string inside a value.
+type deepField struct {
+	off uintptr
+	str bool // string rather than []byte
+}
+
+// copyPlans caches the deep-copy plan of each element type.
+var copyPlans sync.Map // reflect.Type -> []deepField, or error
+
+// copyPlan lists every []byte and string in values of type t, or reports the
+// first field that cannot be deep-copied.
+func copyPlan(t reflect.Type) ([]deepField, error) {
+	if v, ok := copyPlans.Load(t); ok {
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		return v.([]deepField), nil
+	}
+	var plan []deepField
+	err := walkDeep(t, 0, t.String(), &plan)
+	if err != nil {
+		copyPlans.Store(t, err)
+		return nil, err
+	}
+	copyPlans.Store(t, plan)
+	return plan, nil
+}
+
+func walkDeep(t reflect.Type, off uintptr, path string, plan *[]deepField) error {
+	switch {
+	case !typeHasPointers(t):
+		return nil
+	case t.Kind() == reflect.String:
+		*plan = append(*plan, deepField{off: off, str: true})
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		*plan = append(*plan, deepField{off: off})
+	case t.Kind() == reflect.Array:
+		for i := 0; i < t.Len(); i++ {
+			if err := walkDeep(t.Elem(), off+uintptr(i)*t.Elem().Size(), fmt.Sprintf("%s[%d]", path, i), plan); err != nil {
+				return err
+			}
+		}
+	case t.Kind() == reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if err := walkDeep(f.Type, off+f.Offset, path+"."+f.Name, plan); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s of type %s", ErrUnsupportedField, path, t)
+	}
+	return nil
+}
+
+// DeepAppendSlice appends copies of objs like AppendSlice, and also copies
+// every []byte and string they contain into bytes, rewriting the copies to
+// point at arena memory. The appended elements retain nothing the caller
+// owns. T may contain pointers only in []byte and string fields, at any
+// depth of structs and arrays; other pointer kinds fail with
+// ErrUnsupportedField before anything is reserved. Nil slices stay nil.
+//
+// The byte storage is claimed with one reservation and every byte of it is
+// overwritten, so stale bytes left in bytes by a Reset never show through.
+// If either reservation does not fit, nothing stays claimed and the error
+// is a *CapacityError wrapping ErrArenaFull.
+func (a *AtomicArena[T]) DeepAppendSlice(objs []T, bytes *ByteArena) ([]T, error) {
+	plan, err := copyPlan(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	var total int
+	for i := range objs {
+		p := unsafe.Pointer(&objs[i])
+		for _, f := range plan {
+			if f.str {
+				total += len(*(*string)(unsafe.Add(p, f.off)))
+			} else {
+				total += len(*(*[]byte)(unsafe.Add(p, f.off)))
+			}
+		}
+	}
+	n := uintptr(len(objs))
+	start, err := a.reserve(n)
+	if err != nil {
+		return nil, a.allocErr(err, start, n)
+	}
+	buf, err := bytes.AllocBytes(total)
+	if err != nil {
+		a.commitPartial(start, n, 0)
+		return nil, err
+	}
+	seg := a.raw[start : start+n]
+	copy(seg, objs)
+	for i := range seg {
+		p := unsafe.Pointer(&seg[i])
+		for _, f := range plan {
+			if f.str {
+				s := (*string)(unsafe.Add(p, f.off))
+				if len(*s) > 0 {
+					k := copy(buf, *s)
+					*s = unsafe.String(&buf[0], k)
+					buf = buf[k:]
+				}
+				continue
+			}
+			b := (*[]byte)(unsafe.Add(p, f.off))
+			if *b != nil {
+				k := copy(buf, *b)
+				*b = buf[:k:k]
+				buf = buf[k:]
+			}
+		}
+	}
+	a.publish(start, start+n)
+	a.commit(n)
+	return seg, nil
+}
@@ -0,0 +1,87 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+type deepPacket struct {
+	ID      int
+	Data    []byte
+	Src     string
+	Headers [2][]byte
+	Meta    struct{ Tag string }
+}
+
+// inArena reports whether p points into the byte arena's storage
+func inArena(b *ByteArena, p unsafe.Pointer) bool {
+	raw := b.arena.raw
+	base := uintptr(unsafe.Pointer(&raw[0]))
+	return uintptr(p) >= base && uintptr(p) < base+uintptr(len(raw))
+}
+
+// TestDeepAppendSlice verifies the appended copies retain no caller memory
+func TestDeepAppendSlice(t *testing.T) {
+	arena := NewAtomicArena[deepPacket](4)
+	bytes := NewByteArena(1024)
+	data := []byte("payload")
+	h0 := []byte("h0")
+	src := string([]byte("10.0.0.1"))
+	objs := []deepPacket{
+		{ID: 1, Data: data, Src: src, Headers: [2][]byte{h0, nil}},
+		{ID: 2, Data: []byte{}, Meta: struct{ Tag string }{"t"}},
+	}
+	seg, err := arena.DeepAppendSlice(objs, bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// mutate the caller's buffers after the append
+	copy(data, "XXXXXXX")
+	h0[0] = 'Z'
+
+	p := seg[0]
+	if string(p.Data) != "payload" || p.Src != "10.0.0.1" || string(p.Headers[0]) != "h0" || p.Headers[1] != nil {
+		t.Fatalf("deep copy affected by caller mutation: %+v", p)
+	}
+	if !inArena(bytes, unsafe.Pointer(&p.Data[0])) || !inArena(bytes, unsafe.Pointer(unsafe.StringData(p.Src))) ||
+		!inArena(bytes, unsafe.Pointer(&p.Headers[0][0])) || !inArena(bytes, unsafe.Pointer(unsafe.StringData(seg[1].Meta.Tag))) {
+		t.Fatal("copied fields do not point into the byte arena")
+	}
+	if seg[1].Data == nil || len(seg[1].Data) != 0 {
+		t.Fatalf("empty slice not preserved: %v", seg[1].Data)
+	}
+	if cap(p.Data) != len(p.Data) {
+		t.Fatalf("copied slice capacity not clipped: %d", cap(p.Data))
+	}
+	if bytes.Len() != uintptr(len("payload")+len("10.0.0.1")+len("h0")+len("t")) {
+		t.Fatalf("unexpected byte usage %d", bytes.Len())
+	}
+}
+
+// TestDeepAppendSliceErrors covers unsupported types and a full byte arena
+func TestDeepAppendSliceErrors(t *testing.T) {
+	type withPtr struct {
+		Name string
+		Next *int
+	}
+	if _, err := NewAtomicArena[withPtr](1).DeepAppendSlice([]withPtr{{}}, NewByteArena(8)); !errors.Is(err, ErrUnsupportedField) {
+		t.Fatalf("expected ErrUnsupportedField, got %v", err)
+	}
+	arena := NewAtomicArena[deepPacket](4)
+	_, err := arena.DeepAppendSlice([]deepPacket{{Data: make([]byte, 16)}}, NewByteArena(8))
+	if !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if arena.Len() != 0 {
+		t.Fatalf("element slots not given back, len %d", arena.Len())
+	}
+	if err := arena.Reset(false); err != nil {
+		t.Fatalf("arena not quiescent: %v", err)
+	}
+	var ce *CapacityError
+	_, err = NewAtomicArena[deepPacket](1).DeepAppendSlice(make([]deepPacket, 2), NewByteArena(8))
+	if !errors.As(err, &ce) || ce.Requested != 2 || ce.Capacity != 1 {
+		t.Fatalf("expected a *CapacityError for 2 slots of 1, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// ErrAlreadyFreed is returned by FreeSlot and FreePtr for a slot whose
+// element has already been released.
+var ErrAlreadyFreed = errors.New("atomicarena: slot already freed")
+
+// WithDestructor registers fn to release whatever an element owns, such as a
+// file descriptor or a cgo buffer. It is called exactly once for every
+// committed element when the element leaves the arena: when it is
+// tombstoned or freed individually, and for the live elements of the arena
+// on Free, Reset (with or without release), TryShrinkTo, Unreserve and
+// BatchedArena.Drain, always before the slot is zeroed or reused. Slots
+// handed out by Reserve count as committed, so fn must tolerate zero values.
+// Drain copies values out before destroying them. T must match the arena's
+// element type, or the constructor fails with ErrInvalidOptions.
+func WithDestructor[T any](fn func(*T)) Option {
+	return func(o *options) { o.destructor = fn }
+}
+
+// WithCloseOnRelease is WithDestructor with Close as the destructor, for
+// element types that implement io.Closer (with a value or pointer receiver).
+// Errors from Close are joined and returned by the call that released the
+// elements: Reset, Free, Tombstone, FreeSlot or FreePtr.
+func WithCloseOnRelease() Option {
+	return func(o *options) { o.closeOnRelease = true }
+}
+
+// destructorFor builds the arena's destructor from validated options.
+func destructorFor[T any](o options) func(*T) error {
+	switch {
+	case o.closeOnRelease:
+		if _, ok := any(*new(T)).(io.Closer); ok || reflect.TypeFor[T]().Kind() == reflect.Interface {
+			return func(p *T) error {
+				if c, ok := any(*p).(io.Closer); ok {
+					return c.Close()
+				}
+				return nil
+			}
+		}
+		return func(p *T) error { return any(p).(io.Closer).Close() }
+	case o.destructor != nil:
+		fn := o.destructor.(func(*T))
+		return func(p *T) error {
+			fn(p)
+			return nil
+		}
+	}
+	return nil
+}
+
+// validateElem reports options that do not fit the element type T.
+func validateElem[T any](o options) error {
+	if o.destructor != nil {
+		if o.closeOnRelease {
+			return fmt.Errorf("%w: WithDestructor and WithCloseOnRelease are mutually exclusive", ErrInvalidOptions)
+		}
+		if _, ok := o.destructor.(func(*T)); !ok {
+			return fmt.Errorf("%w: destructor %T does not take *%s", ErrInvalidOptions, o.destructor, reflect.TypeFor[T]())
+		}
+	}
+	if o.closeOnRelease {
+		t := reflect.TypeFor[T]()
+		closer := reflect.TypeFor[io.Closer]()
+		if t.Kind() != reflect.Interface && !t.Implements(closer) && !reflect.PointerTo(t).Implements(closer) {
+			return fmt.Errorf("%w: WithCloseOnRelease: %s does not implement io.Closer", ErrInvalidOptions, t)
+		}
+	}
+	return nil
+}
+
+// destroyLive runs the destructor on every live slot in [lo, hi), marking
+// each dead first so no element is destroyed twice.
+func (a *AtomicArena[T]) destroyLive(lo, hi uintptr) error {
+	var errs []error
+	for i := lo; i < hi; i++ {
+		if a.markDead(i) {
+			if err := a.dtor(&a.raw[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FreeSlot releases the element in slot i on its own: it is tombstoned, its
+// destructor runs, and the slot is zeroed. It returns ErrAlreadyFreed if the
+// slot is already dead, ErrOutOfRange if it is not allocated, and ErrFrozen
+// on a frozen arena. The slot itself is reclaimed by Compact or Reset.
+func (a *AtomicArena[T]) FreeSlot(i uintptr) error {
+	if a.Frozen() {
+		return a.frozenErr()
+	}
+	if i >= a.Len() {
+		return fmt.Errorf("%w: free %d, len %d", ErrOutOfRange, i, a.Len())
+	}
+	if !a.markDead(i) {
+		return fmt.Errorf("%w: slot %d", ErrAlreadyFreed, i)
+	}
+	var err error
+	if a.dtor != nil {
+		err = a.dtor(&a.raw[i])
+	}
+	a.zeroRange(i, i+1)
+	return err
+}
+
+// FreePtr is FreeSlot for a pointer returned by the arena. It returns
+// ErrForeignSegment if p does not point into the arena's storage.
+func (a *AtomicArena[T]) FreePtr(p *T) error {
+	i, ok := a.indexOf(p)
+	if !ok {
+		return ErrForeignSegment
+	}
+	return a.FreeSlot(i)
+}
+
+// indexOf returns the slot p points to. Every zero-sized element shares one
+// address, so such pointers cannot be mapped back to a slot.
+func (a *AtomicArena[T]) indexOf(p *T) (uintptr, bool) {
+	size := unsafe.Sizeof(*p)
+	if p == nil || size == 0 || len(a.raw) == 0 {
+		return 0, false
+	}
+	off := uintptr(unsafe.Pointer(p)) - uintptr(unsafe.Pointer(unsafe.SliceData(a.raw)))
+	i := off / size
+	if i >= uintptr(len(a.raw)) || off%size != 0 || &a.raw[i] != p {
+		return 0, false
+	}
+	return i, true
+}
+
+// free implements Free. Without a destructor it just zeroes the allocated
+// storage. With one, it waits for in-flight writes and holds off new
+// reservations while it destroys the live elements, which stay tombstoned
+// so later releases don't destroy them again.
+func (a *AtomicArena[T]) free() (uintptr, error) {
+	if a.dtor == nil {
+		n := a.Len()
+		a.zeroRange(0, n)
+		if a.ops != nil {
+			a.ops.record(OpFree, 0, n, false)
+		}
+		return n, nil
+	}
+	for {
+		c := a.count.Load()
+		if c&frozenBit != 0 {
+			return 0, a.frozenErr()
+		}
+		n := c & countMask
+		if c&busyBit != 0 || a.done.Load() != n {
+			runtime.Gosched()
+			continue
+		}
+		if !a.count.CompareAndSwap(c, c|busyBit) {
+			continue
+		}
+		err := a.destroyLive(0, n)
+		a.zeroRange(0, n)
+		a.count.Store(c)
+		if a.ops != nil {
+			a.ops.record(OpFree, 0, n, false)
+		}
+		return n, err
+	}
+}
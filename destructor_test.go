@@ -0,0 +1,262 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// resource records how often each value was destroyed.
+type resource struct {
+	ID int
+}
+
+// destroyCounter is a destructor that counts calls per element ID.
+type destroyCounter struct {
+	mu    sync.Mutex
+	calls map[int]int
+}
+
+func newDestroyCounter() *destroyCounter {
+	return &destroyCounter{calls: make(map[int]int)}
+}
+
+func (d *destroyCounter) destroy(r *resource) {
+	d.mu.Lock()
+	d.calls[r.ID]++
+	d.mu.Unlock()
+}
+
+// check fails unless exactly the IDs in want were each destroyed once.
+func (d *destroyCounter) check(t *testing.T, want ...int) {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.calls) != len(want) {
+		t.Fatalf("expected %d destroyed elements, got %v", len(want), d.calls)
+	}
+	for _, id := range want {
+		if d.calls[id] != 1 {
+			t.Fatalf("expected element %d destroyed once, got %d (%v)", id, d.calls[id], d.calls)
+		}
+	}
+	clear(d.calls)
+}
+
+// TestDestructorReleasePaths counts destructor calls on every release path
+func TestDestructorReleasePaths(t *testing.T) {
+	d := newDestroyCounter()
+	a := NewAtomicArena[resource](16, WithDestructor(d.destroy))
+	fill := func(ids ...int) {
+		for _, id := range ids {
+			if _, err := a.Alloc(resource{ID: id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	fill(1, 2, 3)
+	if err := a.Reset(true); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t, 1, 2, 3)
+
+	fill(4, 5)
+	if err := a.Reset(false); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t, 4, 5)
+
+	fill(6, 7)
+	if err := a.Free(); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t, 6, 7)
+	// freed elements are not destroyed again by the next Reset
+	if err := a.Reset(true); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t)
+
+	fill(8, 9, 10)
+	p, _ := a.Get(2)
+	if err := a.FreeSlot(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.FreePtr(p); err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != 0 {
+		t.Fatalf("expected FreePtr to zero the slot, got %+v", *p)
+	}
+	d.check(t, 8, 10)
+	if err := a.FreeSlot(0); !errors.Is(err, ErrAlreadyFreed) {
+		t.Fatalf("expected ErrAlreadyFreed, got %v", err)
+	}
+	if err := a.FreeSlot(5); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	if err := a.FreePtr(&resource{}); !errors.Is(err, ErrForeignSegment) {
+		t.Fatalf("expected ErrForeignSegment, got %v", err)
+	}
+
+	fill(11)
+	if err := a.Tombstone(3); err != nil {
+		t.Fatal(err)
+	}
+	a.Tombstone(3)
+	d.check(t, 11)
+	a.Compact()
+	d.check(t)
+	if err := a.Reset(true); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t, 9)
+
+	fill(12, 13, 14)
+	if err := a.TryShrinkTo(1); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t, 13, 14)
+	seg, _ := a.Reserve(2)
+	seg[0].ID, seg[1].ID = 15, 16
+	if err := a.Unreserve(seg); err != nil {
+		t.Fatal(err)
+	}
+	d.check(t, 15, 16)
+}
+
+// TestDestructorDrain covers BatchedArena.Drain, whose unused batch slots
+// must not reach the destructor
+func TestDestructorDrain(t *testing.T) {
+	d := newDestroyCounter()
+	b := NewBatchedArena[resource](64, 8, WithDestructor(d.destroy))
+	c := b.NewBatch()
+	for id := 1; id <= 3; id++ {
+		if _, err := c.Alloc(resource{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := b.Drain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 3 || out[2].ID != 3 {
+		t.Fatalf("expected the drained copies, got %+v", out)
+	}
+	d.check(t, 1, 2, 3)
+}
+
+// TestDestructorConcurrent fills a BatchedArena from several writers, then
+// releases it with concurrent Drain, Free, Tombstone and FreeSlot calls, and
+// checks that every element is destroyed exactly once; run with -race
+func TestDestructorConcurrent(t *testing.T) {
+	const writers, perWriter, rounds = 4, 200, 20
+	d := newDestroyCounter()
+	b := NewBatchedArena[resource](writers*perWriter, 16, WithDestructor(d.destroy))
+	a := b.arena
+	batches := make([]*Batch[resource], writers)
+	for g := range batches {
+		batches[g] = b.NewBatch()
+	}
+	var next atomic.Int64
+	for r := 0; r < rounds; r++ {
+		var wg sync.WaitGroup
+		for _, c := range batches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perWriter/2; i++ {
+					if _, err := c.Alloc(resource{ID: int(next.Add(1))}); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				c.Flush()
+			}()
+		}
+		wg.Wait()
+		n := a.Len()
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				switch g {
+				case 0:
+					for i := uintptr(0); i < n; i += 3 {
+						a.Tombstone(i)
+					}
+				case 1:
+					for i := uintptr(1); i < n; i += 2 {
+						a.FreeSlot(i)
+					}
+				case 2:
+					if r%2 == 0 {
+						a.Free()
+					}
+				}
+				if _, err := b.Drain(); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	ids := make([]int, next.Load())
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	d.check(t, ids...)
+}
+
+// handle is an io.Closer whose Close can fail.
+type handle struct {
+	id     int
+	closed *atomic.Int32
+}
+
+func (h handle) Close() error {
+	h.closed.Add(1)
+	if h.id%2 == 1 {
+		return fmt.Errorf("close handle %d", h.id)
+	}
+	return nil
+}
+
+// TestCloseOnRelease checks that Close errors are joined and returned by Reset
+func TestCloseOnRelease(t *testing.T) {
+	var closed atomic.Int32
+	a := NewAtomicArena[handle](8, WithCloseOnRelease())
+	for id := 0; id < 4; id++ {
+		a.Alloc(handle{id: id, closed: &closed})
+	}
+	err := a.Reset(true)
+	if err == nil || err.Error() != "close handle 1\nclose handle 3" {
+		t.Fatalf("expected the joined Close errors, got %v", err)
+	}
+	if closed.Load() != 4 || a.Len() != 0 {
+		t.Fatalf("expected 4 closes and an empty arena, got %d, len %d", closed.Load(), a.Len())
+	}
+	a.Alloc(handle{id: 5, closed: &closed})
+	if err := a.FreeSlot(0); err == nil || err.Error() != "close handle 5" {
+		t.Fatalf("expected FreeSlot to return the Close error, got %v", err)
+	}
+}
+
+// TestDestructorOptions rejects destructors that do not fit the element type
+func TestDestructorOptions(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"type":   {WithDestructor(func(*int) {})},
+		"closer": {WithCloseOnRelease()},
+		"both":   {WithDestructor(func(*resource) {}), WithCloseOnRelease()},
+	} {
+		if _, err := New[resource](4, opts...); !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%s: expected ErrInvalidOptions, got %v", name, err)
+		}
+	}
+	if _, err := New[*handle](4, WithCloseOnRelease()); err != nil {
+		t.Fatalf("expected a pointer to a Closer to be accepted, got %v", err)
+	}
+}
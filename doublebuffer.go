@@ -0,0 +1,62 @@
+package atomicarena
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DoubleBuffer pairs two arenas so producers can keep allocating into the
+// active one while a flusher drains the other. Swap redirects new allocations
+// and hands back the previously active arena once every allocation that was
+// already in progress on it has finished.
+type DoubleBuffer[T any] struct {
+	arenas   [2]*AtomicArena[T]
+	inflight [2]atomic.Int64 // allocations currently running against each side
+	active   atomic.Uint32
+	swapMu   sync.Mutex // serializes Swap
+}
+
+// NewDoubleBuffer creates a DoubleBuffer whose two arenas each hold maxElems elements.
+func NewDoubleBuffer[T any](maxElems uintptr) *DoubleBuffer[T] {
+	return &DoubleBuffer[T]{
+		arenas: [2]*AtomicArena[T]{NewAtomicArena[T](maxElems), NewAtomicArena[T](maxElems)},
+	}
+}
+
+// Alloc stores obj in the active arena.
+func (d *DoubleBuffer[T]) Alloc(obj T) (*T, error) {
+	for {
+		side := d.active.Load()
+		d.inflight[side].Add(1)
+		if d.active.Load() != side {
+			// lost a race with Swap; retry on the new active side
+			d.inflight[side].Add(-1)
+			continue
+		}
+		p, err := d.arenas[side].Alloc(obj)
+		d.inflight[side].Add(-1)
+		return p, err
+	}
+}
+
+// Active returns the arena currently receiving allocations.
+func (d *DoubleBuffer[T]) Active() *AtomicArena[T] {
+	return d.arenas[d.active.Load()]
+}
+
+// Swap makes the inactive arena active and returns the previously active one
+// after all in-flight allocations on it have completed. No allocation lands in
+// the returned arena after Swap returns. The caller must have drained and
+// reset the arena that becomes active (the one returned by the previous Swap)
+// before calling Swap again.
+func (d *DoubleBuffer[T]) Swap() *AtomicArena[T] {
+	d.swapMu.Lock()
+	defer d.swapMu.Unlock()
+	old := d.active.Load()
+	d.active.Store(1 - old)
+	for d.inflight[old].Load() != 0 {
+		runtime.Gosched()
+	}
+	return d.arenas[old]
+}
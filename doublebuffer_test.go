@@ -0,0 +1,81 @@
+package atomicarena
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestDoubleBufferSwap ensures Swap redirects allocations to the other arena
+func TestDoubleBufferSwap(t *testing.T) {
+	d := NewDoubleBuffer[int](4)
+	_, _ = d.Alloc(1)
+	first := d.Swap()
+	if first.Len() != 1 || first.raw[0] != 1 {
+		t.Fatalf("unexpected flushed arena contents: len=%d", first.Len())
+	}
+	_, _ = d.Alloc(2)
+	if first.Len() != 1 {
+		t.Fatal("allocation landed in swapped-out arena")
+	}
+	if d.Active().Len() != 1 || d.Active().raw[0] != 2 {
+		t.Fatal("allocation did not land in new active arena")
+	}
+}
+
+// TestDoubleBufferAccounting runs producers against periodic swaps and checks
+// every successful allocation is flushed exactly once.
+func TestDoubleBufferAccounting(t *testing.T) {
+	const (
+		producers = 8
+		perProd   = 5000
+	)
+	d := NewDoubleBuffer[int](1 << 16)
+	var stored [producers * perProd]atomic.Bool
+	var wg sync.WaitGroup
+	var stop atomic.Bool
+	wg.Add(producers)
+	for p := 0; p < producers; p++ {
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProd; i++ {
+				v := p*perProd + i
+				if _, err := d.Alloc(v); err == nil {
+					stored[v].Store(true)
+				}
+			}
+		}(p)
+	}
+
+	seen := make([]int, producers*perProd)
+	flush := func(a *AtomicArena[int]) {
+		a.Range(func(_ uintptr, v *int) bool {
+			seen[*v]++
+			return true
+		})
+		a.Reset(false)
+	}
+	done := make(chan struct{})
+	go func() {
+		for !stop.Load() {
+			flush(d.Swap())
+		}
+		close(done)
+	}()
+	wg.Wait()
+	stop.Store(true)
+	<-done
+	// drain both sides
+	flush(d.Swap())
+	flush(d.Swap())
+
+	for v := range seen {
+		want := 0
+		if stored[v].Load() {
+			want = 1
+		}
+		if seen[v] != want {
+			t.Fatalf("value %d flushed %d times, want %d", v, seen[v], want)
+		}
+	}
+}
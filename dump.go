@@ -0,0 +1,125 @@
+package atomicarena
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+)
+
+// settleSpins bounds how long committedPrefix waits for in-flight writes
+// before falling back to the pointer mirror.
+const settleSpins = 64
+
+// String summarizes the arena for debugging, e.g.
+// "AtomicArena[main.Foo] len=3 cap=10 (30.0%) epoch=2".
+// It implements fmt.Stringer.
+func (a *AtomicArena[T]) String() string {
+	n, c := a.Len(), a.Cap()
+	used := 0.0
+	if c > 0 {
+		used = 100 * float64(n) / float64(c)
+	}
+	return fmt.Sprintf("AtomicArena[%s] len=%d cap=%d (%.1f%%) epoch=%d", reflect.TypeFor[T](), n, c, used, a.Epoch())
+}
+
+// committedPrefix returns how many leading slots, at most limit, are safe to
+// read while writers may be active. It first waits briefly for the arena to
+// settle: if the count is unchanged across a load of done that matches it,
+// every reserved slot has been written. Failing that, it walks the pointer
+// mirror, whose entries are published only after their slot is written. It
+// reports false if writes are in flight and the arena has no mirror.
+// Slots handed out by Reserve count as written as soon as they are returned,
+// so their callers must not be filling them while the arena is dumped.
+func (a *AtomicArena[T]) committedPrefix(limit uintptr) (uintptr, bool) {
+	for i := 0; i < settleSpins; i++ {
+		e, n := a.Epoch(), a.Len()
+		if a.done.Load() == n && a.Len() == n && a.Epoch() == e {
+			return min(n, limit), true
+		}
+		runtime.Gosched()
+	}
+	if a.ptrs == nil {
+		return 0, false
+	}
+	n := min(a.Len(), limit)
+	for i := uintptr(0); i < n; i++ {
+		if a.ptrs[i].Load() == nil {
+			return i, true
+		}
+	}
+	return n, true
+}
+
+// dumpLimit converts Dump's limit argument; a negative limit means no limit.
+func dumpLimit(limit int) uintptr {
+	if limit < 0 {
+		return ^uintptr(0)
+	}
+	return uintptr(limit)
+}
+
+// Dump writes the String summary followed by up to limit committed elements,
+// one per line with their index and address, formatted with %+v. A negative
+// limit writes every committed element. Tombstoned slots are skipped but still
+// count against the limit. Only slots whose writes have completed are read, so
+// Dump is safe alongside Alloc and AppendSlice; it returns ErrNotQuiescent if
+// writes stay in flight on an arena built WithoutPointerMirror.
+func (a *AtomicArena[T]) Dump(w io.Writer, limit int) error {
+	n, ok := a.committedPrefix(dumpLimit(limit))
+	if !ok {
+		return ErrNotQuiescent
+	}
+	if _, err := fmt.Fprintln(w, a.String()); err != nil {
+		return err
+	}
+	for i := uintptr(0); i < n; i++ {
+		if a.tombstoned(i) {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "[%d] %p %+v\n", i, &a.raw[i], a.raw[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpDoc is the document DumpJSON writes.
+type dumpDoc[T any] struct {
+	Type     string           `json:"type"`
+	Len      uintptr          `json:"len"`
+	Cap      uintptr          `json:"cap"`
+	Epoch    uint64           `json:"epoch"`
+	Elements []dumpElement[T] `json:"elements"`
+}
+
+type dumpElement[T any] struct {
+	Index uintptr `json:"index"`
+	Addr  string  `json:"addr"`
+	Value T       `json:"value"`
+}
+
+// DumpJSON is Dump for structured logging: it writes a single JSON object
+// holding the arena's type, len, cap and epoch, and an elements array of
+// {index, addr, value} objects. Values are encoded with encoding/json.
+func (a *AtomicArena[T]) DumpJSON(w io.Writer, limit int) error {
+	n, ok := a.committedPrefix(dumpLimit(limit))
+	if !ok {
+		return ErrNotQuiescent
+	}
+	doc := dumpDoc[T]{
+		Type:     reflect.TypeFor[T]().String(),
+		Len:      a.Len(),
+		Cap:      a.Cap(),
+		Epoch:    a.Epoch(),
+		Elements: make([]dumpElement[T], 0, n),
+	}
+	for i := uintptr(0); i < n; i++ {
+		if a.tombstoned(i) {
+			continue
+		}
+		doc.Elements = append(doc.Elements, dumpElement[T]{Index: i, Addr: fmt.Sprintf("%p", &a.raw[i]), Value: a.raw[i]})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
@@ -0,0 +1,131 @@
+package atomicarena
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type dumpItem struct {
+	Name string
+	N    int
+}
+
+// TestString checks the summary line
+func TestString(t *testing.T) {
+	a := NewAtomicArena[dumpItem](10)
+	a.AppendSlice([]dumpItem{{"a", 1}, {"b", 2}, {"c", 3}})
+	a.Reset(false)
+	a.AppendSlice([]dumpItem{{"a", 1}, {"b", 2}, {"c", 3}})
+	want := "AtomicArena[atomicarena.dumpItem] len=3 cap=10 (30.0%) epoch=1"
+	if got := a.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := fmt.Sprint(NewAtomicArena[int](0)); !strings.Contains(got, "(0.0%)") {
+		t.Fatalf("expected 0%% utilization for an empty arena, got %q", got)
+	}
+}
+
+// TestDump asserts Dump prints known values and respects the limit
+func TestDump(t *testing.T) {
+	a := NewAtomicArena[dumpItem](8)
+	for i := 0; i < 5; i++ {
+		a.Alloc(dumpItem{fmt.Sprintf("item%d", i), i * 10})
+	}
+	a.Tombstone(1)
+
+	var buf bytes.Buffer
+	if err := a.Dump(&buf, 3); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 elements, got %d lines:\n%s", len(lines), out)
+	}
+	if !strings.HasPrefix(lines[0], "AtomicArena[atomicarena.dumpItem] len=5") {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if !strings.Contains(out, "{Name:item0 N:0}") || !strings.Contains(out, "{Name:item2 N:20}") {
+		t.Fatalf("expected %%+v formatted elements, got:\n%s", out)
+	}
+	if strings.Contains(out, "item1") || strings.Contains(out, "item3") {
+		t.Fatalf("expected tombstoned and over-limit elements to be omitted, got:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[2], "[2] 0x") {
+		t.Fatalf("expected index and address, got %q", lines[2])
+	}
+
+	buf.Reset()
+	if err := a.Dump(&buf, -1); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "item4") {
+		t.Fatalf("expected a negative limit to dump everything, got:\n%s", buf.String())
+	}
+}
+
+// TestDumpJSON decodes the structured dump
+func TestDumpJSON(t *testing.T) {
+	a := NewAtomicArena[dumpItem](8)
+	for i := 0; i < 4; i++ {
+		a.Alloc(dumpItem{fmt.Sprintf("item%d", i), i})
+	}
+	var buf bytes.Buffer
+	if err := a.DumpJSON(&buf, 2); err != nil {
+		t.Fatalf("DumpJSON failed: %v", err)
+	}
+	var doc struct {
+		Type     string
+		Len, Cap uintptr
+		Elements []struct {
+			Index uintptr
+			Addr  string
+			Value dumpItem
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if doc.Type != "atomicarena.dumpItem" || doc.Len != 4 || doc.Cap != 8 {
+		t.Fatalf("unexpected header %+v", doc)
+	}
+	if len(doc.Elements) != 2 || doc.Elements[1].Index != 1 || doc.Elements[1].Value.Name != "item1" {
+		t.Fatalf("unexpected elements %+v", doc.Elements)
+	}
+	if !strings.HasPrefix(doc.Elements[0].Addr, "0x") {
+		t.Fatalf("expected an address, got %q", doc.Elements[0].Addr)
+	}
+}
+
+// TestDumpConcurrent runs Dump alongside writers; run with -race
+func TestDumpConcurrent(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithoutPointerMirror()}} {
+		a := NewAtomicArena[dumpItem](4096, opts...)
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1024; i++ {
+					a.Alloc(dumpItem{"w", i})
+				}
+			}()
+		}
+		for i := 0; i < 20; i++ {
+			if err := a.Dump(&bytes.Buffer{}, 64); err != nil && err != ErrNotQuiescent {
+				t.Fatalf("Dump failed: %v", err)
+			}
+			if err := a.DumpJSON(&bytes.Buffer{}, 64); err != nil && err != ErrNotQuiescent {
+				t.Fatalf("DumpJSON failed: %v", err)
+			}
+		}
+		wg.Wait()
+		if err := a.Dump(&bytes.Buffer{}, -1); err != nil {
+			t.Fatalf("Dump of a quiescent arena failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNoFreeEntities is returned by Spawn when every entity index is in use.
+var ErrNoFreeEntities = errors.New("atomicarena: no free entities")
+
+// Entity is a generational entity ID: the low 32 bits are a slot index shared
+// by every component, the high 32 bits the generation of that slot. Reusing
+// an index bumps its generation, so IDs of despawned entities go stale.
+type Entity uint64
+
+// Index returns the slot index components store the entity's values at.
+func (e Entity) Index() uint32 { return uint32(e) }
+
+// Generation returns the generation the ID was issued in.
+func (e Entity) Generation() uint32 { return uint32(e >> 32) }
+
+// EntityAllocator hands out generational entity IDs over a fixed number of
+// slots. A slot's generation is odd while its entity is alive and even while
+// the slot is free. All methods are safe for concurrent use.
+type EntityAllocator struct {
+	free *Stack[uint32]
+	gens []atomic.Uint32
+
+	mu    sync.RWMutex
+	hooks []func(idx uint32) // run by Despawn for every registered component
+}
+
+// NewEntityAllocator creates an allocator for up to capacity live entities.
+func NewEntityAllocator(capacity uint32) *EntityAllocator {
+	e := &EntityAllocator{
+		free: NewStack[uint32](uintptr(capacity)),
+		gens: make([]atomic.Uint32, capacity),
+	}
+	// push in reverse so the first spawns take the lowest indices
+	for i := capacity; i > 0; i-- {
+		e.free.Push(i - 1)
+	}
+	return e
+}
+
+// Spawn returns the ID of a new live entity, or ErrNoFreeEntities.
+func (e *EntityAllocator) Spawn() (Entity, error) {
+	idx, ok := e.free.Pop()
+	if !ok {
+		return 0, ErrNoFreeEntities
+	}
+	gen := e.gens[idx].Add(1)
+	return Entity(uint64(gen)<<32 | uint64(idx)), nil
+}
+
+// Alive reports whether id refers to a live entity.
+func (e *EntityAllocator) Alive(id Entity) bool {
+	idx := id.Index()
+	return int(idx) < len(e.gens) && id.Generation()&1 == 1 && e.gens[idx].Load() == id.Generation()
+}
+
+// Despawn destroys the entity, clears its values in every component and
+// frees its index. It reports false if id is stale.
+func (e *EntityAllocator) Despawn(id Entity) bool {
+	idx, gen := id.Index(), id.Generation()
+	if int(idx) >= len(e.gens) || gen&1 == 0 || !e.gens[idx].CompareAndSwap(gen, gen+1) {
+		return false
+	}
+	e.mu.RLock()
+	for _, hook := range e.hooks {
+		hook(idx)
+	}
+	e.mu.RUnlock()
+	e.free.Push(idx)
+	return true
+}
+
+// Component is an arena of values of type T addressed by entity ID. Each slot
+// remembers the generation it was set for, so stale IDs miss. Set, Get and
+// Remove are safe for concurrent use on different entities.
+type Component[T any] struct {
+	ents *EntityAllocator
+	vals []T
+	gens []atomic.Uint32 // generation the slot's value belongs to, 0 if empty
+}
+
+// NewComponent creates a component with one slot per entity index of ents
+// and registers it so Despawn clears its values.
+func NewComponent[T any](ents *EntityAllocator) *Component[T] {
+	n := uintptr(len(ents.gens))
+	vals, _ := NewAtomicArena[T](n).Reserve(n)
+	c := &Component[T]{ents: ents, vals: vals, gens: make([]atomic.Uint32, n)}
+	ents.mu.Lock()
+	ents.hooks = append(ents.hooks, c.clear)
+	ents.mu.Unlock()
+	return c
+}
+
+// Set stores v for the entity and returns a pointer to it. It reports false
+// if id is stale.
+func (c *Component[T]) Set(id Entity, v T) (*T, bool) {
+	if !c.ents.Alive(id) {
+		return nil, false
+	}
+	idx := id.Index()
+	c.vals[idx] = v
+	c.gens[idx].Store(id.Generation())
+	return &c.vals[idx], true
+}
+
+// Get returns the entity's value, or false if it has none or id is stale.
+func (c *Component[T]) Get(id Entity) (*T, bool) {
+	idx := id.Index()
+	if int(idx) >= len(c.gens) || c.gens[idx].Load() != id.Generation() || !c.ents.Alive(id) {
+		return nil, false
+	}
+	return &c.vals[idx], true
+}
+
+// Remove clears the entity's value. It reports false if there was none.
+func (c *Component[T]) Remove(id Entity) bool {
+	idx := id.Index()
+	if int(idx) >= len(c.gens) || !c.gens[idx].CompareAndSwap(id.Generation(), 0) {
+		return false
+	}
+	var zero T
+	c.vals[idx] = zero
+	return true
+}
+
+func (c *Component[T]) clear(idx uint32) {
+	if c.gens[idx].Swap(0) != 0 {
+		var zero T
+		c.vals[idx] = zero
+	}
+}
@@ -0,0 +1,110 @@
+package atomicarena
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func ExampleComponent() {
+	ents := NewEntityAllocator(8)
+	pos := NewComponent[position](ents)
+	vel := NewComponent[velocity](ents)
+
+	a, _ := ents.Spawn()
+	b, _ := ents.Spawn()
+	pos.Set(a, position{0, 0})
+	vel.Set(a, velocity{1, 2})
+	pos.Set(b, position{5, 5})
+
+	// move every entity that has both components
+	for _, e := range []Entity{a, b} {
+		p, ok1 := pos.Get(e)
+		v, ok2 := vel.Get(e)
+		if ok1 && ok2 {
+			p.X += v.DX
+			p.Y += v.DY
+		}
+	}
+	pa, _ := pos.Get(a)
+	pb, _ := pos.Get(b)
+	fmt.Println(*pa, *pb)
+
+	ents.Despawn(a)
+	_, ok := pos.Get(a)
+	fmt.Println("stale id hits:", ok)
+
+	c, _ := ents.Spawn()
+	_, ok = vel.Get(c)
+	fmt.Println("reused index:", c.Index() == a.Index(), "inherits velocity:", ok)
+	// Output:
+	// {1 2} {5 5}
+	// stale id hits: false
+	// reused index: true inherits velocity: false
+}
+
+// TestEntityGenerations ensures stale IDs miss everywhere
+func TestEntityGenerations(t *testing.T) {
+	ents := NewEntityAllocator(1)
+	hp := NewComponent[health](ents)
+	a, _ := ents.Spawn()
+	if _, err := ents.Spawn(); err != ErrNoFreeEntities {
+		t.Fatalf("expected ErrNoFreeEntities, got %v", err)
+	}
+	hp.Set(a, 10)
+	if !hp.Remove(a) || hp.Remove(a) {
+		t.Fatal("Remove should succeed exactly once")
+	}
+	hp.Set(a, 20)
+	if !ents.Despawn(a) || ents.Despawn(a) {
+		t.Fatal("Despawn should succeed exactly once")
+	}
+	b, _ := ents.Spawn()
+	if ents.Alive(a) || !ents.Alive(b) {
+		t.Fatal("wrong liveness after reuse")
+	}
+	if _, ok := hp.Set(a, 30); ok {
+		t.Fatal("Set accepted a stale ID")
+	}
+	if _, ok := hp.Get(b); ok {
+		t.Fatal("new entity inherited a despawned value")
+	}
+	if ents.Alive(Entity(b.Index())) || ents.Alive(Entity(1<<32|5)) {
+		t.Fatal("forged IDs reported alive")
+	}
+}
+
+// TestEntityConcurrent spawns and despawns entities from many goroutines
+func TestEntityConcurrent(t *testing.T) {
+	ents := NewEntityAllocator(64)
+	hp := NewComponent[health](ents)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				e, err := ents.Spawn()
+				if err != nil {
+					continue
+				}
+				if p, ok := hp.Get(e); ok {
+					t.Errorf("fresh entity %x already had value %d", e, *p)
+				}
+				hp.Set(e, health(w))
+				if p, ok := hp.Get(e); !ok || *p != health(w) {
+					t.Errorf("entity %x lost its value", e)
+				}
+				if !ents.Despawn(e) {
+					t.Errorf("despawn of live entity %x failed", e)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 64; i++ {
+		if _, err := ents.Spawn(); err != nil {
+			t.Fatalf("index leaked: %v", err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package atomicarena
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Equal reports whether a and b hold the same committed elements: the same
+// number of them, equal index by index under eq. A slot tombstoned in one
+// arena is equal only to a slot tombstoned in the other. A nil eq compares
+// elements with ==, which requires a comparable T and panics otherwise.
+// Each arena's committed count is read once, as by Committed, so the result
+// is consistent for that prefix while writers keep appending.
+func Equal[T any](a, b *AtomicArena[T], eq func(x, y *T) bool) bool {
+	n, m := a.Committed(), b.Committed()
+	if n != m {
+		return false
+	}
+	eq = equalFunc(eq)
+	for i := uintptr(0); i < n; i++ {
+		if !slotEqual(a, b, i, eq) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the indices below the shorter committed length at which a and
+// b differ, compared as by Equal, and whether the committed lengths differ.
+// Slots past the shorter length are not listed.
+func Diff[T any](a, b *AtomicArena[T], eq func(x, y *T) bool) (diffs []uintptr, lengthsDiffer bool) {
+	n, m := a.Committed(), b.Committed()
+	eq = equalFunc(eq)
+	for i := uintptr(0); i < min(n, m); i++ {
+		if !slotEqual(a, b, i, eq) {
+			diffs = append(diffs, i)
+		}
+	}
+	return diffs, n != m
+}
+
+// equalFunc returns eq, or == for a nil eq.
+func equalFunc[T any](eq func(x, y *T) bool) func(x, y *T) bool {
+	if eq != nil {
+		return eq
+	}
+	if t := reflect.TypeFor[T](); !t.Comparable() {
+		panic(fmt.Sprintf("atomicarena: Equal needs an eq func for non-comparable %s", t))
+	}
+	return func(x, y *T) bool { return any(*x) == any(*y) }
+}
+
+func slotEqual[T any](a, b *AtomicArena[T], i uintptr, eq func(x, y *T) bool) bool {
+	da, db := a.tombstoned(i), b.tombstoned(i)
+	if da || db {
+		return da == db
+	}
+	return eq(&a.raw[i], &b.raw[i])
+}
@@ -0,0 +1,70 @@
+package atomicarena
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestEqualDiff compares random arenas with planted differences
+func TestEqualDiff(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for r := 0; r < 50; r++ {
+		n := rng.Intn(200)
+		a, b := NewAtomicArena[position](256), NewAtomicArena[position](256)
+		for i := 0; i < n; i++ {
+			p := position{rng.Float64(), rng.Float64()}
+			a.Alloc(p)
+			b.Alloc(p)
+		}
+		if !Equal(a, b, nil) || !Equal(a, b, func(x, y *position) bool { return *x == *y }) {
+			t.Fatalf("run %d: expected identical arenas to be equal", r)
+		}
+		var want []uintptr
+		for i := 0; i < n; i++ {
+			if rng.Intn(10) == 0 {
+				b.raw[i].X++
+				want = append(want, uintptr(i))
+			}
+		}
+		extra := rng.Intn(3)
+		for i := 0; i < extra; i++ {
+			b.Alloc(position{})
+		}
+		diffs, lengths := Diff(a, b, nil)
+		if !slices.Equal(diffs, want) || lengths != (extra > 0) {
+			t.Fatalf("run %d: expected %v and a length mismatch %v, got %v, %v", r, want, extra > 0, diffs, lengths)
+		}
+		if Equal(a, b, nil) != (len(want) == 0 && extra == 0) {
+			t.Fatalf("run %d: Equal disagrees with Diff", r)
+		}
+	}
+}
+
+// TestEqualCustom covers a custom eq, tombstones and the non-comparable panic
+func TestEqualCustom(t *testing.T) {
+	a, b := NewAtomicArena[[]int](4), NewAtomicArena[[]int](4)
+	a.Alloc([]int{1, 2})
+	b.Alloc([]int{1, 2})
+	a.Alloc([]int{3})
+	b.Alloc([]int{4})
+	eq := func(x, y *[]int) bool { return slices.Equal(*x, *y) }
+	if diffs, lengths := Diff(a, b, eq); !slices.Equal(diffs, []uintptr{1}) || lengths {
+		t.Fatalf("expected slot 1 to differ, got %v, %v", diffs, lengths)
+	}
+	a.Tombstone(1)
+	if diffs, _ := Diff(a, b, eq); !slices.Equal(diffs, []uintptr{1}) {
+		t.Fatalf("expected a tombstone to differ from a live slot, got %v", diffs)
+	}
+	b.Tombstone(1)
+	if !Equal(a, b, eq) {
+		t.Fatalf("expected matching tombstones to be equal")
+	}
+	defer func() {
+		if r, _ := recover().(string); !strings.Contains(r, "non-comparable") {
+			t.Fatalf("expected a panic for a nil eq on []int, got %v", r)
+		}
+	}()
+	Equal(a, b, nil)
+}
@@ -0,0 +1,52 @@
+package atomicarena
+
+import "fmt"
+
+// BatchError is returned by FillBatches when a batch cannot be stored or
+// onBatch fails. Processed counts the input elements in the batches onBatch
+// accepted before the failure.
+type BatchError struct {
+	Processed int
+	Err       error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("atomicarena: batch after %d elements: %v", e.Processed, e.Err)
+}
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// FillBatches feeds objs through the arena in batches as large as the room
+// left: it copies as many elements as fit, calls onBatch with the
+// arena-resident segment, resets the arena and repeats until objs is used
+// up. The first batch only gets the room left by earlier allocations; every
+// later one gets the whole arena below the soft cap. The segment is valid
+// until onBatch returns. Empty input calls onBatch zero times.
+//
+// If onBatch returns an error, FillBatches stops without resetting, so the
+// failed batch is still in the arena, and returns a *BatchError wrapping it.
+// A batch that cannot be reserved, or a Reset that fails, is reported the
+// same way; in particular an arena that is already full is left as it is.
+// FillBatches is meant for a single writer; allocations made by others
+// between batches are discarded by the Reset.
+func (a *AtomicArena[T]) FillBatches(objs []T, onBatch func(batch []T) error) error {
+	done := 0
+	for done < len(objs) {
+		start, k, err := a.reserveUpTo(uintptr(len(objs) - done))
+		if err != nil {
+			return &BatchError{Processed: done, Err: a.allocErr(err, start, 1)}
+		}
+		seg := a.raw[start : start+k : start+k]
+		copy(seg, objs[done:])
+		a.publish(start, start+k)
+		a.commit(k)
+		if err := onBatch(seg); err != nil {
+			return &BatchError{Processed: done, Err: err}
+		}
+		done += int(k)
+		if err := a.Reset(false); err != nil {
+			return &BatchError{Processed: done, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestFillBatches delivers every element once, in order, including a final
+// partial batch
+func TestFillBatches(t *testing.T) {
+	in := make([]int, 23)
+	for i := range in {
+		in[i] = i
+	}
+	a := NewAtomicArena[int](5)
+	a.Alloc(-1)
+	var got []int
+	var sizes []int
+	err := a.FillBatches(in, func(batch []int) error {
+		if p, ok := a.Get(a.Len() - 1); !ok || p != &batch[len(batch)-1] {
+			t.Fatalf("expected the batch to live in the arena")
+		}
+		got = append(got, batch...)
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, in) {
+		t.Fatalf("expected %v, got %v", in, got)
+	}
+	// the first batch gets the room left by the earlier Alloc
+	if !slices.Equal(sizes, []int{4, 5, 5, 5, 4}) {
+		t.Fatalf("expected batch sizes [4 5 5 5 4], got %v", sizes)
+	}
+	if a.Len() != 0 {
+		t.Fatalf("expected the arena to be reset after the last batch, len %d", a.Len())
+	}
+
+	calls := 0
+	if err := a.FillBatches(nil, func([]int) error { calls++; return nil }); err != nil || calls != 0 {
+		t.Fatalf("expected empty input to make no calls, got %d, %v", calls, err)
+	}
+}
+
+// TestFillBatchesErrors reports how much was processed before onBatch or the
+// arena failed
+func TestFillBatchesErrors(t *testing.T) {
+	in := []int{1, 2, 3, 4, 5, 6, 7}
+	a := NewAtomicArena[int](3)
+	boom := errors.New("boom")
+	calls := 0
+	err := a.FillBatches(in, func(batch []int) error {
+		if calls++; calls == 2 {
+			return boom
+		}
+		return nil
+	})
+	var be *BatchError
+	if !errors.As(err, &be) || !errors.Is(err, boom) || be.Processed != 3 {
+		t.Fatalf("expected a BatchError after 3 elements wrapping boom, got %v", err)
+	}
+	// the failed batch is left in the arena
+	if got := a.Snapshot(); !slices.Equal(got, []int{4, 5, 6}) {
+		t.Fatalf("expected the failed batch [4 5 6] to stay, got %v", got)
+	}
+
+	err = a.FillBatches(in, func([]int) error { return nil })
+	if !errors.As(err, &be) || be.Processed != 0 || capacityErr(t, err).Available != 0 || a.Len() != 3 {
+		t.Fatalf("expected a full arena to be reported and left alone, got %v", err)
+	}
+	z := NewAtomicArena[int](0)
+	if err := z.FillBatches(in, func([]int) error { return nil }); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull from a zero-capacity arena, got %v", err)
+	}
+}
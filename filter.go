@@ -0,0 +1,106 @@
+package atomicarena
+
+import "errors"
+
+// filterChunk is how many matching elements FilterTo and PartitionTo buffer
+// before appending them to a destination with one reservation.
+const filterChunk = 256
+
+// FilterTo appends every committed element of a for which pred returns true
+// to dst, in index order, and returns how many it copied. Tombstoned slots
+// are skipped, and the committed count is taken once, so elements allocated
+// while FilterTo runs are not visited. Matches are appended in chunks, one
+// AppendSlice each. If dst fills up, FilterTo copies as many matches as fit
+// and returns that count with the *CapacityError. Like Dump it returns
+// ErrNotQuiescent if writes stay in flight on an arena built
+// WithoutPointerMirror.
+func (a *AtomicArena[T]) FilterTo(dst *AtomicArena[T], pred func(*T) bool) (int, error) {
+	n, ok := a.committedPrefix(^uintptr(0))
+	if !ok {
+		return 0, ErrNotQuiescent
+	}
+	buf := make([]T, 0, min(n, filterChunk))
+	copied := 0
+	for i := uintptr(0); i < n; i++ {
+		if a.tombstoned(i) || !pred(&a.raw[i]) {
+			continue
+		}
+		if buf = append(buf, a.raw[i]); len(buf) == cap(buf) {
+			k, err := appendFit(dst, buf)
+			copied += k
+			if err != nil {
+				return copied, err
+			}
+			buf = buf[:0]
+		}
+	}
+	k, err := appendFit(dst, buf)
+	return copied + k, err
+}
+
+// PartitionTo appends every committed element of a to yes if pred returns
+// true and to no otherwise, preserving index order in both, and returns how
+// many went to each. It takes the same snapshot as FilterTo. If either
+// destination fills up, PartitionTo stops there and returns the counts
+// copied so far with the *CapacityError.
+func (a *AtomicArena[T]) PartitionTo(yes, no *AtomicArena[T], pred func(*T) bool) (int, int, error) {
+	n, ok := a.committedPrefix(^uintptr(0))
+	if !ok {
+		return 0, 0, ErrNotQuiescent
+	}
+	size := min(n, filterChunk)
+	bufs := [2][]T{make([]T, 0, size), make([]T, 0, size)}
+	dsts := [2]*AtomicArena[T]{yes, no}
+	var counts [2]int
+	flush := func(side int) error {
+		k, err := appendFit(dsts[side], bufs[side])
+		counts[side] += k
+		bufs[side] = bufs[side][:0]
+		return err
+	}
+	for i := uintptr(0); i < n; i++ {
+		if a.tombstoned(i) {
+			continue
+		}
+		side := 1
+		if pred(&a.raw[i]) {
+			side = 0
+		}
+		if bufs[side] = append(bufs[side], a.raw[i]); len(bufs[side]) == cap(bufs[side]) {
+			if err := flush(side); err != nil {
+				return counts[0], counts[1], err
+			}
+		}
+	}
+	for side := range bufs {
+		if err := flush(side); err != nil {
+			return counts[0], counts[1], err
+		}
+	}
+	return counts[0], counts[1], nil
+}
+
+// appendFit appends vals to dst with as few reservations as possible. When
+// dst cannot take all of them it appends the leading run that still fits and
+// returns how many it appended along with the capacity error.
+func appendFit[T any](dst *AtomicArena[T], vals []T) (int, error) {
+	copied := 0
+	for len(vals) > 0 {
+		_, err := dst.AppendSlice(vals)
+		if err == nil {
+			return copied + len(vals), nil
+		}
+		var ce *CapacityError
+		if !errors.As(err, &ce) || ce.Available == 0 || ce.Available >= uintptr(len(vals)) {
+			return copied, err
+		}
+		// another writer may take the space first; the next round then
+		// reports the smaller remainder
+		fit := vals[:ce.Available]
+		if _, err := dst.AppendSlice(fit); err == nil {
+			copied += len(fit)
+			vals = vals[len(fit):]
+		}
+	}
+	return copied, nil
+}
@@ -0,0 +1,99 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// filterSource returns an arena holding 0..n-1 with slot 3 tombstoned.
+func filterSource(n int) *AtomicArena[int] {
+	a := NewAtomicArena[int](uintptr(n))
+	for i := 0; i < n; i++ {
+		a.Alloc(i)
+	}
+	a.Tombstone(3)
+	return a
+}
+
+func even(v *int) bool { return *v%2 == 0 }
+
+// TestFilterTo checks counts and ordering across several chunks
+func TestFilterTo(t *testing.T) {
+	const n = filterChunk*3 + 17
+	src := filterSource(n)
+	dst := NewAtomicArena[int](n)
+	dst.Alloc(-1)
+	got, err := src.FilterTo(dst, func(v *int) bool { return *v%3 == 0 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{-1}
+	for i := 0; i < n; i++ {
+		if i%3 == 0 && i != 3 {
+			want = append(want, i)
+		}
+	}
+	if got != len(want)-1 || !slices.Equal(dst.Snapshot(), want) {
+		t.Fatalf("expected %d elements %v, got %d %v", len(want)-1, want, got, dst.Snapshot())
+	}
+	if src.Len() != n {
+		t.Fatalf("expected the source untouched, len %d", src.Len())
+	}
+}
+
+// TestFilterToFull fills the destination mid-way through a chunk
+func TestFilterToFull(t *testing.T) {
+	src := filterSource(filterChunk * 4)
+	dst := NewAtomicArena[int](filterChunk + 10)
+	got, err := src.FilterTo(dst, even)
+	var ce *CapacityError
+	if !errors.Is(err, ErrArenaFull) || !errors.As(err, &ce) {
+		t.Fatalf("expected a capacity error, got %v", err)
+	}
+	if got != filterChunk+10 || dst.Len() != filterChunk+10 {
+		t.Fatalf("expected %d elements copied, got %d (len %d)", filterChunk+10, got, dst.Len())
+	}
+	for i, v := range dst.Snapshot() {
+		if v != 2*i {
+			t.Fatalf("expected element %d to be %d, got %d", i, 2*i, v)
+		}
+	}
+}
+
+// TestPartitionTo splits an arena and checks both sides keep index order
+func TestPartitionTo(t *testing.T) {
+	const n = filterChunk*2 + 5
+	src := filterSource(n)
+	yes, no := NewAtomicArena[int](n), NewAtomicArena[int](n)
+	y, m, err := src.PartitionTo(yes, no, even)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantYes, wantNo []int
+	for i := 0; i < n; i++ {
+		switch {
+		case i == 3:
+		case i%2 == 0:
+			wantYes = append(wantYes, i)
+		default:
+			wantNo = append(wantNo, i)
+		}
+	}
+	if y != len(wantYes) || m != len(wantNo) {
+		t.Fatalf("expected %d/%d, got %d/%d", len(wantYes), len(wantNo), y, m)
+	}
+	if !slices.Equal(yes.Snapshot(), wantYes) || !slices.Equal(no.Snapshot(), wantNo) {
+		t.Fatalf("expected both partitions in index order")
+	}
+
+	// a full "no" side stops the partition with the counts so far
+	yes, no = NewAtomicArena[int](n), NewAtomicArena[int](4)
+	y, m, err = src.PartitionTo(yes, no, even)
+	if !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if m != 4 || !slices.Equal(no.Snapshot(), []int{1, 5, 7, 9}) || y != int(yes.Len()) {
+		t.Fatalf("unexpected counts %d/%d, no=%v", y, m, no.Snapshot())
+	}
+}
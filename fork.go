@@ -0,0 +1,188 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrForkConflict is returned by Commit when the parent arena has been reset
+// or has allocated since Fork, so the fork's indices no longer line up.
+var ErrForkConflict = errors.New("atomicarena: parent changed since fork")
+
+// ArenaView is a copy-on-write branch of an arena created by Fork. It shares
+// the parent's committed prefix read-only; Set and Alloc write into a
+// private overlay, so the parent is untouched until Commit. Indices are
+// stable across the two: slot i of the view is slot i of the parent, and
+// the n-th Alloc on the view lands at the parent's length at Fork plus n,
+// which is where Commit puts it.
+//
+// A view is not safe for concurrent use, and the parent must not be reset
+// or allocated into while a view is meant to be committed. Pointers returned
+// for the shared prefix point into the parent and must not be written
+// through; use Set.
+type ArenaView[T any] struct {
+	parent  *AtomicArena[T]
+	base    uintptr           // parent length at Fork
+	epoch   uint64            // parent epoch at Fork
+	overlay []*AtomicArena[T] // chunks holding the view's writes, each twice the last
+	patches map[uintptr]*T    // copies of shared slots changed by Set
+	added   []*T              // slots base, base+1, ... allocated by the view
+	done    bool
+}
+
+// forkChunk is the number of slots in a view's first overlay chunk.
+const forkChunk = 16
+
+// Fork branches the arena's current contents. Nothing is copied: the view
+// reads the shared prefix in place and only stores what it changes. The
+// overlay is allocated on the first write and grows in chunks, so a view
+// costs memory in proportion to what it writes rather than to the parent's
+// capacity.
+func (a *AtomicArena[T]) Fork() *ArenaView[T] {
+	return &ArenaView[T]{
+		parent:  a,
+		base:    a.Len(),
+		epoch:   a.Epoch(),
+		patches: make(map[uintptr]*T),
+	}
+}
+
+// store copies obj into the overlay, adding a chunk twice the size of the
+// last, without the pointer mirror, once it is full. Chunks never move, so
+// the pointers it returns stay valid until Discard.
+func (v *ArenaView[T]) store(obj T) (*T, error) {
+	k := len(v.overlay)
+	if k == 0 || v.overlay[k-1].Len() == v.overlay[k-1].Cap() {
+		size := uintptr(forkChunk)
+		if k > 0 {
+			size = 2 * v.overlay[k-1].Cap()
+		}
+		chunk, err := New[T](min(size, v.parent.Cap()), WithoutPointerMirror())
+		if err != nil {
+			return nil, err
+		}
+		v.overlay = append(v.overlay, chunk)
+		k++
+	}
+	return v.overlay[k-1].Alloc(obj)
+}
+
+// Len returns the parent's length at Fork plus the number of Allocs on the view.
+func (v *ArenaView[T]) Len() uintptr {
+	return v.base + uintptr(len(v.added))
+}
+
+// Get returns the view's element i: a value changed by Set or Alloc if there
+// is one, and the parent's otherwise.
+func (v *ArenaView[T]) Get(i uintptr) (*T, bool) {
+	if v.done {
+		return nil, false
+	}
+	if i >= v.base {
+		if k := i - v.base; k < uintptr(len(v.added)) {
+			return v.added[k], true
+		}
+		return nil, false
+	}
+	if p, ok := v.patches[i]; ok {
+		return p, true
+	}
+	return v.parent.Get(i)
+}
+
+// Set replaces element i in the view. A shared slot is copied into the
+// overlay on its first Set; the parent keeps its value.
+func (v *ArenaView[T]) Set(i uintptr, obj T) error {
+	if v.done {
+		return ErrClosed
+	}
+	if i >= v.Len() {
+		return fmt.Errorf("%w: set %d, len %d", ErrOutOfRange, i, v.Len())
+	}
+	if i >= v.base {
+		*v.added[i-v.base] = obj
+		return nil
+	}
+	if p, ok := v.patches[i]; ok {
+		*p = obj
+		return nil
+	}
+	p, err := v.store(obj)
+	if err != nil {
+		return err
+	}
+	v.patches[i] = p
+	return nil
+}
+
+// Alloc appends obj to the view at index Len() and returns its index. It
+// fails with a *CapacityError once the view is as long as the parent's
+// capacity.
+func (v *ArenaView[T]) Alloc(obj T) (uintptr, *T, error) {
+	if v.done {
+		return 0, nil, ErrClosed
+	}
+	if v.Len() == v.parent.Cap() {
+		return 0, nil, &CapacityError{Name: v.parent.opts.name, Requested: 1, Capacity: v.parent.Cap()}
+	}
+	p, err := v.store(obj)
+	if err != nil {
+		return 0, nil, err
+	}
+	v.added = append(v.added, p)
+	return v.Len() - 1, p, nil
+}
+
+// Commit writes the view back into the parent: changed slots are
+// overwritten and allocated ones appended at the indices the view gave
+// them. The parent must be quiescent, with no readers or writers, and must
+// still have the length and epoch it had at Fork, or Commit fails with
+// ErrForkConflict and leaves it untouched. Either way the view is finished.
+func (v *ArenaView[T]) Commit() error {
+	if v.done {
+		return ErrClosed
+	}
+	defer v.Discard()
+	a := v.parent
+	if a.Epoch() != v.epoch {
+		return fmt.Errorf("%w: parent was reset", ErrForkConflict)
+	}
+	c := a.count.Load()
+	n := c & countMask
+	switch {
+	case c&frozenBit != 0:
+		return a.frozenErr()
+	case c&busyBit != 0 || a.done.Load() != n:
+		return ErrNotQuiescent
+	case n != v.base:
+		return fmt.Errorf("%w: parent length %d, %d at fork", ErrForkConflict, n, v.base)
+	}
+	k := uintptr(len(v.added))
+	if !a.count.CompareAndSwap(c, c+k) {
+		return ErrNotQuiescent
+	}
+	a.claimed(n, k)
+	for i, p := range v.patches {
+		a.raw[i] = *p
+	}
+	for j, p := range v.added {
+		i := v.base + uintptr(j)
+		a.raw[i] = *p
+		if a.ptrs != nil {
+			a.ptrs[i].Store(&a.raw[i])
+		}
+	}
+	a.commit(k)
+	return nil
+}
+
+// Discard drops the view and its overlay, leaving the parent as it is.
+func (v *ArenaView[T]) Discard() {
+	if !v.done {
+		v.done = true
+		for _, chunk := range v.overlay {
+			chunk.Close()
+		}
+		v.overlay, v.patches, v.added = nil, nil, nil
+	}
+}
@@ -0,0 +1,146 @@
+package atomicarena
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// TestForkCommit forks, mutates a subset of slots, and checks the parent is
+// untouched until Commit merges the branch
+func TestForkCommit(t *testing.T) {
+	parent := NewAtomicArena[int](8)
+	parent.AppendSlice([]int{10, 11, 12, 13})
+	view := parent.Fork()
+	if err := view.Set(1, 21); err != nil {
+		t.Fatal(err)
+	}
+	view.Set(3, 23)
+	view.Set(3, 33)
+	i, p, err := view.Alloc(14)
+	if err != nil || i != 4 || *p != 14 {
+		t.Fatalf("expected the first Alloc at index 4, got %d, %v", i, err)
+	}
+	view.Set(4, 24)
+	if err := view.Set(5, 0); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange past the view, got %v", err)
+	}
+
+	viewed := make([]int, view.Len())
+	for i := range viewed {
+		p, ok := view.Get(uintptr(i))
+		if !ok {
+			t.Fatalf("view has no element %d", i)
+		}
+		viewed[i] = *p
+	}
+	if want := []int{10, 21, 12, 33, 24}; !slices.Equal(viewed, want) {
+		t.Fatalf("view reads %v, want %v", viewed, want)
+	}
+	if got := parent.Snapshot(); !slices.Equal(got, []int{10, 11, 12, 13}) {
+		t.Fatalf("parent changed before Commit: %v", got)
+	}
+	if shared, _ := view.Get(0); shared != &parent.raw[0] {
+		t.Fatalf("expected unchanged slots to be shared with the parent")
+	}
+
+	if err := view.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := parent.Snapshot(); !slices.Equal(got, []int{10, 21, 12, 33, 24}) {
+		t.Fatalf("parent after Commit: %v", got)
+	}
+	if parent.ptrs[4].Load() != &parent.raw[4] {
+		t.Fatalf("expected the appended slot's pointer to be published")
+	}
+	if _, ok := view.Get(0); ok || view.Commit() == nil {
+		t.Fatalf("expected the view to be finished after Commit")
+	}
+}
+
+// TestForkDiscard leaves the parent as it was
+func TestForkDiscard(t *testing.T) {
+	parent := NewAtomicArena[int](4)
+	parent.Alloc(1)
+	view := parent.Fork()
+	view.Set(0, 2)
+	view.Alloc(3)
+	view.Discard()
+	if got := parent.Snapshot(); !slices.Equal(got, []int{1}) {
+		t.Fatalf("parent changed by a discarded view: %v", got)
+	}
+	if _, _, err := view.Alloc(4); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed after Discard, got %v", err)
+	}
+}
+
+// TestForkConflict refuses to commit once the parent's indices have moved
+func TestForkConflict(t *testing.T) {
+	parent := NewAtomicArena[int](4)
+	parent.Alloc(1)
+	view := parent.Fork()
+	view.Alloc(2)
+	parent.Alloc(3)
+	if err := view.Commit(); !errors.Is(err, ErrForkConflict) {
+		t.Fatalf("expected ErrForkConflict after a parent Alloc, got %v", err)
+	}
+	if got := parent.Snapshot(); !slices.Equal(got, []int{1, 3}) {
+		t.Fatalf("failed Commit changed the parent: %v", got)
+	}
+
+	view = parent.Fork()
+	view.Set(0, 9)
+	parent.Reset(false)
+	parent.AppendSlice([]int{5, 6})
+	if err := view.Commit(); !errors.Is(err, ErrForkConflict) {
+		t.Fatalf("expected ErrForkConflict after a Reset, got %v", err)
+	}
+
+	view = parent.Fork()
+	view.Alloc(7)
+	view.Alloc(8)
+	if _, _, err := view.Alloc(9); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected the view to stop at the parent's capacity, got %v", err)
+	}
+}
+
+// TestForkOverlayLazy sizes the overlay by the view's writes, not the
+// parent's capacity
+func TestForkOverlayLazy(t *testing.T) {
+	const n = 1 << 20
+	parent := NewAtomicArena[int](n)
+	parent.AppendSlice(make([]int, 100))
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	view := parent.Fork()
+	for i := range 40 {
+		if err := view.Set(uintptr(i), i); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, _, err := view.Alloc(i); err != nil {
+			t.Fatalf("Alloc failed: %v", err)
+		}
+	}
+	runtime.ReadMemStats(&after)
+	if grown := after.TotalAlloc - before.TotalAlloc; grown > 64<<10 {
+		t.Fatalf("expected a view with 80 writes to allocate under 64KB, got %d bytes", grown)
+	}
+	if len(view.overlay) != 3 {
+		t.Fatalf("expected the overlay to grow to 3 chunks, got %d", len(view.overlay))
+	}
+	for i := range 40 {
+		if p, ok := view.Get(uintptr(i)); !ok || *p != i {
+			t.Fatalf("expected view element %d to be %d, got %v", i, i, p)
+		}
+		if p, ok := view.Get(uintptr(100 + i)); !ok || *p != i {
+			t.Fatalf("expected view element %d to be %d, got %v", 100+i, i, p)
+		}
+	}
+	if err := view.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if parent.raw[39] != 39 || parent.raw[139] != 39 || parent.Len() != 140 {
+		t.Fatalf("expected Commit to merge every chunk, got len %d", parent.Len())
+	}
+}
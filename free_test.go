@@ -0,0 +1,188 @@
+package atomicarena
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// TestParallelFree ensures the parallel path zeroes every slot
+func TestParallelFree(t *testing.T) {
+	const n = 100_003
+	arena := NewAtomicArena[int64](n, WithParallelFreeThreshold(1024))
+	for i := 0; i < n; i++ {
+		_, _ = arena.Alloc(int64(i) + 1)
+	}
+	if err := arena.Free(); err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if arena.raw[i] != 0 || arena.ptrs[i].Load() != nil {
+			t.Fatalf("slot %d not zeroed", i)
+		}
+	}
+}
+
+// TestFreeAsync ensures storage is zeroed before the channel closes
+func TestFreeAsync(t *testing.T) {
+	const n = 1 << 20
+	arena := NewAtomicArena[int64](n, WithParallelFreeThreshold(1<<16))
+	vals := make([]int64, n)
+	for i := range vals {
+		vals[i] = -1
+	}
+	_, _ = arena.AppendSlice(vals)
+	<-arena.FreeAsync()
+	for i := 0; i < n; i++ {
+		if arena.raw[i] != 0 {
+			t.Fatalf("slot %d not zeroed when FreeAsync completed", i)
+		}
+	}
+
+	arena.Freeze()
+	<-arena.FreeAsync() // closes without zeroing
+}
+
+// plainRecord is a pointer-free struct large enough to be zeroed field by
+// field by a naive loop.
+type plainRecord struct {
+	ID     uint64
+	Vals   [14]float64
+	Flags  uint32
+	Weight float32
+}
+
+// refRecord is a struct holding pointers, which must be cleared with write
+// barriers.
+type refRecord struct {
+	Name  string
+	Next  *refRecord
+	Tags  []string
+	Score [12]int64
+}
+
+// TestFreeZeroesStructs checks that Free, Compact and zeroing reservations
+// leave every slot of plain and pointer-holding structs zero
+func TestFreeZeroesStructs(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		freeZeroesSuite(t, func(i int) plainRecord { return plainRecord{ID: uint64(i) + 1, Vals: [14]float64{13: 1}, Weight: 1} })
+	})
+	t.Run("pointers", func(t *testing.T) {
+		freeZeroesSuite(t, func(i int) refRecord {
+			return refRecord{Name: "r", Next: &refRecord{}, Tags: []string{"t"}, Score: [12]int64{11: int64(i) + 1}}
+		})
+	})
+}
+
+// freeZeroesSuite fills arenas of T with the non-zero values val(i) and checks
+// each way of zeroing them.
+func freeZeroesSuite[T any](t *testing.T, val func(int) T) {
+	const n = 257
+	isZero := func(v T) bool { return reflect.ValueOf(v).IsZero() }
+	a := NewAtomicArena[T](n, WithParallelFreeThreshold(1024))
+	for i := 0; i < n; i++ {
+		a.Alloc(val(i))
+	}
+	if err := a.Free(); err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	inspectReleased(a)
+	for i := range a.raw {
+		if !isZero(a.raw[i]) {
+			t.Fatalf("expected Free to zero slot %d", i)
+		}
+	}
+
+	a = NewAtomicArena[T](n)
+	for i := 0; i < n; i++ {
+		a.Alloc(val(i))
+		if i%2 == 0 {
+			a.Tombstone(uintptr(i))
+		}
+	}
+	a.Compact()
+	if a.Len() != n/2 {
+		t.Fatalf("expected %d live slots, got %d", n/2, a.Len())
+	}
+	inspectReleased(a)
+	for i := a.Len(); i < n; i++ {
+		if !isZero(a.raw[i]) {
+			t.Fatalf("expected Compact to zero freed slot %d", i)
+		}
+	}
+
+	a = NewAtomicArena[T](n, WithZeroOnReserve())
+	for i := 0; i < n; i++ {
+		a.Alloc(val(i))
+	}
+	a.Reset(false)
+	seg, err := a.Reserve(n)
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	for i := range seg {
+		if !isZero(seg[i]) {
+			t.Fatalf("expected a zeroing Reserve to clear stale slot %d", i)
+		}
+	}
+}
+
+// BenchmarkFreeStrategy compares the zeroing strategy Free picks at
+// construction with a per-element store loop, across benchSizes, for a plain
+// struct and one holding pointers
+func BenchmarkFreeStrategy(b *testing.B) {
+	b.Run("plain", func(b *testing.B) { benchmarkFreeStrategy[plainRecord](b) })
+	b.Run("pointers", func(b *testing.B) { benchmarkFreeStrategy[refRecord](b) })
+}
+
+// benchmarkFreeStrategy runs BenchmarkFreeStrategy for an arena of T.
+func benchmarkFreeStrategy[T any](b *testing.B) {
+	for _, s := range benchSizes {
+		maxElems := max(s.totalBytes/unsafe.Sizeof(*new(T)), 1)
+		b.Run("Loop/"+s.name, func(b *testing.B) {
+			arena := NewAtomicArena[T](maxElems, WithParallelFreeThreshold(^uintptr(0)), WithoutPointerMirror())
+			_, _ = arena.Reserve(maxElems)
+			b.SetBytes(int64(s.totalBytes))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var zero T
+				for j := range arena.raw {
+					arena.raw[j] = zero
+				}
+			}
+		})
+		b.Run("Free/"+s.name, func(b *testing.B) {
+			arena := NewAtomicArena[T](maxElems, WithParallelFreeThreshold(^uintptr(0)), WithoutPointerMirror())
+			_, _ = arena.Reserve(maxElems)
+			b.SetBytes(int64(s.totalBytes))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = arena.Free()
+			}
+		})
+	}
+}
+
+// BenchmarkFree compares serial and parallel zeroing across benchSizes.
+func BenchmarkFree(b *testing.B) {
+	for _, mode := range []struct {
+		name      string
+		threshold uintptr
+	}{
+		{"Serial", ^uintptr(0)},
+		{"Parallel", defaultParallelFree},
+	} {
+		for _, s := range benchSizes {
+			b.Run(mode.name+"/"+s.name, func(b *testing.B) {
+				maxElems := s.totalBytes / unsafe.Sizeof(int64(0))
+				arena := NewAtomicArena[int64](maxElems, WithParallelFreeThreshold(mode.threshold))
+				_, _ = arena.Reserve(maxElems)
+				b.SetBytes(int64(s.totalBytes))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_ = arena.Free()
+				}
+			})
+		}
+	}
+}
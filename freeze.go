@@ -0,0 +1,61 @@
+package atomicarena
+
+import (
+	"errors"
+	"runtime"
+)
+
+var (
+	// ErrFrozen is returned by mutating operations on a frozen arena.
+	ErrFrozen = errors.New("atomicarena: arena frozen")
+	// ErrUnfreezeDisabled is returned by Unfreeze unless the arena was built WithUnfreeze.
+	ErrUnfreezeDisabled = errors.New("atomicarena: unfreeze not enabled")
+)
+
+// Freeze atomically makes the arena read-only. Afterwards Alloc, Reserve,
+// AppendSlice, Reset and Free return ErrFrozen, while Get, Range and Snapshot
+// keep working. Freeze returns only once every allocation that won the race
+// against it has finished writing, so readers never observe a half-written slot.
+func (a *AtomicArena[T]) Freeze() {
+	for {
+		c := a.count.Load()
+		if c&busyBit != 0 {
+			// let an in-progress reset finish first
+			runtime.Gosched()
+			continue
+		}
+		if c&frozenBit != 0 || a.count.CompareAndSwap(c, c|frozenBit) {
+			break
+		}
+	}
+	// count can no longer change; wait for in-flight writes to land
+	for a.done.Load() != a.Len() {
+		runtime.Gosched()
+	}
+	if a.waiters.wantAt.Load() != ^uintptr(0) {
+		// waiters can no longer be satisfied by new writes
+		a.wakeCommitted()
+	}
+}
+
+// Frozen reports whether the arena is currently frozen.
+func (a *AtomicArena[T]) Frozen() bool {
+	return a.count.Load()&frozenBit != 0
+}
+
+// Unfreeze makes a frozen arena writable again. It returns ErrUnfreezeDisabled
+// unless the arena was constructed with WithUnfreeze, and ErrClosed after Close.
+func (a *AtomicArena[T]) Unfreeze() error {
+	if !a.opts.unfreeze {
+		return ErrUnfreezeDisabled
+	}
+	if a.closed.Load() {
+		return ErrClosed
+	}
+	for {
+		c := a.count.Load()
+		if c&frozenBit == 0 || a.count.CompareAndSwap(c, c&^frozenBit) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestFreezeRejectsWrites ensures every mutating operation fails on a frozen arena
+func TestFreezeRejectsWrites(t *testing.T) {
+	arena := NewAtomicArena[int](4)
+	_, _ = arena.Alloc(1)
+	arena.Freeze()
+	if !arena.Frozen() {
+		t.Fatal("expected arena to be frozen")
+	}
+	if _, err := arena.Alloc(2); !errors.Is(err, ErrFrozen) {
+		t.Errorf("Alloc: expected ErrFrozen, got %v", err)
+	}
+	if _, err := arena.Reserve(1); !errors.Is(err, ErrFrozen) {
+		t.Errorf("Reserve: expected ErrFrozen, got %v", err)
+	}
+	if _, err := arena.AppendSlice([]int{3}); !errors.Is(err, ErrFrozen) {
+		t.Errorf("AppendSlice: expected ErrFrozen, got %v", err)
+	}
+	if err := arena.Reset(true); !errors.Is(err, ErrFrozen) {
+		t.Errorf("Reset: expected ErrFrozen, got %v", err)
+	}
+	// reads keep working
+	if v, ok := arena.Get(0); !ok || *v != 1 {
+		t.Errorf("Get after Freeze: got %v, %v", v, ok)
+	}
+	if s := arena.Snapshot(); len(s) != 1 || s[0] != 1 {
+		t.Errorf("Snapshot after Freeze: got %v", s)
+	}
+}
+
+// TestUnfreeze ensures Unfreeze is only permitted when enabled at construction
+func TestUnfreeze(t *testing.T) {
+	arena := NewAtomicArena[int](2)
+	arena.Freeze()
+	if err := arena.Unfreeze(); !errors.Is(err, ErrUnfreezeDisabled) {
+		t.Fatalf("expected ErrUnfreezeDisabled, got %v", err)
+	}
+
+	arena = NewAtomicArena[int](2, WithUnfreeze())
+	arena.Freeze()
+	if err := arena.Unfreeze(); err != nil {
+		t.Fatalf("Unfreeze failed: %v", err)
+	}
+	if arena.Frozen() {
+		t.Fatal("arena still frozen after Unfreeze")
+	}
+	if _, err := arena.Alloc(1); err != nil {
+		t.Fatalf("Alloc after Unfreeze failed: %v", err)
+	}
+}
+
+// TestFreezeConcurrentAlloc races Alloc against Freeze: every allocation must
+// either be fully visible once Freeze returns or have failed.
+func TestFreezeConcurrentAlloc(t *testing.T) {
+	for iter := 0; iter < 100; iter++ {
+		const workers = 8
+		arena := NewAtomicArena[int](10_000)
+		var succeeded atomic.Int64
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				<-start
+				for {
+					p, err := arena.Alloc(7)
+					if err != nil {
+						if !errors.Is(err, ErrFrozen) && arena.Len() != arena.Cap() {
+							t.Errorf("unexpected error: %v", err)
+						}
+						return
+					}
+					if *p != 7 {
+						t.Errorf("allocated slot has wrong value %d", *p)
+					}
+					succeeded.Add(1)
+				}
+			}()
+		}
+		close(start)
+		arena.Freeze()
+		n := arena.Len()
+		for i := uintptr(0); i < n; i++ {
+			if arena.raw[i] != 7 || arena.ptrs[i].Load() != &arena.raw[i] {
+				t.Fatalf("slot %d half-committed after Freeze", i)
+			}
+		}
+		wg.Wait()
+		if uintptr(succeeded.Load()) != n {
+			t.Fatalf("expected %d successful allocations, got %d", n, succeeded.Load())
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ArenaGroup is a fixed set of independent arenas carved from one
+// allocation. Unlike a pool, the caller routes every allocation to a group
+// of its choosing, so a group's identity can carry meaning: a tenant, a CPU
+// or a connection. Each group has its own counter and capacity: filling or
+// resetting one never affects its siblings, while their storage stays
+// contiguous for locality.
+type ArenaGroup[T any] struct {
+	groups []*AtomicArena[T]
+	name   string   // WithName, without the group suffix
+	lenc   lenCache // TotalLen as of the last refresh, for ApproxLen
+}
+
+// NewArenaGroup creates groups arenas of elemsPerGroup slots each, stored
+// back to back in a single allocation and configured by opts. Under
+// WithName, group i is named "name[i]". WithBaseAlignment aligns the start
+// of the shared storage, and hence group 0. Like NewAtomicArena it panics if
+// the options are invalid or the storage cannot be allocated.
+func NewArenaGroup[T any](groups int, elemsPerGroup uintptr, opts ...Option) *ArenaGroup[T] {
+	g, err := newArenaGroup[T](groups, elemsPerGroup, opts)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+func newArenaGroup[T any](groups int, per uintptr, opts []Option) (*ArenaGroup[T], error) {
+	if groups < 0 {
+		return nil, fmt.Errorf("%w: %d groups", ErrInvalidOptions, groups)
+	}
+	o := buildOptions(opts)
+	if err := o.validate(false); err != nil {
+		return nil, err
+	}
+	if err := validateElem[T](o); err != nil {
+		return nil, err
+	}
+	if per > 0 && uintptr(groups) > ^uintptr(0)/per {
+		return nil, fmt.Errorf("%w: %d groups of %d elements overflow uintptr", ErrTooLarge, groups, per)
+	}
+	if zeroSized[T]() {
+		o.noMirror = true
+	}
+	raw, ptrs, err := groupStorage[T](uintptr(groups)*per, o)
+	if err != nil {
+		return nil, err
+	}
+	g := &ArenaGroup[T]{groups: make([]*AtomicArena[T], groups), name: o.name}
+	g.lenc.sum = g.TotalLen
+	for i := range g.groups {
+		lo, hi := uintptr(i)*per, uintptr(i+1)*per
+		opt := o
+		if o.name != "" {
+			opt.name = fmt.Sprintf("%s[%d]", o.name, i)
+		}
+		var p []atomic.Pointer[T]
+		if ptrs != nil {
+			p = ptrs[lo:hi:hi]
+		}
+		g.groups[i] = newAtomicArena(raw[lo:hi:hi], p, opt)
+		g.groups[i].lenc = &g.lenc
+		if o.prefault {
+			g.groups[i].Prefault()
+		}
+	}
+	return g, nil
+}
+
+// groupStorage allocates the element storage and pointer mirror shared by
+// every group, reporting ErrTooLarge as newArena does.
+func groupStorage[T any](n uintptr, o options) (raw []T, ptrs []atomic.Pointer[T], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			raw, ptrs, err = nil, nil, fmt.Errorf("%w: requested %s: %v", ErrTooLarge, describeRequest[T](n), r)
+		}
+	}()
+	if raw, err = alignedSlice[T](n, o.baseAlign); err != nil {
+		return nil, nil, err
+	}
+	if !o.noMirror {
+		ptrs = make([]atomic.Pointer[T], n)
+	}
+	return raw, ptrs, nil
+}
+
+// Group returns arena i. It panics if i is out of range, like a slice index.
+func (g *ArenaGroup[T]) Group(i int) *AtomicArena[T] {
+	return g.groups[i]
+}
+
+// Len returns the number of groups.
+func (g *ArenaGroup[T]) Len() int {
+	return len(g.groups)
+}
+
+// TotalLen returns the number of slots allocated across all groups.
+func (g *ArenaGroup[T]) TotalLen() uintptr {
+	var n uintptr
+	for _, a := range g.groups {
+		n += a.Len()
+	}
+	return n
+}
+
+// ArenaGroupStats summarizes an ArenaGroup: the embedded Stats holds the
+// totals and Groups holds each group's own Stats, by index.
+type ArenaGroupStats struct {
+	Stats
+	Groups []Stats
+}
+
+// Stats returns the totals across groups and each group's Stats. Len, Cap,
+// Bytes, SoftCap, SoftRejected, Allocated, Rejected, ReservationRetries and
+// the zeroing counters are summed, as is PeakLen, which bounds the group's
+// own peak from above; Epoch counts the resets of all groups together, and
+// Frozen reports whether every group is frozen. Name is the group's
+// WithName. Like Stats, groups are sampled one after another.
+func (g *ArenaGroup[T]) Stats() ArenaGroupStats {
+	s := ArenaGroupStats{Groups: make([]Stats, len(g.groups))}
+	for i, a := range g.groups {
+		s.Groups[i] = a.Stats()
+	}
+	s.Stats = sumStats(s.Groups...)
+	s.Name = g.name
+	return s
+}
+
+// ResetAll resets every group with Reset(release). It resets all of them
+// even if some fail, and returns the failures joined, each prefixed with its
+// group index.
+func (g *ArenaGroup[T]) ResetAll(release bool) error {
+	var errs []error
+	for i, a := range g.groups {
+		if err := a.Reset(release); err != nil {
+			errs = append(errs, fmt.Errorf("group %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
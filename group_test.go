@@ -0,0 +1,82 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+// TestArenaGroupIsolation fills and resets groups independently
+func TestArenaGroupIsolation(t *testing.T) {
+	g := NewArenaGroup[int64](3, 4)
+	for i := 0; i < g.Len(); i++ {
+		for v := 0; v < i+1; v++ {
+			if _, err := g.Group(i).Alloc(int64(10*i + v)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// a full group fails on its own
+	g.Group(2).AppendSlice([]int64{23})
+	if _, err := g.Group(2).Alloc(99); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected group 2 to be full, got %v", err)
+	}
+	if _, err := g.Group(0).Alloc(1); err != nil {
+		t.Fatalf("expected a full sibling not to affect group 0, got %v", err)
+	}
+	if g.TotalLen() != 2+2+4 {
+		t.Fatalf("expected a total of 8, got %d", g.TotalLen())
+	}
+	// resetting one group leaves its neighbours' contents in place
+	if err := g.Group(1).Reset(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Group(0).Snapshot(); !slices.Equal(got, []int64{0, 1}) {
+		t.Fatalf("expected group 0 untouched, got %v", got)
+	}
+	if got := g.Group(2).Snapshot(); !slices.Equal(got, []int64{20, 21, 22, 23}) {
+		t.Fatalf("expected group 2 untouched, got %v", got)
+	}
+	g.Group(1).AppendSlice([]int64{1, 2, 3, 4})
+	if _, err := g.Group(1).Alloc(5); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected group 1 to stay within its own slots, got %v", err)
+	}
+
+	// the groups share one contiguous allocation
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(g.Group(0).raw)))
+	for i := 1; i < g.Len(); i++ {
+		if p := uintptr(unsafe.Pointer(unsafe.SliceData(g.Group(i).raw))); p != base+uintptr(i)*4*8 {
+			t.Fatalf("expected group %d at offset %d, got %d", i, i*32, p-base)
+		}
+	}
+
+	s := g.Stats()
+	if s.Len != 10 || s.Cap != 12 || len(s.Groups) != 3 || s.Groups[1].Epoch != 1 || s.Epoch != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if err := g.ResetAll(false); err != nil || g.TotalLen() != 0 {
+		t.Fatalf("expected ResetAll to empty every group, got %v and len %d", err, g.TotalLen())
+	}
+}
+
+// TestArenaGroupNamed checks per-group names and joined ResetAll errors
+func TestArenaGroupNamed(t *testing.T) {
+	g := NewArenaGroup[int](2, 2, WithName("tenants"))
+	defer g.Group(0).Close()
+	defer g.Group(1).Close()
+	if _, ok := findArena("tenants[1]"); !ok {
+		t.Fatalf("expected group 1 to be registered as tenants[1]")
+	}
+	if g.Stats().Name != "tenants" {
+		t.Fatalf("expected the group name in Stats, got %q", g.Stats().Name)
+	}
+	g.Group(1).Freeze()
+	err := g.ResetAll(true)
+	if !errors.Is(err, ErrFrozen) || err.Error() != "group 1: atomicarena: arena frozen" {
+		t.Fatalf("expected group 1's ErrFrozen, got %v", err)
+	}
+	if NewArenaGroup[struct{}](3, 8).Group(2).Cap() != 8 {
+		t.Fatalf("expected zero-sized groups to keep their capacity")
+	}
+}
@@ -0,0 +1,79 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// ErrCannotGrowInPlace is returned by Grow when the arena's storage has no
+// room past its capacity. Copy the contents into a larger arena instead, for
+// example with Merge.
+var ErrCannotGrowInPlace = errors.New("atomicarena: arena cannot grow in place")
+
+// WithGrowLimit maps address space for up to n slots when an MmapArena is
+// created, of which only the requested capacity is usable at first; Grow
+// extends the capacity into the rest without moving any element. Untouched
+// pages of the reserve cost address space but no memory, though WithLocked
+// locks the whole mapping. A limit below the capacity has no effect.
+// Heap-backed constructors reject it with ErrInvalidOptions.
+func WithGrowLimit(n uintptr) Option {
+	return func(o *options) { o.growLimit = n }
+}
+
+// Grow raises the capacity by additional slots without moving the storage,
+// so every pointer stays valid. Only an MmapArena created with WithGrowLimit
+// has room to grow into; heap-backed arenas, and growth past the limit or
+// into capacity given up by Truncate, fail with ErrCannotGrowInPlace.
+//
+// Grow may run concurrently with allocations: it briefly holds off new
+// reservations and waits for in-flight writes, as Reset does, so every
+// allocation sees either the old capacity or the new one. Watermarks are
+// recomputed for the new capacity, a soft cap set by WithSoftCap stays
+// where it is, and an arena with a Budget reserves the extra bytes from it
+// first. It returns ErrFrozen on a frozen arena.
+func (a *AtomicArena[T]) Grow(additional uintptr) error {
+	for {
+		c := a.count.Load()
+		if c&frozenBit != 0 {
+			return a.frozenErr()
+		}
+		n := c & countMask
+		if c&busyBit != 0 || a.done.Load() != n {
+			runtime.Gosched()
+			continue
+		}
+		old := a.maxElems.Load()
+		room := uintptr(len(a.raw)) - old
+		if additional > room {
+			return fmt.Errorf("%w: %d more slots requested, room for %d", ErrCannotGrowInPlace, additional, room)
+		}
+		if additional == 0 {
+			return nil
+		}
+		if !a.count.CompareAndSwap(c, c|busyBit) {
+			continue
+		}
+		if a.budget != nil {
+			extra := additional * unsafe.Sizeof(*new(T))
+			if err := a.budget.reserve(extra); err != nil {
+				a.count.Store(c)
+				return err
+			}
+			a.budgetBytes += extra
+		}
+		a.setCap(old+additional, n)
+		a.count.Store(c)
+		return nil
+	}
+}
+
+// setCap makes newMax the capacity of an arena holding n slots, adjusting
+// the soft cap and watermarks to it. Reservations must be held off.
+func (a *AtomicArena[T]) setCap(newMax, n uintptr) {
+	a.maxElems.Store(newMax)
+	a.softCap.Store(a.opts.softCapFor(newMax))
+	a.marks = newWatermarks(a.opts.watermarks, newMax)
+	a.armMarks(n)
+}
@@ -0,0 +1,111 @@
+package atomicarena
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// TestGrowHeapArena reports that a heap arena cannot grow in place
+func TestGrowHeapArena(t *testing.T) {
+	a := NewAtomicArena[int](4)
+	if err := a.Grow(1); !errors.Is(err, ErrCannotGrowInPlace) {
+		t.Fatalf("expected ErrCannotGrowInPlace, got %v", err)
+	}
+	if err := a.Grow(0); err != nil || a.Cap() != 4 {
+		t.Fatalf("expected growing by 0 to succeed and change nothing, got %v", err)
+	}
+	if _, err := New[int](4, WithGrowLimit(8)); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected WithGrowLimit to be rejected for heap storage, got %v", err)
+	}
+}
+
+// TestMmapGrowLimit grows up to the limit, keeping the soft cap and pointers in place
+func TestMmapGrowLimit(t *testing.T) {
+	m, err := NewMmapArena[int64](4, WithGrowLimit(10), WithSoftCap(6))
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	seg, _ := m.Reserve(4)
+	seg[0] = 42
+	if _, err := m.Alloc(1); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected the initial capacity to be 4, got %v", err)
+	}
+	if err := m.Grow(6); err != nil {
+		t.Fatalf("Grow failed: %v", err)
+	}
+	if m.Cap() != 10 || m.arena.SoftCap() != 6 {
+		t.Fatalf("expected capacity 10 and soft cap 6, got %d and %d", m.Cap(), m.arena.SoftCap())
+	}
+	if p, _ := m.Get(0); p != &seg[0] || *p != 42 {
+		t.Fatalf("expected existing slots not to move")
+	}
+	if err := m.Grow(1); !errors.Is(err, ErrCannotGrowInPlace) {
+		t.Fatalf("expected ErrCannotGrowInPlace past the limit, got %v", err)
+	}
+	if err := m.Truncate(8); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	if err := m.Grow(1); !errors.Is(err, ErrCannotGrowInPlace) {
+		t.Fatalf("expected truncated capacity not to be regained, got %v", err)
+	}
+}
+
+// TestMmapGrowConcurrent grows while producers allocate and checks every value lands exactly once
+func TestMmapGrowConcurrent(t *testing.T) {
+	const producers, per, step = 4, 5000, 500
+	const limit = producers * per
+	m, err := NewMmapArena[int64](step, WithGrowLimit(limit))
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	ptrs := make([][]*int64, producers)
+	var wg sync.WaitGroup
+	for g := 0; g < producers; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < per; {
+				p, err := m.Alloc(int64(g*per + i))
+				if errors.Is(err, ErrArenaFull) {
+					runtime.Gosched()
+					continue
+				}
+				if err != nil {
+					t.Errorf("Alloc failed: %v", err)
+					return
+				}
+				ptrs[g] = append(ptrs[g], p)
+				i++
+			}
+		}(g)
+	}
+	for m.Cap() < limit {
+		if err := m.Grow(step); err != nil {
+			t.Fatalf("Grow failed: %v", err)
+		}
+		runtime.Gosched()
+	}
+	wg.Wait()
+	if m.Len() != limit || m.arena.Stats().Cap != limit {
+		t.Fatalf("expected %d slots in use of %d, got %d of %d", limit, limit, m.Len(), m.arena.Stats().Cap)
+	}
+	seen := make([]bool, limit)
+	for i := uintptr(0); i < limit; i++ {
+		p, _ := m.Get(i)
+		if seen[*p] {
+			t.Fatalf("value %d stored twice", *p)
+		}
+		seen[*p] = true
+	}
+	for g, ps := range ptrs {
+		for i, p := range ps {
+			if *p != int64(g*per+i) {
+				t.Fatalf("expected pointer %d of producer %d to stay valid, got %d", i, g, *p)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package atomicarena
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// BucketCount is one bucket of a ByteArena size histogram: the number of
+// requests for at most UpperBound bytes that did not fit a smaller bucket.
+// The last bucket, with UpperBound math.MaxInt, counts everything larger
+// than the largest configured bound.
+type BucketCount struct {
+	UpperBound int
+	Count      uint64
+}
+
+// WithSizeHistogram makes a ByteArena count its allocation requests by size.
+// Each bound is the inclusive upper limit of a bucket, and the bounds must be
+// strictly ascending, or the constructor fails with ErrInvalidOptions. A
+// final bucket catches requests larger than the last bound. Other arenas
+// ignore the histogram.
+func WithSizeHistogram(buckets []int) Option {
+	bounds := slices.Clone(buckets)
+	return func(o *options) { o.sizeBuckets = bounds }
+}
+
+// validateBuckets checks the bounds passed to WithSizeHistogram.
+func validateBuckets(bounds []int) error {
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			return fmt.Errorf("%w: size histogram bounds %v are not strictly ascending", ErrInvalidOptions, bounds)
+		}
+	}
+	return nil
+}
+
+// sizeHistogram counts requests per bucket. Recording is one binary search
+// over the immutable bounds and one atomic add, so it never blocks.
+type sizeHistogram struct {
+	bounds []int
+	counts []atomic.Uint64 // len(bounds)+1; the last is the overflow bucket
+}
+
+// newSizeHistogram returns nil when no buckets were configured.
+func newSizeHistogram(bounds []int) *sizeHistogram {
+	if bounds == nil {
+		return nil
+	}
+	return &sizeHistogram{bounds: bounds, counts: make([]atomic.Uint64, len(bounds)+1)}
+}
+
+func (h *sizeHistogram) record(n int) {
+	i, _ := slices.BinarySearch(h.bounds, n)
+	h.counts[i].Add(1)
+}
+
+func (h *sizeHistogram) snapshot() []BucketCount {
+	out := make([]BucketCount, len(h.counts))
+	for i := range out {
+		bound := math.MaxInt
+		if i < len(h.bounds) {
+			bound = h.bounds[i]
+		}
+		out[i] = BucketCount{UpperBound: bound, Count: h.counts[i].Load()}
+	}
+	return out
+}
+
+// Histogram returns the request counts per size bucket, ending with the
+// overflow bucket, or nil if the arena was built without WithSizeHistogram.
+// Every AllocBytes and AllocBytesAligned call is counted, including those
+// that fail, so the histogram shows demand rather than what fit. Counts
+// accumulate across Reset. Under concurrent allocation the buckets are
+// sampled one after another.
+func (b *ByteArena) Histogram() []BucketCount {
+	if b.sizes == nil {
+		return nil
+	}
+	return b.sizes.snapshot()
+}
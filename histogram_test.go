@@ -0,0 +1,114 @@
+package atomicarena
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestSizeHistogram drives a known size distribution through every entry point
+func TestSizeHistogram(t *testing.T) {
+	bounds := []int{16, 64, 256}
+	b := NewByteArena(1<<16, WithSizeHistogram(bounds))
+	bounds[0] = 1000 // the option keeps its own copy
+	for _, n := range []int{0, 1, 16, 16, 17, 64, 200, 256, 257, 4096} {
+		if _, err := b.AllocBytes(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.AllocBytesAligned(32, 64)
+	b.CopyString("hello")
+	b.AllocBytes(1 << 20) // fails, but still counted
+	b.AllocBytes(-1)      // rejected before counting
+	b.Reset()
+	want := []BucketCount{{16, 5}, {64, 3}, {256, 2}, {math.MaxInt, 3}}
+	if got := b.Histogram(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := b.Stats().Histogram; !slices.Equal(got, want) {
+		t.Fatalf("expected Stats to carry the histogram, got %v", got)
+	}
+	if NewByteArena(16).Histogram() != nil {
+		t.Fatalf("expected no histogram without WithSizeHistogram")
+	}
+}
+
+// TestSizeHistogramInvalid rejects bounds that are not strictly ascending
+func TestSizeHistogramInvalid(t *testing.T) {
+	for _, bounds := range [][]int{{64, 16}, {8, 8}} {
+		func() {
+			defer func() {
+				if err, _ := recover().(error); !errors.Is(err, ErrInvalidOptions) {
+					t.Errorf("%v: expected a panic with ErrInvalidOptions, got %v", bounds, err)
+				}
+			}()
+			NewByteArena(16, WithSizeHistogram(bounds))
+		}()
+	}
+}
+
+// TestSizeHistogramExpvar publishes the stats and reads back the JSON
+func TestSizeHistogramExpvar(t *testing.T) {
+	b := NewByteArena(1024, WithSizeHistogram([]int{8}))
+	b.AllocBytes(4)
+	b.AllocBytes(9)
+	v := expvar.Func(func() any { return b.Stats() })
+	var out struct{ Histogram []BucketCount }
+	if err := json.Unmarshal([]byte(v.String()), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Histogram) != 2 || out.Histogram[0].Count != 1 || out.Histogram[1].Count != 1 {
+		t.Fatalf("unexpected expvar output %s", v.String())
+	}
+	if !strings.Contains(v.String(), `"UpperBound":8`) {
+		t.Fatalf("expected the bounds in %s", v.String())
+	}
+}
+
+// TestSizeHistogramConcurrent counts from many goroutines; run with -race
+func TestSizeHistogramConcurrent(t *testing.T) {
+	b := NewByteArena(1<<20, WithSizeHistogram([]int{1, 2, 3}))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				b.AllocBytes(1 + i%4)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, c := range b.Histogram() {
+		if c.Count != 2000 {
+			t.Fatalf("expected 2000 requests per bucket, got %v", b.Histogram())
+		}
+	}
+}
+
+// BenchmarkAllocBytesHistogram compares AllocBytes with and without the
+// histogram; the difference is a binary search and one atomic add.
+func BenchmarkAllocBytesHistogram(b *testing.B) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"off", nil},
+		{"on", []Option{WithSizeHistogram([]int{8, 16, 32, 64, 128, 256, 512, 1024})}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			arena := NewByteArena(1<<20, tc.opts...)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := arena.AllocBytes(24); err != nil {
+					arena.Reset()
+				}
+			}
+		})
+	}
+}
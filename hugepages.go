@@ -0,0 +1,39 @@
+package atomicarena
+
+// hugePageSize is the size of the huge pages WithHugePages asks for; huge
+// page mappings are aligned to it.
+const hugePageSize = 2 << 20
+
+// hugeBacking records which kind of huge pages back a mapping.
+type hugeBacking uint8
+
+const (
+	hugeNone        hugeBacking = iota // ordinary pages
+	hugeTransparent                    // transparent huge pages, advised with MADV_HUGEPAGE
+	hugeTLB                            // pages from the hugetlbfs pool, mapped with MAP_HUGETLB
+)
+
+// WithHugePages asks for transparent huge pages for an MmapArena's storage,
+// cutting TLB misses on large arenas. The mapping is aligned to 2MB, the
+// size of a huge page, and advised with MADV_HUGEPAGE; the kernel then backs
+// it with huge pages as they become available. If the advice is refused the
+// arena silently keeps ordinary pages. Stats reports the outcome in
+// HugePages. It only has an effect on Linux, and heap-backed constructors
+// reject it with ErrInvalidOptions.
+//
+// With WithPrefault the pages are touched after the advice rather than
+// populated by the kernel, so they can be huge from the start.
+func WithHugePages() Option {
+	return func(o *options) { o.hugePages = true }
+}
+
+// WithHugeTLB maps an MmapArena's storage from the pool of pre-reserved huge
+// pages with MAP_HUGETLB, which unlike WithHugePages guarantees huge pages
+// but fails when the pool is too small, as it is unless the administrator
+// set vm.nr_hugepages. The arena then falls back to WithHugePages. Storage
+// from the pool is never returned before Close: Truncate keeps it mapped and
+// released slots are zeroed instead of dropped. It only has an effect on
+// Linux, and heap-backed constructors reject it with ErrInvalidOptions.
+func WithHugeTLB() Option {
+	return func(o *options) { o.hugeTLB = true }
+}
@@ -0,0 +1,45 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestHugePagesHeapArena rejects huge pages for heap-backed storage
+func TestHugePagesHeapArena(t *testing.T) {
+	if _, err := New[int](4, WithHugePages()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected WithHugePages to be rejected for heap storage, got %v", err)
+	}
+	if _, err := New[int](4, WithHugeTLB()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected WithHugeTLB to be rejected for heap storage, got %v", err)
+	}
+}
+
+// TestMmapHugePagesUsable allocates, truncates and resets an arena that asked for huge pages
+func TestMmapHugePagesUsable(t *testing.T) {
+	for _, opt := range []Option{WithHugePages(), WithHugeTLB()} {
+		m, err := NewMmapArena[int64](1<<18, opt, WithPrefault())
+		if err != nil {
+			t.Fatalf("NewMmapArena failed: %v", err)
+		}
+		seg, _ := m.Reserve(1 << 17)
+		for i := range seg {
+			seg[i] = int64(i)
+		}
+		if err := m.Truncate(1 << 17); err != nil {
+			t.Fatalf("Truncate failed: %v", err)
+		}
+		if err := m.ResetRelease(); err != nil {
+			t.Fatalf("ResetRelease failed: %v", err)
+		}
+		seg, _ = m.Reserve(1 << 17)
+		for i, v := range seg {
+			if v != 0 {
+				t.Fatalf("expected slot %d to be zero after ResetRelease, got %d", i, v)
+			}
+		}
+		if err := m.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrIdxRange is returned when an arena has more slots than an Idx can address.
+var ErrIdxRange = errors.New("atomicarena: index does not fit in Idx")
+
+// Idx is a compact 32-bit reference to a slot of an arena. Elements that refer
+// to each other by Idx rather than by pointer stay pointer-free, so the GC
+// never scans them. Like the indices of AllocIndexed, an Idx names a slot and
+// is only meaningful within the Epoch it was obtained in.
+type Idx uint32
+
+// NoIdx is the null Idx. Arenas addressable by Idx have at most MaxUint32
+// slots, so it never names an allocated slot.
+const NoIdx = Idx(math.MaxUint32)
+
+// NewIdxArena is New for arenas addressed by Idx: it fails with ErrIdxRange if
+// maxElems exceeds math.MaxUint32, so AllocIdx can never run out of indices.
+func NewIdxArena[T any](maxElems uintptr, opts ...Option) (*AtomicArena[T], error) {
+	if uint64(maxElems) > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: %d slots", ErrIdxRange, maxElems)
+	}
+	return New[T](maxElems, opts...)
+}
+
+// AllocIdx stores obj like Alloc and returns a compact reference to it. On an
+// arena with more than math.MaxUint32 slots it fails with ErrIdxRange once the
+// indices outgrow Idx; the slot stays allocated. Use NewIdxArena to rule that
+// out up front.
+func (a *AtomicArena[T]) AllocIdx(obj T) (Idx, error) {
+	idx, _, err := a.alloc(obj)
+	if err != nil {
+		return NoIdx, err
+	}
+	if a.prof != nil {
+		a.prof.sample(1)
+	}
+	if uint64(idx) >= uint64(NoIdx) {
+		return NoIdx, fmt.Errorf("%w: slot %d", ErrIdxRange, idx)
+	}
+	return Idx(idx), nil
+}
+
+// Resolve returns the element i refers to, or nil if i is NoIdx, beyond the
+// allocated slots, tombstoned, or expired under WithTTL.
+func (a *AtomicArena[T]) Resolve(i Idx) *T {
+	if i == NoIdx {
+		return nil
+	}
+	p, _ := a.Get(uintptr(i))
+	return p
+}
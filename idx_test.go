@@ -0,0 +1,175 @@
+package atomicarena
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+// idxNode is a doubly-linked node that refers to its neighbours by Idx, so
+// an arena of them holds no pointers at all.
+type idxNode struct {
+	Value      int64
+	Prev, Next Idx
+}
+
+// idxList is a doubly-linked list built entirely from Idx references.
+type idxList struct {
+	nodes       *AtomicArena[idxNode]
+	front, back Idx
+}
+
+func (l *idxList) pushBack(v int64) Idx {
+	i, err := l.nodes.AllocIdx(idxNode{Value: v, Prev: l.back, Next: NoIdx})
+	if err != nil {
+		panic(err)
+	}
+	if b := l.nodes.Resolve(l.back); b != nil {
+		b.Next = i
+	} else {
+		l.front = i
+	}
+	l.back = i
+	return i
+}
+
+func (l *idxList) remove(i Idx) {
+	n := l.nodes.Resolve(i)
+	if p := l.nodes.Resolve(n.Prev); p != nil {
+		p.Next = n.Next
+	} else {
+		l.front = n.Next
+	}
+	if nx := l.nodes.Resolve(n.Next); nx != nil {
+		nx.Prev = n.Prev
+	} else {
+		l.back = n.Prev
+	}
+	l.nodes.Tombstone(uintptr(i))
+}
+
+func (l *idxList) forward() []int64 {
+	var out []int64
+	for n := l.nodes.Resolve(l.front); n != nil; n = l.nodes.Resolve(n.Next) {
+		out = append(out, n.Value)
+	}
+	return out
+}
+
+func (l *idxList) backward() []int64 {
+	var out []int64
+	for n := l.nodes.Resolve(l.back); n != nil; n = l.nodes.Resolve(n.Prev) {
+		out = append(out, n.Value)
+	}
+	return out
+}
+
+// TestIdxLinkedList builds and edits a doubly-linked list of Idx references
+func TestIdxLinkedList(t *testing.T) {
+	nodes, err := NewIdxArena[idxNode](16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes.pointers {
+		t.Fatalf("expected idxNode to be pointer-free")
+	}
+	l := &idxList{nodes: nodes, front: NoIdx, back: NoIdx}
+	var ids []Idx
+	for v := int64(1); v <= 5; v++ {
+		ids = append(ids, l.pushBack(v))
+	}
+	l.remove(ids[0])
+	l.remove(ids[2])
+	l.remove(ids[4])
+	l.pushBack(6)
+
+	want := []int64{2, 4, 6}
+	got := l.forward()
+	if len(got) != len(want) {
+		t.Fatalf("forward: expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("forward: expected %v, got %v", want, got)
+		}
+	}
+	back := l.backward()
+	for i := range want {
+		if back[len(back)-1-i] != want[i] {
+			t.Fatalf("backward: expected reverse of %v, got %v", want, back)
+		}
+	}
+	if l.nodes.Resolve(ids[2]) != nil || l.nodes.Resolve(NoIdx) != nil || l.nodes.Resolve(Idx(12)) != nil {
+		t.Fatalf("expected removed, null and unallocated references to resolve to nil")
+	}
+}
+
+// TestIdxRange checks the construction-time and allocation-time limits
+func TestIdxRange(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) < 8 {
+		t.Skip("uintptr cannot exceed MaxUint32")
+	}
+	big := uint64(math.MaxUint32) + 1
+	if _, err := NewIdxArena[byte](uintptr(big)); !errors.Is(err, ErrIdxRange) {
+		t.Fatalf("expected ErrIdxRange, got %v", err)
+	}
+	// an arena built without the check reports the first slot beyond Idx
+	a := NewAtomicArena[struct{}](uintptr(big))
+	if _, err := a.Reserve(uintptr(NoIdx)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.AllocIdx(struct{}{}); !errors.Is(err, ErrIdxRange) {
+		t.Fatalf("expected ErrIdxRange for slot MaxUint32, got %v", err)
+	}
+}
+
+// ptrNode is the pointer-based equivalent of idxNode.
+type ptrNode struct {
+	Value      int64
+	Prev, Next *ptrNode
+}
+
+// BenchmarkGCScan measures a full GC with a million linked nodes live, linked
+// by pointers or by Idx. Both arenas skip the pointer mirror so only the
+// element storage differs; the Idx nodes are never scanned.
+func BenchmarkGCScan(b *testing.B) {
+	const n = 1 << 20
+	b.Run("pointers", func(b *testing.B) {
+		a := NewAtomicArena[ptrNode](n, WithoutPointerMirror())
+		var prev *ptrNode
+		for i := 0; i < n; i++ {
+			p, _ := a.Alloc(ptrNode{Value: int64(i), Prev: prev})
+			if prev != nil {
+				prev.Next = p
+			}
+			prev = p
+		}
+		benchGC(b)
+		runtime.KeepAlive(a)
+	})
+	b.Run("idx", func(b *testing.B) {
+		a, _ := NewIdxArena[idxNode](n, WithoutPointerMirror())
+		l := &idxList{nodes: a, front: NoIdx, back: NoIdx}
+		for i := 0; i < n; i++ {
+			l.pushBack(int64(i))
+		}
+		benchGC(b)
+		runtime.KeepAlive(a)
+	})
+}
+
+// benchGC times forced collections and reports the stop-the-world pause per collection.
+func benchGC(b *testing.B) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+	}
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "pause-ns/op")
+}
@@ -0,0 +1,93 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is returned when building an index with RejectDuplicates
+// finds two elements with the same key.
+var ErrDuplicateKey = errors.New("atomicarena: duplicate index key")
+
+// DuplicatePolicy selects how an Index handles elements sharing a key.
+type DuplicatePolicy int
+
+const (
+	// LastWriterWins maps a key to the highest-indexed element carrying it.
+	LastWriterWins DuplicatePolicy = iota
+	// RejectDuplicates makes building the index fail on a repeated key.
+	RejectDuplicates
+)
+
+// Index is a secondary lookup table over the live elements of an arena,
+// keyed by a user-provided extractor. It is built from a single sample of the
+// arena's committed prefix and does not track later allocations, tombstones
+// or expiries until Rebuild is called.
+// Once the arena is reset the index is stale and Get reports misses.
+type Index[K comparable, T any] struct {
+	arena  *AtomicArena[T]
+	key    func(*T) K
+	policy DuplicatePolicy
+	m      map[K]*T
+	epoch  uint64 // arena epoch the index was built against
+}
+
+// BuildIndex builds an Index over the allocated elements of a.
+func BuildIndex[K comparable, T any](a *AtomicArena[T], key func(*T) K, policy DuplicatePolicy) (*Index[K, T], error) {
+	idx := &Index[K, T]{arena: a, key: key, policy: policy}
+	if err := idx.Rebuild(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Rebuild recomputes the index from the arena's committed prefix, leaving
+// out tombstoned and expired elements and slots still being written.
+// On error the index is left empty.
+func (x *Index[K, T]) Rebuild() error {
+	a := x.arena
+	epoch := a.Epoch()
+	n := a.Committed()
+	m := make(map[K]*T, n)
+	pos := make(map[K]uintptr)
+	for i := uintptr(0); i < n; i++ {
+		if a.gone(i) {
+			continue
+		}
+		p := &a.raw[i]
+		k := x.key(p)
+		if x.policy == RejectDuplicates {
+			if first, dup := pos[k]; dup {
+				x.m, x.epoch = nil, epoch
+				return fmt.Errorf("%w: %v at indices %d and %d", ErrDuplicateKey, k, first, i)
+			}
+			pos[k] = i
+		}
+		m[k] = p
+	}
+	x.m, x.epoch = m, epoch
+	return nil
+}
+
+// Get returns the element indexed under k. It always misses on a stale index.
+func (x *Index[K, T]) Get(k K) (*T, bool) {
+	if x.Stale() {
+		return nil, false
+	}
+	p, ok := x.m[k]
+	return p, ok
+}
+
+// Len returns the number of keys in the index, or zero if it is stale.
+func (x *Index[K, T]) Len() int {
+	if x.Stale() {
+		return 0
+	}
+	return len(x.m)
+}
+
+// Stale reports whether the arena has been reset or compacted since the index
+// was built.
+func (x *Index[K, T]) Stale() bool {
+	return x.arena.Epoch() != x.epoch
+}
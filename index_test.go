@@ -0,0 +1,99 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+type indexEntity struct {
+	Name string
+	HP   int
+}
+
+func indexName(e *indexEntity) string { return e.Name }
+
+// TestIndexLastWriterWins ensures the highest index wins for duplicate keys
+func TestIndexLastWriterWins(t *testing.T) {
+	arena := NewAtomicArena[indexEntity](4)
+	_, _ = arena.AppendSlice([]indexEntity{{"orc", 1}, {"elf", 2}, {"orc", 3}})
+	idx, err := BuildIndex(arena, indexName, LastWriterWins)
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	if idx.Len() != 2 {
+		t.Fatalf("expected 2 keys, got %d", idx.Len())
+	}
+	if e, ok := idx.Get("orc"); !ok || e.HP != 3 || e != &arena.raw[2] {
+		t.Errorf("expected last orc, got %+v, %v", e, ok)
+	}
+}
+
+// TestIndexRejectDuplicates ensures duplicate keys are reported
+func TestIndexRejectDuplicates(t *testing.T) {
+	arena := NewAtomicArena[indexEntity](4)
+	_, _ = arena.AppendSlice([]indexEntity{{"orc", 1}, {"elf", 2}, {"orc", 3}})
+	if _, err := BuildIndex(arena, indexName, RejectDuplicates); !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+}
+
+// TestIndexRebuildAfterReset ensures a reset arena makes the index stale
+func TestIndexRebuildAfterReset(t *testing.T) {
+	arena := NewAtomicArena[indexEntity](4)
+	_, _ = arena.Alloc(indexEntity{"orc", 1})
+	idx, err := BuildIndex(arena, indexName, RejectDuplicates)
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+
+	arena.Reset(true)
+	if !idx.Stale() {
+		t.Fatal("expected index to be stale after Reset")
+	}
+	if _, ok := idx.Get("orc"); ok {
+		t.Fatal("stale index returned an entry")
+	}
+	if idx.Len() != 0 {
+		t.Fatalf("expected stale index to be empty, got %d", idx.Len())
+	}
+
+	_, _ = arena.Alloc(indexEntity{"elf", 2})
+	if err := idx.Rebuild(); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if _, ok := idx.Get("orc"); ok {
+		t.Error("rebuilt index still contains reset entry")
+	}
+	if e, ok := idx.Get("elf"); !ok || e.HP != 2 {
+		t.Errorf("expected elf after rebuild, got %+v, %v", e, ok)
+	}
+}
+
+// TestIndexRebuildSkipsDead leaves tombstoned elements and uncommitted slots out of a rebuilt index
+func TestIndexRebuildSkipsDead(t *testing.T) {
+	arena := NewAtomicArena[indexEntity](8)
+	_, _ = arena.AppendSlice([]indexEntity{{"orc", 1}, {"elf", 2}, {"orc", 3}})
+	idx, err := BuildIndex(arena, indexName, LastWriterWins)
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	arena.Tombstone(1)
+	arena.Tombstone(2)
+	seg, _ := arena.Reserve(1)
+	seg[0] = indexEntity{"troll", 4}
+	if err := idx.Rebuild(); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if _, ok := idx.Get("elf"); ok {
+		t.Error("expected the tombstoned elf to miss after Rebuild")
+	}
+	if e, ok := idx.Get("orc"); !ok || e.HP != 1 {
+		t.Errorf("expected the live orc, got %+v, %v", e, ok)
+	}
+	if _, ok := idx.Get("troll"); ok {
+		t.Error("expected the unpublished troll to miss")
+	}
+	if _, err := BuildIndex(arena, indexName, RejectDuplicates); err != nil {
+		t.Fatalf("expected a tombstoned duplicate to be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package atomicarena
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestReserveIndexed checks that the start index locates the segment in raw
+func TestReserveIndexed(t *testing.T) {
+	a := NewAtomicArena[int](32)
+	a.Alloc(7)
+	start, seg, err := a.ReserveIndexed(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 1 || len(seg) != 10 {
+		t.Fatalf("expected 10 slots at 1, got %d at %d", len(seg), start)
+	}
+	for i := range seg {
+		if &a.raw[start+uintptr(i)] != &seg[i] {
+			t.Fatalf("slot %d of the segment is not raw[%d]", i, start+uintptr(i))
+		}
+	}
+	idx, p, err := a.AllocIndexed(42)
+	if err != nil || idx != 11 || *p != 42 {
+		t.Fatalf("expected 42 at 11, got %d, %v", idx, err)
+	}
+	if g, ok := a.Get(idx); !ok || g != p {
+		t.Fatalf("expected Get(%d) to return the allocated pointer", idx)
+	}
+
+	// after Reset the same index names the same slot
+	a.Reset(false)
+	idx2, p2, _ := a.AllocIndexed(1)
+	if idx2 != 0 || p2 != &a.raw[0] {
+		t.Fatalf("expected the first allocation after Reset at index 0, got %d", idx2)
+	}
+
+	if _, _, err := a.ReserveIndexed(64); err == nil {
+		t.Fatalf("expected an oversized reservation to fail")
+	}
+	full := NewAtomicArena[int](0)
+	if _, _, err := full.AllocIndexed(1); err == nil {
+		t.Fatalf("expected AllocIndexed on a full arena to fail")
+	}
+}
+
+// TestReserveIndexedConcurrent ensures concurrent reservations never overlap
+func TestReserveIndexedConcurrent(t *testing.T) {
+	const goroutines, perG = 8, 200
+	a := NewAtomicArena[uint32](goroutines * perG * 4)
+	owner := make([]int32, a.Cap())
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perG; i++ {
+				n := uintptr(i%3 + 1)
+				start, seg, err := a.ReserveIndexed(n)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for j := range seg {
+					seg[j] = uint32(start) + uint32(j)
+				}
+				idx, p, err := a.AllocIndexed(0)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				*p = uint32(idx)
+				mu.Lock()
+				for k := start; k < start+n; k++ {
+					if owner[k] != 0 {
+						t.Errorf("slot %d handed out twice", k)
+					}
+					owner[k] = int32(g + 1)
+				}
+				if owner[idx] != 0 {
+					t.Errorf("slot %d handed out twice", idx)
+				}
+				owner[idx] = int32(g + 1)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for i, v := range a.Snapshot() {
+		if v != uint32(i) {
+			t.Fatalf("slot %d holds %d; its writer used a different index", i, v)
+		}
+	}
+}
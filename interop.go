@@ -0,0 +1,65 @@
+package atomicarena
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// BasePointer returns the address of slot 0, where the arena's storage
+// begins, or nil if the arena has no storage, as after Close. Slot i lives
+// at BasePointer() + i*Stride(). The storage never moves while the arena is
+// in use, except when Truncate copies it with allowMove, so the address
+// stays valid until then or until Close.
+//
+// Heap storage stays allocated only as long as the arena is reachable.
+// Callers handing the address to code the garbage collector cannot see,
+// such as C or a system call, must keep the arena alive until that code is
+// done with it, with runtime.KeepAlive after a synchronous call or with
+// PinnedRegion when C holds on to the address.
+func (a *AtomicArena[T]) BasePointer() unsafe.Pointer {
+	if len(a.raw) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(unsafe.SliceData(a.raw))
+}
+
+// Stride returns the distance in bytes between consecutive slots, which is
+// unsafe.Sizeof(T): the size of the element type, including the padding Go
+// places after its last field to keep the next element aligned.
+// WithBaseAlignment shifts the base but leaves the stride alone.
+func (a *AtomicArena[T]) Stride() uintptr {
+	return unsafe.Sizeof(*new(T))
+}
+
+// UsedBytes returns the size of the allocated region that starts at
+// BasePointer, Len()*Stride(). Writes to its last slots may still be in
+// flight; use Committed()*Stride() to cover only completed ones.
+func (a *AtomicArena[T]) UsedBytes() uintptr {
+	return a.Len() * a.Stride()
+}
+
+// PinnedRegion returns the allocated region, as BasePointer and UsedBytes
+// do, with the storage pinned by a runtime.Pinner, so C code may keep the
+// address beyond the call it was passed to. The region stays pinned until
+// unpin is called, which is safe to call more than once; call it before
+// Truncate moves the storage or Close releases it. Elements containing Go
+// pointers are not pinned through, so only pointer-free data should be
+// handed to C this way. Mapped storage lies outside the Go heap and needs no
+// pinning; pinning it does nothing.
+func (a *AtomicArena[T]) PinnedRegion() (ptr unsafe.Pointer, n uintptr, unpin func()) {
+	ptr, n = a.BasePointer(), a.UsedBytes()
+	if ptr == nil {
+		return nil, 0, func() {}
+	}
+	var p runtime.Pinner
+	p.Pin(ptr)
+	a.pins.Add(1)
+	var once sync.Once
+	return ptr, n, func() {
+		once.Do(func() {
+			p.Unpin()
+			a.pins.Add(-1)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package atomicarena
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+// TestStride checks the stride and region of packed and padded element types
+func TestStride(t *testing.T) {
+	a := NewAtomicArena[int32](8)
+	a.Reserve(3)
+	if a.Stride() != 4 || a.UsedBytes() != 12 {
+		t.Fatalf("expected stride 4 and 12 used bytes, got %d and %d", a.Stride(), a.UsedBytes())
+	}
+	p := NewAtomicArena[padded](8, WithBaseAlignment(256))
+	p.Reserve(3)
+	// the trailing pad depends on int64's alignment, 4 bytes on 386
+	size := unsafe.Sizeof(padded{})
+	if size <= 10 || p.Stride() != size || p.UsedBytes() != 3*size {
+		t.Fatalf("expected padded elements to take %d bytes, got stride %d and %d used bytes", size, p.Stride(), p.UsedBytes())
+	}
+	if uintptr(p.BasePointer())%256 != 0 {
+		t.Fatalf("expected the base to honour WithBaseAlignment, got %p", p.BasePointer())
+	}
+	for i := uintptr(0); i < 3; i++ {
+		s, _ := p.Get(i)
+		if unsafe.Add(p.BasePointer(), i*p.Stride()) != unsafe.Pointer(s) {
+			t.Fatalf("expected slot %d at base + %d*stride", i, i)
+		}
+	}
+	p.Close()
+	if p.BasePointer() != nil {
+		t.Fatalf("expected no base pointer after Close")
+	}
+}
+
+// TestPinnedRegion pins the allocated region and releases the pinner on unpin
+func TestPinnedRegion(t *testing.T) {
+	a := NewAtomicArena[int64](16)
+	a.AppendSlice([]int64{1, 2, 3})
+	ptr, n, unpin := a.PinnedRegion()
+	if ptr != a.BasePointer() || n != 24 || a.pins.Load() != 1 {
+		t.Fatalf("expected 24 pinned bytes at the base, got %d at %p, %d pins", n, ptr, a.pins.Load())
+	}
+	if got := unsafe.Slice((*int64)(ptr), n/8); got[2] != 3 {
+		t.Fatalf("expected the region to hold the elements, got %v", got)
+	}
+	unpin()
+	unpin()
+	if a.pins.Load() != 0 {
+		t.Fatalf("expected unpin to release the pinner once, got %d pins", a.pins.Load())
+	}
+	// a pinner collected while still pinning makes the runtime panic
+	runtime.GC()
+	runtime.GC()
+
+	a.Close()
+	if ptr, n, unpin := a.PinnedRegion(); ptr != nil || n != 0 || a.pins.Load() != 0 {
+		t.Fatalf("expected nothing to pin after Close")
+	} else {
+		unpin()
+	}
+}
+
+// TestMmapBasePointer exposes the mapping's region until Close
+func TestMmapBasePointer(t *testing.T) {
+	m, err := NewMmapArena[padded](64)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	m.Reserve(5)
+	size := unsafe.Sizeof(padded{})
+	if m.BasePointer() == nil || m.Stride() != size || m.UsedBytes() != 5*size {
+		t.Fatalf("expected %d used bytes at stride %d, got %d at stride %d", 5*size, size, m.UsedBytes(), m.Stride())
+	}
+	m.Close()
+	if m.BasePointer() != nil || m.UsedBytes() != 0 {
+		t.Fatalf("expected an empty region after Close")
+	}
+}
@@ -0,0 +1,75 @@
+package atomicarena
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"unsafe"
+)
+
+// ArenaBuffers is a list of segments of a ByteArena, gathered by IOVecs for
+// vectored I/O without copying them into one buffer. Its segments alias the
+// arena and die at Reset; WriteTo refuses to write them afterwards.
+type ArenaBuffers struct {
+	// Buffers holds the segments, for APIs that take net.Buffers directly.
+	// Writing them that way skips the Reset check done by WriteTo.
+	Buffers net.Buffers
+	arena   *AtomicArena[byte]
+	epoch   uint64 // arena epoch the segments belong to
+}
+
+// IOVecs returns the arena bytes [lo, hi) of each range, in order, as
+// segments for vectored I/O, without copying them. Offsets count from the
+// start of the arena; Offset finds that of a buffer from AllocBytes. Each
+// range must lie within the allocated bytes, or IOVecs fails with
+// ErrOutOfRange. A ByteArena does not record where its buffers begin and
+// end, so the caller names the ranges to send; there is no collector of
+// every live buffer, which would also pick up alignment padding.
+func (b *ByteArena) IOVecs(ranges [][2]uintptr) (*ArenaBuffers, error) {
+	a := b.arena
+	v := &ArenaBuffers{Buffers: make(net.Buffers, 0, len(ranges)), arena: a, epoch: a.Epoch()}
+	n := a.Len()
+	for _, r := range ranges {
+		lo, hi := r[0], r[1]
+		if lo > hi || hi > n {
+			return nil, fmt.Errorf("%w: range [%d, %d), len %d", ErrOutOfRange, lo, hi, n)
+		}
+		v.Buffers = append(v.Buffers, a.raw[lo:hi:hi])
+	}
+	return v, nil
+}
+
+// Offset returns the offset from the start of the arena of buf, a buffer
+// returned by the arena, for use in an IOVecs range. It reports false if buf
+// does not lie in the arena's storage.
+func (b *ByteArena) Offset(buf []byte) (uintptr, bool) {
+	raw := b.arena.raw
+	if len(buf) == 0 || len(raw) == 0 {
+		return 0, false
+	}
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(raw)))
+	p := uintptr(unsafe.Pointer(unsafe.SliceData(buf)))
+	if p < base || p-base > uintptr(len(raw))-uintptr(len(buf)) {
+		return 0, false
+	}
+	return p - base, true
+}
+
+// Stale reports whether the arena has been reset since IOVecs gathered the
+// segments, so they may hold other data.
+func (v *ArenaBuffers) Stale() bool {
+	return v.arena.Epoch() != v.epoch
+}
+
+// WriteTo writes the segments to w with net.Buffers.WriteTo, which hands
+// them to the kernel in a single writev on connections that support it and
+// writes them one by one otherwise. Like net.Buffers it consumes the
+// segments it writes. It returns ErrStale without writing if the arena has
+// been reset since IOVecs; the check cannot catch a Reset during the write,
+// which the caller must rule out.
+func (v *ArenaBuffers) WriteTo(w io.Writer) (int64, error) {
+	if v.Stale() {
+		return 0, ErrStale
+	}
+	return v.Buffers.WriteTo(w)
+}
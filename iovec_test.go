@@ -0,0 +1,73 @@
+package atomicarena
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+// TestIOVecsPipe writes chosen arena segments through a net.Pipe and checks the bytes arrive in order
+func TestIOVecsPipe(t *testing.T) {
+	b := NewByteArena(1 << 10)
+	var ranges [][2]uintptr
+	var want []byte
+	for i, s := range []string{"header:", "skipped", "payload-1,", "payload-2", "trailer"} {
+		buf, err := b.CopyBytes([]byte(s))
+		if err != nil {
+			t.Fatalf("CopyBytes failed: %v", err)
+		}
+		if i == 1 {
+			continue
+		}
+		off, ok := b.Offset(buf)
+		if !ok {
+			t.Fatalf("expected buffer %d to lie in the arena", i)
+		}
+		ranges = append(ranges, [2]uintptr{off, off + uintptr(len(buf))})
+		want = append(want, s...)
+	}
+	v, err := b.IOVecs(ranges)
+	if err != nil {
+		t.Fatalf("IOVecs failed: %v", err)
+	}
+	w, r := net.Pipe()
+	got := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		got <- data
+	}()
+	n, err := v.WriteTo(w)
+	w.Close()
+	if err != nil || n != int64(len(want)) {
+		t.Fatalf("expected %d bytes written, got %d and %v", len(want), n, err)
+	}
+	if data := <-got; !bytes.Equal(data, want) {
+		t.Fatalf("expected %q, got %q", want, data)
+	}
+}
+
+// TestIOVecsChecks rejects ranges past the allocated bytes, foreign buffers and writes after Reset
+func TestIOVecsChecks(t *testing.T) {
+	b := NewByteArena(64)
+	buf, _ := b.AllocBytes(16)
+	if _, err := b.IOVecs([][2]uintptr{{8, 17}}); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange past the allocated bytes, got %v", err)
+	}
+	if _, err := b.IOVecs([][2]uintptr{{9, 8}}); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange for a reversed range, got %v", err)
+	}
+	if _, ok := b.Offset(make([]byte, 4)); ok {
+		t.Fatalf("expected a heap buffer not to lie in the arena")
+	}
+	v, err := b.IOVecs([][2]uintptr{{0, 16}})
+	if err != nil || len(v.Buffers) != 1 || &v.Buffers[0][0] != &buf[0] || cap(v.Buffers[0]) != 16 {
+		t.Fatalf("expected one clipped segment aliasing the buffer, got %v", err)
+	}
+	b.Reset()
+	var out bytes.Buffer
+	if n, err := v.WriteTo(&out); !errors.Is(err, ErrStale) || n != 0 || out.Len() != 0 {
+		t.Fatalf("expected ErrStale and nothing written after Reset, got %d and %v", n, err)
+	}
+}
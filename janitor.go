@@ -0,0 +1,49 @@
+package atomicarena
+
+import (
+	"sync"
+	"time"
+)
+
+// StartJanitor launches a goroutine that resets the arena (zeroing its storage
+// too if release is set) once it has gone a full interval without any slot
+// being reserved. Activity is detected by sampling the reservation counter and
+// epoch on each tick, so the allocation path carries no clock reads.
+// The reset is committed with a CAS against the sampled count, so an
+// allocation that lands after the idle check makes the janitor back off
+// instead of discarding it.
+// Under WithTTL each tick also tombstones the elements that have expired.
+// The returned stop function terminates the goroutine and waits for it to exit.
+func (a *AtomicArena[T]) StartJanitor(interval time.Duration, release bool) (stop func()) {
+	quit := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := a.opts.timeSource().NewTicker(interval)
+		defer ticker.Stop()
+		last, lastEpoch := a.count.Load(), a.Epoch()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C():
+			}
+			a.expireStale()
+			c, epoch := a.count.Load(), a.Epoch()
+			if c == last && epoch == lastEpoch && c&countMask != 0 {
+				// untouched for a full interval; reset only if that still holds
+				_, _, _ = a.rewind(release, func(cur uintptr) bool {
+					return cur == last && a.Epoch() == lastEpoch
+				})
+			}
+			last, lastEpoch = a.count.Load(), a.Epoch()
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(quit)
+			<-exited
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package atomicarena_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Raezil/atomicarena"
+	"github.com/Raezil/atomicarena/atomicarenatest"
+)
+
+// waitFor yields until cond holds, failing t if it does not within a second.
+// It never sleeps; the janitor runs as soon as it is scheduled.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		runtime.Gosched()
+	}
+}
+
+// TestJanitorResetsIdleArena ensures an idle arena is reset and zeroed
+func TestJanitorResetsIdleArena(t *testing.T) {
+	clock := atomicarenatest.NewFakeClock(time.Unix(0, 0))
+	arena := atomicarena.NewAtomicArena[int](8, atomicarena.WithClock(clock))
+	_, _ = arena.AppendSlice([]int{1, 2, 3})
+	stop := arena.StartJanitor(time.Minute, true)
+	defer stop()
+	clock.BlockUntil(1)
+	// a tick before a full interval has passed changes nothing
+	clock.Advance(30 * time.Second)
+	if arena.Len() != 3 {
+		t.Fatal("janitor reset the arena early")
+	}
+	clock.Advance(30 * time.Second)
+	waitFor(t, func() bool { return arena.Len() == 0 })
+	stop()
+	seg, _ := arena.Reserve(3)
+	for i, v := range seg {
+		if v != 0 {
+			t.Fatalf("slot %d not released", i)
+		}
+	}
+}
+
+// TestJanitorNeverDropsActiveWrites keeps allocating while the janitor runs
+// and checks it only ever resets the arena as a whole: whatever survives must
+// be a gapless run of the most recent values.
+func TestJanitorNeverDropsActiveWrites(t *testing.T) {
+	const n = 20_000
+	clock := atomicarenatest.NewFakeClock(time.Unix(0, 0))
+	arena := atomicarena.NewAtomicArena[int](1<<20, atomicarena.WithClock(clock))
+	stop := arena.StartJanitor(100*time.Microsecond, true)
+	clock.BlockUntil(1)
+	for i := 1; i <= n; i++ {
+		if _, err := arena.Alloc(i); err != nil {
+			t.Fatalf("Alloc failed: %v", err)
+		}
+		if i%100 == 0 {
+			// ticks land while allocations continue
+			clock.Advance(100 * time.Microsecond)
+		}
+		if i%1000 == 0 {
+			// give the janitor idle periods
+			clock.Advance(100 * time.Microsecond)
+			runtime.Gosched()
+			clock.Advance(100 * time.Microsecond)
+			runtime.Gosched()
+		}
+	}
+	stop()
+	l := arena.Len()
+	for k := uintptr(0); k < l; k++ {
+		if p, _ := arena.Get(k); *p != n-int(l)+1+int(k) {
+			t.Fatalf("slot %d holds %d, want %d (len %d)", k, *p, n-int(l)+1+int(k), l)
+		}
+	}
+}
+
+// TestJanitorStopNoLeak ensures stop terminates the janitor goroutine
+func TestJanitorStopNoLeak(t *testing.T) {
+	clock := atomicarenatest.NewFakeClock(time.Unix(0, 0))
+	before := runtime.NumGoroutine()
+	var stops []func()
+	for i := 0; i < 10; i++ {
+		stops = append(stops, atomicarena.NewAtomicArena[int](1, atomicarena.WithClock(clock)).StartJanitor(time.Millisecond, false))
+	}
+	clock.BlockUntil(10)
+	var wg sync.WaitGroup
+	for _, stop := range stops {
+		wg.Add(1)
+		go func(stop func()) {
+			defer wg.Done()
+			stop()
+			stop() // idempotent
+		}(stop)
+	}
+	wg.Wait()
+	if n := clock.Waiters(); n != 0 {
+		t.Fatalf("expected every ticker to be stopped, %d left", n)
+	}
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before })
+}
@@ -0,0 +1,87 @@
+package atomicarena
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// UnmarshalJSONInto decodes the JSON array in data straight into a, one
+// element per slot, instead of decoding into a slice and copying it in. Each
+// element counts as written, and is published in the pointer mirror, as soon
+// as it has been decoded. It returns the number of elements stored. If the
+// arena fills up, decoding stops with a *CapacityError wrapping ErrArenaFull;
+// on malformed JSON it stops with the decoder's error. Either way the
+// elements decoded before stay in the arena. A JSON null stores nothing.
+func UnmarshalJSONInto[T any](data []byte, a *AtomicArena[T]) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	n, err := DecodeJSONInto(dec, a)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return n, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("atomicarena: data after the top-level JSON array")
+		}
+		return n, err
+	}
+	return n, nil
+}
+
+// DecodeJSONInto is UnmarshalJSONInto for a stream: it reads the next JSON
+// array from dec token by token, so the input is never held in memory at
+// once. Settings such as UseNumber or DisallowUnknownFields apply to the
+// elements. It returns io.EOF if the stream holds no further value. When the
+// arena fills up, the element that did not fit has not been read yet.
+func DecodeJSONInto[T any](dec *json.Decoder, a *AtomicArena[T]) (int, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, err
+	}
+	if tok == nil {
+		return 0, nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return 0, fmt.Errorf("atomicarena: expected a JSON array, got %v", tok)
+	}
+	n := 0
+	for dec.More() {
+		if err := a.decodeSlot(dec); err != nil {
+			return n, err
+		}
+		n++
+	}
+	// the closing bracket
+	if _, err := dec.Token(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return n, err
+	}
+	return n, nil
+}
+
+// decodeSlot reserves one slot and decodes the next value from dec into it.
+// A slot whose value fails to decode is cleared and given back.
+func (a *AtomicArena[T]) decodeSlot(dec *json.Decoder) error {
+	idx, err := a.reserve(1)
+	if err != nil {
+		return a.allocErr(err, idx, 1)
+	}
+	seg := a.raw[idx : idx+1]
+	// Decode merges into what is there, so stale values must go first
+	a.zeroStale(idx, seg)
+	if err := dec.Decode(&seg[0]); err != nil {
+		clear(seg)
+		a.commitPartial(idx, 1, 0)
+		return err
+	}
+	a.publish(idx, idx+1)
+	a.commit(1)
+	return nil
+}
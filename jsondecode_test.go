@@ -0,0 +1,160 @@
+package atomicarena
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// telemetry is a nested element type for the JSON decoding tests.
+type telemetry struct {
+	Host  string            `json:"host"`
+	At    position          `json:"at"`
+	Tags  []string          `json:"tags,omitempty"`
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// TestUnmarshalJSONInto decodes nested structs and checks every slot
+func TestUnmarshalJSONInto(t *testing.T) {
+	a := NewAtomicArena[telemetry](8)
+	// a stale value from an earlier epoch must not be merged into slot 0
+	a.Alloc(telemetry{Host: "old", Tags: []string{"stale"}, Extra: map[string]string{"k": "v"}})
+	a.Reset(false)
+	data := `[{"host":"a","at":{"X":1,"Y":2},"tags":["x","y"]},{"host":"b","at":{"X":3,"Y":4}}]`
+	n, err := UnmarshalJSONInto([]byte(data), a)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 elements, got %d, %v", n, err)
+	}
+	p, _ := a.Get(0)
+	if p.Host != "a" || p.At != (position{1, 2}) || len(p.Tags) != 2 || p.Tags[1] != "y" || p.Extra != nil {
+		t.Fatalf("unexpected slot 0 %+v", *p)
+	}
+	if q, _ := a.Get(1); q.Host != "b" || q.At != (position{3, 4}) || q.Tags != nil {
+		t.Fatalf("unexpected slot 1 %+v", *q)
+	}
+	if a.Len() != 2 || a.Committed() != 2 || a.LoadPointer(1) == nil {
+		t.Fatalf("expected both slots committed and published, got len %d", a.Len())
+	}
+	if n, err := UnmarshalJSONInto([]byte(" null "), a); err != nil || n != 0 || a.Len() != 2 {
+		t.Fatalf("expected null to store nothing, got %d, %v", n, err)
+	}
+}
+
+// TestUnmarshalJSONIntoFull stops at the capacity and reports how much was stored
+func TestUnmarshalJSONIntoFull(t *testing.T) {
+	a := NewAtomicArena[int](3)
+	n, err := UnmarshalJSONInto([]byte(`[1, 2, 3, 4, 5]`), a)
+	if ce := capacityErr(t, err); ce.Requested != 1 || ce.Available != 0 {
+		t.Fatalf("unexpected capacity error %+v", *ce)
+	}
+	if n != 3 || a.Len() != 3 {
+		t.Fatalf("expected 3 elements stored, got %d, len %d", n, a.Len())
+	}
+	if got := a.Snapshot(); fmt.Sprint(got) != "[1 2 3]" {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+}
+
+// TestUnmarshalJSONIntoMalformed reports the good elements and the decoder's error
+func TestUnmarshalJSONIntoMalformed(t *testing.T) {
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	for _, tc := range []struct {
+		data string
+		n    int
+		ok   func(error) bool
+	}{
+		{`[{"X":1},{"X":2},{"X":`, 2, func(err error) bool { return errors.Is(err, io.ErrUnexpectedEOF) }},
+		{`[{"X":1},{"X":2},{"X":3}`, 3, func(err error) bool { return err != nil }},
+		{`[{"X":1},{"X":2} {"X":3}]`, 2, func(err error) bool { return errors.As(err, &syntax) }},
+		{`[{"X":1},{"X":"two"}]`, 1, func(err error) bool { return errors.As(err, &typ) }},
+		{`[{"X":1}] [{"X":2}]`, 1, func(err error) bool { return err != nil }},
+		{`{"X":1}`, 0, func(err error) bool { return err != nil && strings.Contains(err.Error(), "expected a JSON array") }},
+		{``, 0, func(err error) bool { return errors.Is(err, io.ErrUnexpectedEOF) }},
+	} {
+		a := NewAtomicArena[position](8)
+		n, err := UnmarshalJSONInto([]byte(tc.data), a)
+		if n != tc.n || !tc.ok(err) {
+			t.Fatalf("%s: expected %d elements and a matching error, got %d, %v", tc.data, tc.n, n, err)
+		}
+		// the element that failed to decode was given back
+		if a.Len() != uintptr(tc.n) {
+			t.Fatalf("%s: expected len %d, got %d", tc.data, tc.n, a.Len())
+		}
+		if tc.n > 0 {
+			if p, _ := a.Get(uintptr(tc.n - 1)); p.X != float64(tc.n) {
+				t.Fatalf("%s: unexpected last element %+v", tc.data, *p)
+			}
+		}
+	}
+}
+
+// TestDecodeJSONInto streams several arrays through one decoder
+func TestDecodeJSONInto(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`[1,2] [] [3] [4,5,6]`))
+	a := NewAtomicArena[int](5)
+	var counts []int
+	for {
+		n, err := DecodeJSONInto(dec, a)
+		counts = append(counts, n)
+		if err == io.EOF {
+			t.Fatalf("expected the arena to fill before the stream ends")
+		}
+		if err != nil {
+			if !errors.Is(err, ErrArenaFull) {
+				t.Fatalf("expected ErrArenaFull, got %v", err)
+			}
+			break
+		}
+	}
+	if fmt.Sprint(counts) != "[2 0 1 2]" || fmt.Sprint(a.Snapshot()) != "[1 2 3 4 5]" {
+		t.Fatalf("unexpected counts %v or contents %v", counts, a.Snapshot())
+	}
+	// the element that did not fit is still the next value in the stream
+	var rest int
+	if err := dec.Decode(&rest); err != nil || rest != 6 {
+		t.Fatalf("expected 6 to be left in the stream, got %d, %v", rest, err)
+	}
+	if _, err := DecodeJSONInto(json.NewDecoder(strings.NewReader(" ")), a); err != io.EOF {
+		t.Fatalf("expected io.EOF at the end of the stream, got %v", err)
+	}
+}
+
+// BenchmarkUnmarshalJSON compares decoding into the arena against decoding a
+// slice and appending it
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	const n = 10000
+	vals := make([]telemetry, n)
+	for i := range vals {
+		vals[i] = telemetry{Host: fmt.Sprintf("host-%d", i%16), At: position{float64(i), float64(-i)}}
+	}
+	data, _ := json.Marshal(vals)
+	a := NewAtomicArena[telemetry](n)
+	b.Run("into", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			a.Reset(false)
+			if _, err := UnmarshalJSONInto(data, a); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("slice+AppendSlice", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			a.Reset(false)
+			var out []telemetry
+			if err := json.Unmarshal(data, &out); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := a.AppendSlice(out); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package atomicarena
+
+// Last returns the most recently committed element that is not tombstoned,
+// or false if there is none, as on a fresh or reset arena. It is based on
+// Committed, so it never returns a slot whose write is still in flight, and
+// is safe next to concurrent Alloc calls.
+func (a *AtomicArena[T]) Last() (*T, bool) {
+	for i := a.Committed(); i > 0; i-- {
+		if !a.tombstoned(i - 1) {
+			return &a.raw[i-1], true
+		}
+	}
+	return nil, false
+}
+
+// PeekN returns copies of up to the last k committed elements that are not
+// tombstoned, oldest first. Like Last, it reads only committed slots; it
+// returns nil if there are none or k is not positive.
+func (a *AtomicArena[T]) PeekN(k int) []T {
+	if k <= 0 {
+		return nil
+	}
+	var out []T
+	i := a.Committed()
+	for ; i > 0 && len(out) < k; i-- {
+		if !a.tombstoned(i - 1) {
+			out = append(out, a.raw[i-1])
+		}
+	}
+	for l, r := 0, len(out)-1; l < r; l, r = l+1, r-1 {
+		out[l], out[r] = out[r], out[l]
+	}
+	return out
+}
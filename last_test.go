@@ -0,0 +1,78 @@
+package atomicarena
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestLastPeekN covers fresh, tombstoned and reset arenas
+func TestLastPeekN(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	if _, ok := a.Last(); ok || a.PeekN(3) != nil {
+		t.Fatalf("expected nothing on a fresh arena")
+	}
+	for i := 1; i <= 5; i++ {
+		a.Alloc(i)
+	}
+	if p, ok := a.Last(); !ok || *p != 5 {
+		t.Fatalf("expected 5, got %v, %v", p, ok)
+	}
+	a.Tombstone(4)
+	if p, ok := a.Last(); !ok || *p != 4 {
+		t.Fatalf("expected the tombstoned tail skipped, got %v, %v", p, ok)
+	}
+	if got := a.PeekN(3); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Fatalf("expected [2 3 4], got %v", got)
+	}
+	if got := a.PeekN(10); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("expected every live element, got %v", got)
+	}
+	if a.PeekN(0) != nil {
+		t.Fatalf("expected nil for k = 0")
+	}
+	a.Reset(false)
+	if _, ok := a.Last(); ok || len(a.PeekN(2)) != 0 {
+		t.Fatalf("expected nothing after Reset")
+	}
+}
+
+// TestLastConcurrent reads Last while writers allocate; every value seen
+// must have been fully written. Run with -race
+func TestLastConcurrent(t *testing.T) {
+	type entry struct{ A, B int }
+	a := NewAtomicArena[entry](20000)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 5000; i++ {
+				a.Alloc(entry{i, -i})
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			if p, ok := a.Last(); !ok || p.A == 0 {
+				t.Fatalf("expected a last element after the writers finished")
+			}
+			return
+		default:
+		}
+		if p, ok := a.Last(); ok && (p.A == 0 || p.B != -p.A) {
+			t.Fatalf("Last returned a value that was never committed: %+v", *p)
+		}
+		for _, e := range a.PeekN(4) {
+			if e.A == 0 || e.B != -e.A {
+				t.Fatalf("PeekN returned a value that was never committed: %+v", e)
+			}
+		}
+	}
+}
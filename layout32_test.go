@@ -0,0 +1,37 @@
+//go:build 386 || arm || mips || mipsle
+
+package atomicarena
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// TestLayout32 asserts that every 64-bit atomic is 8-byte aligned on 32-bit
+// platforms, where misaligned 64-bit atomics panic
+func TestLayout32(t *testing.T) {
+	check := func(name string, offset, align uintptr) {
+		t.Helper()
+		if offset%8 != 0 || align < 8 {
+			t.Errorf("%s at offset %d with alignment %d", name, offset, align)
+		}
+	}
+	var a AtomicArena[byte]
+	check("AtomicArena.epoch", unsafe.Offsetof(a.epoch), unsafe.Alignof(a.epoch))
+	var s Stack[byte]
+	check("Stack.head", unsafe.Offsetof(s.head), unsafe.Alignof(s.head))
+	check("Stack.free", unsafe.Offsetof(s.free), unsafe.Alignof(s.free))
+	check("Stack.n", unsafe.Offsetof(s.n), unsafe.Alignof(s.n))
+	var d DoubleBuffer[byte]
+	check("DoubleBuffer.inflight", unsafe.Offsetof(d.inflight), unsafe.Alignof(d.inflight))
+	var pool ArenaPool[byte]
+	check("ArenaPool.created", unsafe.Offsetof(pool.created), unsafe.Alignof(pool.created))
+
+	// heap-allocated values and slices of them inherit the alignment
+	for i := 0; i < 16; i++ {
+		p := NewAtomicArena[byte](1)
+		if uintptr(unsafe.Pointer(&p.epoch))%8 != 0 || uintptr(unsafe.Pointer(&p.dead[0]))%8 != 0 {
+			t.Fatal("misaligned 64-bit atomic in a heap arena")
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package atomicarena
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// WithLeakWarning reports arenas that are garbage-collected without Close,
+// which would otherwise leak what Close releases: a mapping, a budget
+// reservation or a registry entry. The arena records its creation stack,
+// and if it is collected while still open, logf is called with a message
+// naming the arena and that stack. The check is removed by Close.
+//
+// logf runs on a runtime goroutine after collection and must not block. It
+// is held by the runtime, not the arena, so it must not refer to the arena
+// itself or the arena is never collected. Before Go 1.24 named arenas stay
+// registered until Close, so only unnamed arenas can be reported.
+func WithLeakWarning(logf func(format string, args ...any)) Option {
+	return func(o *options) { o.leakLogf = logf }
+}
+
+// leakReport is what a leaked arena tells its leak check. It must not point
+// back at the arena, or the arena could never be collected.
+type leakReport struct {
+	label string
+	pcs   []uintptr
+	logf  func(string, ...any)
+}
+
+// newLeakReport captures the stack of the arena constructor's caller.
+func newLeakReport(label string, logf func(string, ...any)) leakReport {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return leakReport{label: label, pcs: pcs[:n], logf: logf}
+}
+
+// report logs the leak with the creation stack, leaving out the package's
+// own constructor frames.
+func (r leakReport) report() {
+	var b strings.Builder
+	frames := runtime.CallersFrames(r.pcs)
+	ctor := true
+	for {
+		f, more := frames.Next()
+		name, _ := strings.CutPrefix(f.Function, "github.com/Raezil/atomicarena.")
+		if ctor = ctor && name != f.Function && strings.HasPrefix(strings.ToLower(name), "new"); !ctor {
+			fmt.Fprintf(&b, "\n\t%s\n\t\t%s:%d", f.Function, f.File, f.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	r.logf("atomicarena: %s garbage-collected without Close; created at:%s", r.label, b.String())
+}
+
+// leakLabel describes an arena in a leak warning.
+func (a *AtomicArena[T]) leakLabel() string {
+	if a.opts.name != "" {
+		return fmt.Sprintf("arena %q", a.opts.name)
+	}
+	return a.traceLabel()
+}
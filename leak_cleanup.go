@@ -0,0 +1,19 @@
+//go:build go1.24
+
+package atomicarena
+
+import "runtime"
+
+// leakCheck is the cleanup that reports a leaked arena.
+type leakCheck = runtime.Cleanup
+
+// watchLeak attaches a cleanup that reports a when it is collected. The
+// cleanup holds only r, so it neither keeps a nor its storage alive.
+func watchLeak[T any](a *AtomicArena[T], r leakReport) leakCheck {
+	return runtime.AddCleanup(a, leakReport.report, r)
+}
+
+// unwatchLeak removes the leak check; Close calls it.
+func unwatchLeak[T any](a *AtomicArena[T]) {
+	a.leak.Stop()
+}
@@ -0,0 +1,20 @@
+//go:build !go1.24
+
+package atomicarena
+
+import "runtime"
+
+// leakCheck is unused before Go 1.24, where the check is a finalizer.
+type leakCheck struct{}
+
+// watchLeak sets a finalizer that reports a when it is collected. The
+// finalizer only reads r, so a is released again right after it runs.
+func watchLeak[T any](a *AtomicArena[T], r leakReport) leakCheck {
+	runtime.SetFinalizer(a, func(*AtomicArena[T]) { r.report() })
+	return leakCheck{}
+}
+
+// unwatchLeak removes the leak check; Close calls it.
+func unwatchLeak[T any](a *AtomicArena[T]) {
+	runtime.SetFinalizer(a, nil)
+}
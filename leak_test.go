@@ -0,0 +1,101 @@
+package atomicarena
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// leakLog collects leak warnings.
+type leakLog struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *leakLog) logf(format string, args ...any) {
+	l.mu.Lock()
+	l.msgs = append(l.msgs, fmt.Sprintf(format, args...))
+	l.mu.Unlock()
+}
+
+func (l *leakLog) lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.msgs...)
+}
+
+// awaitLeak collects garbage until a warning arrives or the deadline passes.
+func awaitLeak(l *leakLog, d time.Duration) []string {
+	deadline := time.Now().Add(d)
+	for {
+		runtime.GC()
+		if msgs := l.lines(); len(msgs) > 0 || time.Now().After(deadline) {
+			return msgs
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+//go:noinline
+func leakArena(l *leakLog) {
+	a := NewAtomicArena[int64](1024, WithName("leaky"), WithLeakWarning(l.logf))
+	a.Alloc(1)
+}
+
+// TestLeakWarning drops an open arena and expects a warning with its name
+// and creation stack
+func TestLeakWarning(t *testing.T) {
+	if !weakRegistry {
+		t.Skip("named arenas stay registered before Go 1.24")
+	}
+	l := &leakLog{}
+	leakArena(l)
+	msgs := awaitLeak(l, 5*time.Second)
+	if len(msgs) != 1 {
+		t.Fatalf("expected one warning, got %q", msgs)
+	}
+	msg := msgs[0]
+	if !strings.HasPrefix(msg, `atomicarena: arena "leaky" garbage-collected without Close`) {
+		t.Fatalf("unexpected warning %q", msg)
+	}
+	if !strings.Contains(msg, "atomicarena.leakArena") || strings.Contains(msg, "newAtomicArena") {
+		t.Fatalf("expected the stack to start at the caller, got:\n%s", msg)
+	}
+}
+
+// TestLeakWarningMmap covers mapped arenas, whose mapping a leak never unmaps
+func TestLeakWarningMmap(t *testing.T) {
+	l := &leakLog{}
+	func() {
+		m, err := NewMmapArena[int64](64, WithLeakWarning(l.logf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Alloc(1)
+	}()
+	msgs := awaitLeak(l, 5*time.Second)
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "AtomicArena[int64]") {
+		t.Fatalf("expected a warning for the unnamed arena, got %q", msgs)
+	}
+}
+
+// TestLeakWarningClosed ensures Close removes the check
+func TestLeakWarningClosed(t *testing.T) {
+	l := &leakLog{}
+	func() {
+		a := NewAtomicArena[int64](16, WithName("tidy"), WithLeakWarning(l.logf))
+		a.Alloc(1)
+		a.Close()
+		m, err := NewMmapArena[int64](16, WithLeakWarning(l.logf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Close()
+	}()
+	if msgs := awaitLeak(l, 50*time.Millisecond); len(msgs) != 0 {
+		t.Fatalf("expected no warning after Close, got %q", msgs)
+	}
+}
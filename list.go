@@ -0,0 +1,121 @@
+package atomicarena
+
+// Node is a doubly linked list node allocated from an arena. List maintains
+// Next and Prev for its own nodes; intrusive structures can allocate Nodes
+// from an AtomicArena[Node[T]] directly and link them however they like.
+type Node[T any] struct {
+	Value      T
+	Next, Prev *Node[T]
+	list       *List[T] // owning List, nil for free or foreign nodes
+}
+
+// List is a doubly linked list whose nodes live in a fixed-capacity arena.
+// Removed nodes are kept on a free list and reused by later pushes, so a
+// list can churn indefinitely as long as it never holds more than its
+// capacity at once. A List is not safe for concurrent use.
+type List[T any] struct {
+	arena       *AtomicArena[Node[T]]
+	front, back *Node[T]
+	free        *Node[T] // removed nodes, chained through Next
+	n           int
+}
+
+// NewList creates an empty list that can hold up to capacity nodes.
+func NewList[T any](capacity uintptr) *List[T] {
+	return &List[T]{arena: NewAtomicArena[Node[T]](capacity)}
+}
+
+// node returns a fresh node from the free list or the arena.
+func (l *List[T]) node(v T) (*Node[T], error) {
+	n := l.free
+	if n != nil {
+		l.free = n.Next
+		*n = Node[T]{Value: v}
+	} else {
+		var err error
+		if n, err = l.arena.Alloc(Node[T]{Value: v}); err != nil {
+			return nil, err
+		}
+	}
+	n.list = l
+	l.n++
+	return n, nil
+}
+
+// PushFront inserts v at the front of the list and returns its node.
+func (l *List[T]) PushFront(v T) (*Node[T], error) {
+	n, err := l.node(v)
+	if err != nil {
+		return nil, err
+	}
+	n.Next = l.front
+	if l.front != nil {
+		l.front.Prev = n
+	} else {
+		l.back = n
+	}
+	l.front = n
+	return n, nil
+}
+
+// PushBack inserts v at the back of the list and returns its node.
+func (l *List[T]) PushBack(v T) (*Node[T], error) {
+	n, err := l.node(v)
+	if err != nil {
+		return nil, err
+	}
+	n.Prev = l.back
+	if l.back != nil {
+		l.back.Next = n
+	} else {
+		l.front = n
+	}
+	l.back = n
+	return n, nil
+}
+
+// Remove unlinks n and recycles its slot. It reports false if n does not
+// belong to the list. n must not be used after it has been removed.
+func (l *List[T]) Remove(n *Node[T]) bool {
+	if n == nil || n.list != l {
+		return false
+	}
+	if n.Prev != nil {
+		n.Prev.Next = n.Next
+	} else {
+		l.front = n.Next
+	}
+	if n.Next != nil {
+		n.Next.Prev = n.Prev
+	} else {
+		l.back = n.Prev
+	}
+	*n = Node[T]{Next: l.free}
+	l.free = n
+	l.n--
+	return true
+}
+
+// Front returns the first node, or nil if the list is empty.
+func (l *List[T]) Front() *Node[T] {
+	return l.front
+}
+
+// Back returns the last node, or nil if the list is empty.
+func (l *List[T]) Back() *Node[T] {
+	return l.back
+}
+
+// Len returns the number of nodes in the list.
+func (l *List[T]) Len() int {
+	return l.n
+}
+
+// Iterate calls fn with each value from front to back until fn returns false.
+func (l *List[T]) Iterate(fn func(v *T) bool) {
+	for n := l.front; n != nil; n = n.Next {
+		if !fn(&n.Value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package atomicarena
+
+import (
+	"container/list"
+	"slices"
+	"testing"
+)
+
+func listValues(l *List[int]) []int {
+	var out []int
+	l.Iterate(func(v *int) bool {
+		out = append(out, *v)
+		return true
+	})
+	return out
+}
+
+// TestListBasic covers pushes, removal from every position and iteration
+func TestListBasic(t *testing.T) {
+	l := NewList[int](8)
+	two, _ := l.PushBack(2)
+	l.PushBack(3)
+	one, _ := l.PushFront(1)
+	four, _ := l.PushBack(4)
+	if got := listValues(l); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("unexpected order %v", got)
+	}
+	if !l.Remove(two) || !l.Remove(one) || !l.Remove(four) {
+		t.Fatal("Remove failed")
+	}
+	if l.Remove(two) {
+		t.Fatal("removed a node twice")
+	}
+	if got := listValues(l); !slices.Equal(got, []int{3}) || l.Front() != l.Back() || l.Len() != 1 {
+		t.Fatalf("unexpected list %v", got)
+	}
+	other := NewList[int](1)
+	if other.Remove(l.Front()) {
+		t.Fatal("removed a node from the wrong list")
+	}
+}
+
+// TestListReuse churns far more nodes through the list than its arena holds
+func TestListReuse(t *testing.T) {
+	l := NewList[int](100)
+	for i := 0; i < 1_000_000; i++ {
+		if _, err := l.PushBack(i); err != nil {
+			t.Fatalf("push %d failed: %v", i, err)
+		}
+		if l.Len() == 100 {
+			for l.Len() > 0 {
+				l.Remove(l.Front())
+			}
+		}
+	}
+	if l.arena.Len() != 100 {
+		t.Fatalf("expected nodes to be reused, arena len %d", l.arena.Len())
+	}
+	for i := 0; i < 100; i++ {
+		l.PushFront(i)
+	}
+	if _, err := l.PushFront(0); err == nil {
+		t.Fatal("expected a full list")
+	}
+}
+
+// BenchmarkList compares List against container/list for push/remove churn
+func BenchmarkList(b *testing.B) {
+	b.Run("List", func(b *testing.B) {
+		l := NewList[int](1024)
+		for i := 0; i < b.N; i++ {
+			l.PushBack(i)
+			if l.Len() == 1024 {
+				for l.Len() > 0 {
+					l.Remove(l.Front())
+				}
+			}
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		l := list.New()
+		for i := 0; i < b.N; i++ {
+			l.PushBack(i)
+			if l.Len() == 1024 {
+				for l.Len() > 0 {
+					l.Remove(l.Front())
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Matrix is a dense row-major matrix stored in one contiguous reservation of
+// an arena. Row r starts RowStride elements after row r-1; the elements
+// between the end of a row and the start of the next are padding, present
+// only in matrices from ReserveMatrixAligned. Like any reserved segment it
+// dies at Reset.
+type Matrix[T any] struct {
+	data       []T // rows*stride elements
+	rows, cols uintptr
+	stride     uintptr // elements from one row's start to the next's
+}
+
+// ReserveMatrix reserves a rows×cols matrix of contiguous, tightly packed
+// rows. Its elements hold whatever Reserve would leave in them. It fails
+// like Reserve, with ErrTooLarge if rows*cols overflows.
+func (a *AtomicArena[T]) ReserveMatrix(rows, cols uintptr) (Matrix[T], error) {
+	return a.reserveMatrix(rows, cols, cols, 1)
+}
+
+// ReserveMatrixAligned reserves a rows×cols matrix whose rows each start on
+// an align-byte boundary, align being a power of two; zero selects the
+// default of 64, a cache line. The row stride is cols rounded up to the
+// next whole number of boundaries, counting elements at unsafe.Sizeof(T),
+// padding included, and slots are skipped before the first row to align it.
+// The skipped slots are tombstoned, so Get and Range pass over them. It
+// fails with ErrBadAlignment for an align that is not a power of two, and
+// ErrMisaligned if the arena's storage, placed by WithBaseAlignment, cannot
+// put an element on such a boundary.
+func (a *AtomicArena[T]) ReserveMatrixAligned(rows, cols, align uintptr) (Matrix[T], error) {
+	if align == 0 {
+		align = defaultBaseAlign
+	}
+	if align&(align-1) != 0 {
+		return Matrix[T]{}, fmt.Errorf("%w: %d", ErrBadAlignment, align)
+	}
+	size := unsafe.Sizeof(*new(T))
+	if size == 0 {
+		return a.reserveMatrix(rows, cols, cols, 1)
+	}
+	// rows stay aligned when the stride is a multiple of this many elements
+	step := align / gcd(size, align)
+	stride := (cols + step - 1) / step * step
+	if stride < cols {
+		return Matrix[T]{}, fmt.Errorf("%w: row of %d elements aligned to %d bytes overflows uintptr", ErrTooLarge, cols, align)
+	}
+	return a.reserveMatrix(rows, cols, stride, align)
+}
+
+// reserveMatrix reserves a rows×cols matrix with the given row stride whose
+// first element sits on an align-byte boundary, claiming the slots skipped
+// to reach it in the same CAS.
+func (a *AtomicArena[T]) reserveMatrix(rows, cols, stride, align uintptr) (Matrix[T], error) {
+	if stride != 0 && rows > ^uintptr(0)/stride {
+		return Matrix[T]{}, fmt.Errorf("%w: %d×%d matrix overflows uintptr", ErrTooLarge, rows, stride)
+	}
+	n := rows * stride
+	var pad uintptr // slots skipped to reach the boundary, for the last start seen
+	start, err := a.reserveWithin(n, a.softCap.Load(), func(start uintptr) (uintptr, error) {
+		var ok bool
+		if pad, ok = a.alignPad(start, align); !ok {
+			return 0, fmt.Errorf("%w: elements of %d bytes cannot start on a %d-byte boundary", ErrMisaligned, unsafe.Sizeof(*new(T)), align)
+		}
+		return pad, nil
+	}, false)
+	if err != nil {
+		if errors.Is(err, ErrMisaligned) {
+			return Matrix[T]{}, err
+		}
+		return Matrix[T]{}, a.allocErr(err, start, pad+n)
+	}
+	for i := start; i < start+pad; i++ {
+		a.markDead(i)
+	}
+	lo := start + pad
+	data := a.raw[lo : lo+n : lo+n]
+	if a.opts.zeroOnReserve {
+		a.zeroStale(lo, data)
+	}
+	a.commit(pad + n)
+	return Matrix[T]{data: data, rows: rows, cols: cols, stride: stride}, nil
+}
+
+// alignPad returns how many slots from start the first one on an align-byte
+// boundary is, reporting false if no slot is.
+func (a *AtomicArena[T]) alignPad(start, align uintptr) (uintptr, bool) {
+	if align <= 1 {
+		return 0, true
+	}
+	size := unsafe.Sizeof(*new(T))
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(a.raw))) + start*size
+	// slot addresses repeat modulo align after align/gcd(size, align) slots
+	for pad := uintptr(0); pad < align/gcd(size, align); pad++ {
+		if (addr+pad*size)%align == 0 {
+			return pad, true
+		}
+	}
+	return 0, false
+}
+
+// Dims returns the number of rows and columns.
+func (m Matrix[T]) Dims() (rows, cols uintptr) {
+	return m.rows, m.cols
+}
+
+// RowStride returns the distance in elements from the start of one row to
+// the start of the next: cols for ReserveMatrix, and at least cols for
+// ReserveMatrixAligned. It is the leading dimension BLAS-style routines ask
+// for.
+func (m Matrix[T]) RowStride() uintptr {
+	return m.stride
+}
+
+// At returns a pointer to the element at row r, column c. It panics if
+// either is out of range, like a slice index.
+func (m Matrix[T]) At(r, c uintptr) *T {
+	if r >= m.rows || c >= m.cols {
+		panic(fmt.Sprintf("atomicarena: matrix index [%d, %d] out of range for %d×%d", r, c, m.rows, m.cols))
+	}
+	return &m.data[r*m.stride+c]
+}
+
+// Row returns row r as a slice of cols elements, with its capacity clipped
+// so appending to it never writes into the next row. It panics if r is out
+// of range.
+func (m Matrix[T]) Row(r uintptr) []T {
+	if r >= m.rows {
+		panic(fmt.Sprintf("atomicarena: matrix row %d out of range for %d rows", r, m.rows))
+	}
+	lo := r * m.stride
+	return m.data[lo : lo+m.cols : lo+m.cols]
+}
+
+// Get returns the element at row r, column c, or ErrOutOfRange.
+func (m Matrix[T]) Get(r, c uintptr) (T, error) {
+	if r >= m.rows || c >= m.cols {
+		var zero T
+		return zero, fmt.Errorf("%w: matrix index [%d, %d] of %d×%d", ErrOutOfRange, r, c, m.rows, m.cols)
+	}
+	return m.data[r*m.stride+c], nil
+}
+
+// Set stores v at row r, column c, or returns ErrOutOfRange.
+func (m Matrix[T]) Set(r, c uintptr, v T) error {
+	if r >= m.rows || c >= m.cols {
+		return fmt.Errorf("%w: matrix index [%d, %d] of %d×%d", ErrOutOfRange, r, c, m.rows, m.cols)
+	}
+	m.data[r*m.stride+c] = v
+	return nil
+}
+
+// Data returns the matrix's storage, rows*RowStride elements in row-major
+// order including any row padding, aliasing the arena.
+func (m Matrix[T]) Data() []T {
+	return m.data
+}
+
+// BasePointer returns the address of the element at row 0, column 0, or nil
+// for an empty matrix. Together with RowStride and the arena's Stride it
+// describes the matrix to C or assembly routines; the caveats of
+// AtomicArena.BasePointer apply.
+func (m Matrix[T]) BasePointer() unsafe.Pointer {
+	if len(m.data) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(unsafe.SliceData(m.data))
+}
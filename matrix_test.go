@@ -0,0 +1,106 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+// TestReserveMatrix fills a matrix with non-power-of-two dimensions and reads it back row by row
+func TestReserveMatrix(t *testing.T) {
+	a := NewAtomicArena[float64](64)
+	a.Alloc(-1)
+	m, err := a.ReserveMatrix(5, 7)
+	if err != nil {
+		t.Fatalf("ReserveMatrix failed: %v", err)
+	}
+	if r, c := m.Dims(); r != 5 || c != 7 || m.RowStride() != 7 || len(m.Data()) != 35 {
+		t.Fatalf("expected a packed 5×7 matrix, got %d×%d with stride %d", r, c, m.RowStride())
+	}
+	for r := uintptr(0); r < 5; r++ {
+		for c := uintptr(0); c < 7; c++ {
+			if err := m.Set(r, c, float64(r*10+c)); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+		}
+	}
+	if row := m.Row(3); len(row) != 7 || cap(row) != 7 || row[6] != 36 {
+		t.Fatalf("expected row 3 to hold 30 to 36 with its capacity clipped, got %v", row)
+	}
+	if p, _ := a.Get(1 + 2*7 + 4); p != m.At(2, 4) || *p != 24 {
+		t.Fatalf("expected element [2, 4] at arena slot 19")
+	}
+	if unsafe.Add(m.BasePointer(), 7*a.Stride()) != unsafe.Pointer(&m.Row(1)[0]) {
+		t.Fatalf("expected row 1 one stride of 7 elements after the base")
+	}
+	if _, err := m.Get(5, 0); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange past the last row, got %v", err)
+	}
+	if err := m.Set(0, 7, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange past the last column, got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected At to panic out of range")
+		}
+	}()
+	m.At(0, 7)
+}
+
+// TestReserveMatrixAligned starts every row of padded elements on a cache line
+func TestReserveMatrixAligned(t *testing.T) {
+	a := NewAtomicArena[padded](256)
+	a.Alloc(padded{})
+	m, err := a.ReserveMatrixAligned(3, 5, 0)
+	if err != nil {
+		t.Fatalf("ReserveMatrixAligned failed: %v", err)
+	}
+	// elements of 24 bytes, 12 on 32-bit platforms, line up with 64-byte
+	// boundaries every 8 elements, 16 on 32-bit platforms
+	step := 64 / gcd(unsafe.Sizeof(padded{}), 64)
+	if stride := (5 + step - 1) / step * step; m.RowStride() != stride || len(m.Data()) != int(3*stride) {
+		t.Fatalf("expected a row stride of %d, got %d with %d elements", stride, m.RowStride(), len(m.Data()))
+	}
+	for r := uintptr(0); r < 3; r++ {
+		if addr := uintptr(unsafe.Pointer(&m.Row(r)[0])); addr%64 != 0 {
+			t.Fatalf("expected row %d on a cache line, got %#x", r, addr)
+		}
+	}
+	skipped := a.Len() - 1 - uintptr(len(m.Data()))
+	if skipped >= step {
+		t.Fatalf("expected fewer than %d slots skipped to align the first row, got %d", step, skipped)
+	}
+	for i := uintptr(1); i <= skipped; i++ {
+		if _, ok := a.Get(i); ok {
+			t.Fatalf("expected skipped slot %d to be tombstoned", i)
+		}
+	}
+	if _, err := a.ReserveMatrixAligned(1, 1, 48); !errors.Is(err, ErrBadAlignment) {
+		t.Fatalf("expected ErrBadAlignment, got %v", err)
+	}
+	b := NewAtomicArena[int64](64) // heap storage aligned to 64
+	w, _ := b.ReserveMatrixAligned(2, 3, 16)
+	if w.RowStride() != 4 {
+		t.Fatalf("expected 3 int64s rounded up to a stride of 4, got %d", w.RowStride())
+	}
+}
+
+// TestReserveMatrixFull fails without reserving when the matrix does not fit
+func TestReserveMatrixFull(t *testing.T) {
+	a := NewAtomicArena[int32](20)
+	if _, err := a.ReserveMatrix(3, 7); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if _, err := a.ReserveMatrixAligned(4, 5, 64); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull once rows are padded to 16, got %v", err)
+	}
+	if _, err := a.ReserveMatrix(^uintptr(0), 2); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if a.Len() != 0 {
+		t.Fatalf("expected failed reservations to take nothing, got len %d", a.Len())
+	}
+	if _, err := a.ReserveMatrix(4, 5); err != nil || a.Len() != 20 {
+		t.Fatalf("expected a 4×5 matrix to fill the arena exactly, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build gc && !tinygo && !atomicarena_purego
+
+package atomicarena
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+//go:linkname memclrNoHeapPointers runtime.memclrNoHeapPointers
+//go:nosplit
+func memclrNoHeapPointers(ptr unsafe.Pointer, n uintptr)
+
+// clearMirror zeroes a run of the pointer mirror with a single memclr.
+// Skipping the write barriers is safe here because every mirror entry points
+// into the arena's own storage, which a.raw keeps reachable regardless.
+func clearMirror[T any](p []atomic.Pointer[T]) {
+	if len(p) == 0 {
+		return
+	}
+	memclrNoHeapPointers(unsafe.Pointer(&p[0]), uintptr(len(p))*unsafe.Sizeof(p[0]))
+}
+
+// clearElems zeroes a run of pointer-free elements with a single memclr.
+// Callers must check hasPointers first: elements holding pointers need the
+// write barriers that the builtin clear performs.
+func clearElems[T any](p []T) {
+	if len(p) == 0 {
+		return
+	}
+	memclrNoHeapPointers(unsafe.Pointer(&p[0]), uintptr(len(p))*unsafe.Sizeof(p[0]))
+}
@@ -0,0 +1,19 @@
+//go:build !gc || tinygo || atomicarena_purego
+
+package atomicarena
+
+import "sync/atomic"
+
+// clearMirror zeroes a run of the pointer mirror. This portable version is
+// used by toolchains without go:linkname access to the runtime, such as
+// gccgo and TinyGo, and under the atomicarena_purego tag.
+func clearMirror[T any](p []atomic.Pointer[T]) {
+	for i := range p {
+		p[i].Store(nil)
+	}
+}
+
+// clearElems zeroes a run of pointer-free elements.
+func clearElems[T any](p []T) {
+	clear(p)
+}
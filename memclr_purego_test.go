@@ -0,0 +1,30 @@
+//go:build atomicarena_purego
+
+package atomicarena
+
+import "testing"
+
+// TestPortableClear exercises Free and Reset on the portable clearing path
+func TestPortableClear(t *testing.T) {
+	arena := NewAtomicArena[int](64)
+	for i := 0; i < 64; i++ {
+		arena.Alloc(i + 1)
+	}
+	arena.Free()
+	for i := range arena.raw {
+		if arena.raw[i] != 0 || arena.ptrs[i].Load() != nil {
+			t.Fatalf("slot %d not cleared by Free", i)
+		}
+	}
+	for i := 0; i < 64; i++ {
+		arena.Alloc(i + 1)
+	}
+	if err := arena.Reset(true); err != nil {
+		t.Fatal(err)
+	}
+	for i := range arena.raw {
+		if arena.raw[i] != 0 || arena.ptrs[i].Load() != nil {
+			t.Fatalf("slot %d not cleared by Reset", i)
+		}
+	}
+}
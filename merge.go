@@ -0,0 +1,97 @@
+package atomicarena
+
+import (
+	"fmt"
+)
+
+// MergeError reports which source passed to Merge did not fit into the receiver.
+// Sources before Source were merged; Source and everything after it were not.
+type MergeError struct {
+	Source    int     // index of the failing source in the Merge arguments
+	Needed    uintptr // number of slots the source required
+	Available uintptr // free slots in the receiver when the reservation failed
+	Err       error   // underlying reservation error
+}
+
+func (e *MergeError) Error() string {
+	return fmt.Sprintf("atomicarena: merge source %d: need %d slots, %d available: %v",
+		e.Source, e.Needed, e.Available, e.Err)
+}
+
+func (e *MergeError) Unwrap() error { return e.Err }
+
+// Clone returns an independent arena with the same capacity holding a copy of
+// the allocated contents of a. Pointers published via Alloc are republished
+// into the clone so its pointer mirror matches the source, and tombstones are kept.
+// The clone is built with the same options as a and is frozen if a is, but
+// never closed. It is exact only if a is not being mutated concurrently.
+func (a *AtomicArena[T]) Clone() *AtomicArena[T] {
+	c, err := newArena[T](a.maxElems.Load(), a.opts)
+	if err != nil {
+		panic(err)
+	}
+	n := a.Len()
+	copy(c.raw[:n], a.raw[:n])
+	for i := uintptr(0); i < n && c.ptrs != nil; i++ {
+		if a.ptrs[i].Load() != nil {
+			c.ptrs[i].Store(&c.raw[i])
+		}
+	}
+	for w := range c.dead {
+		c.dead[w].Store(a.dead[w].Load())
+	}
+	if c.ttl != nil {
+		c.ttl.base = a.ttl.base
+		for i := uintptr(0); i < n; i++ {
+			c.ttl.at[i].Store(a.ttl.at[i].Load())
+		}
+	}
+	c.unpub.Store(a.unpub.Load())
+	c.armMarks(n)
+	c.count.Store(n | a.count.Load()&frozenBit)
+	c.done.Store(n)
+	return c
+}
+
+// Merge appends the committed, live contents of each source to a, using a
+// single reservation per source: tombstoned and expired elements are left
+// out, and the rest keep their order. It stops at the first source that does
+// not fit and returns a *MergeError wrapping ErrArenaFull; earlier sources
+// stay merged. Behavior is undefined if a source is mutated while Merge is
+// running.
+func (a *AtomicArena[T]) Merge(others ...*AtomicArena[T]) error {
+	for i, src := range others {
+		objs := src.live()
+		if _, err := a.AppendSlice(objs); err != nil {
+			return &MergeError{
+				Source:    i,
+				Needed:    uintptr(len(objs)),
+				Available: a.room(),
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
+
+// live returns the committed elements of a that are neither tombstoned nor
+// expired. Without any such gaps it is the arena's own storage; otherwise
+// the survivors are copied out.
+func (a *AtomicArena[T]) live() []T {
+	n := a.Committed()
+	first := uintptr(0)
+	for first < n && !a.gone(first) {
+		first++
+	}
+	if first == n {
+		return a.raw[:n]
+	}
+	out := make([]T, first, n-1)
+	copy(out, a.raw[:first])
+	for i := first + 1; i < n; i++ {
+		if !a.gone(i) {
+			out = append(out, a.raw[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,145 @@
+package atomicarena
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestCloneRandom verifies Clone copies contents and pointer mirror exactly
+func TestCloneRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 50; iter++ {
+		size := uintptr(rng.Intn(200) + 1)
+		arena := NewAtomicArena[int64](size)
+		fill := uintptr(rng.Intn(int(size) + 1))
+		for i := uintptr(0); i < fill; i++ {
+			v := rng.Int63()
+			if rng.Intn(2) == 0 {
+				if _, err := arena.Alloc(v); err != nil {
+					t.Fatalf("Alloc failed: %v", err)
+				}
+			} else if _, err := arena.AppendSlice([]int64{v}); err != nil {
+				t.Fatalf("AppendSlice failed: %v", err)
+			}
+		}
+
+		clone := arena.Clone()
+		if clone.Cap() != arena.Cap() || clone.Len() != arena.Len() {
+			t.Fatalf("clone len/cap %d/%d, want %d/%d", clone.Len(), clone.Cap(), arena.Len(), arena.Cap())
+		}
+		for i := uintptr(0); i < fill; i++ {
+			if clone.raw[i] != arena.raw[i] {
+				t.Fatalf("index %d: expected %d, got %d", i, arena.raw[i], clone.raw[i])
+			}
+			if (arena.ptrs[i].Load() == nil) != (clone.ptrs[i].Load() == nil) {
+				t.Fatalf("index %d: pointer mirror mismatch", i)
+			}
+			if p := clone.ptrs[i].Load(); p != nil && p != &clone.raw[i] {
+				t.Fatalf("index %d: clone pointer does not reference clone storage", i)
+			}
+		}
+
+		// the clone must be independent of the source
+		if fill > 0 {
+			clone.raw[0]++
+			if clone.raw[0] == arena.raw[0] {
+				t.Fatal("clone shares storage with source")
+			}
+		}
+	}
+}
+
+// TestMerge ensures Merge appends sources in order
+func TestMerge(t *testing.T) {
+	dst := NewAtomicArena[int](10)
+	_, _ = dst.AppendSlice([]int{1, 2})
+	a := NewAtomicArena[int](4)
+	_, _ = a.AppendSlice([]int{3, 4, 5})
+	b := NewAtomicArena[int](4)
+	_, _ = b.Alloc(6)
+
+	if err := dst.Merge(a, b, NewAtomicArena[int](1)); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want := []int{1, 2, 3, 4, 5, 6}
+	if dst.Len() != uintptr(len(want)) {
+		t.Fatalf("expected len %d, got %d", len(want), dst.Len())
+	}
+	for i, v := range want {
+		if dst.raw[i] != v {
+			t.Errorf("index %d: expected %d, got %d", i, v, dst.raw[i])
+		}
+	}
+}
+
+// TestMergeError ensures Merge names the source that did not fit
+func TestMergeError(t *testing.T) {
+	dst := NewAtomicArena[int](4)
+	a := NewAtomicArena[int](2)
+	_, _ = a.AppendSlice([]int{1, 2})
+	b := NewAtomicArena[int](3)
+	_, _ = b.AppendSlice([]int{3, 4, 5})
+
+	err := dst.Merge(a, b)
+	var me *MergeError
+	if !errors.As(err, &me) {
+		t.Fatalf("expected *MergeError, got %v", err)
+	}
+	if me.Source != 1 || me.Needed != 3 || me.Available != 2 {
+		t.Errorf("unexpected error fields: %+v", me)
+	}
+	if !errors.Is(err, ErrArenaFull) {
+		t.Errorf("expected error to wrap ErrArenaFull")
+	}
+	if dst.Len() != 2 {
+		t.Errorf("expected first source to stay merged, len=%d", dst.Len())
+	}
+}
+
+// TestMergeSkipsDead leaves tombstoned and expired source elements out of the merge
+func TestMergeSkipsDead(t *testing.T) {
+	c := newStepClock()
+	src := NewAtomicArena[int](8, WithTTL(time.Second), WithClock(c))
+	_, _ = src.AppendSlice([]int{1, 2})
+	c.now = c.now.Add(time.Second)
+	_, _ = src.AppendSlice([]int{3, 4, 5})
+	src.Tombstone(3)
+	seg, _ := src.Reserve(1)
+	seg[0] = 6
+	c.now = c.now.Add(time.Second / 2)
+
+	dst := NewAtomicArena[int](4)
+	if err := dst.Merge(src); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if want := []int{3, 5}; dst.Len() != 2 || dst.raw[0] != want[0] || dst.raw[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, dst.raw[:dst.Len()])
+	}
+	small := NewAtomicArena[int](1)
+	var me *MergeError
+	if err := small.Merge(src); !errors.As(err, &me) || me.Needed != 2 {
+		t.Fatalf("expected a *MergeError needing the 2 live slots, got %v", err)
+	}
+}
+
+// TestCloneFrozen keeps the source's frozen state
+func TestCloneFrozen(t *testing.T) {
+	a := NewAtomicArena[int](4, WithUnfreeze())
+	a.Alloc(1)
+	a.Freeze()
+	c := a.Clone()
+	if !c.Frozen() || c.Len() != 1 {
+		t.Fatalf("expected a frozen clone of 1 slot, got frozen=%v len=%d", c.Frozen(), c.Len())
+	}
+	if _, err := c.Alloc(2); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+	if err := c.Unfreeze(); err != nil || a.Unfreeze() != nil {
+		t.Fatalf("Unfreeze failed: %v", err)
+	}
+	if _, err := c.Alloc(2); err != nil || c.Len() != 2 || a.Len() != 1 {
+		t.Fatalf("expected the thawed clone to grow alone, got %v, len %d and %d", err, c.Len(), a.Len())
+	}
+}
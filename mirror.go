@@ -0,0 +1,132 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoMirror is returned by StorePointer on an arena built
+// WithoutPointerMirror.
+var ErrNoMirror = errors.New("atomicarena: arena has no pointer mirror")
+
+// LoadPointer atomically loads slot i's entry in the pointer mirror: the
+// address of the slot once its write has completed, or nil if the slot is
+// unpublished, beyond Len, or the arena has no mirror. A non-nil result can
+// be dereferenced without further synchronization, which makes the mirror
+// usable by lock-free readers outside the package.
+func (a *AtomicArena[T]) LoadPointer(i uintptr) *T {
+	if a.ptrs == nil || i >= a.Len() {
+		return nil
+	}
+	return a.ptrs[i].Load()
+}
+
+// StorePointer atomically sets slot i's mirror entry, for callers that fill
+// Reserve'd slots themselves and publish them one at a time; like
+// PublishRange, publishing lets Committed count the slot, and unpublishing
+// holds the committed prefix back at it again. p must be the address of
+// slot i, or nil to unpublish it; any other pointer is refused with
+// ErrForeignSegment. It returns ErrOutOfRange for slots beyond Len,
+// ErrNoMirror if the arena has no mirror, and ErrFrozen on a frozen arena.
+func (a *AtomicArena[T]) StorePointer(i uintptr, p *T) error {
+	if a.Frozen() {
+		return a.frozenErr()
+	}
+	if a.ptrs == nil {
+		return ErrNoMirror
+	}
+	if n := a.Len(); i >= n {
+		return fmt.Errorf("%w: store %d, len %d", ErrOutOfRange, i, n)
+	}
+	if j, ok := a.indexOf(p); p != nil && (!ok || j != i) {
+		return ErrForeignSegment
+	}
+	switch old := a.ptrs[i].Swap(p); {
+	case old == nil && p != nil:
+		a.published(1)
+	case old != nil && p == nil:
+		a.unpub.Add(1)
+	}
+	return nil
+}
+
+// PublishRange marks slots [lo, hi) of a Reserve'd segment as filled: it
+// publishes them in the pointer mirror, for pointer-based readers and Dump,
+// and lets Committed and WaitForCommitted count them. Every other way of
+// storing values (Alloc, AppendSlice, AllocMany, AppendFrom, batches,
+// ArenaSlice, transactions, forks and Merge) publishes each slot once it is
+// written; only Reserve, ReserveIndexed and ReserveZeroed leave their slots
+// unpublished. Each reserved slot should be published once: an arena without
+// a mirror cannot tell a slot published twice from two, so on such an arena
+// a repeated PublishRange may let Committed count slots another Reserve has
+// not filled yet. It returns ErrOutOfRange unless lo <= hi <= Len, and
+// ErrFrozen on a frozen arena.
+func (a *AtomicArena[T]) PublishRange(lo, hi uintptr) error {
+	if a.Frozen() {
+		return a.frozenErr()
+	}
+	if n := a.Len(); lo > hi || hi > n {
+		return fmt.Errorf("%w: publish [%d, %d), len %d", ErrOutOfRange, lo, hi, n)
+	}
+	k := hi - lo
+	if a.ptrs != nil {
+		k = 0
+		for i := lo; i < hi; i++ {
+			if a.ptrs[i].Swap(&a.raw[i]) == nil {
+				k++
+			}
+		}
+	}
+	a.published(k)
+	return nil
+}
+
+// published records that k unpublished slots have been published and wakes
+// WaitForCommitted callers, since Committed may count them now.
+func (a *AtomicArena[T]) published(k uintptr) {
+	for {
+		u := a.unpub.Load()
+		if u == 0 || a.unpub.CompareAndSwap(u, u-min(u, k)) {
+			break
+		}
+	}
+	if a.waiters.wantAt.Load() != ^uintptr(0) {
+		a.wakeCommitted()
+	}
+}
+
+// dropUnpublished records that slots holding k unpublished reservations were
+// discarded, leaving to slots allocated. No more than to reservations can
+// still be unpublished, which bounds the count on an arena without a mirror,
+// where k is not known.
+func (a *AtomicArena[T]) dropUnpublished(k, to uintptr) {
+	for {
+		u := a.unpub.Load()
+		v := min(u-min(u, k), to)
+		if v == u || a.unpub.CompareAndSwap(u, v) {
+			return
+		}
+	}
+}
+
+// unpublished counts the slots of [lo, hi) missing from the pointer mirror,
+// or returns zero on an arena without one. None may have a write in flight.
+func (a *AtomicArena[T]) unpublished(lo, hi uintptr) uintptr {
+	k := uintptr(0)
+	for i := lo; i < hi && a.ptrs != nil; i++ {
+		if a.ptrs[i].Load() == nil {
+			k++
+		}
+	}
+	return k
+}
+
+// publish stores the address of each slot in [lo, hi) in the pointer mirror.
+func (a *AtomicArena[T]) publish(lo, hi uintptr) {
+	if a.ptrs == nil {
+		return
+	}
+	for i := lo; i < hi; i++ {
+		a.ptrs[i].Store(&a.raw[i])
+	}
+}
@@ -0,0 +1,274 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+// TestWithoutPointerMirror exercises the arena API with the mirror disabled
+func TestWithoutPointerMirror(t *testing.T) {
+	arena := NewAtomicArena[int](8, WithoutPointerMirror())
+	if arena.ptrs != nil {
+		t.Fatal("expected no pointer mirror")
+	}
+	for i := 0; i < 4; i++ {
+		p, err := arena.Alloc(i * 10)
+		if err != nil || *p != i*10 {
+			t.Fatalf("Alloc %d: %v", i, err)
+		}
+	}
+	if _, err := arena.AppendSlice([]int{40, 50}); err != nil {
+		t.Fatalf("AppendSlice failed: %v", err)
+	}
+	if p, ok := arena.Get(5); !ok || *p != 50 {
+		t.Fatalf("Get(5) = %v, %v", p, ok)
+	}
+	if err := arena.Tombstone(1); err != nil {
+		t.Fatal(err)
+	}
+	clone := arena.Clone()
+	if clone.ptrs != nil {
+		t.Fatal("clone should inherit the disabled mirror")
+	}
+	if moved := arena.Compact(); moved[5] != 4 {
+		t.Fatalf("unexpected compaction %v", moved)
+	}
+	if err := arena.SortFunc(func(a, b *int) bool { return *a > *b }); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(arena.Snapshot()); got != "[50 40 30 20 0]" {
+		t.Fatalf("unexpected contents %v", got)
+	}
+	if got := fmt.Sprint(clone.Snapshot()); got != "[0 20 30 40 50]" {
+		t.Fatalf("unexpected clone contents %v", got)
+	}
+	err := arena.Reset(true)
+	inspectReleased(arena)
+	if err != nil || arena.Len() != 0 || arena.raw[0] != 0 {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	arena.Prefault()
+}
+
+// TestWithoutPointerMirrorSize checks the memory saving
+func TestWithoutPointerMirrorSize(t *testing.T) {
+	with := NewAtomicArena[int64](1 << 16)
+	without := NewAtomicArena[int64](1<<16, WithoutPointerMirror())
+	want := 1 << 16 * unsafe.Sizeof(atomic.Pointer[int64]{})
+	if saved := with.SizeBytes() - without.SizeBytes(); saved != want {
+		t.Fatalf("expected to save %d bytes, saved %d", want, saved)
+	}
+}
+
+// TestMmapWithoutPointerMirror ensures mapped arenas skip the mirror region
+func TestMmapWithoutPointerMirror(t *testing.T) {
+	m, err := NewMmapArena[int64](1024, WithoutPointerMirror())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if len(m.mem) != 1024*8 {
+		t.Fatalf("expected %d mapped bytes, got %d", 1024*8, len(m.mem))
+	}
+	if _, err := m.Alloc(7); err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := m.Get(0); !ok || *p != 7 {
+		t.Fatalf("Get(0) = %v, %v", p, ok)
+	}
+	if err := m.Reset(true); err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := NewMmapArena[struct{}](16, WithoutPointerMirror())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer z.Close()
+	if _, err := z.Alloc(struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkAllocMirror compares Alloc with and without the pointer mirror
+func BenchmarkAllocMirror(b *testing.B) {
+	for _, mode := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Mirror", nil},
+		{"NoMirror", []Option{WithoutPointerMirror()}},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			arena := NewAtomicArena[int](1<<20, mode.opts...)
+			b.ReportMetric(float64(arena.SizeBytes())/float64(arena.Cap()), "bytes/slot")
+			b.ResetTimer()
+			for i := 0; i < b.N; {
+				if _, err := arena.Alloc(i); err != nil {
+					arena.Reset(false)
+					continue
+				}
+				i++
+			}
+		})
+	}
+}
+
+// TestLoadStorePointer covers the public mirror accessors
+func TestLoadStorePointer(t *testing.T) {
+	a := NewAtomicArena[int](4)
+	if a.LoadPointer(0) != nil || a.LoadPointer(100) != nil {
+		t.Fatalf("expected nil for never-allocated slots")
+	}
+	p, _ := a.Alloc(7)
+	if a.LoadPointer(0) != p || *a.LoadPointer(0) != 7 {
+		t.Fatalf("expected the allocated pointer after Alloc")
+	}
+	seg, _ := a.Reserve(2)
+	if a.LoadPointer(1) != nil {
+		t.Fatalf("expected Reserve'd slots to be unpublished")
+	}
+	seg[0] = 8
+	if err := a.StorePointer(1, &seg[0]); err != nil || a.LoadPointer(1) != &seg[0] {
+		t.Fatalf("expected StorePointer to publish slot 1, got %v", err)
+	}
+	for _, tc := range []struct {
+		i    uintptr
+		p    *int
+		want error
+	}{
+		{2, &seg[0], ErrForeignSegment},
+		{2, new(int), ErrForeignSegment},
+		{3, nil, ErrOutOfRange},
+	} {
+		if err := a.StorePointer(tc.i, tc.p); !errors.Is(err, tc.want) {
+			t.Fatalf("StorePointer(%d): expected %v, got %v", tc.i, tc.want, err)
+		}
+	}
+	if err := a.StorePointer(0, nil); err != nil || a.LoadPointer(0) != nil {
+		t.Fatalf("expected StorePointer(nil) to unpublish, got %v", err)
+	}
+	a.Reset(false)
+	if a.LoadPointer(1) != nil {
+		t.Fatalf("expected nil after Reset")
+	}
+	b := NewAtomicArena[int](2, WithoutPointerMirror())
+	q, _ := b.Alloc(1)
+	if b.LoadPointer(0) != nil || !errors.Is(b.StorePointer(0, q), ErrNoMirror) {
+		t.Fatalf("expected no mirror access WithoutPointerMirror")
+	}
+}
+
+// TestPublishEveryPath fills an arena through each allocation path and checks
+// that Get and LoadPointer agree on every slot
+func TestPublishEveryPath(t *testing.T) {
+	const n = 6
+	vals := []int{0, 1, 2, 3, 4, 5}
+	for name, fill := range map[string]func(a *AtomicArena[int]){
+		"Alloc": func(a *AtomicArena[int]) {
+			for _, v := range vals {
+				a.Alloc(v)
+			}
+		},
+		"AppendSlice": func(a *AtomicArena[int]) { a.AppendSlice(vals) },
+		"AllocManyFunc": func(a *AtomicArena[int]) {
+			a.AllocManyFunc(n, func(i uintptr, p *int) { *p = int(i) })
+		},
+		"AppendFrom": func(a *AtomicArena[int]) {
+			i := 0
+			a.AppendFrom(func() (int, bool) { i++; return i - 1, i <= n })
+		},
+		"ArenaSlice": func(a *AtomicArena[int]) {
+			s := NewArenaSlice(a, 3)
+			for _, v := range vals {
+				s.Append(v)
+			}
+		},
+		"Batch": func(a *AtomicArena[int]) {
+			b := &BatchedArena[int]{arena: a, k: 4, batches: make(map[*Batch[int]]struct{})}
+			c := b.NewBatch()
+			for _, v := range vals {
+				c.Alloc(v)
+			}
+			c.Close()
+		},
+		"Txn": func(a *AtomicArena[int]) {
+			tx := a.Begin()
+			tx.Alloc(vals[0])
+			tx.AppendSlice(vals[1:])
+			tx.Commit()
+		},
+		"Fork": func(a *AtomicArena[int]) {
+			v := a.Fork()
+			for _, x := range vals {
+				v.Alloc(x)
+			}
+			v.Commit()
+		},
+		"Merge": func(a *AtomicArena[int]) {
+			src := NewAtomicArena[int](n)
+			src.AppendSlice(vals)
+			a.Merge(src)
+		},
+		"Reserve+PublishRange": func(a *AtomicArena[int]) {
+			lo, seg, _ := a.ReserveIndexed(n)
+			copy(seg, vals)
+			if err := a.PublishRange(lo, lo+n); err != nil {
+				t.Fatal(err)
+			}
+		},
+	} {
+		a := NewAtomicArena[int](8)
+		// stale values and mirror entries from an earlier epoch must not leak
+		a.AppendSlice([]int{9, 9, 9, 9, 9, 9, 9, 9})
+		a.Reset(false)
+		fill(a)
+		if a.Len() != n || a.Committed() != n {
+			t.Fatalf("%s: expected %d slots, got len %d, committed %d", name, n, a.Len(), a.Committed())
+		}
+		for i := uintptr(0); i < n; i++ {
+			p, ok := a.Get(i)
+			if !ok || *p != int(i) || a.LoadPointer(i) != p {
+				t.Fatalf("%s: slot %d: expected Get and LoadPointer to agree on %d, got %v, %v", name, i, i, p, a.LoadPointer(i))
+			}
+		}
+		if a.LoadPointer(n) != nil {
+			t.Fatalf("%s: expected slot %d to stay unpublished", name, n)
+		}
+	}
+}
+
+// TestPublishRangeErrors covers unpublished reservations and the bounds checks
+func TestPublishRangeErrors(t *testing.T) {
+	a := NewAtomicArena[int](4)
+	a.AppendSlice([]int{1, 2})
+	a.Reset(false)
+	if _, err := a.Reserve(3); err != nil {
+		t.Fatal(err)
+	}
+	for i := uintptr(0); i < 3; i++ {
+		if a.LoadPointer(i) != nil {
+			t.Fatalf("expected Reserve'd slot %d to be unpublished after Reset(false)", i)
+		}
+	}
+	if err := a.PublishRange(1, 3); err != nil || a.LoadPointer(0) != nil || a.LoadPointer(2) == nil {
+		t.Fatalf("expected only slots 1 and 2 to be published, got %v", err)
+	}
+	for _, r := range [][2]uintptr{{2, 1}, {0, 4}} {
+		if err := a.PublishRange(r[0], r[1]); !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("PublishRange(%d, %d): expected ErrOutOfRange, got %v", r[0], r[1], err)
+		}
+	}
+	a.Freeze()
+	if err := a.PublishRange(0, 1); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+	b := NewAtomicArena[int](2, WithoutPointerMirror())
+	b.Reserve(2)
+	if err := b.PublishRange(0, 2); err != nil {
+		t.Fatalf("expected PublishRange WithoutPointerMirror to be a no-op, got %v", err)
+	}
+}
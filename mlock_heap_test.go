@@ -0,0 +1,21 @@
+//go:build atomicarena_heapmmap
+
+package atomicarena
+
+import "testing"
+
+// TestLockedUnsupported ensures WithLocked degrades to unlocked storage
+func TestLockedUnsupported(t *testing.T) {
+	m, err := NewMmapArena[uint64](16, WithLocked())
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	if m.Locked() {
+		t.Fatal("heap fallback reported locked memory")
+	}
+	_, _ = m.Alloc(1)
+	if err := m.Wipe(); err != nil {
+		t.Fatalf("Wipe failed: %v", err)
+	}
+}
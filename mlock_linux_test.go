@@ -0,0 +1,27 @@
+//go:build linux && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestLockedLimitError ensures exceeding RLIMIT_MEMLOCK yields a descriptive error
+func TestLockedLimitError(t *testing.T) {
+	m, err := NewMmapArena[uint64](1<<26/8, WithLocked())
+	if err == nil {
+		defer m.Close()
+		if !m.Locked() {
+			t.Fatal("expected arena to report locked")
+		}
+		t.Skip("process may lock 64MB; cannot exercise the limit error")
+	}
+	if !errors.Is(err, ErrMemLock) {
+		t.Fatalf("expected ErrMemLock, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "RLIMIT_MEMLOCK") {
+		t.Fatalf("error does not mention the limit: %v", err)
+	}
+}
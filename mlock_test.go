@@ -0,0 +1,66 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+func newLockedArena(t *testing.T, n uintptr) *MmapArena[uint64] {
+	t.Helper()
+	m, err := NewMmapArena[uint64](n, WithLocked())
+	if errors.Is(err, ErrMemLock) {
+		t.Skipf("memory lock limit too low: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	if !m.Locked() {
+		m.Close()
+		t.Skip("memory locking not supported on this platform")
+	}
+	return m
+}
+
+// TestWipeZeroesMemory ensures Wipe and locked Reset/Free zero the used region
+func TestWipeZeroesMemory(t *testing.T) {
+	m := newLockedArena(t, 1024)
+	defer m.Close()
+	zeroed := func() bool {
+		inspectReleased(m.arena)
+		for _, b := range m.mem {
+			if b != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	fill := func() {
+		for i := 0; i < 100; i++ {
+			_, _ = m.Alloc(0xdeadbeef)
+		}
+	}
+	fill()
+	if err := m.Wipe(); err != nil {
+		t.Fatalf("Wipe failed: %v", err)
+	}
+	if !zeroed() || m.Len() != 0 {
+		t.Fatalf("Wipe left data behind (len %d)", m.Len())
+	}
+
+	fill()
+	if err := m.Free(); err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	if !zeroed() {
+		t.Fatal("Free on locked arena did not wipe")
+	}
+
+	fill()
+	if err := m.Reset(false); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if !zeroed() || m.Len() != 0 {
+		t.Fatal("Reset on locked arena did not wipe")
+	}
+}
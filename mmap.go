@@ -0,0 +1,408 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+var (
+	// ErrMemLock is returned when locking an arena's storage in memory fails.
+	ErrMemLock = errors.New("atomicarena: cannot lock arena memory")
+
+	errLockUnsupported = errors.New("atomicarena: memory locking not supported")
+)
+
+// pageSize is the operating system's memory page size.
+var pageSize = os.Getpagesize()
+
+// MmapArena is an arena whose storage is mapped directly from the operating
+// system instead of the Go heap, so it neither inflates GC scanning nor stays
+// resident after Close. Only pointer-free element types are supported.
+// On platforms without mmap support, or with the atomicarena_heapmmap build
+// tag, the storage falls back to a heap-allocated byte slice.
+type MmapArena[T any] struct {
+	arena     *AtomicArena[T]
+	mem       []byte
+	ptrsOff   uintptr // offset of the pointer mirror in mem
+	closed    atomic.Bool
+	locked    bool // storage is mlocked; releases wipe it
+	pooled    bool // storage comes from the huge page pool and is only unmapped by Close
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewMmapArena maps storage for maxElems elements of type T.
+// It returns ErrPointerType if T contains pointers.
+func NewMmapArena[T any](maxElems uintptr, opts ...Option) (*MmapArena[T], error) {
+	t := reflect.TypeFor[T]()
+	if hasPointers[T]() {
+		return nil, fmt.Errorf("%w: cannot map %s", ErrPointerType, t)
+	}
+	elem := t.Size()
+	o := buildOptions(opts)
+	if err := o.validate(true); err != nil {
+		return nil, err
+	}
+	if err := validateElem[T](o); err != nil {
+		return nil, err
+	}
+	if elem == 0 {
+		// every slot shares one address; see New
+		o.noMirror = true
+	}
+	ptrSize := unsafe.Sizeof(atomic.Pointer[T]{})
+	mirror := ptrSize
+	if o.noMirror {
+		mirror = 0
+	}
+	// map room for WithGrowLimit; only maxElems slots are usable at first
+	slots := max(maxElems, o.growLimit)
+	if elem+mirror > 0 && slots > (^uintptr(0)/2)/(elem+mirror) {
+		return nil, fmt.Errorf("atomicarena: %d elements of %s overflow the address space", slots, t)
+	}
+	// raw elements first, then the pointer mirror aligned to a pointer boundary
+	ptrsOff := (slots*elem + ptrSize - 1) &^ (ptrSize - 1)
+	size := ptrsOff + slots*mirror
+	// the kernel would populate the reserve too
+	populate := o.prefault && slots == maxElems
+	var mem []byte
+	huge := hugeNone
+	if o.numaBind || o.numaInterleave {
+		// the policy only applies to pages faulted in after it is set
+		populate = false
+	}
+	if size > 0 {
+		var err error
+		if o.hugePages || o.hugeTLB {
+			// prefault after the advice so the pages can start out huge
+			populate = false
+			mem, huge, err = mapHugeMemory(size, o.hugeTLB)
+		} else {
+			mem, err = mapMemory(size, populate)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("atomicarena: mapping %d bytes: %w", size, err)
+		}
+		if err := bindMemory(mem, &o); err != nil {
+			_ = unmapMemory(mem)
+			return nil, err
+		}
+	}
+	var raw []T
+	var ptrs []atomic.Pointer[T]
+	switch {
+	case slots > 0 && mem == nil:
+		// zero-sized elements without a mirror need no backing memory
+		raw = make([]T, slots)
+	case slots > 0:
+		raw = unsafe.Slice((*T)(unsafe.Pointer(&mem[0])), slots)
+		if !o.noMirror {
+			ptrs = unsafe.Slice((*atomic.Pointer[T])(unsafe.Pointer(&mem[ptrsOff])), slots)
+		}
+	}
+	m := &MmapArena[T]{arena: newAtomicArena(raw, ptrs, o), mem: mem, ptrsOff: ptrsOff, pooled: huge == hugeTLB}
+	m.arena.setCap(maxElems, 0)
+	m.arena.huge = huge != hugeNone
+	if mem != nil && !o.locked && !m.pooled {
+		m.arena.discard = m.discardSlots
+	}
+	if o.locked && mem != nil {
+		switch err := lockMemory(mem); {
+		case err == nil:
+			m.locked = true
+		case errors.Is(err, errLockUnsupported):
+			// degrade to unlocked storage; Locked reports false
+		default:
+			_ = unmapMemory(mem)
+			return nil, err
+		}
+	}
+	if o.prefault && !(populate && mapPopulates) {
+		m.prefault()
+	}
+	return m, nil
+}
+
+// Locked reports whether the storage is locked in physical memory.
+func (m *MmapArena[T]) Locked() bool {
+	return m.locked
+}
+
+// Stats returns a summary of the arena's state, as AtomicArena.Stats does.
+// HugePages reports whether WithHugePages or WithHugeTLB took effect.
+func (m *MmapArena[T]) Stats() Stats {
+	return m.arena.Stats()
+}
+
+// Prefault touches every page of the mapping. Like AtomicArena.Prefault it
+// must not run concurrently with writers.
+func (m *MmapArena[T]) Prefault() error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	m.prefault()
+	return nil
+}
+
+// prefault touches the pages of the usable slots, leaving out the
+// WithGrowLimit reserve and whatever Truncate unmapped.
+func (m *MmapArena[T]) prefault() {
+	n := m.arena.Cap()
+	prefault(elemBytes(m.arena.raw, n))
+	if m.arena.ptrs != nil {
+		prefault(elemBytes(m.arena.ptrs, n))
+	}
+}
+
+// closedErr maps errors from the underlying arena after Close to ErrClosed.
+func (m *MmapArena[T]) closedErr(err error) error {
+	if err == ErrFrozen && m.closed.Load() {
+		return ErrClosed
+	}
+	return err
+}
+
+// Alloc stores obj in the next free slot. It returns ErrClosed after Close.
+func (m *MmapArena[T]) Alloc(obj T) (*T, error) {
+	if m.closed.Load() {
+		return nil, ErrClosed
+	}
+	p, err := m.arena.Alloc(obj)
+	return p, m.closedErr(err)
+}
+
+// Reserve reserves n slots and returns them as a slice. It returns ErrClosed after Close.
+func (m *MmapArena[T]) Reserve(n uintptr) ([]T, error) {
+	if m.closed.Load() {
+		return nil, ErrClosed
+	}
+	seg, err := m.arena.Reserve(n)
+	return seg, m.closedErr(err)
+}
+
+// Reset rewinds the arena. With release set it hands the pages of the used
+// region, and of its pointer mirror, back to the OS, so resident memory drops
+// while the mapping stays valid: the pages fault back in zeroed on reuse.
+// Only the partial pages at either end are zeroed by hand. This happens while
+// new reservations are held off, so no allocation can land on a page before
+// it is dropped. Free and the other paths that zero released slots drop
+// whole pages the same way. Under the heap fallback, and with storage from
+// WithHugeTLB, the used region is zeroed instead, as Free does. On a locked
+// arena Reset always wipes the used region.
+func (m *MmapArena[T]) Reset(release bool) error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	if m.locked {
+		return m.Wipe()
+	}
+	return m.closedErr(m.arena.Reset(release))
+}
+
+// ResetRelease is Reset(true): it rewinds the arena and returns the pages
+// of the used region to the OS.
+func (m *MmapArena[T]) ResetRelease() error {
+	return m.Reset(true)
+}
+
+// discardSlots is the arena's zeroRange for mapped storage: it drops the
+// whole pages that slots [lo, hi) and their mirror entries cover and zeroes
+// the rest, reporting false to leave ranges without a whole page of
+// elements to ordinary zeroing.
+func (m *MmapArena[T]) discardSlots(lo, hi uintptr) bool {
+	a := m.arena
+	raw := elemBytes(a.raw[lo:hi], hi-lo)
+	s, e, ok := discardInner(raw)
+	if !ok {
+		return false
+	}
+	clear(raw[:s])
+	clear(raw[e:])
+	if a.ptrs != nil {
+		ptrs := a.ptrs[lo:hi]
+		s, e, _ := discardInner(elemBytes(ptrs, hi-lo))
+		size := unsafe.Sizeof(ptrs[0])
+		clearMirror(ptrs[:s/size])
+		clearMirror(ptrs[e/size:])
+	}
+	return true
+}
+
+// discardInner hands the pages lying wholly inside b back to the OS and
+// returns their offsets in b. It reports false, with an empty range, if b
+// covers no whole page or the OS refused.
+func discardInner(b []byte) (lo, hi uintptr, ok bool) {
+	inner := pageAligned(b)
+	if len(inner) == 0 || discardPages(inner) != nil {
+		return 0, 0, false
+	}
+	lo = uintptr(unsafe.Pointer(&inner[0])) - uintptr(unsafe.Pointer(&b[0]))
+	return lo, lo + uintptr(len(inner)), true
+}
+
+// Get returns the element at index i, or false if it is not allocated or the
+// arena is closed. Get must not race with Close.
+func (m *MmapArena[T]) Get(i uintptr) (*T, bool) {
+	if m.closed.Load() {
+		return nil, false
+	}
+	return m.arena.Get(i)
+}
+
+// Len returns the number of allocated slots, or zero after Close.
+func (m *MmapArena[T]) Len() uintptr {
+	if m.closed.Load() {
+		return 0
+	}
+	return m.arena.Len()
+}
+
+// Cap returns the arena's capacity.
+func (m *MmapArena[T]) Cap() uintptr {
+	return m.arena.Cap()
+}
+
+// BasePointer returns the address of the mapping's first slot, or nil after
+// Close. The mapping lies outside the Go heap, so the address may be handed
+// to C or to system calls without pinning; it stays valid until Close.
+func (m *MmapArena[T]) BasePointer() unsafe.Pointer {
+	if m.closed.Load() {
+		return nil
+	}
+	return m.arena.BasePointer()
+}
+
+// Stride returns the distance in bytes between consecutive slots, like
+// AtomicArena.Stride.
+func (m *MmapArena[T]) Stride() uintptr {
+	return m.arena.Stride()
+}
+
+// UsedBytes returns the size of the allocated region that starts at
+// BasePointer, or zero after Close.
+func (m *MmapArena[T]) UsedBytes() uintptr {
+	return m.Len() * m.Stride()
+}
+
+// Close waits for in-flight allocations to finish, unmaps the storage and
+// makes further operations fail with ErrClosed. Pointers previously returned
+// by the arena must not be used afterwards. Close is idempotent.
+func (m *MmapArena[T]) Close() error {
+	m.closeOnce.Do(func() {
+		m.closed.Store(true)
+		m.arena.Freeze()
+		_ = m.arena.Close()
+		if m.mem != nil {
+			if sanitized {
+				// the sanitizer would otherwise flag whatever is mapped here next
+				unpoison(unsafe.Pointer(&m.mem[0]), uintptr(len(m.mem)))
+			}
+			m.closeErr = unmapMemory(m.mem)
+		}
+	})
+	return m.closeErr
+}
+
+// Truncate lowers the capacity to newMax like AtomicArena.Truncate, in
+// place, and releases the pages of element storage and pointer mirror beyond
+// it, so the memory goes back to the operating system. Their addresses stay
+// reserved, inaccessible, until Close unmaps the whole range. Pointers to
+// allocated slots stay valid. It has the same requirements and errors as
+// AtomicArena.Truncate, and returns ErrClosed after Close. Under the heap
+// fallback, or with storage from WithHugeTLB, the capacity drops but no
+// memory is returned.
+func (m *MmapArena[T]) Truncate(newMax uintptr) error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	if err := m.closedErr(m.arena.Truncate(newMax, false)); err != nil {
+		return err
+	}
+	if m.mem == nil || m.pooled {
+		return nil
+	}
+	// release everything past the new end of each region; pages an earlier
+	// Truncate released are released again, which costs nothing
+	elem := unsafe.Sizeof(*new(T))
+	if err := releasePages(m.mem[min(newMax*elem, m.ptrsOff):m.ptrsOff]); err != nil {
+		return err
+	}
+	if m.arena.ptrs != nil {
+		return releasePages(m.mem[m.ptrsOff+newMax*unsafe.Sizeof(atomic.Pointer[T]{}):])
+	}
+	return nil
+}
+
+// Grow raises the capacity by additional slots within the room mapped by
+// WithGrowLimit, like AtomicArena.Grow: nothing moves, and it may run
+// concurrently with allocations. It fails with ErrCannotGrowInPlace past the
+// limit and returns ErrClosed after Close.
+func (m *MmapArena[T]) Grow(additional uintptr) error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	return m.closedErr(m.arena.Grow(additional))
+}
+
+// pageAligned returns the sub-slice of mem covering only whole pages.
+func pageAligned(mem []byte) []byte {
+	if len(mem) == 0 {
+		return nil
+	}
+	page := uintptr(pageSize)
+	start := uintptr(unsafe.Pointer(&mem[0]))
+	lo := (start + page - 1) &^ (page - 1)
+	hi := (start + uintptr(len(mem))) &^ (page - 1)
+	if hi <= lo {
+		return nil
+	}
+	return mem[lo-start : hi-start]
+}
+
+// Free zeroes the used region without rewinding the arena.
+// On a locked arena the region is wiped.
+func (m *MmapArena[T]) Free() error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	if m.locked {
+		return m.wipe()
+	}
+	return m.closedErr(m.arena.Free())
+}
+
+// Wipe overwrites the used region, including the pointer mirror, with zeros in
+// a way the compiler cannot elide, then rewinds the arena. It must not run
+// concurrently with writers.
+func (m *MmapArena[T]) Wipe() error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	if err := m.wipe(); err != nil {
+		return err
+	}
+	return m.closedErr(m.arena.Reset(false))
+}
+
+func (m *MmapArena[T]) wipe() error {
+	if m.arena.Frozen() {
+		return m.closedErr(ErrFrozen)
+	}
+	n := m.arena.Len()
+	wipeBytes(elemBytes(m.arena.raw, n))
+	wipeBytes(elemBytes(m.arena.ptrs, n))
+	return nil
+}
+
+// wipeBytes zeroes b. The memory is reachable from outside this function and
+// is kept alive past the clear, so the stores can't be treated as dead.
+func wipeBytes(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
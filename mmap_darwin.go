@@ -0,0 +1,28 @@
+//go:build darwin && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// mapPopulates reports whether mapMemory can pre-populate pages itself.
+const mapPopulates = false
+
+const mapPopulate = 0
+
+// discardPages hands the pages of mem, which must be page-aligned, back to
+// the OS. Pages given up with MADV_FREE may keep their contents, so fresh
+// zero pages are mapped over them instead.
+func discardPages(mem []byte) error {
+	_, _, errno := syscall.Syscall6(syscall.SYS_MMAP, uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)),
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE|syscall.MAP_FIXED, ^uintptr(0), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// memlockLimit describes the memory lock limit for error messages.
+func memlockLimit() string { return "" }
@@ -0,0 +1,29 @@
+//go:build atomicarena_heapmmap || !(linux || darwin || windows)
+
+package atomicarena
+
+import "unsafe"
+
+// Heap fallback for platforms without a supported mapping primitive.
+// The storage is an ordinary byte slice and is reclaimed by the GC.
+
+func mapMemory(size uintptr, _ bool) ([]byte, error) {
+	// []uint64 backing guarantees 8-byte alignment for the element types
+	words := make([]uint64, (size+7)/8)
+	return unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), size), nil
+}
+
+// mapPopulates reports whether mapMemory can pre-populate pages itself.
+const mapPopulates = false
+
+func unmapMemory([]byte) error { return nil }
+
+// discardPages only zeroes mem: heap storage cannot be handed back.
+func discardPages(mem []byte) error {
+	clear(mem)
+	return nil
+}
+
+func releasePages([]byte) error { return nil }
+
+func lockMemory([]byte) error { return errLockUnsupported }
@@ -0,0 +1,8 @@
+//go:build linux && !arm && !atomicarena_heapmmap
+
+package atomicarena
+
+import "syscall"
+
+// mapHugeTLB is MAP_HUGETLB, whose value differs between architectures.
+const mapHugeTLB = syscall.MAP_HUGETLB
@@ -0,0 +1,6 @@
+//go:build linux && arm && !atomicarena_heapmmap
+
+package atomicarena
+
+// mapHugeTLB is MAP_HUGETLB, which package syscall leaves out on linux/arm.
+const mapHugeTLB = 0x40000
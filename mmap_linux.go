@@ -0,0 +1,70 @@
+//go:build linux && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// mapPopulates reports whether mapMemory can pre-populate pages itself.
+const mapPopulates = true
+
+const mapPopulate = syscall.MAP_POPULATE
+
+// discardPages hands the pages of mem, which must be page-aligned, back to
+// the OS. Private anonymous pages fault back in zeroed after MADV_DONTNEED.
+func discardPages(mem []byte) error {
+	return syscall.Madvise(mem, syscall.MADV_DONTNEED)
+}
+
+// mapHugeMemory maps size bytes for WithHugePages, or for WithHugeTLB with
+// tlb set, and reports which kind of huge pages back the mapping. The pages
+// are never populated up front: the advice has to come first.
+func mapHugeMemory(size uintptr, tlb bool) ([]byte, hugeBacking, error) {
+	if tlb {
+		// hugetlb mappings are aligned by the kernel but unmapped in whole pages
+		full := (size + hugePageSize - 1) &^ (hugePageSize - 1)
+		if mem, err := mapAnon(full, syscall.MAP_ANON|syscall.MAP_PRIVATE|mapHugeTLB); err == nil {
+			return mem[:size], hugeTLB, nil
+		}
+	}
+	// over-map by a huge page and trim both ends to align the start to one
+	region, err := mapAnon(size+hugePageSize, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, hugeNone, err
+	}
+	base := uintptr(unsafe.Pointer(&region[0]))
+	head := (base+hugePageSize-1)&^(hugePageSize-1) - base
+	tail := head + (size+uintptr(pageSize)-1)&^uintptr(pageSize-1)
+	if err := unmapRange(region[:head]); err != nil {
+		_ = unmapRange(region)
+		return nil, hugeNone, err
+	}
+	if err := unmapRange(region[tail:]); err != nil {
+		_ = unmapRange(region[head:])
+		return nil, hugeNone, err
+	}
+	mem := region[head : head+size : head+size]
+	if syscall.Madvise(mem, syscall.MADV_HUGEPAGE) != nil {
+		// kernels without transparent huge pages refuse the advice
+		return mem, hugeNone, nil
+	}
+	return mem, hugeTransparent, nil
+}
+
+// memlockLimit describes RLIMIT_MEMLOCK for error messages.
+func memlockLimit() string {
+	resource := 8 // RLIMIT_MEMLOCK
+	switch runtime.GOARCH {
+	case "mips", "mipsle", "mips64", "mips64le":
+		resource = 9
+	}
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(resource, &lim); err != nil {
+		return ""
+	}
+	return fmt.Sprintf(" (RLIMIT_MEMLOCK is %d bytes)", lim.Cur)
+}
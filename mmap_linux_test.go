@@ -0,0 +1,209 @@
+//go:build linux && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// TestMmapArenaOffHeap checks the storage is covered by a mapping listed in
+// /proc/self/maps and that the mapping is gone after Close.
+func TestMmapArenaOffHeap(t *testing.T) {
+	m, err := NewMmapArena[int64](1 << 20)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	addr := uintptr(unsafe.Pointer(&m.mem[0]))
+	if !mapped(t, addr) {
+		t.Fatal("arena storage is not mapped")
+	}
+	_ = m.Close()
+	if mapped(t, addr) {
+		t.Fatal("mapping still present after Close")
+	}
+}
+
+// mapped reports whether addr lies in a mapping listed in /proc/self/maps.
+func mapped(t *testing.T, addr uintptr) bool {
+	t.Helper()
+	_, ok := protection(t, addr)
+	return ok
+}
+
+// protection returns the permissions, such as "rw-p", of the mapping listed
+// in /proc/self/maps that contains addr, reporting false if there is none.
+func protection(t *testing.T, addr uintptr) (string, bool) {
+	t.Helper()
+	maps, err := os.ReadFile("/proc/self/maps")
+	if err != nil {
+		t.Skipf("cannot read /proc/self/maps: %v", err)
+	}
+	for _, line := range strings.Split(string(maps), "\n") {
+		var lo, hi uintptr
+		var perms string
+		if _, err := fmt.Sscanf(line, "%x-%x %s", &lo, &hi, &perms); err == nil && lo <= addr && addr < hi {
+			return perms, true
+		}
+	}
+	return "", false
+}
+
+// resident returns the resident kilobytes of mem, a page-aligned mapping,
+// counted page by page with mincore so neighbouring mappings the kernel
+// merged it with are left out.
+func resident(t *testing.T, mem []byte) int {
+	t.Helper()
+	page := os.Getpagesize()
+	vec := make([]byte, (len(mem)+page-1)/page)
+	if _, _, errno := syscall.Syscall(syscall.SYS_MINCORE, uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)), uintptr(unsafe.Pointer(&vec[0]))); errno != 0 {
+		t.Skipf("mincore failed: %v", errno)
+	}
+	n := 0
+	for _, v := range vec {
+		n += int(v & 1)
+	}
+	return n * page >> 10
+}
+
+// TestMmapResetReleaseRSS checks ResetRelease drops the resident pages of
+// both the storage and the mirror while keeping the mapping usable.
+func TestMmapResetReleaseRSS(t *testing.T) {
+	const n = 1 << 20
+	m, err := NewMmapArena[int64](n)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	if _, err := m.arena.AppendSlice(make([]int64, n)); err != nil {
+		t.Fatalf("AppendSlice failed: %v", err)
+	}
+	for i := range m.arena.raw {
+		m.arena.raw[i] = int64(i) + 1
+	}
+	// the whole mapping: 8MiB of elements plus a mirror of n pointers
+	want := int(uintptr(len(m.mem)) >> 10)
+	if kb := resident(t, m.mem); kb < want-64 {
+		t.Fatalf("expected about %d kB resident after filling, got %d kB", want, kb)
+	}
+	if err := m.ResetRelease(); err != nil {
+		t.Fatalf("ResetRelease failed: %v", err)
+	}
+	if kb := resident(t, m.mem); kb > 64 {
+		t.Fatalf("expected the pages to be released, %d kB still resident", kb)
+	}
+	p, _ := m.Alloc(5)
+	seg, _ := m.Reserve(n - 1)
+	if *p != 5 || seg[0] != 0 || seg[n-2] != 0 {
+		t.Fatal("expected the released pages to fault back in zeroed")
+	}
+}
+
+// TestMmapTruncateReleases checks Truncate releases the tail of both the
+// element storage and the pointer mirror, keeping its addresses reserved,
+// and keeps the live slots usable.
+func TestMmapTruncateReleases(t *testing.T) {
+	const n = 1 << 20
+	m, err := NewMmapArena[int64](n)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	p, _ := m.Alloc(42)
+	elemTail := uintptr(unsafe.Pointer(&m.mem[n/2*8]))
+	mirrorTail := uintptr(unsafe.Pointer(&m.mem[len(m.mem)-1]))
+	if !mapped(t, elemTail) || !mapped(t, mirrorTail) {
+		t.Fatal("arena storage is not mapped")
+	}
+	if err := m.Truncate(1024); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	for _, addr := range []uintptr{elemTail, mirrorTail} {
+		if perms, ok := protection(t, addr); !ok || perms != "---p" {
+			t.Fatalf("expected the truncated tail to stay reserved without access, got %q, %v", perms, ok)
+		}
+	}
+	if kb := resident(t, m.mem[n/2*8:m.ptrsOff]) + resident(t, m.mem[m.ptrsOff+n/2*unsafe.Sizeof(uintptr(0)):]); kb != 0 {
+		t.Fatalf("expected the truncated tail to take no memory, %d kB resident", kb)
+	}
+	if !mapped(t, uintptr(unsafe.Pointer(p))) || *p != 42 {
+		t.Fatal("expected the allocated slot to stay mapped and intact")
+	}
+	if err := m.Truncate(100); err != nil {
+		t.Fatalf("second Truncate failed: %v", err)
+	}
+	if err := m.Prefault(); err != nil {
+		t.Fatalf("Prefault failed: %v", err)
+	}
+	seg, err := m.Reserve(99)
+	if err != nil || len(seg) != 99 {
+		t.Fatalf("expected the remaining 99 slots to be usable, got %v", err)
+	}
+	if _, err := m.Alloc(1); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull at the new capacity, got %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+// TestMmapTruncateThenClose maps memory after a Truncate, asking for the
+// released range, and checks the mapping lands elsewhere and survives Close.
+func TestMmapTruncateThenClose(t *testing.T) {
+	const n = 1 << 20
+	m, err := NewMmapArena[int64](n)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	lo := uintptr(unsafe.Pointer(&m.mem[0]))
+	hi := lo + uintptr(len(m.mem))
+	if err := m.Truncate(16); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	// the kernel honours the address hint whenever the range there is free
+	hint := lo + uintptr(n/2*8)
+	addr, _, errno := syscall.Syscall6(sysMmap, hint, uintptr(pageSize),
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE, ^uintptr(0), 0)
+	if errno != 0 {
+		t.Fatalf("mmap failed: %v", errno)
+	}
+	if addr >= lo && addr < hi {
+		t.Fatalf("expected the released range to stay reserved, got a mapping at %#x", addr)
+	}
+	other := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&addr))), pageSize)
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	// faults if Close unmapped it
+	other[0] = 1
+	if err := unmapMemory(other); err != nil {
+		t.Fatalf("unmapMemory failed: %v", err)
+	}
+}
+
+// TestMmapHugePagesAligned maps a huge-page arena at a 2MB boundary and reports the advice was taken
+func TestMmapHugePagesAligned(t *testing.T) {
+	if _, err := os.Stat("/sys/kernel/mm/transparent_hugepage"); err != nil {
+		t.Skipf("kernel without transparent huge pages: %v", err)
+	}
+	m, err := NewMmapArena[int64](3<<18, WithHugePages())
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	addr := uintptr(unsafe.Pointer(&m.mem[0]))
+	if addr%hugePageSize != 0 {
+		t.Fatalf("expected the mapping to be 2MB-aligned, got %#x", addr)
+	}
+	if !m.Stats().HugePages {
+		t.Fatal("expected MADV_HUGEPAGE to succeed")
+	}
+	_ = m.Close()
+	if mapped(t, addr) {
+		t.Fatal("mapping still present after Close")
+	}
+}
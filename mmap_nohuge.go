@@ -0,0 +1,9 @@
+//go:build !linux || atomicarena_heapmmap
+
+package atomicarena
+
+// mapHugeMemory maps ordinary pages: huge pages are only requested on Linux.
+func mapHugeMemory(size uintptr, _ bool) ([]byte, hugeBacking, error) {
+	mem, err := mapMemory(size, false)
+	return mem, hugeNone, err
+}
@@ -0,0 +1,8 @@
+//go:build (darwin || (linux && !(386 || arm || mips || mipsle))) && !atomicarena_heapmmap
+
+package atomicarena
+
+import "syscall"
+
+// sysMmap is the mmap system call; 32-bit Linux uses mmap2 instead.
+const sysMmap = syscall.SYS_MMAP
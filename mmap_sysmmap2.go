@@ -0,0 +1,9 @@
+//go:build linux && (386 || arm || mips || mipsle) && !atomicarena_heapmmap
+
+package atomicarena
+
+import "syscall"
+
+// sysMmap is mmap2 on 32-bit Linux, where plain mmap takes its arguments in
+// a struct in memory rather than in registers.
+const sysMmap = syscall.SYS_MMAP2
@@ -0,0 +1,145 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type mmapSample struct {
+	TS    int64
+	Value float64
+}
+
+// TestMmapArena covers allocation, capacity and Get on the mapped backend
+func TestMmapArena(t *testing.T) {
+	m, err := NewMmapArena[mmapSample](3)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	for i := 0; i < 3; i++ {
+		p, err := m.Alloc(mmapSample{int64(i), float64(i) / 2})
+		if err != nil {
+			t.Fatalf("Alloc %d failed: %v", i, err)
+		}
+		if p.TS != int64(i) {
+			t.Fatalf("unexpected value %+v", *p)
+		}
+	}
+	if _, err := m.Alloc(mmapSample{}); err == nil {
+		t.Fatal("expected error when full")
+	}
+	if v, ok := m.Get(1); !ok || v.Value != 0.5 {
+		t.Fatalf("Get(1) = %v, %v", v, ok)
+	}
+	if err := m.Reset(true); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	seg, err := m.Reserve(3)
+	if err != nil {
+		t.Fatalf("Reserve after Reset failed: %v", err)
+	}
+	for i := range seg {
+		if seg[i] != (mmapSample{}) {
+			t.Fatalf("slot %d not zeroed after Reset(true): %+v", i, seg[i])
+		}
+	}
+}
+
+// TestMmapResetRelease drops pages on reset and on a rollback whose edges fall mid-page
+func TestMmapResetRelease(t *testing.T) {
+	const n = 2000
+	m, err := NewMmapArena[[3]int64](n)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	fill := func() {
+		vals := make([][3]int64, n)
+		for i := range vals {
+			vals[i] = [3]int64{int64(i), -1, 1}
+		}
+		if _, err := m.arena.AppendSlice(vals); err != nil {
+			t.Fatalf("AppendSlice failed: %v", err)
+		}
+	}
+	fill()
+	if err := m.ResetRelease(); err != nil {
+		t.Fatalf("ResetRelease failed: %v", err)
+	}
+	for i := range m.arena.ptrs {
+		if m.arena.ptrs[i].Load() != nil {
+			t.Fatalf("mirror entry %d not cleared", i)
+		}
+	}
+	seg, _ := m.Reserve(n)
+	for i := range seg {
+		if seg[i] != ([3]int64{}) {
+			t.Fatalf("slot %d not zeroed: %v", i, seg[i])
+		}
+	}
+	m.Reset(false)
+	fill()
+	if err := m.arena.TryShrinkTo(7); err != nil {
+		t.Fatalf("TryShrinkTo failed: %v", err)
+	}
+	inspectReleased(m.arena)
+	for i, v := range m.arena.raw {
+		if want := ([3]int64{int64(i), -1, 1}); (i < 7) != (v == want) || i >= 7 && v != ([3]int64{}) {
+			t.Fatalf("slot %d holds %v after shrinking to 7", i, v)
+		}
+	}
+}
+
+// TestMmapArenaClose ensures operations after Close fail instead of faulting
+func TestMmapArenaClose(t *testing.T) {
+	m, err := NewMmapArena[int64](1 << 16)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := m.Alloc(1); err != nil {
+					if !errors.Is(err, ErrClosed) && m.Len() != m.Cap() {
+						t.Errorf("unexpected error: %v", err)
+					}
+					return
+				}
+			}
+		}()
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	wg.Wait()
+	if err := m.Close(); err != nil {
+		t.Fatalf("second Close failed: %v", err)
+	}
+	if _, err := m.Alloc(1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from Alloc, got %v", err)
+	}
+	if _, err := m.Reserve(1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from Reserve, got %v", err)
+	}
+	if err := m.Reset(false); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from Reset, got %v", err)
+	}
+	if _, ok := m.Get(0); ok {
+		t.Fatal("Get succeeded after Close")
+	}
+}
+
+// TestMmapArenaPointerType ensures pointer-containing types are rejected
+func TestMmapArenaPointerType(t *testing.T) {
+	if _, err := NewMmapArena[*int](1); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("expected ErrPointerType, got %v", err)
+	}
+	if _, err := NewMmapArena[struct{ S []byte }](1); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("expected ErrPointerType, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build (linux || darwin) && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+func mapMemory(size uintptr, populate bool) ([]byte, error) {
+	flags := syscall.MAP_ANON | syscall.MAP_PRIVATE
+	if populate {
+		flags |= mapPopulate
+	}
+	return mapAnon(size, flags)
+}
+
+// mapAnon maps size bytes of anonymous read-write memory. It calls mmap
+// directly rather than through syscall.Mmap, which only unmaps whole
+// mappings it made itself, so mappings can be trimmed into alignment.
+func mapAnon(size uintptr, flags int) ([]byte, error) {
+	addr, _, errno := syscall.Syscall6(sysMmap, 0, size,
+		syscall.PROT_READ|syscall.PROT_WRITE, uintptr(flags), ^uintptr(0), 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	// convert without a uintptr-to-pointer cast; the memory is not Go-managed
+	base := *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+	return unsafe.Slice((*byte)(base), size), nil
+}
+
+// unmapMemory unmaps a mapping from mapMemory or mapHugeMemory, including
+// any capacity past its length.
+func unmapMemory(mem []byte) error {
+	return unmapRange(mem[:cap(mem)])
+}
+
+func lockMemory(mem []byte) error {
+	if err := syscall.Mlock(mem); err != nil {
+		return fmt.Errorf("%w: locking %d bytes%s: %v", ErrMemLock, len(mem), memlockLimit(), err)
+	}
+	return nil
+}
+
+// releasePages returns the pages fully covered by mem, which lies inside a
+// mapping from mapMemory, to the OS by mapping inaccessible pages over them.
+// Unmapping them instead would leave a hole that a later mapping could take,
+// and that unmapMemory would then unmap as part of the whole range.
+func releasePages(mem []byte) error {
+	mem = pageAligned(mem)
+	if len(mem) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall6(sysMmap, uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)),
+		syscall.PROT_NONE, syscall.MAP_ANON|syscall.MAP_PRIVATE|syscall.MAP_FIXED, ^uintptr(0), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// unmapRange unmaps mem, which must start on a page boundary.
+func unmapRange(mem []byte) error {
+	if len(mem) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MUNMAP, uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
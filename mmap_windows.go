@@ -0,0 +1,82 @@
+//go:build windows && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	memCommit   = 0x1000
+	memReserve  = 0x2000
+	memDecommit = 0x4000
+	memRelease  = 0x8000
+	memReset    = 0x80000
+
+	pageReadWrite = 0x04
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procVirtualAlloc = kernel32.NewProc("VirtualAlloc")
+	procVirtualFree  = kernel32.NewProc("VirtualFree")
+	procVirtualLock  = kernel32.NewProc("VirtualLock")
+)
+
+func mapMemory(size uintptr, _ bool) ([]byte, error) {
+	addr, _, err := procVirtualAlloc.Call(0, size, memReserve|memCommit, pageReadWrite)
+	if addr == 0 {
+		return nil, err
+	}
+	// convert without a uintptr-to-pointer cast; the memory is not Go-managed
+	base := *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+	return unsafe.Slice((*byte)(base), size), nil
+}
+
+// mapPopulates reports whether mapMemory can pre-populate pages itself.
+const mapPopulates = false
+
+func unmapMemory(mem []byte) error {
+	ok, _, err := procVirtualFree.Call(uintptr(unsafe.Pointer(&mem[0])), 0, memRelease)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
+
+// discardPages zeroes the pages of mem, which must be page-aligned, and
+// marks them with MEM_RESET so the OS may reclaim them rather than page them
+// out. Decommitting would return them at once, but a failed recommit would
+// leave the range faulting; reset pages read back either as they were left
+// or as zero, and both are zero here. A refused reset costs only the
+// reclaim, so it is not reported.
+func discardPages(mem []byte) error {
+	clear(mem)
+	procVirtualAlloc.Call(uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)), memReset, pageReadWrite)
+	return nil
+}
+
+// releasePages decommits the pages fully covered by mem, returning their
+// memory to the OS. A region can only be released as a whole, so the
+// address range stays reserved until unmapMemory.
+func releasePages(mem []byte) error {
+	mem = pageAligned(mem)
+	if len(mem) == 0 {
+		return nil
+	}
+	ok, _, err := procVirtualFree.Call(uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)), memDecommit)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
+
+func lockMemory(mem []byte) error {
+	ok, _, err := procVirtualLock.Call(uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)))
+	if ok == 0 {
+		return fmt.Errorf("%w: locking %d bytes: %v", ErrMemLock, len(mem), err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package atomicarena
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// model is the sequential reference for AtomicArena: a fixed buffer and a
+// length. Like the real arena, Reset without release leaves old values in
+// place, where a later Reserve sees them again.
+type model struct {
+	raw []int64
+	n   int
+}
+
+func (m *model) alloc(v int64) bool {
+	if m.n == len(m.raw) {
+		return false
+	}
+	m.raw[m.n] = v
+	m.n++
+	return true
+}
+
+func (m *model) reserve(k int) ([]int64, bool) {
+	if k > len(m.raw)-m.n {
+		return nil, false
+	}
+	seg := m.raw[m.n : m.n+k]
+	m.n += k
+	return seg, true
+}
+
+func (m *model) appendSlice(vals []int64) bool {
+	seg, ok := m.reserve(len(vals))
+	if ok {
+		copy(seg, vals)
+	}
+	return ok
+}
+
+func (m *model) reset(release bool) {
+	if release {
+		clear(m.raw[:m.n])
+	}
+	m.n = 0
+}
+
+func (m *model) drain() []int64 {
+	out := slices.Clone(m.raw[:m.n])
+	m.reset(false)
+	return out
+}
+
+func (m *model) get(i int) (int64, bool) {
+	if i < 0 || i >= m.n {
+		return 0, false
+	}
+	return m.raw[i], true
+}
+
+// opNames lists the operations the driver generates, indexed by opcode.
+var opNames = [...]string{"Alloc", "AppendSlice", "Reserve", "Reset", "Drain", "Get"}
+
+// runOps decodes ops from a byte stream, applies each to both a fresh arena
+// and the model, and fails on the first observable difference. Every
+// operation consumes up to three bytes: the opcode and two arguments.
+func runOps(t *testing.T, capacity int, program []byte) {
+	t.Helper()
+	a := NewAtomicArena[int64](uintptr(capacity))
+	m := &model{raw: make([]int64, capacity)}
+	arg := func(i int) int {
+		if i < len(program) {
+			return int(program[i])
+		}
+		return 0
+	}
+	for pc, step := 0, 0; pc < len(program); pc, step = pc+3, step+1 {
+		op, x, y := int(program[pc])%len(opNames), arg(pc+1), arg(pc+2)
+		fail := func(format string, args ...any) {
+			t.Helper()
+			t.Fatalf("step %d (%s %d %d): "+format, append([]any{step, opNames[op], x, y}, args...)...)
+		}
+		switch op {
+		case 0: // Alloc
+			v := int64(x<<8 | y)
+			p, err := a.Alloc(v)
+			ok := m.alloc(v)
+			if (err == nil) != ok || err != nil && !errors.Is(err, ErrArenaFull) {
+				fail("arena returned %v, model ok=%v", err, ok)
+			}
+			if ok && *p != v {
+				fail("Alloc stored %d, want %d", *p, v)
+			}
+		case 1: // AppendSlice
+			vals := make([]int64, x%8)
+			for i := range vals {
+				vals[i] = int64(y + i)
+			}
+			seg, err := a.AppendSlice(vals)
+			ok := m.appendSlice(vals)
+			if (err == nil) != ok {
+				fail("arena returned %v, model ok=%v", err, ok)
+			}
+			if ok && !slices.Equal(seg, vals) {
+				fail("segment %v, want %v", seg, vals)
+			}
+		case 2: // Reserve, then fill the segment
+			k := x % 8
+			seg, err := a.Reserve(uintptr(k))
+			mseg, ok := m.reserve(k)
+			if (err == nil) != ok {
+				fail("arena returned %v, model ok=%v", err, ok)
+			}
+			if ok {
+				if !slices.Equal(seg, mseg) {
+					fail("reserved segment holds %v, model %v", seg, mseg)
+				}
+				for i := range seg {
+					seg[i], mseg[i] = int64(-y-i), int64(-y-i)
+				}
+			}
+		case 3: // Reset
+			release := x%2 == 1
+			if err := a.Reset(release); err != nil {
+				fail("Reset failed: %v", err)
+			}
+			m.reset(release)
+		case 4: // Drain
+			got := a.Snapshot()
+			if err := a.Reset(false); err != nil {
+				fail("Reset failed: %v", err)
+			}
+			if want := m.drain(); !slices.Equal(got, want) {
+				fail("drained %v, want %v", got, want)
+			}
+		case 5: // Get
+			i := x % (capacity + 2)
+			p, ok := a.Get(uintptr(i))
+			v, mok := m.get(i)
+			if ok != mok || ok && *p != v {
+				fail("Get(%d) = %v, want %d, %v", i, ok, v, mok)
+			}
+		}
+		if a.Len() != uintptr(m.n) {
+			fail("len %d, model %d", a.Len(), m.n)
+		}
+	}
+	if got := a.Snapshot(); !slices.Equal(got, m.raw[:m.n]) {
+		t.Fatalf("final contents %v, model %v", got, m.raw[:m.n])
+	}
+}
+
+// TestModel runs random operation sequences against the model
+func TestModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	runs := 500
+	if testing.Short() {
+		runs = 100
+	}
+	for r := 0; r < runs; r++ {
+		program := make([]byte, 3*(1+rng.Intn(64)))
+		rng.Read(program)
+		runOps(t, 1+rng.Intn(24), program)
+	}
+}
+
+// FuzzOps drives the model comparison from fuzzer-generated programs
+func FuzzOps(f *testing.F) {
+	f.Add(uint8(4), []byte{0, 1, 2, 1, 3, 4, 2, 2, 0, 5, 1, 0, 3, 0, 0, 2, 3, 0, 4, 0, 0})
+	f.Add(uint8(16), []byte{1, 7, 9, 1, 7, 9, 1, 7, 9, 5, 20, 0, 3, 1, 0, 2, 5, 0, 5, 2, 0})
+	f.Add(uint8(1), []byte{0, 0, 1, 0, 0, 2, 2, 1, 0, 4, 0, 0})
+	f.Fuzz(func(t *testing.T, capacity uint8, program []byte) {
+		if len(program) > 3*256 {
+			program = program[:3*256]
+		}
+		runOps(t, int(capacity%64), program)
+	})
+}
+
+// TestModelConcurrent is the concurrent stress mode: writers record every
+// value whose Alloc or AppendSlice succeeded, and after quiescence the
+// multiset of stored values must equal the multiset of successful writes.
+func TestModelConcurrent(t *testing.T) {
+	const writers, perWriter = 8, 2000
+	for _, capacity := range []uintptr{writers * perWriter * 3 / 2, writers * perWriter / 3} {
+		a := NewAtomicArena[int64](capacity)
+		accepted := make([][]int64, writers)
+		var wg sync.WaitGroup
+		for g := 0; g < writers; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rng := rand.New(rand.NewSource(int64(g)))
+				for i := 0; i < perWriter; i++ {
+					v := int64(g)<<32 | int64(i)
+					if rng.Intn(4) == 0 {
+						vals := []int64{v, -v - 1}
+						if _, err := a.AppendSlice(vals); err == nil {
+							accepted[g] = append(accepted[g], vals...)
+						}
+						continue
+					}
+					if _, err := a.Alloc(v); err == nil {
+						accepted[g] = append(accepted[g], v)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		want := slices.Concat(accepted...)
+		got := a.Snapshot()
+		slices.Sort(want)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("capacity %d: stored %d values, accepted %d; multisets differ", capacity, len(got), len(want))
+		}
+		if capacity < writers*perWriter && a.Len() < capacity-1 {
+			t.Fatalf("capacity %d: expected the arena to fill, len %d", capacity, a.Len())
+		}
+	}
+}
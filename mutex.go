@@ -0,0 +1,74 @@
+package atomicarena
+
+import "sync"
+
+// MutexArena is an Arena guarded by a sync.Mutex. It is the obviously-correct
+// baseline the lock-free arena is tested against, and under extreme write
+// contention it can outperform AtomicArena. It is safe for concurrent use;
+// values reached through returned pointers need their own synchronization.
+type MutexArena[T any] struct {
+	mu    sync.Mutex
+	arena Arena[T]
+}
+
+var _ Allocator[int] = (*MutexArena[int])(nil)
+
+// NewMutexArena creates a MutexArena that can hold up to maxElems elements of type T.
+func NewMutexArena[T any](maxElems uintptr) *MutexArena[T] {
+	return &MutexArena[T]{arena: Arena[T]{raw: make([]T, maxElems)}}
+}
+
+// Alloc stores obj in the next free slot and returns a pointer to it.
+func (m *MutexArena[T]) Alloc(obj T) (*T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.Alloc(obj)
+}
+
+// Reserve claims n slots and returns them as a slice the caller may write into.
+func (m *MutexArena[T]) Reserve(n uintptr) ([]T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.Reserve(n)
+}
+
+// AppendSlice copies objs into newly reserved slots and returns the segment.
+func (m *MutexArena[T]) AppendSlice(objs []T) ([]T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.AppendSlice(objs)
+}
+
+// Reset rewinds the arena so its slots can be reused, zeroing them first if
+// release is set.
+func (m *MutexArena[T]) Reset(release bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.Reset(release)
+}
+
+// Free zeroes the allocated storage without rewinding the arena.
+func (m *MutexArena[T]) Free() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.Free()
+}
+
+// Get returns a pointer to the element at index i, or false if i has not been allocated.
+func (m *MutexArena[T]) Get(i uintptr) (*T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.Get(i)
+}
+
+// Len returns the number of slots currently allocated in the arena.
+func (m *MutexArena[T]) Len() uintptr {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.arena.Len()
+}
+
+// Cap returns the maximum number of elements the arena can hold.
+func (m *MutexArena[T]) Cap() uintptr {
+	return m.arena.Cap()
+}
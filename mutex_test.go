@@ -0,0 +1,137 @@
+package atomicarena
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// runWorkload drives a with a randomized concurrent mix of Alloc, Reserve and
+// AppendSlice seeded per goroutine, and returns the number of slots each
+// operation kind obtained. Every written value is unique, so the final
+// contents can be compared across implementations regardless of ordering.
+func runWorkload(a Allocator[int], seed int64, workers int) [3]uintptr {
+	var mu sync.Mutex
+	var total [3]uintptr
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed + int64(w)))
+			var got [3]uintptr
+			for op := 0; op < 200; op++ {
+				v := (w*200 + op) * 8
+				switch rng.Intn(3) {
+				case 0:
+					if _, err := a.Alloc(v); err == nil {
+						got[0]++
+					}
+				case 1:
+					n := rng.Intn(4)
+					if seg, err := a.Reserve(uintptr(n)); err == nil {
+						for i := range seg {
+							seg[i] = v + i
+						}
+						got[1] += uintptr(n)
+					}
+				case 2:
+					objs := make([]int, rng.Intn(4))
+					for i := range objs {
+						objs[i] = v + i
+					}
+					if _, err := a.AppendSlice(objs); err == nil {
+						got[2] += uintptr(len(objs))
+					}
+				}
+			}
+			mu.Lock()
+			for i := range total {
+				total[i] += got[i]
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return total
+}
+
+// contents returns the allocated values of a in sorted order
+func contents(a Allocator[int]) []int {
+	out := make([]int, 0, a.Len())
+	for i := uintptr(0); i < a.Len(); i++ {
+		v, _ := a.Get(i)
+		out = append(out, *v)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// TestDifferentialMutexArena compares the lock-free arena against MutexArena
+// on randomized concurrent workloads. Capacity exceeds the maximum demand, so
+// both must accept every request and end with identical contents.
+func TestDifferentialMutexArena(t *testing.T) {
+	const workers = 8
+	for seed := int64(0); seed < 20; seed++ {
+		lockFree := NewAtomicArena[int](workers * 200 * 3)
+		baseline := NewMutexArena[int](workers * 200 * 3)
+		gotA := runWorkload(lockFree, seed, workers)
+		gotB := runWorkload(baseline, seed, workers)
+		if gotA != gotB {
+			t.Fatalf("seed %d: counters diverged: %v vs %v", seed, gotA, gotB)
+		}
+		if lockFree.Len() != baseline.Len() || lockFree.Len() != gotA[0]+gotA[1]+gotA[2] {
+			t.Fatalf("seed %d: lengths diverged: %d vs %d", seed, lockFree.Len(), baseline.Len())
+		}
+		if !slices.Equal(contents(lockFree), contents(baseline)) {
+			t.Fatalf("seed %d: contents diverged", seed)
+		}
+	}
+}
+
+// TestDifferentialFull checks both implementations fill to exactly Cap under contention
+func TestDifferentialFull(t *testing.T) {
+	for _, a := range []Allocator[int]{NewAtomicArena[int](500), NewMutexArena[int](500)} {
+		runWorkload(a, 1, 16)
+		if a.Len() > a.Cap() {
+			t.Fatalf("%T overshot capacity: %d", a, a.Len())
+		}
+		vals := contents(a)
+		if len(slices.Compact(vals)) != len(contents(a)) {
+			t.Fatalf("%T stored duplicate values", a)
+		}
+	}
+}
+
+// BenchmarkContention compares AtomicArena and MutexArena under increasing goroutine counts
+func BenchmarkContention(b *testing.B) {
+	impls := []struct {
+		name string
+		new  func(n uintptr) Allocator[int]
+	}{
+		{"AtomicArena", func(n uintptr) Allocator[int] { return NewAtomicArena[int](n) }},
+		{"MutexArena", func(n uintptr) Allocator[int] { return NewMutexArena[int](n) }},
+	}
+	for _, impl := range impls {
+		for _, g := range []int{1, 8, 64} {
+			b.Run(fmt.Sprintf("%s/%d", impl.name, g), func(b *testing.B) {
+				a := impl.new(uintptr(b.N))
+				per := b.N/g + 1
+				var wg sync.WaitGroup
+				b.ResetTimer()
+				for w := 0; w < g; w++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for i := 0; i < per; i++ {
+							a.Alloc(i)
+						}
+					}()
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
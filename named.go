@@ -0,0 +1,79 @@
+package atomicarena
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ArenaInfo describes a named arena, as listed by Arenas.
+type ArenaInfo struct {
+	Name  string  `json:"name"`
+	Type  string  `json:"type"` // element type, e.g. "main.Foo"
+	Len   uintptr `json:"len"`
+	Cap   uintptr `json:"cap"`
+	Bytes uintptr `json:"bytes"` // total memory footprint, as reported by SizeBytes
+}
+
+// namedArenas is the process-wide table behind Arenas. Each entry samples
+// its arena through a probe that does not keep the arena alive where the
+// toolchain supports weak pointers; see trackArena.
+var namedArenas struct {
+	mu      sync.RWMutex
+	entries map[uint64]func() (ArenaInfo, bool)
+	nextID  atomic.Uint64
+}
+
+// registerProbe adds probe to the table and returns its id.
+func registerProbe(probe func() (ArenaInfo, bool)) uint64 {
+	id := namedArenas.nextID.Add(1)
+	namedArenas.mu.Lock()
+	defer namedArenas.mu.Unlock()
+	if namedArenas.entries == nil {
+		namedArenas.entries = make(map[uint64]func() (ArenaInfo, bool))
+	}
+	namedArenas.entries[id] = probe
+	return id
+}
+
+// untrackArena removes a registry entry. Id 0, used by unnamed arenas, is
+// never registered.
+func untrackArena(id uint64) {
+	if id == 0 {
+		return
+	}
+	namedArenas.mu.Lock()
+	defer namedArenas.mu.Unlock()
+	delete(namedArenas.entries, id)
+}
+
+// info samples the arena for the registry.
+func (a *AtomicArena[T]) info() ArenaInfo {
+	return ArenaInfo{
+		Name:  a.opts.name,
+		Type:  reflect.TypeFor[T]().String(),
+		Len:   a.Len(),
+		Cap:   a.Cap(),
+		Bytes: a.SizeBytes(),
+	}
+}
+
+// Arenas lists every named arena that has not been closed or garbage
+// collected, sorted by name and then element type. Each entry is sampled
+// at the time of the call.
+func Arenas() []ArenaInfo {
+	namedArenas.mu.RLock()
+	out := make([]ArenaInfo, 0, len(namedArenas.entries))
+	for _, probe := range namedArenas.entries {
+		if info, ok := probe(); ok {
+			out = append(out, info)
+		}
+	}
+	namedArenas.mu.RUnlock()
+	slices.SortFunc(out, func(x, y ArenaInfo) int {
+		return cmp.Or(cmp.Compare(x.Name, y.Name), cmp.Compare(x.Type, y.Type))
+	})
+	return out
+}
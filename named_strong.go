@@ -0,0 +1,14 @@
+//go:build !go1.24
+
+package atomicarena
+
+// trackArena registers a named arena. Toolchains before Go 1.24 lack weak
+// pointers, so the registry holds the arena until Close unregisters it.
+func trackArena[T any](a *AtomicArena[T]) uint64 {
+	return registerProbe(func() (ArenaInfo, bool) {
+		return a.info(), true
+	})
+}
+
+// weakRegistry reports whether the registry lets unclosed arenas be collected.
+const weakRegistry = false
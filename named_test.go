@@ -0,0 +1,135 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// findArena returns the registry entry with the given name.
+func findArena(name string) (ArenaInfo, bool) {
+	for _, info := range Arenas() {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return ArenaInfo{}, false
+}
+
+// TestNamedErrors ensures allocation errors and Stats carry the name
+func TestNamedErrors(t *testing.T) {
+	a := NewAtomicArena[int](1, WithName("frames"))
+	defer a.Close()
+	a.Alloc(1)
+	_, err := a.Alloc(2)
+	if !errors.Is(err, ErrArenaFull) || !strings.Contains(err.Error(), `atomicarena "frames"`) {
+		t.Fatalf("expected a named ErrArenaFull, got %v", err)
+	}
+	if _, err := a.AppendSlice([]int{1}); !errors.Is(err, ErrArenaFull) || !strings.Contains(err.Error(), "frames") {
+		t.Fatalf("expected a named ErrArenaFull from AppendSlice, got %v", err)
+	}
+	if s := a.Stats(); s.Name != "frames" {
+		t.Fatalf("expected Stats to carry the name, got %q", s.Name)
+	}
+	// unnamed arenas carry no name
+	b := NewAtomicArena[int](0)
+	if _, err := b.Reserve(1); err == nil || strings.Contains(err.Error(), `"`) {
+		t.Fatalf("expected an unnamed capacity error, got %v", err)
+	}
+}
+
+// TestArenasRegistration covers registration and unregistration on Close
+func TestArenasRegistration(t *testing.T) {
+	a := NewAtomicArena[position](8, WithName("registry-close"))
+	a.Alloc(position{1, 2})
+	info, ok := findArena("registry-close")
+	if !ok {
+		t.Fatalf("expected the named arena to be registered")
+	}
+	want := ArenaInfo{Name: "registry-close", Type: "atomicarena.position", Len: 1, Cap: 8, Bytes: a.SizeBytes()}
+	if info != want {
+		t.Fatalf("expected %+v, got %+v", want, info)
+	}
+	if _, ok := findArena(""); ok {
+		t.Fatalf("unnamed arenas must not be registered")
+	}
+	a.Close()
+	if _, ok := findArena("registry-close"); ok {
+		t.Fatalf("expected Close to unregister the arena")
+	}
+	a.Close()
+
+	m, err := NewMmapArena[int64](4, WithName("registry-mmap"))
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	if _, ok := findArena("registry-mmap"); !ok {
+		t.Fatalf("expected the mapped arena to be registered")
+	}
+	m.Close()
+	if _, ok := findArena("registry-mmap"); ok {
+		t.Fatalf("expected MmapArena.Close to unregister the arena")
+	}
+}
+
+// TestArenasCollected ensures the registry does not keep arenas alive
+func TestArenasCollected(t *testing.T) {
+	if !weakRegistry {
+		t.Skip("weak registration needs Go 1.24")
+	}
+	func() {
+		a := NewAtomicArena[int](1024, WithName("registry-gc"))
+		a.Alloc(1)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		runtime.GC()
+		if _, ok := findArena("registry-gc"); !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the collected arena to leave the registry")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestArenasConcurrent reads the registry while arenas churn; run with -race
+func TestArenasConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				a := NewAtomicArena[int](16, WithName(fmt.Sprintf("churn-%d-%d", g, i)))
+				a.Alloc(i)
+				a.Close()
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		for _, info := range Arenas() {
+			if info.Name == "" || info.Cap == 0 {
+				t.Errorf("unexpected entry %+v", info)
+			}
+		}
+	}
+	close(stop)
+	wg.Wait()
+	for _, info := range Arenas() {
+		if strings.HasPrefix(info.Name, "churn-") {
+			t.Fatalf("closed arena %q still registered", info.Name)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build go1.24
+
+package atomicarena
+
+import (
+	"runtime"
+	"weak"
+)
+
+// trackArena registers a named arena through a weak pointer, so the registry
+// does not keep it alive; a cleanup drops the entry once it is collected.
+func trackArena[T any](a *AtomicArena[T]) uint64 {
+	wp := weak.Make(a)
+	id := registerProbe(func() (ArenaInfo, bool) {
+		if a := wp.Value(); a != nil {
+			return a.info(), true
+		}
+		return ArenaInfo{}, false
+	})
+	runtime.AddCleanup(a, untrackArena, id)
+	return id
+}
+
+// weakRegistry reports whether the registry lets unclosed arenas be collected.
+const weakRegistry = true
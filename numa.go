@@ -0,0 +1,173 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNUMAPolicy is returned when the kernel refuses the NUMA memory policy
+// requested by WithNUMABind or WithNUMAInterleave.
+var ErrNUMAPolicy = errors.New("atomicarena: cannot apply NUMA memory policy")
+
+// WithNUMABind places an MmapArena's pages on NUMA node node with mbind,
+// so threads on that node reach them without crossing the interconnect.
+// The policy is set before any page is touched, prefaulted pages included.
+// Construction fails with ErrNUMAPolicy if the node does not exist. It only
+// has an effect on Linux, and heap-backed constructors reject it with
+// ErrInvalidOptions, as they do a negative node.
+func WithNUMABind(node int) Option {
+	return func(o *options) { o.numaBind, o.numaNode = true, node }
+}
+
+// WithNUMAInterleave spreads an MmapArena's pages round-robin over every
+// online NUMA node, evening out the bandwidth of an arena shared by threads
+// on all of them. It cannot be combined with WithNUMABind. It only has an
+// effect on Linux, and heap-backed constructors reject it with
+// ErrInvalidOptions.
+func WithNUMAInterleave() Option {
+	return func(o *options) { o.numaInterleave = true }
+}
+
+// validateNUMA reports NUMA options that cannot be honoured.
+func validateNUMA(o *options, mmap bool) error {
+	switch {
+	case !o.numaBind && !o.numaInterleave:
+		return nil
+	case !mmap:
+		return fmt.Errorf("%w: NUMA placement requires mmap-backed storage", ErrInvalidOptions)
+	case o.numaBind && o.numaInterleave:
+		return fmt.Errorf("%w: WithNUMABind and WithNUMAInterleave are exclusive", ErrInvalidOptions)
+	case o.numaNode < 0:
+		return fmt.Errorf("%w: NUMA node %d", ErrInvalidOptions, o.numaNode)
+	}
+	return nil
+}
+
+// NUMAArena is a set of MmapArenas, one per NUMA node, each with its pages
+// bound to its node. Alloc and Reserve go to the shard of the node the
+// calling thread runs on, so data is written, and usually read back, from
+// local memory. On machines with one node, and on platforms other than
+// Linux, it holds a single unbound shard.
+type NUMAArena[T any] struct {
+	shards []*MmapArena[T]
+	nodes  []int // node of each shard
+	byNode []int // shard of each node, -1 for offline nodes
+}
+
+// NewNUMAArena creates one MmapArena of perNode slots for every online NUMA
+// node, configured by opts. Under WithName, the shard of node n is named
+// "name[n]". The shards are placed by the arena itself, so WithNUMABind and
+// WithNUMAInterleave are rejected with ErrInvalidOptions. It returns
+// ErrPointerType if T contains pointers, and ErrNUMAPolicy if a shard cannot
+// be bound to its node.
+func NewNUMAArena[T any](perNode uintptr, opts ...Option) (*NUMAArena[T], error) {
+	o := buildOptions(opts)
+	if o.numaBind || o.numaInterleave {
+		return nil, fmt.Errorf("%w: NUMAArena places its shards itself", ErrInvalidOptions)
+	}
+	nodes := numaNodes()
+	n := &NUMAArena[T]{nodes: nodes, byNode: make([]int, nodes[len(nodes)-1]+1)}
+	for i := range n.byNode {
+		n.byNode[i] = -1
+	}
+	for i, node := range nodes {
+		shardOpts := opts
+		if o.name != "" {
+			shardOpts = append(opts[:len(opts):len(opts)], WithName(fmt.Sprintf("%s[%d]", o.name, node)))
+		}
+		if len(nodes) > 1 {
+			shardOpts = append(shardOpts[:len(shardOpts):len(shardOpts)], WithNUMABind(node))
+		}
+		m, err := NewMmapArena[T](perNode, shardOpts...)
+		if err != nil {
+			_ = n.Close()
+			return nil, err
+		}
+		n.shards = append(n.shards, m)
+		n.byNode[node] = i
+	}
+	return n, nil
+}
+
+// Len returns the number of shards.
+func (n *NUMAArena[T]) Len() int {
+	return len(n.shards)
+}
+
+// Shard returns shard i. It panics if i is out of range, like a slice index.
+func (n *NUMAArena[T]) Shard(i int) *MmapArena[T] {
+	return n.shards[i]
+}
+
+// Node returns the NUMA node shard i is bound to.
+func (n *NUMAArena[T]) Node(i int) int {
+	return n.nodes[i]
+}
+
+// local returns the index of the calling thread's shard. The goroutine may
+// migrate to another node right after, which only costs locality.
+func (n *NUMAArena[T]) local() int {
+	if len(n.shards) == 1 {
+		return 0
+	}
+	if node := currentNode(); node >= 0 && node < len(n.byNode) && n.byNode[node] >= 0 {
+		return n.byNode[node]
+	}
+	return 0
+}
+
+// Local returns the shard of the node the calling thread runs on. Finding
+// it takes a getcpu system call, so callers making many allocations in a
+// row can look it up once and allocate from it directly.
+func (n *NUMAArena[T]) Local() *MmapArena[T] {
+	return n.shards[n.local()]
+}
+
+// Alloc stores obj in the local shard, or in the next shard with room if
+// the local one is full. It returns the local shard's ErrArenaFull if every
+// shard is full, and ErrClosed after Close.
+func (n *NUMAArena[T]) Alloc(obj T) (*T, error) {
+	i := n.local()
+	p, err := n.shards[i].Alloc(obj)
+	for j := 1; j < len(n.shards) && errors.Is(err, ErrArenaFull); j++ {
+		var next error
+		if p, next = n.shards[(i+j)%len(n.shards)].Alloc(obj); !errors.Is(next, ErrArenaFull) {
+			err = next
+		}
+	}
+	return p, err
+}
+
+// Reserve reserves count slots from the local shard, or from the next
+// shard with room, like Alloc.
+func (n *NUMAArena[T]) Reserve(count uintptr) ([]T, error) {
+	i := n.local()
+	seg, err := n.shards[i].Reserve(count)
+	for j := 1; j < len(n.shards) && errors.Is(err, ErrArenaFull); j++ {
+		var next error
+		if seg, next = n.shards[(i+j)%len(n.shards)].Reserve(count); !errors.Is(next, ErrArenaFull) {
+			err = next
+		}
+	}
+	return seg, err
+}
+
+// TotalLen returns the number of slots allocated across all shards.
+func (n *NUMAArena[T]) TotalLen() uintptr {
+	var total uintptr
+	for _, m := range n.shards {
+		total += m.Len()
+	}
+	return total
+}
+
+// Close closes every shard and returns their errors joined.
+func (n *NUMAArena[T]) Close() error {
+	var errs []error
+	for i, m := range n.shards {
+		if err := m.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("shard %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,7 @@
+//go:build linux && !amd64 && !atomicarena_heapmmap
+
+package atomicarena
+
+import "syscall"
+
+const sysGetcpu = syscall.SYS_GETCPU
@@ -0,0 +1,7 @@
+//go:build linux && !atomicarena_heapmmap
+
+package atomicarena
+
+// sysGetcpu is getcpu's system call number, which the syscall package only
+// defines for other architectures.
+const sysGetcpu = 309
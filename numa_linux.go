@@ -0,0 +1,111 @@
+//go:build linux && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// mbind modes, from linux/mempolicy.h.
+const (
+	mpolBind       = 2
+	mpolInterleave = 3
+)
+
+// numaSyscall issues the mbind and getcpu calls; tests replace it to check
+// their arguments. Both calls are brief and never block, so they are made
+// raw.
+var numaSyscall = syscall.RawSyscall6
+
+// numaSysfs is the sysfs directory describing the NUMA nodes; tests
+// replace it to fake a topology.
+var numaSysfs fs.FS = os.DirFS("/sys/devices/system/node")
+
+// numaNodes returns the online NUMA nodes in ascending order. Machines with
+// NUMA disabled, or without sysfs, have only node 0.
+func numaNodes() []int {
+	b, err := fs.ReadFile(numaSysfs, "online")
+	if err != nil {
+		return []int{0}
+	}
+	nodes, err := parseNodeList(strings.TrimSpace(string(b)))
+	if err != nil || len(nodes) == 0 {
+		return []int{0}
+	}
+	return nodes
+}
+
+// parseNodeList parses a kernel node list such as "0-3,8", in ascending
+// order.
+func parseNodeList(s string) ([]int, error) {
+	var nodes []int
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, err
+			}
+		}
+		if first < 0 || last < first || len(nodes) > 0 && first <= nodes[len(nodes)-1] {
+			return nil, fmt.Errorf("malformed node list %q", s)
+		}
+		for n := first; n <= last; n++ {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+// currentNode returns the NUMA node of the CPU the calling thread runs on,
+// or -1 if the kernel cannot tell.
+func currentNode() int {
+	var cpu, node uint32
+	if _, _, errno := numaSyscall(sysGetcpu, uintptr(unsafe.Pointer(&cpu)), uintptr(unsafe.Pointer(&node)), 0, 0, 0, 0); errno != 0 {
+		return -1
+	}
+	return int(node)
+}
+
+// bindMemory applies the memory policy of WithNUMABind or WithNUMAInterleave
+// to mem, which no page of has been touched yet. Interleaving over a single
+// node is skipped, so machines without NUMA support accept it.
+func bindMemory(mem []byte, o *options) error {
+	var mode uintptr
+	var nodes []int
+	switch {
+	case o.numaBind:
+		mode, nodes = mpolBind, []int{o.numaNode}
+	case o.numaInterleave:
+		if mode, nodes = mpolInterleave, numaNodes(); len(nodes) == 1 {
+			return nil
+		}
+	default:
+		return nil
+	}
+	const bits = int(unsafe.Sizeof(uintptr(0)) * 8)
+	mask := make([]uintptr, nodes[len(nodes)-1]/bits+1)
+	for _, n := range nodes {
+		mask[n/bits] |= 1 << (n % bits)
+	}
+	// the kernel reads one bit fewer than maxnode
+	maxNode := uintptr(len(mask)*bits + 1)
+	_, _, errno := numaSyscall(syscall.SYS_MBIND, uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)), mode,
+		uintptr(unsafe.Pointer(&mask[0])), maxNode, 0)
+	runtime.KeepAlive(mask)
+	if errno != 0 {
+		return fmt.Errorf("%w: nodes %v for %d bytes: %v", ErrNUMAPolicy, nodes, len(mem), errno)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+//go:build linux && !atomicarena_heapmmap
+
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"unsafe"
+)
+
+// mbindCall records the arguments of one mbind call made through fakeNUMA.
+type mbindCall struct {
+	addr, len, mode, maxNode uintptr
+	mask                     []uintptr
+}
+
+// fakeNUMA replaces the NUMA syscall layer with one reporting the nodes in
+// online, placing the caller on node and failing mbind with errno. It
+// returns the mbind calls made until the test ends.
+func fakeNUMA(t *testing.T, online string, node uint32, errno syscall.Errno) *[]mbindCall {
+	t.Helper()
+	calls := new([]mbindCall)
+	oldSys, oldFS := numaSyscall, numaSysfs
+	t.Cleanup(func() { numaSyscall, numaSysfs = oldSys, oldFS })
+	numaSysfs = fstest.MapFS{"online": {Data: []byte(online + "\n")}}
+	numaSyscall = func(trap, a1, a2, a3, a4, a5, a6 uintptr) (uintptr, uintptr, syscall.Errno) {
+		const bits = unsafe.Sizeof(uintptr(0)) * 8
+		switch trap {
+		case sysGetcpu:
+			**(**uint32)(unsafe.Pointer(&a2)) = node
+			return 0, 0, 0
+		case syscall.SYS_MBIND:
+			mask := unsafe.Slice(*(**uintptr)(unsafe.Pointer(&a4)), (a5-1)/bits)
+			*calls = append(*calls, mbindCall{addr: a1, len: a2, mode: a3, maxNode: a5, mask: slices.Clone(mask)})
+			if a6 != 0 {
+				t.Errorf("expected no mbind flags, got %#x", a6)
+			}
+			return 0, 0, errno
+		}
+		t.Errorf("unexpected system call %d", trap)
+		return 0, 0, syscall.ENOSYS
+	}
+	return calls
+}
+
+// TestNUMABindSyscall binds the whole mapping to the requested node
+func TestNUMABindSyscall(t *testing.T) {
+	calls := fakeNUMA(t, "0-1", 0, 0)
+	m, err := NewMmapArena[int64](1024, WithNUMABind(1), WithPrefault())
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	want := mbindCall{addr: uintptr(unsafe.Pointer(&m.mem[0])), len: uintptr(len(m.mem)), mode: mpolBind,
+		maxNode: unsafe.Sizeof(uintptr(0))*8 + 1, mask: []uintptr{1 << 1}}
+	if len(*calls) != 1 || !equalCall((*calls)[0], want) {
+		t.Fatalf("expected %+v, got %+v", want, *calls)
+	}
+}
+
+// TestNUMAInterleaveSyscall interleaves over every online node and skips single-node machines
+func TestNUMAInterleaveSyscall(t *testing.T) {
+	calls := fakeNUMA(t, "0-1,4", 0, 0)
+	m, err := NewMmapArena[int64](1024, WithNUMAInterleave())
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	m.Close()
+	if len(*calls) != 1 || (*calls)[0].mode != mpolInterleave || !slices.Equal((*calls)[0].mask, []uintptr{0b10011}) {
+		t.Fatalf("expected one interleave call over nodes 0, 1 and 4, got %+v", *calls)
+	}
+	calls = fakeNUMA(t, "0", 0, 0)
+	m, err = NewMmapArena[int64](1024, WithNUMAInterleave())
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	m.Close()
+	if len(*calls) != 0 {
+		t.Fatalf("expected no mbind call on one node, got %+v", *calls)
+	}
+}
+
+// TestNUMABindFailure reports ErrNUMAPolicy when the kernel refuses the node
+func TestNUMABindFailure(t *testing.T) {
+	fakeNUMA(t, "0", 0, syscall.EINVAL)
+	if _, err := NewMmapArena[int64](1024, WithNUMABind(3)); !errors.Is(err, ErrNUMAPolicy) {
+		t.Fatalf("expected ErrNUMAPolicy, got %v", err)
+	}
+}
+
+// TestNUMAArenaRouting binds one shard per node and allocates from the caller's node
+func TestNUMAArenaRouting(t *testing.T) {
+	calls := fakeNUMA(t, "0,2", 2, 0)
+	n, err := NewNUMAArena[int64](16)
+	if err != nil {
+		t.Fatalf("NewNUMAArena failed: %v", err)
+	}
+	defer n.Close()
+	if n.Len() != 2 || n.Node(0) != 0 || n.Node(1) != 2 {
+		t.Fatalf("expected shards for nodes 0 and 2, got %d shards", n.Len())
+	}
+	if len(*calls) != 2 || (*calls)[0].mask[0] != 1<<0 || (*calls)[1].mask[0] != 1<<2 {
+		t.Fatalf("expected each shard bound to its node, got %+v", *calls)
+	}
+	if _, err := n.Alloc(7); err != nil {
+		t.Fatalf("Alloc failed: %v", err)
+	}
+	if n.Local() != n.Shard(1) || n.Shard(1).Len() != 1 || n.Shard(0).Len() != 0 {
+		t.Fatal("expected the allocation to land in the shard of node 2")
+	}
+}
+
+// TestNUMABindKernel binds a prefaulted arena to the first online node for real
+func TestNUMABindKernel(t *testing.T) {
+	m, err := NewMmapArena[int64](1<<16, WithNUMABind(numaNodes()[0]), WithPrefault())
+	if errors.Is(err, ErrNUMAPolicy) {
+		t.Skipf("kernel without NUMA support: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	m.Close()
+}
+
+// TestParseNodeList parses kernel node lists and rejects malformed ones
+func TestParseNodeList(t *testing.T) {
+	if nodes, err := parseNodeList("0-2,5,7-8"); err != nil || !slices.Equal(nodes, []int{0, 1, 2, 5, 7, 8}) {
+		t.Fatalf("expected nodes 0-2, 5 and 7-8, got %v (%v)", nodes, err)
+	}
+	for _, s := range []string{"", "1-0", "2,1", "a"} {
+		if _, err := parseNodeList(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
+
+// TestCurrentNode reports an online node for the calling thread
+func TestCurrentNode(t *testing.T) {
+	if node := currentNode(); !slices.Contains(numaNodes(), node) {
+		t.Fatalf("expected an online node, got %d of %v", node, numaNodes())
+	}
+}
+
+// equalCall reports whether two recorded mbind calls match.
+func equalCall(a, b mbindCall) bool {
+	return a.addr == b.addr && a.len == b.len && a.mode == b.mode && a.maxNode == b.maxNode && slices.Equal(a.mask, b.mask)
+}
@@ -0,0 +1,12 @@
+//go:build !linux || atomicarena_heapmmap
+
+package atomicarena
+
+// NUMA placement is only implemented on Linux: elsewhere there is a single
+// node, and memory policies are ignored.
+
+func numaNodes() []int { return []int{0} }
+
+func currentNode() int { return 0 }
+
+func bindMemory([]byte, *options) error { return nil }
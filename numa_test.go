@@ -0,0 +1,102 @@
+package atomicarena
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// TestNUMAOptions rejects NUMA placement without mmap storage and conflicting placements
+func TestNUMAOptions(t *testing.T) {
+	if _, err := New[int](4, WithNUMABind(0)); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected WithNUMABind to be rejected for heap storage, got %v", err)
+	}
+	if _, err := NewMmapArena[int](4, WithNUMABind(0), WithNUMAInterleave()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected bind and interleave to be exclusive, got %v", err)
+	}
+	if _, err := NewMmapArena[int](4, WithNUMABind(-1)); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected a negative node to be rejected, got %v", err)
+	}
+	if _, err := NewNUMAArena[int](4, WithNUMAInterleave()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected NUMAArena to reject placement options, got %v", err)
+	}
+	m, err := NewMmapArena[int](4, WithNUMAInterleave(), WithPrefault())
+	if err != nil {
+		t.Fatalf("expected interleaving to succeed, got %v", err)
+	}
+	m.Close()
+}
+
+// TestNUMAArenaSpills moves on to the other shards once the local one is full
+func TestNUMAArenaSpills(t *testing.T) {
+	n, err := NewNUMAArena[int64](4, WithName("numa"))
+	if err != nil {
+		t.Fatalf("NewNUMAArena failed: %v", err)
+	}
+	total := uintptr(n.Len()) * 4
+	for i := uintptr(0); i < total; i++ {
+		if _, err := n.Alloc(int64(i)); err != nil {
+			t.Fatalf("Alloc %d failed: %v", i, err)
+		}
+	}
+	if _, err := n.Alloc(0); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull once every shard is full, got %v", err)
+	}
+	if _, err := n.Reserve(1); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull from Reserve, got %v", err)
+	}
+	if n.TotalLen() != total {
+		t.Fatalf("expected %d slots in use, got %d", total, n.TotalLen())
+	}
+	if err := n.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := n.Alloc(0); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// BenchmarkNUMALocality fills and rereads blocks from the local shard or from one interleaved arena; the gap shows on multi-node machines
+func BenchmarkNUMALocality(b *testing.B) {
+	const block = 1 << 16
+	b.Run("local", func(b *testing.B) {
+		n, err := NewNUMAArena[int64](1 << 24)
+		if err != nil {
+			b.Fatalf("NewNUMAArena failed: %v", err)
+		}
+		defer n.Close()
+		benchmarkBlocks(b, func() ([]int64, error) { return n.Reserve(block) })
+	})
+	b.Run("interleaved", func(b *testing.B) {
+		m, err := NewMmapArena[int64](1<<24, WithNUMAInterleave())
+		if err != nil {
+			b.Fatalf("NewMmapArena failed: %v", err)
+		}
+		defer m.Close()
+		benchmarkBlocks(b, func() ([]int64, error) { return m.Reserve(block) })
+	})
+}
+
+// benchmarkBlocks has every goroutine, locked to its thread, fill a block
+// and sum it repeatedly.
+func benchmarkBlocks(b *testing.B, reserve func() ([]int64, error)) {
+	b.RunParallel(func(pb *testing.PB) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		seg, err := reserve()
+		if err != nil {
+			b.Errorf("Reserve failed: %v", err)
+			return
+		}
+		for i := range seg {
+			seg[i] = int64(i)
+		}
+		var sum int64
+		for pb.Next() {
+			for _, v := range seg {
+				sum += v
+			}
+		}
+		runtime.KeepAlive(sum)
+	})
+}
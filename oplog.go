@@ -0,0 +1,220 @@
+package atomicarena
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoOpLog is returned by WriteOpLog on an arena built without WithOpLog.
+var ErrNoOpLog = errors.New("atomicarena: operation log not enabled")
+
+// WithOpLog records the arena's operations in a ring buffer of the last size
+// records, for reconstructing the interleaving behind a bug that only shows
+// up under concurrency. Successful Alloc, Reserve, Reset, Free and
+// BatchedArena.Drain calls are logged with the slots they touched, the
+// goroutine that made them and the time on the arena's Clock; OpLog reads
+// the records and WriteOpLog prints them. Concurrent allocations may be
+// logged in a different order from the one they claimed slots in, which
+// their indices give, but never after the Reset that ends them. Other
+// operations, such as AllocMany or handing slots back with Unreserve, are
+// not logged. Writers only wait for each other when one laps the ring onto
+// a slot another is still filling, but each record costs a clock read and a
+// runtime.Stack call to identify the goroutine, so the log is a
+// debugging aid rather than something to leave on. Zero, the default,
+// disables it, leaving a nil check on each operation.
+func WithOpLog(size int) Option {
+	return func(o *options) { o.opLogSize = max(size, 0) }
+}
+
+// OpKind identifies the operation an OpRecord describes.
+type OpKind uint8
+
+const (
+	// OpAlloc is a single-slot allocation: Alloc, AllocIndexed,
+	// AllocPriority, AllocWithRetry or TryAlloc.
+	OpAlloc OpKind = iota + 1
+	// OpReserve claims a run of slots: Reserve, ReserveIndexed,
+	// ReserveZeroed or AppendSlice.
+	OpReserve
+	// OpReset rewinds the arena, from Reset, ResetWhenIdle, a janitor or
+	// BatchedArena.Drain.
+	OpReset
+	// OpFree zeroes the storage with Free.
+	OpFree
+	// OpDrain copies the values out of a BatchedArena. The reset that
+	// follows is logged as its own OpReset.
+	OpDrain
+)
+
+var opKindNames = [...]string{OpAlloc: "alloc", OpReserve: "reserve", OpReset: "reset", OpFree: "free", OpDrain: "drain"}
+
+// String returns the operation's name as WriteOpLog prints it.
+func (k OpKind) String() string {
+	if int(k) < len(opKindNames) && opKindNames[k] != "" {
+		return opKindNames[k]
+	}
+	return fmt.Sprintf("OpKind(%d)", uint8(k))
+}
+
+// OpRecord is one entry of the operation log.
+type OpRecord struct {
+	Seq       uint64    // position in the log, from 1; gaps mark overwritten records
+	Op        OpKind    // the operation
+	Index     uintptr   // first slot claimed by Alloc or Reserve; 0 otherwise
+	Len       uintptr   // slots claimed, or released by Reset, Free and Drain
+	Release   bool      // a Reset that zeroed the storage
+	Goroutine uint64    // hash of the calling goroutine's id
+	Time      time.Time // when the operation was logged, on the arena's Clock
+}
+
+// opLog is the ring of the most recent records. Each slot is guarded by its
+// seq, a seqlock word that holds opLocked while a writer fills the slot and
+// the record's Seq after, so a writer that laps the ring cannot mix its
+// fields with another's, and readers can tell a complete record from one
+// being overwritten.
+type opLog struct {
+	clock Clock
+	next  atomic.Uint64 // Seq of the last record started
+	slots []opSlot
+}
+
+type opSlot struct {
+	seq       atomic.Uint64
+	op        atomic.Uint32 // OpKind, plus opReleased
+	index     atomic.Uintptr
+	n         atomic.Uintptr
+	goroutine atomic.Uint64
+	at        atomic.Int64 // Unix nanoseconds
+}
+
+// opReleased marks a Reset that zeroed the storage in opSlot.op.
+const opReleased = 1 << 8
+
+// opLocked is the seq of a slot a writer is filling.
+const opLocked = ^uint64(0)
+
+func newOpLog(o options) *opLog {
+	if o.opLogSize == 0 {
+		return nil
+	}
+	return &opLog{clock: o.timeSource(), slots: make([]opSlot, o.opLogSize)}
+}
+
+// record appends an operation, overwriting the oldest record once the ring
+// is full. Allocations record themselves before they commit, so a Reset,
+// which waits for every commit, is always logged after them. A writer whose
+// slot already holds a later record drops its own, which that record has
+// overwritten.
+func (l *opLog) record(op OpKind, index, n uintptr, release bool) {
+	seq := l.next.Add(1)
+	s := &l.slots[(seq-1)%uint64(len(l.slots))]
+	for {
+		cur := s.seq.Load()
+		if cur != opLocked && cur > seq {
+			return
+		}
+		if cur != opLocked && s.seq.CompareAndSwap(cur, opLocked) {
+			break
+		}
+		runtime.Gosched()
+	}
+	kind := uint32(op)
+	if release {
+		kind |= opReleased
+	}
+	s.op.Store(kind)
+	s.index.Store(index)
+	s.n.Store(n)
+	s.goroutine.Store(goroutineHash())
+	s.at.Store(l.clock.Now().UnixNano())
+	s.seq.Store(seq)
+}
+
+// records returns the complete records still in the ring, oldest first.
+// Records being written, or overwritten while they are read, are left out.
+func (l *opLog) records() []OpRecord {
+	last := l.next.Load()
+	first := last - min(last, uint64(len(l.slots))) + 1
+	out := make([]OpRecord, 0, last-first+1)
+	for seq := first; seq <= last; seq++ {
+		s := &l.slots[(seq-1)%uint64(len(l.slots))]
+		if s.seq.Load() != seq {
+			continue
+		}
+		kind := s.op.Load()
+		r := OpRecord{
+			Seq:       seq,
+			Op:        OpKind(kind),
+			Index:     s.index.Load(),
+			Len:       s.n.Load(),
+			Release:   kind&opReleased != 0,
+			Goroutine: s.goroutine.Load(),
+			Time:      time.Unix(0, s.at.Load()),
+		}
+		if s.seq.Load() != seq {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// goroutineHash identifies the calling goroutine. Go keeps goroutine ids to
+// itself, so the id is read from the header of the goroutine's stack trace,
+// "goroutine 42 [running]:", and mixed so that equal hashes, rather than the
+// values themselves, are what a reader compares.
+func goroutineHash() uint64 {
+	var buf [32]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	var id uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	// the splitmix64 finalizer
+	id = (id ^ id>>30) * 0xbf58476d1ce4e5b9
+	id = (id ^ id>>27) * 0x94d049bb133111eb
+	return id ^ id>>31
+}
+
+// OpLog returns the records in the arena's operation log, oldest first, or
+// nil unless the arena was built WithOpLog. Operations still in progress
+// when it runs are not included.
+func (a *AtomicArena[T]) OpLog() []OpRecord {
+	if a.ops == nil {
+		return nil
+	}
+	return a.ops.records()
+}
+
+// WriteOpLog writes the arena's operation log to w as text, one record per
+// line, oldest first:
+//
+//	7 2026-10-14T09:30:00.000000125Z g=5f8e2c1ad7b40c93 reserve index=3 len=16
+//	8 2026-10-14T09:30:00.000000131Z g=1d04e7a9bc3f2865 reset index=0 len=19 release
+//
+// giving the sequence number, time, goroutine hash, operation, index and
+// length, and "release" for a Reset that zeroed the storage. It returns
+// ErrNoOpLog unless the arena was built WithOpLog.
+func (a *AtomicArena[T]) WriteOpLog(w io.Writer) error {
+	if a.ops == nil {
+		return ErrNoOpLog
+	}
+	bw := bufio.NewWriter(w)
+	for _, r := range a.ops.records() {
+		fmt.Fprintf(bw, "%d %s g=%016x %s index=%d len=%d", r.Seq, r.Time.UTC().Format(time.RFC3339Nano), r.Goroutine, r.Op, r.Index, r.Len)
+		if r.Release {
+			fmt.Fprint(bw, " release")
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,209 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestOpLog records a known sequence of operations in order
+func TestOpLog(t *testing.T) {
+	c := newStepClock()
+	a := NewAtomicArena[int](16, WithOpLog(8), WithClock(c))
+	a.Alloc(1)
+	c.now = c.now.Add(time.Second)
+	a.Reserve(3)
+	a.AppendSlice([]int{4, 5})
+	a.Reset(true)
+	a.Alloc(6)
+	a.Free()
+	a.Reset(false)
+
+	want := []OpRecord{
+		{Seq: 1, Op: OpAlloc, Index: 0, Len: 1},
+		{Seq: 2, Op: OpReserve, Index: 1, Len: 3},
+		{Seq: 3, Op: OpReserve, Index: 4, Len: 2},
+		{Seq: 4, Op: OpReset, Len: 6, Release: true},
+		{Seq: 5, Op: OpAlloc, Index: 0, Len: 1},
+		{Seq: 6, Op: OpFree, Len: 1},
+		{Seq: 7, Op: OpReset, Len: 1},
+	}
+	got := a.OpLog()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d records, got %+v", len(want), got)
+	}
+	self := goroutineHash()
+	for i, r := range got {
+		if r.Goroutine != self {
+			t.Fatalf("expected record %d from this goroutine, got %x", r.Seq, r.Goroutine)
+		}
+		if at := time.Unix(int64(min(i, 1)), 0); !r.Time.Equal(at) {
+			t.Fatalf("expected record %d at %v, got %v", r.Seq, at, r.Time)
+		}
+		r.Goroutine, r.Time = 0, time.Time{}
+		if r != want[i] {
+			t.Fatalf("expected %+v, got %+v", want[i], r)
+		}
+	}
+}
+
+// TestOpLogWraps keeps only the most recent records once the ring is full
+func TestOpLogWraps(t *testing.T) {
+	a := NewAtomicArena[int](16, WithOpLog(4))
+	for i := range 10 {
+		a.Alloc(i)
+	}
+	got := a.OpLog()
+	if len(got) != 4 || got[0].Seq != 7 || got[0].Index != 6 || got[3].Seq != 10 || got[3].Index != 9 {
+		t.Fatalf("expected records 7 to 10, got %+v", got)
+	}
+}
+
+// TestOpLogDrain logs a Drain followed by the reset it performs
+func TestOpLogDrain(t *testing.T) {
+	b := NewBatchedArena[int](64, 8, WithOpLog(16))
+	batch := b.NewBatch()
+	for i := range 3 {
+		batch.Alloc(i)
+	}
+	if _, err := b.Drain(); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	var ops []OpKind
+	for _, r := range b.arena.OpLog() {
+		ops = append(ops, r.Op)
+	}
+	if len(ops) != 3 || ops[0] != OpReserve || ops[1] != OpDrain || ops[2] != OpReset {
+		t.Fatalf("expected reserve, drain and reset, got %v", ops)
+	}
+}
+
+// TestWriteOpLog prints one line per record
+func TestWriteOpLog(t *testing.T) {
+	var sb strings.Builder
+	if err := NewAtomicArena[int](4).WriteOpLog(&sb); !errors.Is(err, ErrNoOpLog) {
+		t.Fatalf("expected ErrNoOpLog, got %v", err)
+	}
+	if NewAtomicArena[int](4).OpLog() != nil {
+		t.Fatalf("expected no records without WithOpLog")
+	}
+	c := newStepClock()
+	a := NewAtomicArena[int](4, WithOpLog(4), WithClock(c))
+	a.Reserve(2)
+	c.now = c.now.Add(1500 * time.Millisecond)
+	a.Reset(true)
+	if err := a.WriteOpLog(&sb); err != nil {
+		t.Fatalf("WriteOpLog failed: %v", err)
+	}
+	g := strings.TrimLeft(strings.Fields(sb.String())[2], "g=")
+	want := "1 1970-01-01T00:00:00Z g=" + g + " reserve index=0 len=2\n" +
+		"2 1970-01-01T00:00:01.5Z g=" + g + " reset index=0 len=2 release\n"
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+}
+
+// TestOpLogConcurrent keeps every record intact under concurrent writers
+func TestOpLogConcurrent(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	const workers, per = 4, 200
+	a := NewAtomicArena[int](workers*per, WithOpLog(workers*per))
+	var wg sync.WaitGroup
+	hashes := make([]uint64, workers)
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hashes[w] = goroutineHash()
+			for i := range per {
+				a.Alloc(i)
+			}
+		}()
+	}
+	wg.Wait()
+	got := a.OpLog()
+	if len(got) != workers*per {
+		t.Fatalf("expected %d records, got %d", workers*per, len(got))
+	}
+	seen := make([]bool, workers*per)
+	counts := make(map[uint64]int)
+	for i, r := range got {
+		if r.Seq != uint64(i+1) || r.Op != OpAlloc || r.Len != 1 || seen[r.Index] {
+			t.Fatalf("expected distinct single-slot allocations in order, got %+v", r)
+		}
+		seen[r.Index] = true
+		counts[r.Goroutine]++
+	}
+	for _, h := range hashes {
+		if counts[h] != per {
+			t.Fatalf("expected %d records from goroutine %x, got %d", per, h, counts[h])
+		}
+	}
+}
+
+// TestOpLogLapping keeps records whole when writers lap each other on a slot
+func TestOpLogLapping(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	const workers, per = 4, 2000
+	l := newOpLog(options{opLogSize: 1})
+	// a writer that laps onto a slot still being filled waits its turn
+	l.next.Store(1)
+	l.slots[0].seq.Store(opLocked)
+	lapped := make(chan struct{})
+	go func() {
+		l.record(OpAlloc, 2, 2, false)
+		close(lapped)
+	}()
+	select {
+	case <-lapped:
+		t.Fatal("expected the lapping writer to wait for the slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+	l.slots[0].seq.Store(1)
+	<-lapped
+	if got := l.records(); len(got) != 1 || got[0].Seq != 2 || got[0].Index != 2 {
+		t.Fatalf("expected record 2 in the slot, got %+v", got)
+	}
+	l = newOpLog(options{opLogSize: 1})
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range per {
+				v := uintptr(w*per + i)
+				l.record(OpAlloc, v, v, false)
+			}
+		}()
+	}
+	stop := make(chan struct{})
+	read := make(chan error)
+	go func() {
+		for {
+			for _, r := range l.records() {
+				if r.Index != r.Len {
+					read <- fmt.Errorf("expected a record with equal index and len, got %+v", r)
+					return
+				}
+			}
+			select {
+			case <-stop:
+				read <- nil
+				return
+			default:
+			}
+		}
+	}()
+	wg.Wait()
+	close(stop)
+	if err := <-read; err != nil {
+		t.Fatal(err)
+	}
+	if got := l.records(); len(got) != 1 || got[0].Seq != workers*per {
+		t.Fatalf("expected only record %d to remain, got %+v", workers*per, got)
+	}
+}
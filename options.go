@@ -0,0 +1,165 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidOptions is returned when a constructor is given options that
+// cannot be combined or do not apply to the kind of arena being built.
+var ErrInvalidOptions = errors.New("atomicarena: invalid options")
+
+// Option configures an arena at construction time.
+type Option func(*options)
+
+// options holds the construction-time configuration of an arena.
+type options struct {
+	unfreeze bool // Unfreeze is permitted
+	prefault bool // touch every page of storage at construction
+	locked   bool // lock mmap-backed storage in physical memory
+	noMirror bool // skip allocating and maintaining the pointer mirror
+
+	parallelFree uintptr // bytes above which Free zeroes in parallel; 0 means default
+	baseAlign    uintptr // required alignment of the first element; 0 means none
+
+	name        string // diagnostic name; non-empty names are listed by Arenas
+	profileRate int    // sample one in this many allocations; 0 disables profiling
+	opLogSize   int    // records kept by the operation log; 0 disables it
+	tracing     bool   // annotate the execution trace with lifecycle events
+
+	zeroOnReserve bool // Reserve behaves like ReserveZeroed
+	zeroOnAlloc   bool // every allocation that leaves slots to the caller clears them
+
+	destructor     any  // func(*T) run on released elements, for the arena's T
+	closeOnRelease bool // Close released elements, which implement io.Closer
+	refCounting    bool // Reset waits for references taken with Acquire
+
+	leakLogf func(string, ...any) // reports arenas collected without Close
+
+	sizeBuckets []int       // ByteArena size histogram bounds; nil disables it
+	watermarks  []Watermark // utilization thresholds reported as the count rises
+
+	softCap    uintptr // slots ordinary allocations may use, if softCapped
+	softCapped bool    // WithSoftCap was given
+	growLimit  uintptr // slots an MmapArena maps room for, set by WithGrowLimit
+	hugePages  bool    // advise transparent huge pages for mapped storage
+	hugeTLB    bool    // map storage from the huge page pool, falling back to hugePages
+
+	numaBind       bool // bind mapped storage to numaNode
+	numaNode       int  // NUMA node set by WithNUMABind
+	numaInterleave bool // interleave mapped storage over the online nodes
+
+	clock Clock         // time source of the time-based features; nil means the time package
+	ttl   time.Duration // element lifetime set by WithTTL; 0 means none
+}
+
+// defaultParallelFree is the size above which Free splits zeroing across goroutines.
+const defaultParallelFree = 8 << 20
+
+func (o *options) freeThreshold() uintptr {
+	if o.parallelFree == 0 {
+		return defaultParallelFree
+	}
+	return o.parallelFree
+}
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// validate reports option combinations that cannot be honoured. mmap is set
+// for arenas whose storage is mapped outside the Go heap.
+func (o *options) validate(mmap bool) error {
+	if o.locked && !mmap {
+		return fmt.Errorf("%w: WithLocked requires mmap-backed storage", ErrInvalidOptions)
+	}
+	if o.growLimit != 0 && !mmap {
+		return fmt.Errorf("%w: WithGrowLimit requires mmap-backed storage", ErrInvalidOptions)
+	}
+	if (o.hugePages || o.hugeTLB) && !mmap {
+		return fmt.Errorf("%w: huge pages require mmap-backed storage", ErrInvalidOptions)
+	}
+	if o.baseAlign&(o.baseAlign-1) != 0 {
+		return fmt.Errorf("%w: base alignment %d is not a power of two", ErrInvalidOptions, o.baseAlign)
+	}
+	if mmap && o.baseAlign > uintptr(pageSize) {
+		// mappings are only page-aligned
+		return fmt.Errorf("%w: base alignment %d exceeds the page size", ErrInvalidOptions, o.baseAlign)
+	}
+	if err := validateBuckets(o.sizeBuckets); err != nil {
+		return err
+	}
+	if err := validateTTL(o.ttl); err != nil {
+		return err
+	}
+	if err := validateNUMA(o, mmap); err != nil {
+		return err
+	}
+	return validateWatermarks(o.watermarks)
+}
+
+// WithUnfreeze allows a frozen arena to be made writable again via Unfreeze.
+func WithUnfreeze() Option {
+	return func(o *options) { o.unfreeze = true }
+}
+
+// WithPrefault touches every page of the arena's storage at construction so
+// the first allocations don't pay page-fault latency. Mmap-backed arenas on
+// Linux request pre-populated pages from the kernel instead.
+func WithPrefault() Option {
+	return func(o *options) { o.prefault = true }
+}
+
+// WithLocked locks an mmap-backed arena's storage in physical memory so it is
+// never swapped out, and makes Free and Reset wipe released slots. It fails
+// with ErrMemLock if the lock limit is too low, and is ignored on platforms
+// without memory locking. Heap-backed constructors reject it with
+// ErrInvalidOptions.
+func WithLocked() Option {
+	return func(o *options) { o.locked = true }
+}
+
+// WithoutPointerMirror builds the arena without its ptrs mirror, saving one
+// pointer per slot and an atomic store per Alloc. Get, Range and Snapshot read
+// the storage directly and are unaffected. For pointer-free element types of
+// at most 8 bytes, such as byte, int32 or int64, Alloc then comes down to the
+// reservation, one plain store and the commit, and AppendSlice to the
+// reservation and a memmove.
+func WithoutPointerMirror() Option {
+	return func(o *options) { o.noMirror = true }
+}
+
+// WithBaseAlignment places the first element of the arena's storage on an
+// n-byte boundary, so blocks of elements can be used with aligned vector
+// loads. n must be a power of two; zero selects the default of 64, a cache
+// line. The heap buffer is over-allocated by up to n bytes' worth of
+// elements to make room for the shift; small arenas of pointer-containing
+// types may instead be rounded up to 32KB.
+func WithBaseAlignment(n uintptr) Option {
+	if n == 0 {
+		n = defaultBaseAlign
+	}
+	return func(o *options) { o.baseAlign = n }
+}
+
+// defaultBaseAlign is the alignment WithBaseAlignment(0) selects.
+const defaultBaseAlign = 64
+
+// WithParallelFreeThreshold sets the number of bytes above which Free zeroes
+// storage using multiple goroutines. The default is 8MB; pass ^uintptr(0) to
+// always zero on the calling goroutine.
+func WithParallelFreeThreshold(bytes uintptr) Option {
+	return func(o *options) { o.parallelFree = bytes }
+}
+
+// WithName labels the arena for diagnostics. The name is included in the
+// arena's allocation errors and Stats, and named arenas are listed by Arenas
+// until they are closed or garbage collected.
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
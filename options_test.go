@@ -0,0 +1,59 @@
+package atomicarena
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestNewValidatesOptions ensures conflicting options are rejected together
+func TestNewValidatesOptions(t *testing.T) {
+	if _, err := New[int](8, WithLocked()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions for WithLocked on the heap, got %v", err)
+	}
+	if _, err := NewAtomicArenaChecked[int](8, WithPrefault(), WithLocked()); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions from NewAtomicArenaChecked, got %v", err)
+	}
+	if _, err := New[int](8, WithUnfreeze(), WithPrefault(), WithParallelFreeThreshold(1)); err != nil {
+		t.Fatalf("compatible options rejected: %v", err)
+	}
+	m, err := NewMmapArena[int](8, WithLocked())
+	if err != nil {
+		t.Fatalf("WithLocked rejected for an mmap arena: %v", err)
+	}
+	m.Close()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected NewAtomicArena to panic on invalid options")
+		}
+	}()
+	NewAtomicArena[int](8, WithLocked())
+}
+
+// TestNewDefaultsMatchNewAtomicArena ensures New without options builds the
+// same arena as the plain constructor
+func TestNewDefaultsMatchNewAtomicArena(t *testing.T) {
+	a, err := New[float64](100)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	b := NewAtomicArena[float64](100)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("New and NewAtomicArena built different arenas")
+	}
+	for i := 0; i < 100; i++ {
+		pa, errA := a.Alloc(float64(i))
+		pb, errB := b.Alloc(float64(i))
+		if errA != nil || errB != nil || math.Float64bits(*pa) != math.Float64bits(*pb) {
+			t.Fatalf("alloc %d diverged: %v %v", i, errA, errB)
+		}
+	}
+	if !reflect.DeepEqual(a.Snapshot(), b.Snapshot()) || a.Len() != b.Len() {
+		t.Fatal("arena contents diverged")
+	}
+	if _, err := New[int](0); err != nil {
+		t.Fatalf("New should accept zero capacity: %v", err)
+	}
+}
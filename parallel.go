@@ -0,0 +1,99 @@
+package atomicarena
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelMinElems is the least number of elements worth handing to a
+// worker goroutine; Transform and Reduce run inline below twice this.
+const parallelMinElems = 1 << 13
+
+// parallelChunks splits [0, n) into at most GOMAXPROCS chunks of at least
+// parallelMinElems elements and returns the chunk size; n itself means a
+// single chunk.
+func parallelChunks(n uintptr) uintptr {
+	workers := min(uintptr(runtime.GOMAXPROCS(0)), n/parallelMinElems)
+	if workers <= 1 {
+		return max(n, 1)
+	}
+	return (n + workers - 1) / workers
+}
+
+// Transform calls f on every committed element, skipping tombstoned slots.
+// The committed count is taken once, so slots allocated while Transform
+// runs are not visited. Above a size threshold the index range is split
+// across GOMAXPROCS goroutines, so f must be safe to call concurrently on
+// different elements. Like Dump it returns ErrNotQuiescent, without calling
+// f, if writes stay in flight on an arena built WithoutPointerMirror.
+func (a *AtomicArena[T]) Transform(f func(*T)) error {
+	n, ok := a.committedPrefix(^uintptr(0))
+	if !ok {
+		return ErrNotQuiescent
+	}
+	chunk := parallelChunks(n)
+	if chunk >= n {
+		a.transformRange(0, n, f)
+		return nil
+	}
+	var wg sync.WaitGroup
+	for lo := uintptr(0); lo < n; lo += chunk {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.transformRange(lo, min(lo+chunk, n), f)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (a *AtomicArena[T]) transformRange(lo, hi uintptr, f func(*T)) {
+	for i := lo; i < hi; i++ {
+		if !a.tombstoned(i) {
+			f(&a.raw[i])
+		}
+	}
+}
+
+// Reduce folds f over the committed elements of a in index order, skipping
+// tombstoned slots, with the same snapshot and parallel strategy as
+// Transform. Each worker folds its own chunk starting from init, and the
+// partial results are combined in index order with merge, so init must be
+// an identity for merge and merge must be associative. Small arenas are
+// folded inline from init without calling merge.
+func Reduce[T, R any](a *AtomicArena[T], init R, f func(R, *T) R, merge func(R, R) R) (R, error) {
+	n, ok := a.committedPrefix(^uintptr(0))
+	if !ok {
+		return init, ErrNotQuiescent
+	}
+	chunk := parallelChunks(n)
+	if chunk >= n {
+		return reduceRange(a, 0, n, init, f), nil
+	}
+	parts := make([]R, (n+chunk-1)/chunk)
+	var wg sync.WaitGroup
+	for k := range parts {
+		lo := uintptr(k) * chunk
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parts[k] = reduceRange(a, lo, min(lo+chunk, n), init, f)
+		}()
+	}
+	wg.Wait()
+	acc := parts[0]
+	for _, p := range parts[1:] {
+		acc = merge(acc, p)
+	}
+	return acc, nil
+}
+
+func reduceRange[T, R any](a *AtomicArena[T], lo, hi uintptr, acc R, f func(R, *T) R) R {
+	for i := lo; i < hi; i++ {
+		if !a.tombstoned(i) {
+			acc = f(acc, &a.raw[i])
+		}
+	}
+	return acc
+}
@@ -0,0 +1,95 @@
+package atomicarena
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+// TestTransformReduce compares the parallel helpers with sequential loops
+// over random data, on both sides of the parallel threshold; run with -race
+func TestTransformReduce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 100, parallelMinElems*2 - 1, parallelMinElems*8 + 3} {
+		a := NewAtomicArena[int64](uintptr(n) + 16)
+		want := make([]int64, n)
+		for i := range want {
+			want[i] = rng.Int63n(1000)
+			a.Alloc(want[i])
+		}
+		if n > 10 {
+			a.Tombstone(7)
+			want[7] = 0
+		}
+		if err := a.Transform(func(v *int64) { *v = *v*2 + 1 }); err != nil {
+			t.Fatal(err)
+		}
+		var sum int64
+		for i := range want {
+			if n > 10 && i == 7 {
+				continue
+			}
+			want[i] = want[i]*2 + 1
+			sum += want[i]
+		}
+		for i, v := range a.raw[:n] {
+			if i == 7 && n > 10 {
+				continue
+			}
+			if v != want[i] {
+				t.Fatalf("n=%d: expected element %d to be %d, got %d", n, i, want[i], v)
+			}
+		}
+		got, err := Reduce(a, int64(0), func(acc int64, v *int64) int64 { return acc + *v }, func(x, y int64) int64 { return x + y })
+		if err != nil || got != sum {
+			t.Fatalf("n=%d: expected Reduce to return %d, got %d, %v", n, sum, got, err)
+		}
+		// order-sensitive merge: concatenation must keep index order
+		first, _ := Reduce(a, []int64(nil), func(acc []int64, v *int64) []int64 { return append(acc, *v) },
+			func(x, y []int64) []int64 { return append(x, y...) })
+		if len(first) > 0 && (first[0] != want[0] || first[len(first)-1] != want[n-1]) {
+			t.Fatalf("n=%d: expected partial results merged in index order", n)
+		}
+		if a.raw[n] != 0 {
+			t.Fatalf("n=%d: expected slots past the committed count untouched", n)
+		}
+	}
+}
+
+// TestTransformSmallInline ensures small arenas run without goroutines
+func TestTransformSmallInline(t *testing.T) {
+	if parallelChunks(parallelMinElems*2-1) != parallelMinElems*2-1 {
+		t.Fatalf("expected arenas below the threshold to use a single chunk")
+	}
+	a := NewAtomicArena[int](1024)
+	for i := 0; i < 1024; i++ {
+		a.Alloc(i)
+	}
+	f := func(v *int) { *v++ }
+	sum := func(acc int, v *int) int { return acc + *v }
+	add := func(x, y int) int { return x + y }
+	if allocs := testing.AllocsPerRun(10, func() { a.Transform(f) }); allocs != 0 {
+		t.Fatalf("expected an inline Transform, got %v allocations", allocs)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { Reduce(a, 0, sum, add) }); allocs != 0 {
+		t.Fatalf("expected an inline Reduce, got %v allocations", allocs)
+	}
+	if runtime.GOMAXPROCS(0) > 1 && parallelChunks(parallelMinElems*8) >= parallelMinElems*8 {
+		t.Fatalf("expected large arenas to be split")
+	}
+}
+
+// BenchmarkReduce sums a million elements
+func BenchmarkReduce(b *testing.B) {
+	const n = 1 << 20
+	a := NewAtomicArena[float64](n)
+	for i := 0; i < n; i++ {
+		a.Alloc(float64(i))
+	}
+	sum := func(acc float64, v *float64) float64 { return acc + *v }
+	add := func(x, y float64) float64 { return x + y }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Reduce(a, 0, sum, add)
+	}
+}
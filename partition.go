@@ -0,0 +1,161 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// PartitionedArena splits one allocation into two hard partitions: a bulk
+// region for ordinary traffic and a reserved tail region for allocations
+// that must succeed under overload, such as error reports. Each partition
+// has its own counter, so filling the bulk region never takes a slot from
+// the reserved one. Unlike WithSoftCap, which lets privileged callers use
+// whatever slots are left, the reserved region is set aside up front and
+// ordinary allocations can never reach it.
+type PartitionedArena[T any] struct {
+	bulk     *AtomicArena[T]
+	reserved *AtomicArena[T]
+	name     string // WithName, without the partition suffix
+}
+
+// NewPartitionedArena creates an arena of bulk slots followed by reserved
+// slots, stored back to back in a single allocation and configured by opts,
+// which apply to both partitions. Under WithName, the partitions are named
+// "name[bulk]" and "name[reserved]". Like NewAtomicArena it panics if the
+// options are invalid or the storage cannot be allocated.
+func NewPartitionedArena[T any](bulk, reserved uintptr, opts ...Option) *PartitionedArena[T] {
+	p, err := newPartitionedArena[T](bulk, reserved, opts)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func newPartitionedArena[T any](bulk, reserved uintptr, opts []Option) (*PartitionedArena[T], error) {
+	o := buildOptions(opts)
+	if err := o.validate(false); err != nil {
+		return nil, err
+	}
+	if err := validateElem[T](o); err != nil {
+		return nil, err
+	}
+	if reserved > ^uintptr(0)-bulk {
+		return nil, fmt.Errorf("%w: %d bulk and %d reserved elements overflow uintptr", ErrTooLarge, bulk, reserved)
+	}
+	if zeroSized[T]() {
+		o.noMirror = true
+	}
+	n := bulk + reserved
+	raw, ptrs, err := groupStorage[T](n, o)
+	if err != nil {
+		return nil, err
+	}
+	part := func(lo, hi uintptr, suffix string) *AtomicArena[T] {
+		opt := o
+		if o.name != "" {
+			opt.name = o.name + suffix
+		}
+		var p []atomic.Pointer[T]
+		if ptrs != nil {
+			p = ptrs[lo:hi:hi]
+		}
+		a := newAtomicArena(raw[lo:hi:hi], p, opt)
+		if o.prefault {
+			a.Prefault()
+		}
+		return a
+	}
+	return &PartitionedArena[T]{
+		bulk:     part(0, bulk, "[bulk]"),
+		reserved: part(bulk, n, "[reserved]"),
+		name:     o.name,
+	}, nil
+}
+
+// Bulk returns the bulk partition, for the allocation methods beyond Alloc.
+func (p *PartitionedArena[T]) Bulk() *AtomicArena[T] {
+	return p.bulk
+}
+
+// Reserved returns the reserved partition.
+func (p *PartitionedArena[T]) Reserved() *AtomicArena[T] {
+	return p.reserved
+}
+
+// Alloc stores obj in the bulk partition. It fails with ErrArenaFull once
+// the bulk partition is full, even if reserved slots are free.
+func (p *PartitionedArena[T]) Alloc(obj T) (*T, error) {
+	return p.bulk.Alloc(obj)
+}
+
+// AllocReserved stores obj in the reserved partition, which bulk traffic
+// cannot exhaust.
+func (p *PartitionedArena[T]) AllocReserved(obj T) (*T, error) {
+	return p.reserved.Alloc(obj)
+}
+
+// Cap returns the total number of slots in both partitions.
+func (p *PartitionedArena[T]) Cap() uintptr {
+	return p.bulk.Cap() + p.reserved.Cap()
+}
+
+// IndexOf returns the slot ptr points to, counting bulk slots first and
+// reserved slots after them, or false if ptr does not point into either
+// partition.
+func (p *PartitionedArena[T]) IndexOf(ptr *T) (uintptr, bool) {
+	if i, ok := p.bulk.indexOf(ptr); ok {
+		return i, true
+	}
+	if i, ok := p.reserved.indexOf(ptr); ok {
+		return p.bulk.Cap() + i, true
+	}
+	return 0, false
+}
+
+// Contains reports whether ptr points into either partition's storage.
+func (p *PartitionedArena[T]) Contains(ptr *T) bool {
+	_, ok := p.IndexOf(ptr)
+	return ok
+}
+
+// Get returns the element at slot i, numbered as by IndexOf, or false if
+// that slot has not been allocated in its partition.
+func (p *PartitionedArena[T]) Get(i uintptr) (*T, bool) {
+	if n := p.bulk.Cap(); i >= n {
+		return p.reserved.Get(i - n)
+	}
+	return p.bulk.Get(i)
+}
+
+// Reset resets both partitions with Reset(release). It resets the reserved
+// partition even if the bulk one fails, and returns the failures joined,
+// each prefixed with its partition.
+func (p *PartitionedArena[T]) Reset(release bool) error {
+	var errs []error
+	if err := p.bulk.Reset(release); err != nil {
+		errs = append(errs, fmt.Errorf("bulk: %w", err))
+	}
+	if err := p.reserved.Reset(release); err != nil {
+		errs = append(errs, fmt.Errorf("reserved: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// PartitionedStats summarizes a PartitionedArena: the embedded Stats holds
+// the totals and Bulk and Reserved hold each partition's own Stats.
+type PartitionedStats struct {
+	Stats
+	Bulk     Stats
+	Reserved Stats
+}
+
+// Stats returns the totals across both partitions and each one's Stats,
+// combined as ArenaGroup.Stats combines its groups. Name is the arena's
+// WithName.
+func (p *PartitionedArena[T]) Stats() PartitionedStats {
+	s := PartitionedStats{Bulk: p.bulk.Stats(), Reserved: p.reserved.Stats()}
+	s.Stats = sumStats(s.Bulk, s.Reserved)
+	s.Name = p.name
+	return s
+}
@@ -0,0 +1,91 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// TestPartitionedReservedSurvivesFullBulk allocates reserved slots after bulk traffic fills its partition
+func TestPartitionedReservedSurvivesFullBulk(t *testing.T) {
+	const bulk, reserved = 64, 4
+	p := NewPartitionedArena[int](bulk, reserved)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := p.Alloc(1); errors.Is(err, ErrArenaFull) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if p.Bulk().Len() != bulk {
+		t.Fatalf("expected the bulk partition to be full, got %d", p.Bulk().Len())
+	}
+	seen := make(map[*int]bool)
+	for i := 0; i < reserved; i++ {
+		r, err := p.AllocReserved(-i)
+		if err != nil {
+			t.Fatalf("expected reserved allocation %d to succeed, got %v", i, err)
+		}
+		idx, ok := p.IndexOf(r)
+		if !ok || idx != bulk+uintptr(i) || seen[r] {
+			t.Fatalf("expected reserved slot %d after the bulk slots, got %d", i, idx)
+		}
+		seen[r] = true
+	}
+	for i := uintptr(0); i < bulk; i++ {
+		b, _ := p.Get(i)
+		if seen[b] || *b != 1 {
+			t.Fatalf("expected bulk slot %d untouched by reserved allocations", i)
+		}
+	}
+	if _, err := p.AllocReserved(0); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected the reserved partition to be full, got %v", err)
+	}
+	if _, err := p.Alloc(0); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected bulk allocations not to spill into the reserved partition, got %v", err)
+	}
+}
+
+// TestPartitionedStorage keeps both partitions in one contiguous buffer
+func TestPartitionedStorage(t *testing.T) {
+	p := NewPartitionedArena[int64](3, 2, WithName("parts"))
+	defer p.Bulk().Close()
+	defer p.Reserved().Close()
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(p.bulk.raw)))
+	if r := uintptr(unsafe.Pointer(unsafe.SliceData(p.reserved.raw))); r != base+3*8 {
+		t.Fatalf("expected the reserved partition at offset 24, got %d", r-base)
+	}
+	b, _ := p.Alloc(1)
+	r, _ := p.AllocReserved(2)
+	if !p.Contains(b) || !p.Contains(r) || p.Contains(new(int64)) {
+		t.Fatalf("expected Contains to accept exactly the arena's slots")
+	}
+	if got, ok := p.Get(3); !ok || got != r {
+		t.Fatalf("expected Get(3) to return the first reserved slot")
+	}
+	if _, ok := p.Get(4); ok {
+		t.Fatalf("expected an unallocated reserved slot to be missing")
+	}
+	s := p.Stats()
+	if s.Len != 2 || s.Cap != 5 || s.Bulk.Len != 1 || s.Reserved.Cap != 2 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if s.Name != "parts" || s.Bulk.Name != "parts[bulk]" || s.Reserved.Name != "parts[reserved]" {
+		t.Fatalf("expected partition names, got %q, %q and %q", s.Name, s.Bulk.Name, s.Reserved.Name)
+	}
+	if err := p.Reset(true); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	inspectReleased(p.Bulk())
+	inspectReleased(p.Reserved())
+	if p.Bulk().Len() != 0 || p.Reserved().Len() != 0 || *b != 0 || *r != 0 {
+		t.Fatalf("expected Reset to clear both partitions")
+	}
+}
@@ -0,0 +1,96 @@
+package atomicarena
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type withSlice struct {
+	ID   int
+	Tags []string
+}
+
+type embedsSlice struct {
+	withSlice
+	Score float64
+}
+
+type plainPair struct {
+	A int64
+	B [4]uint16
+}
+
+// TestHasPointers checks the classification the arenas cache at construction
+func TestHasPointers(t *testing.T) {
+	cases := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"int", NewAtomicArena[int](1).pointers, false},
+		{"plainPair", NewAtomicArena[plainPair](1).pointers, false},
+		{"[0]*int", NewAtomicArena[[0]*int](1).pointers, false},
+		{"string", NewAtomicArena[string](1).pointers, true},
+		{"*int", NewAtomicArena[*int](1).pointers, true},
+		{"embedsSlice", NewAtomicArena[embedsSlice](1).pointers, true},
+		{"[2]any", NewAtomicArena[[2]any](1).pointers, true},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("%s: expected pointers=%v, got %v", c.name, c.want, c.got)
+		}
+	}
+}
+
+// TestPointerPathsRejected ensures byte-level features refuse pointerful types
+func TestPointerPathsRejected(t *testing.T) {
+	s := NewAtomicArena[string](2)
+	s.Alloc("x")
+	if _, err := s.WriteTo(&bytes.Buffer{}); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("string WriteTo: expected ErrPointerType, got %v", err)
+	}
+	if _, err := ReadArenaFrom[*int](&bytes.Buffer{}); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("*int ReadArenaFrom: expected ErrPointerType, got %v", err)
+	}
+	if _, err := NewMmapArena[embedsSlice](4); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("embedsSlice NewMmapArena: expected ErrPointerType, got %v", err)
+	}
+
+	ints := NewAtomicArena[int](2)
+	ints.Alloc(7)
+	if _, err := ints.WriteTo(&bytes.Buffer{}); err != nil {
+		t.Fatalf("int WriteTo failed: %v", err)
+	}
+	m, err := NewMmapArena[plainPair](4)
+	if err != nil {
+		t.Fatalf("plainPair NewMmapArena failed: %v", err)
+	}
+	m.Close()
+}
+
+// TestFreeClearsAllKinds checks that the safe and the memclr clearing paths both zero storage
+func TestFreeClearsAllKinds(t *testing.T) {
+	v := 3
+	p := NewAtomicArena[*int](4)
+	p.Alloc(&v)
+	s := NewAtomicArena[embedsSlice](4)
+	s.Alloc(embedsSlice{withSlice{1, []string{"a"}}, 2})
+	n := NewAtomicArena[plainPair](4)
+	n.Alloc(plainPair{A: 9, B: [4]uint16{1, 2, 3, 4}})
+
+	for _, err := range []error{p.Free(), s.Free(), n.Free()} {
+		if err != nil {
+			t.Fatalf("Free failed: %v", err)
+		}
+	}
+	if p.raw[0] != nil {
+		t.Fatalf("expected *int slot cleared")
+	}
+	if s.raw[0].Tags != nil || s.raw[0].ID != 0 || s.raw[0].Score != 0 {
+		t.Fatalf("expected embedsSlice slot cleared, got %+v", s.raw[0])
+	}
+	if n.raw[0] != (plainPair{}) {
+		t.Fatalf("expected plainPair slot cleared, got %+v", n.raw[0])
+	}
+}
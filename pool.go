@@ -0,0 +1,100 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrPoolExhausted is returned by Acquire when maxArenas are outstanding
+	// and the pool is not in blocking mode.
+	ErrPoolExhausted = errors.New("atomicarena: arena pool exhausted")
+	// ErrNotAcquired is returned by Release for an arena that is not currently
+	// acquired from the pool, including one that was already released.
+	ErrNotAcquired = errors.New("atomicarena: arena not acquired from pool")
+)
+
+// PoolOption configures an ArenaPool.
+type PoolOption func(*poolOptions)
+
+type poolOptions struct {
+	block bool // Acquire waits instead of failing when the pool is exhausted
+	free  bool // Release zeroes the arena's storage, not just its count
+}
+
+// WithBlockingAcquire makes Acquire wait for a Release when maxArenas are outstanding.
+func WithBlockingAcquire() PoolOption {
+	return func(o *poolOptions) { o.block = true }
+}
+
+// WithFreeOnRelease makes Release zero the arena's storage before pooling it.
+func WithFreeOnRelease() PoolOption {
+	return func(o *poolOptions) { o.free = true }
+}
+
+// ArenaPool recycles up to maxArenas arenas of arenaElems elements each.
+// Arenas are created lazily. The pool's bookkeeping is only touched by
+// Acquire and Release, never by allocations from the arenas themselves.
+type ArenaPool[T any] struct {
+	arenaElems uintptr
+	maxArenas  int
+	opts       poolOptions
+	idle       chan *AtomicArena[T]
+	created    atomic.Int64
+	out        sync.Map // *AtomicArena[T] -> struct{}, arenas currently acquired
+}
+
+// NewArenaPool creates a pool handing out arenas of arenaElems elements,
+// with at most maxArenas outstanding at once.
+func NewArenaPool[T any](arenaElems uintptr, maxArenas int, opts ...PoolOption) *ArenaPool[T] {
+	p := &ArenaPool[T]{
+		arenaElems: arenaElems,
+		maxArenas:  maxArenas,
+		idle:       make(chan *AtomicArena[T], maxArenas),
+	}
+	for _, opt := range opts {
+		opt(&p.opts)
+	}
+	return p
+}
+
+// Acquire returns an empty arena, reusing a released one when available.
+func (p *ArenaPool[T]) Acquire() (*AtomicArena[T], error) {
+	var a *AtomicArena[T]
+	select {
+	case a = <-p.idle:
+	default:
+		if n := p.created.Add(1); n <= int64(p.maxArenas) {
+			a = NewAtomicArena[T](p.arenaElems)
+			break
+		}
+		p.created.Add(-1)
+		if !p.opts.block {
+			return nil, ErrPoolExhausted
+		}
+		a = <-p.idle
+	}
+	p.out.Store(a, struct{}{})
+	return a, nil
+}
+
+// Release resets a and returns it to the pool. It returns ErrNotAcquired if a
+// is not currently acquired from this pool. If a cannot be reset (for example
+// because it was frozen) it is dropped and replaced with a fresh arena.
+func (p *ArenaPool[T]) Release(a *AtomicArena[T]) error {
+	if _, ok := p.out.LoadAndDelete(a); !ok {
+		return ErrNotAcquired
+	}
+	if err := a.Reset(p.opts.free); err != nil {
+		p.idle <- NewAtomicArena[T](p.arenaElems)
+		return err
+	}
+	p.idle <- a
+	return nil
+}
+
+// Outstanding returns the number of arenas currently acquired.
+func (p *ArenaPool[T]) Outstanding() int {
+	return int(p.created.Load()) - len(p.idle)
+}
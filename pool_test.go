@@ -0,0 +1,117 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPoolAcquireRelease ensures arenas are reset and recycled
+func TestPoolAcquireRelease(t *testing.T) {
+	p := NewArenaPool[int](4, 1, WithFreeOnRelease())
+	a, err := p.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	_, _ = a.AppendSlice([]int{1, 2, 3})
+	if _, err := p.Acquire(); !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+	if err := p.Release(a); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := p.Release(a); !errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("expected ErrNotAcquired on double release, got %v", err)
+	}
+
+	b, err := p.Acquire()
+	if err != nil {
+		t.Fatalf("second Acquire failed: %v", err)
+	}
+	if b != a {
+		t.Fatal("expected released arena to be reused")
+	}
+	inspectReleased(b)
+	if b.Len() != 0 || b.raw[0] != 0 {
+		t.Fatalf("released arena not reset: len=%d raw[0]=%d", b.Len(), b.raw[0])
+	}
+	if err := p.Release(NewAtomicArena[int](4)); !errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("expected ErrNotAcquired for foreign arena, got %v", err)
+	}
+}
+
+// TestPoolBlockingAcquire ensures a blocking Acquire waits for Release
+func TestPoolBlockingAcquire(t *testing.T) {
+	p := NewArenaPool[int](4, 1, WithBlockingAcquire())
+	a, _ := p.Acquire()
+	got := make(chan *AtomicArena[int])
+	go func() {
+		b, _ := p.Acquire()
+		got <- b
+	}()
+	select {
+	case <-got:
+		t.Fatal("Acquire did not block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	_ = p.Release(a)
+	if b := <-got; b != a {
+		t.Fatal("blocked Acquire did not receive released arena")
+	}
+}
+
+// TestPoolConcurrent hammers the pool from many goroutines
+func TestPoolConcurrent(t *testing.T) {
+	p := NewArenaPool[int](16, 4, WithBlockingAcquire())
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a, err := p.Acquire()
+				if err != nil {
+					t.Errorf("Acquire failed: %v", err)
+					return
+				}
+				if a.Len() != 0 {
+					t.Errorf("acquired arena not empty: %d", a.Len())
+				}
+				_, _ = a.Alloc(i)
+				if err := p.Release(a); err != nil {
+					t.Errorf("Release failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if p.Outstanding() != 0 {
+		t.Fatalf("expected no outstanding arenas, got %d", p.Outstanding())
+	}
+}
+
+// BenchmarkPool compares pooled arenas with creating a new arena each time.
+func BenchmarkPool(b *testing.B) {
+	const elems = 1024
+	b.Run("Pool", func(b *testing.B) {
+		p := NewArenaPool[int](elems, 256, WithBlockingAcquire())
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				a, _ := p.Acquire()
+				_, _ = a.Alloc(1)
+				_ = p.Release(a)
+			}
+		})
+	})
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				a := NewAtomicArena[int](elems)
+				_, _ = a.Alloc(1)
+			}
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package atomicarena
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Prefault touches every page of the arena's raw buffer and pointer mirror so
+// later writes don't take first-touch page faults. Contents are unchanged:
+// each page is touched with an atomic add of zero. Prefault must not run
+// concurrently with writers to the arena.
+func (a *AtomicArena[T]) Prefault() {
+	prefault(elemBytes(a.raw, uintptr(len(a.raw))))
+	prefault(elemBytes(a.ptrs, uintptr(len(a.ptrs))))
+}
+
+// prefault writes to one 4-byte word in each page spanned by mem without
+// changing its value. Buffers smaller than a word are left alone.
+func prefault(mem []byte) {
+	if len(mem) < 4 {
+		return
+	}
+	base := uintptr(unsafe.Pointer(&mem[0]))
+	end := uintptr(len(mem))
+	page := uintptr(pageSize)
+	for off := (base+3)&^3 - base; off+4 <= end; off = (base+off+page)&^(page-1) - base {
+		atomic.AddUint32((*uint32)(unsafe.Add(unsafe.Pointer(&mem[0]), off)), 0)
+	}
+}
@@ -0,0 +1,84 @@
+package atomicarena
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestPrefaultPreservesContents ensures Prefault completes without altering data
+func TestPrefaultPreservesContents(t *testing.T) {
+	arena := NewAtomicArena[uint32](100_000, WithPrefault())
+	vals := make([]uint32, 50_000)
+	for i := range vals {
+		vals[i] = uint32(i) * 2654435761
+	}
+	_, _ = arena.AppendSlice(vals)
+	_, _ = arena.Alloc(7)
+	arena.Prefault()
+	for i, v := range vals {
+		if arena.raw[i] != v {
+			t.Fatalf("index %d: expected %d, got %d", i, v, arena.raw[i])
+		}
+	}
+	if arena.ptrs[len(vals)].Load() != &arena.raw[len(vals)] {
+		t.Fatal("pointer mirror altered by Prefault")
+	}
+
+	// tiny arenas are fine too
+	NewAtomicArena[byte](1, WithPrefault()).Prefault()
+}
+
+// TestMmapPrefault ensures the mapped backend accepts WithPrefault and Prefault
+func TestMmapPrefault(t *testing.T) {
+	m, err := NewMmapArena[int64](1<<16, WithPrefault())
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	_, _ = m.Alloc(42)
+	if err := m.Prefault(); err != nil {
+		t.Fatalf("Prefault failed: %v", err)
+	}
+	if v, _ := m.Get(0); *v != 42 {
+		t.Fatalf("Prefault altered contents: %d", *v)
+	}
+	_ = m.Close()
+	if err := m.Prefault(); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// BenchmarkFirstTouch measures the latency of the first write to each page of
+// a fresh 64MB arena with and without prefaulting, reporting p50 and p99.
+func BenchmarkFirstTouch(b *testing.B) {
+	const elems = 64 << 20 / 8
+	perPage := uintptr(pageSize / 8)
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Lazy", nil},
+		{"Prefault", []Option{WithPrefault()}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			var lat []time.Duration
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				arena := NewAtomicArena[int64](elems, tc.opts...)
+				b.StartTimer()
+				for {
+					start := time.Now()
+					seg, err := arena.Reserve(perPage)
+					if err != nil {
+						break
+					}
+					seg[0] = 1
+					lat = append(lat, time.Since(start))
+				}
+			}
+			sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+			b.ReportMetric(float64(lat[len(lat)/2].Nanoseconds()), "p50-ns")
+			b.ReportMetric(float64(lat[len(lat)*99/100].Nanoseconds()), "p99-ns")
+		})
+	}
+}
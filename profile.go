@@ -0,0 +1,140 @@
+package atomicarena
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrNoProfile is returned by WriteAllocProfile on an arena built without
+// WithAllocProfiling.
+var ErrNoProfile = errors.New("atomicarena: allocation profiling not enabled")
+
+// profileDepth is the maximum number of frames recorded per sample.
+const profileDepth = 32
+
+// WithAllocProfiling samples one in every rate allocations (Alloc, Reserve
+// and AppendSlice calls), recording the caller's stack so WriteAllocProfile
+// can report which code paths fill the arena. Zero, the default, disables
+// profiling.
+func WithAllocProfiling(rate int) Option {
+	return func(o *options) { o.profileRate = max(rate, 0) }
+}
+
+// allocProfile is the side table of sampled allocation stacks.
+type allocProfile struct {
+	rate int
+	elem uintptr // element size in bytes
+	tick atomic.Uint64
+
+	mu      sync.Mutex
+	records map[[profileDepth]uintptr]*profileRecord
+}
+
+type profileRecord struct {
+	objects, bytes int64
+}
+
+func newAllocProfile[T any](rate int) *allocProfile {
+	if rate == 0 {
+		return nil
+	}
+	var zero T
+	return &allocProfile{
+		rate:    rate,
+		elem:    unsafe.Sizeof(zero),
+		records: make(map[[profileDepth]uintptr]*profileRecord),
+	}
+}
+
+// sample records an allocation of n elements if it is picked by the sampling
+// rate. Each sample stands for rate allocations, so the recorded counts are
+// estimates of the totals, exact at rate 1. It must be called directly from
+// the allocating method so the stack starts at that method's caller.
+func (p *allocProfile) sample(n uintptr) {
+	if p.tick.Add(1)%uint64(p.rate) != 0 {
+		return
+	}
+	var stk [profileDepth]uintptr
+	// skip runtime.Callers, sample and the allocating method
+	runtime.Callers(3, stk[:])
+	w := int64(p.rate)
+	p.mu.Lock()
+	r := p.records[stk]
+	if r == nil {
+		r = &profileRecord{}
+		p.records[stk] = r
+	}
+	r.objects += int64(n) * w
+	r.bytes += int64(n*p.elem) * w
+	p.mu.Unlock()
+}
+
+func (p *allocProfile) reset() {
+	p.mu.Lock()
+	clear(p.records)
+	p.mu.Unlock()
+}
+
+// WriteAllocProfile writes the arena's sampled allocation stacks in the text
+// format of pprof heap profiles, so `go tool pprof` can read it. Every
+// allocation since the last Reset is outstanding, so in-use and allocated
+// figures are equal. With debug > 0 each stack is followed by its symbolized
+// frames; unlike runtime profiles there is no protobuf form for debug 0. It
+// returns ErrNoProfile unless the arena was built WithAllocProfiling.
+func (a *AtomicArena[T]) WriteAllocProfile(w io.Writer, debug int) error {
+	p := a.prof
+	if p == nil {
+		return ErrNoProfile
+	}
+	type entry struct {
+		stk []uintptr
+		profileRecord
+	}
+	var total profileRecord
+	p.mu.Lock()
+	entries := make([]entry, 0, len(p.records))
+	for stk, r := range p.records {
+		n := slices.Index(stk[:], 0)
+		if n < 0 {
+			n = len(stk)
+		}
+		entries = append(entries, entry{slices.Clone(stk[:n]), *r})
+		total.objects += r.objects
+		total.bytes += r.bytes
+	}
+	p.mu.Unlock()
+	slices.SortFunc(entries, func(x, y entry) int {
+		return cmp.Or(cmp.Compare(y.bytes, x.bytes), slices.Compare(x.stk, y.stk))
+	})
+
+	// counts are already scaled by the sampling rate, so report a period of 1
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "heap profile: %d: %d [%d: %d] @ heap/1\n", total.objects, total.bytes, total.objects, total.bytes)
+	for _, e := range entries {
+		fmt.Fprintf(bw, "%d: %d [%d: %d] @", e.objects, e.bytes, e.objects, e.bytes)
+		for _, pc := range e.stk {
+			fmt.Fprintf(bw, " %#x", pc)
+		}
+		fmt.Fprintln(bw)
+		if debug > 0 {
+			frames := runtime.CallersFrames(e.stk)
+			for {
+				f, more := frames.Next()
+				fmt.Fprintf(bw, "#\t%#x\t%s+%#x\t%s:%d\n", f.PC, f.Function, f.PC-f.Entry, f.File, f.Line)
+				if !more {
+					break
+				}
+			}
+			fmt.Fprintln(bw)
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,124 @@
+package atomicarena
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//go:noinline
+func allocFromFirst(a *AtomicArena[int64], n int) {
+	for i := 0; i < n; i++ {
+		a.Alloc(int64(i))
+	}
+}
+
+//go:noinline
+func allocFromSecond(a *AtomicArena[int64], n int) {
+	for i := 0; i < n; i++ {
+		a.Alloc(int64(i))
+	}
+}
+
+// profileCounts maps each function named in a debug=1 profile to the
+// "objects: bytes" figure of the record whose stack contains it.
+func profileCounts(t *testing.T, out string) map[string]string {
+	t.Helper()
+	counts := make(map[string]string)
+	var current string
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "heap profile:"), line == "":
+		case strings.HasPrefix(line, "#"):
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				t.Fatalf("malformed frame line %q", line)
+			}
+			fn, _, _ := strings.Cut(fields[2], "+")
+			counts[fn] = current
+		default:
+			head, _, ok := strings.Cut(line, " [")
+			if !ok {
+				t.Fatalf("malformed record line %q", line)
+			}
+			current = head
+		}
+	}
+	return counts
+}
+
+// TestAllocProfile asserts both allocation sites appear with the right counts
+func TestAllocProfile(t *testing.T) {
+	a := NewAtomicArena[int64](64, WithAllocProfiling(1))
+	allocFromFirst(a, 3)
+	allocFromSecond(a, 5)
+
+	var buf bytes.Buffer
+	if err := a.WriteAllocProfile(&buf, 1); err != nil {
+		t.Fatalf("WriteAllocProfile failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "heap profile: 8: 64 [8: 64] @ heap/1\n") {
+		t.Fatalf("unexpected header in:\n%s", out)
+	}
+	counts := profileCounts(t, out)
+	for fn, want := range map[string]string{
+		"github.com/Raezil/atomicarena.allocFromFirst":  "3: 24",
+		"github.com/Raezil/atomicarena.allocFromSecond": "5: 40",
+	} {
+		if got := counts[fn]; got != want {
+			t.Errorf("%s: expected %q, got %q in:\n%s", fn, want, got, out)
+		}
+	}
+	if _, ok := counts["github.com/Raezil/atomicarena.(*AtomicArena[...]).Alloc"]; ok {
+		t.Errorf("expected stacks to start at Alloc's caller:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := a.WriteAllocProfile(&buf, 0); err != nil {
+		t.Fatalf("WriteAllocProfile failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "#") || strings.Count(buf.String(), "\n") != 3 {
+		t.Fatalf("expected only header and record lines with debug=0, got:\n%s", buf.String())
+	}
+}
+
+// TestAllocProfileSampling checks rate scaling, Reset and the disabled default
+func TestAllocProfileSampling(t *testing.T) {
+	a := NewAtomicArena[int64](64, WithAllocProfiling(4))
+	allocFromFirst(a, 8)
+	a.AppendSlice(make([]int64, 3))
+	var buf bytes.Buffer
+	a.WriteAllocProfile(&buf, 0)
+	// two of eight Allocs were sampled, each standing for four; the AppendSlice was not
+	if !strings.HasPrefix(buf.String(), "heap profile: 8: 64 ") {
+		t.Fatalf("expected scaled totals, got:\n%s", buf.String())
+	}
+
+	a.Reset(false)
+	buf.Reset()
+	a.WriteAllocProfile(&buf, 0)
+	if got := buf.String(); got != "heap profile: 0: 0 [0: 0] @ heap/1\n" {
+		t.Fatalf("expected Reset to clear the profile, got:\n%s", got)
+	}
+
+	if err := NewAtomicArena[int](1).WriteAllocProfile(&buf, 0); !errors.Is(err, ErrNoProfile) {
+		t.Fatalf("expected ErrNoProfile, got %v", err)
+	}
+}
+
+// BenchmarkAllocProfiling compares Alloc with profiling off and sampled
+func BenchmarkAllocProfiling(b *testing.B) {
+	for _, rate := range []int{0, 1024, 1} {
+		b.Run(fmt.Sprintf("rate=%d", rate), func(b *testing.B) {
+			a := NewAtomicArena[int64](1<<16, WithAllocProfiling(rate))
+			for i := 0; i < b.N; i++ {
+				if _, err := a.Alloc(int64(i)); err != nil {
+					a.Reset(false)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrQueueFull is returned by Enqueue when every slot of the queue is in use.
+var ErrQueueFull = errors.New("atomicarena: queue full")
+
+// queueSlot is one cell of the ring. seq tells producers and consumers whose
+// turn the cell is: pos when it is free for the producer at pos, pos+1 once
+// that producer has stored its value. Differences of positions are compared
+// as int, which is as wide as uintptr, so wraparound is harmless.
+type queueSlot[T any] struct {
+	seq atomic.Uintptr
+	val T
+}
+
+// Queue is a bounded lock-free multi-producer multi-consumer FIFO queue in
+// the style of Dmitry Vyukov's, with per-slot sequence numbers. Its ring
+// lives in an arena's storage, so Enqueue and Dequeue never allocate.
+type Queue[T any] struct {
+	buf []queueSlot[T]
+	n   uintptr
+
+	_   [64]byte
+	enq atomic.Uintptr // next position to enqueue at
+	_   [64]byte
+	deq atomic.Uintptr // next position to dequeue from
+	_   [64]byte
+}
+
+// NewQueue creates a queue holding up to capacity values. capacity is
+// clamped to at least 1.
+func NewQueue[T any](capacity uintptr) *Queue[T] {
+	capacity = max(capacity, 1)
+	buf, _ := NewAtomicArena[queueSlot[T]](capacity).Reserve(capacity)
+	for i := range buf {
+		buf[i].seq.Store(uintptr(i))
+	}
+	return &Queue[T]{buf: buf, n: capacity}
+}
+
+// Enqueue appends v to the queue, or returns ErrQueueFull.
+func (q *Queue[T]) Enqueue(v T) error {
+	pos := q.enq.Load()
+	for {
+		s := &q.buf[pos%q.n]
+		switch d := int(s.seq.Load() - pos); {
+		case d == 0:
+			if q.enq.CompareAndSwap(pos, pos+1) {
+				s.val = v
+				s.seq.Store(pos + 1)
+				return nil
+			}
+			pos = q.enq.Load()
+		case d < 0:
+			// the slot still holds the value from one lap ago
+			return ErrQueueFull
+		default:
+			pos = q.enq.Load()
+		}
+	}
+}
+
+// Dequeue removes and returns the oldest value, or false if the queue is empty.
+func (q *Queue[T]) Dequeue() (T, bool) {
+	pos := q.deq.Load()
+	for {
+		s := &q.buf[pos%q.n]
+		switch d := int(s.seq.Load() - (pos + 1)); {
+		case d == 0:
+			if q.deq.CompareAndSwap(pos, pos+1) {
+				v := s.val
+				var zero T
+				s.val = zero
+				s.seq.Store(pos + q.n)
+				return v, true
+			}
+			pos = q.deq.Load()
+		case d < 0:
+			var zero T
+			return zero, false
+		default:
+			pos = q.deq.Load()
+		}
+	}
+}
+
+// Len returns the number of values in the queue. Under concurrent use it is
+// a snapshot that may already be out of date.
+func (q *Queue[T]) Len() uintptr {
+	deq := q.deq.Load()
+	enq := q.enq.Load()
+	if enq < deq {
+		return 0
+	}
+	return min(enq-deq, q.n)
+}
+
+// Cap returns the maximum number of values the queue can hold.
+func (q *Queue[T]) Cap() uintptr {
+	return q.n
+}
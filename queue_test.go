@@ -0,0 +1,125 @@
+package atomicarena
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestQueueFIFO covers ordering, full and empty conditions on one goroutine
+func TestQueueFIFO(t *testing.T) {
+	q := NewQueue[int](3)
+	for lap := 0; lap < 5; lap++ {
+		for i := 0; i < 3; i++ {
+			if err := q.Enqueue(lap*10 + i); err != nil {
+				t.Fatalf("Enqueue failed: %v", err)
+			}
+		}
+		if err := q.Enqueue(99); err != ErrQueueFull {
+			t.Fatalf("expected ErrQueueFull, got %v", err)
+		}
+		if q.Len() != 3 {
+			t.Fatalf("expected len 3, got %d", q.Len())
+		}
+		for i := 0; i < 3; i++ {
+			if v, ok := q.Dequeue(); !ok || v != lap*10+i {
+				t.Fatalf("Dequeue = %d, %v; want %d", v, ok, lap*10+i)
+			}
+		}
+		if _, ok := q.Dequeue(); ok {
+			t.Fatal("expected an empty queue")
+		}
+	}
+}
+
+// TestQueueMPMC checks exactly-once delivery across many producers and consumers
+func TestQueueMPMC(t *testing.T) {
+	producers, consumers, per := 8, 8, 250_000
+	if testing.Short() {
+		per = 25_000
+	}
+	q := NewQueue[int](1024)
+	seen := make([]atomic.Uint32, producers*per)
+	var received atomic.Int64
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < per; i++ {
+				for q.Enqueue(p*per+i) != nil {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	total := int64(producers * per)
+	var cwg sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for received.Load() < total {
+				v, ok := q.Dequeue()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				if seen[v].Add(1) != 1 {
+					t.Errorf("value %d delivered twice", v)
+				}
+				// each consumer sees any one producer's values in order
+				if p, i := v/per, v%per; i <= last[p] {
+					t.Errorf("producer %d out of order: %d after %d", p, i, last[p])
+				} else {
+					last[p] = i
+				}
+				received.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	cwg.Wait()
+	for v := range seen {
+		if seen[v].Load() != 1 {
+			t.Fatalf("value %d delivered %d times", v, seen[v].Load())
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected an empty queue, len %d", q.Len())
+	}
+}
+
+// BenchmarkQueue compares Queue against a buffered channel
+func BenchmarkQueue(b *testing.B) {
+	b.Run("Queue", func(b *testing.B) {
+		q := NewQueue[int](1024)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for q.Enqueue(1) != nil {
+					runtime.Gosched()
+				}
+				for {
+					if _, ok := q.Dequeue(); ok {
+						break
+					}
+					runtime.Gosched()
+				}
+			}
+		})
+	})
+	b.Run("Channel", func(b *testing.B) {
+		ch := make(chan int, 1024)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ch <- 1
+				<-ch
+			}
+		})
+	})
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package atomicarena
+
+// raceEnabled reports whether the tests run under the race detector.
+const raceEnabled = true
@@ -0,0 +1,161 @@
+package atomicarena
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RCUArena lets readers keep pointers from Get across a reset. Like
+// DoubleBuffer it holds two arenas: ResetDeferred moves new allocations to
+// the spare one at once, while the retired arena is only reset, zeroing its
+// storage, after every reader that pinned it has called Unpin.
+//
+// Readers bracket their accesses with Pin and Unpin and read through the
+// returned Unpinner. Pointers obtained through it stay valid and unchanged
+// until Unpin; they must not be used afterwards.
+type RCUArena[T any] struct {
+	arenas [2]*AtomicArena[T]
+	pins   [2][]pinCount // readers and allocations on each side, striped
+	active atomic.Uint32
+
+	resetMu   sync.Mutex    // serializes ResetDeferred
+	reclaimed chan struct{} // closed once the retired side has been reset
+}
+
+// pinCount is one stripe of a striped counter, padded to its own cache
+// line so goroutines on different stripes don't share it.
+type pinCount struct {
+	n atomic.Int64
+	_ [56]byte
+}
+
+// newStripes returns a striped counter with one stripe per P, rounded up to
+// a power of two.
+func newStripes() []pinCount {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	return make([]pinCount, n)
+}
+
+// pickStripe chooses a random stripe of s.
+func pickStripe(s []pinCount) uint32 {
+	return rand.Uint32() & uint32(len(s)-1)
+}
+
+// sumStripes totals a striped counter.
+func sumStripes(s []pinCount) int64 {
+	var n int64
+	for i := range s {
+		n += s[i].n.Load()
+	}
+	return n
+}
+
+// Unpinner is a reader's pin on one side of an RCUArena.
+type Unpinner[T any] struct {
+	r     *RCUArena[T]
+	side  uint32
+	shard uint32
+}
+
+// NewRCUArena creates an RCUArena whose two arenas each hold maxElems
+// elements built with opts.
+func NewRCUArena[T any](maxElems uintptr, opts ...Option) *RCUArena[T] {
+	r := &RCUArena[T]{
+		arenas:    [2]*AtomicArena[T]{NewAtomicArena[T](maxElems, opts...), NewAtomicArena[T](maxElems, opts...)},
+		pins:      [2][]pinCount{newStripes(), newStripes()},
+		reclaimed: make(chan struct{}),
+	}
+	close(r.reclaimed)
+	return r
+}
+
+// Pin marks the calling reader as active on the current arena until Unpin.
+// It is a single increment on a randomly chosen counter stripe.
+func (r *RCUArena[T]) Pin() Unpinner[T] {
+	shard := pickStripe(r.pins[0])
+	for {
+		side := r.active.Load()
+		r.pins[side][shard].n.Add(1)
+		if r.active.Load() == side {
+			return Unpinner[T]{r: r, side: side, shard: shard}
+		}
+		// lost a race with ResetDeferred; the side may already be reclaiming
+		r.pins[side][shard].n.Add(-1)
+	}
+}
+
+// Unpin drops the pin. Pointers read through u must not be used afterwards.
+func (u Unpinner[T]) Unpin() {
+	u.r.pins[u.side][u.shard].n.Add(-1)
+}
+
+// Arena returns the pinned arena.
+func (u Unpinner[T]) Arena() *AtomicArena[T] {
+	return u.r.arenas[u.side]
+}
+
+// Get returns a pointer to element i of the pinned arena, valid until Unpin.
+// A slot beyond Committed, whose write may still be in flight, is reported
+// as missing.
+func (u Unpinner[T]) Get(i uintptr) (*T, bool) {
+	a := u.r.arenas[u.side]
+	if i >= a.Committed() {
+		return nil, false
+	}
+	return a.Get(i)
+}
+
+// Alloc stores obj in the active arena.
+func (r *RCUArena[T]) Alloc(obj T) (*T, error) {
+	u := r.Pin()
+	defer u.Unpin()
+	return u.Arena().Alloc(obj)
+}
+
+// Active returns the arena currently receiving allocations.
+func (r *RCUArena[T]) Active() *AtomicArena[T] {
+	return r.arenas[r.active.Load()]
+}
+
+// ResetDeferred redirects new allocations and readers to the spare arena
+// and resets the previously active one in the background once every pin on
+// it has been dropped. The returned channel is closed when that has
+// happened. If the spare arena is itself still waiting for readers from the
+// previous ResetDeferred, ResetDeferred waits for it first, so at most two
+// arenas are ever in use.
+func (r *RCUArena[T]) ResetDeferred() <-chan struct{} {
+	r.resetMu.Lock()
+	defer r.resetMu.Unlock()
+	<-r.reclaimed
+	old := r.active.Load()
+	r.active.Store(1 - old)
+	done := make(chan struct{})
+	r.reclaimed = done
+	go func() {
+		r.awaitGrace(old)
+		_ = r.arenas[old].Reset(true)
+		close(done)
+	}()
+	return done
+}
+
+// awaitGrace waits until no pins remain on side. Pins taken after the swap
+// see the new active side and back off, so the sum can only reach zero once.
+func (r *RCUArena[T]) awaitGrace(side uint32) {
+	for spins := 0; ; spins++ {
+		if sumStripes(r.pins[side]) == 0 {
+			return
+		}
+		if spins < 64 {
+			runtime.Gosched()
+		} else {
+			sleep(r.arenas[0].opts.timeSource(), 50*time.Microsecond)
+		}
+	}
+}
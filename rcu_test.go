@@ -0,0 +1,104 @@
+package atomicarena
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// checked holds a value and its complement so a torn or zeroed read shows.
+type checked struct {
+	V, Inv int64
+}
+
+// TestRCUDeferredReclaim holds a pin across ResetDeferred and checks that the
+// retired arena is only reset after Unpin
+func TestRCUDeferredReclaim(t *testing.T) {
+	r := NewRCUArena[checked](8)
+	r.Alloc(checked{V: 7, Inv: ^7})
+	u := r.Pin()
+	p, ok := u.Get(0)
+	if !ok {
+		t.Fatal("expected slot 0")
+	}
+	done := r.ResetDeferred()
+	if _, err := r.Alloc(checked{V: 8, Inv: ^8}); err != nil {
+		t.Fatal(err)
+	}
+	if r.Active() == u.Arena() || r.Active().Len() != 1 {
+		t.Fatalf("expected new allocations in the spare arena")
+	}
+	select {
+	case <-done:
+		t.Fatal("retired arena reclaimed while pinned")
+	default:
+	}
+	if p.V != 7 || p.Inv != ^7 {
+		t.Fatalf("pinned value changed: %+v", *p)
+	}
+	u.Unpin()
+	<-done
+	inspectReleased(u.Arena())
+	if u.Arena().Len() != 0 || p.V != 0 {
+		t.Fatalf("expected the retired arena to be reset, len %d, value %+v", u.Arena().Len(), *p)
+	}
+}
+
+// TestRCUReadersAcrossReset runs readers that hold pointers while a writer
+// keeps calling ResetDeferred; run with -race
+func TestRCUReadersAcrossReset(t *testing.T) {
+	const n = 64
+	r := NewRCUArena[checked](n)
+	fill := func(base int64) {
+		for i := int64(0); i < n; i++ {
+			if _, err := r.Alloc(checked{V: base + i, Inv: ^(base + i)}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}
+	fill(0)
+	var stop atomic.Bool
+	var reads atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := uintptr(g); !stop.Load(); i++ {
+				u := r.Pin()
+				var held []*checked
+				for k := uintptr(0); k < 4; k++ {
+					if p, ok := u.Get((i + k) % n); ok {
+						held = append(held, p)
+					}
+				}
+				want := make([]checked, len(held))
+				for k, p := range held {
+					want[k] = *p
+					if p.Inv != ^p.V {
+						t.Errorf("inconsistent element %+v", *p)
+					}
+				}
+				for k := 0; k < 8; k++ {
+					for j, p := range held {
+						if *p != want[j] {
+							t.Errorf("pinned element changed from %+v to %+v", want[j], *p)
+						}
+					}
+				}
+				u.Unpin()
+				reads.Add(int64(len(held)))
+				runtime.Gosched()
+			}
+		}()
+	}
+	for round := int64(1); round <= 50 || reads.Load() == 0; round++ {
+		r.ResetDeferred()
+		fill(round * n)
+	}
+	<-r.ResetDeferred()
+	stop.Store(true)
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+package atomicarena
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// ErrOutstandingRefs is wrapped by the *RefsError TryReset returns while
+// references are held.
+var ErrOutstandingRefs = errors.New("atomicarena: outstanding references")
+
+// RefsError reports a reset refused because references taken with Acquire
+// had not been released. It wraps ErrOutstandingRefs.
+type RefsError struct {
+	Name string // arena name set by WithName; empty if unnamed
+	Refs int64  // references outstanding when the reset was attempted
+}
+
+// Error renders the failure like `atomicarena "frames": 3 outstanding references`.
+func (e *RefsError) Error() string {
+	prefix, unit := "atomicarena", "references"
+	if e.Name != "" {
+		prefix = fmt.Sprintf("atomicarena %q", e.Name)
+	}
+	if e.Refs == 1 {
+		unit = "reference"
+	}
+	return fmt.Sprintf("%s: %d outstanding %s", prefix, e.Refs, unit)
+}
+
+// Unwrap returns ErrOutstandingRefs, so errors.Is(err, ErrOutstandingRefs) holds.
+func (e *RefsError) Unwrap() error { return ErrOutstandingRefs }
+
+// WithRefCounting guards resets with a reference count: readers bracket
+// their use of pointers from the arena with Acquire and ReleaseRef, Reset
+// waits until no references are outstanding, and TryReset fails instead of
+// waiting. The count is striped across cache lines, so Acquire does not
+// contend with other readers.
+func WithRefCounting() Option {
+	return func(o *options) { o.refCounting = true }
+}
+
+// RefToken is a reference taken with Acquire. Pass it to ReleaseRef once.
+type RefToken struct {
+	stripe uint32
+}
+
+// Acquire takes a reference that holds off Reset until it is released with
+// ReleaseRef. If a reset is in progress, Acquire waits for it to finish, so
+// pointers read after Acquire point at the arena's new contents. Without
+// WithRefCounting it does nothing.
+func (a *AtomicArena[T]) Acquire() RefToken {
+	if a.refs == nil {
+		return RefToken{}
+	}
+	s := pickStripe(a.refs)
+	for {
+		a.refs[s].n.Add(1)
+		if a.count.Load()&busyBit == 0 {
+			return RefToken{stripe: s}
+		}
+		// a reset is running; it either saw this reference or we back off
+		a.refs[s].n.Add(-1)
+		for a.count.Load()&busyBit != 0 {
+			runtime.Gosched()
+		}
+	}
+}
+
+// ReleaseRef releases a reference taken with Acquire.
+func (a *AtomicArena[T]) ReleaseRef(t RefToken) {
+	if a.refs != nil {
+		a.refs[t.stripe].n.Add(-1)
+	}
+}
+
+// Refs returns the number of outstanding references. It is zero without
+// WithRefCounting.
+func (a *AtomicArena[T]) Refs() int64 {
+	if a.refs == nil {
+		return 0
+	}
+	return sumStripes(a.refs)
+}
+
+// TryReset is Reset that fails with a *RefsError, wrapping
+// ErrOutstandingRefs, instead of waiting for outstanding references.
+func (a *AtomicArena[T]) TryReset(release bool) error {
+	return a.reset(release)
+}
+
+// ResetWhenIdle waits until no references are outstanding and then resets
+// the arena. It returns ctx.Err() if ctx is done first, leaving the arena
+// untouched.
+func (a *AtomicArena[T]) ResetWhenIdle(ctx context.Context, release bool) error {
+	clock := a.opts.timeSource()
+	wait := time.Microsecond
+	for {
+		err := a.reset(release)
+		if !errors.Is(err, ErrOutstandingRefs) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(wait):
+		}
+		wait = min(2*wait, time.Millisecond)
+	}
+}
@@ -0,0 +1,108 @@
+package atomicarena
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRefCountBlockedThenReleased holds references across TryReset and
+// Reset, then releases them
+func TestRefCountBlockedThenReleased(t *testing.T) {
+	a := NewAtomicArena[int](8, WithRefCounting(), WithName("refs"))
+	defer a.Close()
+	a.Alloc(42)
+	r1, r2 := a.Acquire(), a.Acquire()
+	err := a.TryReset(true)
+	var re *RefsError
+	if !errors.As(err, &re) || !errors.Is(err, ErrOutstandingRefs) || re.Refs != 2 {
+		t.Fatalf("expected a RefsError for 2 references, got %v", err)
+	}
+	if got := err.Error(); got != `atomicarena "refs": 2 outstanding references` {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if p, ok := a.Get(0); !ok || *p != 42 {
+		t.Fatalf("expected the failed reset to leave the arena untouched")
+	}
+
+	reset := make(chan error)
+	go func() { reset <- a.Reset(true) }()
+	a.ReleaseRef(r1)
+	select {
+	case err := <-reset:
+		t.Fatalf("Reset returned %v with a reference outstanding", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	a.ReleaseRef(r2)
+	if err := <-reset; err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 0 || a.Refs() != 0 {
+		t.Fatalf("expected an empty arena, len %d, refs %d", a.Len(), a.Refs())
+	}
+	if err := a.TryReset(false); err != nil {
+		t.Fatalf("expected TryReset to succeed without references, got %v", err)
+	}
+}
+
+// TestRefCountContextCancel abandons ResetWhenIdle when its context ends
+func TestRefCountContextCancel(t *testing.T) {
+	a := NewAtomicArena[int](8, WithRefCounting())
+	a.Alloc(1)
+	ref := a.Acquire()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := a.ResetWhenIdle(ctx, true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if a.Len() != 1 {
+		t.Fatalf("expected the arena untouched, len %d", a.Len())
+	}
+	a.ReleaseRef(ref)
+	if err := a.ResetWhenIdle(context.Background(), true); err != nil || a.Len() != 0 {
+		t.Fatalf("expected the reset to go through, got %v, len %d", err, a.Len())
+	}
+}
+
+// TestRefCountConcurrent checks that no reader sees its element reset while
+// it holds a reference; run with -race
+func TestRefCountConcurrent(t *testing.T) {
+	a := NewAtomicArena[int](64, WithRefCounting())
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				ref := a.Acquire()
+				if p, ok := a.Get(0); ok {
+					v := *p
+					for k := 0; k < 16; k++ {
+						if *p != v {
+							t.Errorf("element changed from %d to %d under a reference", v, *p)
+						}
+					}
+				}
+				a.ReleaseRef(ref)
+			}
+		}()
+	}
+	for i := 1; i <= 200; i++ {
+		if _, err := a.Alloc(i); err != nil {
+			t.Fatal(err)
+		}
+		if err := a.Reset(true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
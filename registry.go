@@ -0,0 +1,91 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrAlreadyRegistered is returned by RegisterArena when the registry
+// already holds an arena for the element type.
+var ErrAlreadyRegistered = errors.New("atomicarena: arena already registered")
+
+// registryKey is the Registry map key for element type T. Like ctxKey, every
+// instantiation is a distinct type, so it needs no reflection.
+type registryKey[T any] struct{}
+
+// registered is the type-erased view of an arena the registry operates on.
+type registered interface {
+	Reset(release bool) error
+	Stats() Stats
+}
+
+type registryEntry struct {
+	arena registered
+	typ   reflect.Type
+}
+
+// Registry holds at most one arena per element type, so code juggling many
+// component types can reach each arena through a generic accessor. The zero
+// value is an empty registry ready for use; registration is safe
+// concurrently with lookups.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[any]registryEntry
+}
+
+// RegisterArena creates an arena for elements of type T and adds it to r.
+// It returns ErrAlreadyRegistered if r already holds an arena for T.
+func RegisterArena[T any](r *Registry, maxElems uintptr, opts ...Option) (*AtomicArena[T], error) {
+	a, err := New[T](maxElems, opts...)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[registryKey[T]{}]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyRegistered, reflect.TypeFor[T]())
+	}
+	if r.entries == nil {
+		r.entries = make(map[any]registryEntry)
+	}
+	r.entries[registryKey[T]{}] = registryEntry{arena: a, typ: reflect.TypeFor[T]()}
+	return a, nil
+}
+
+// ArenaOf returns the arena registered in r for elements of type T.
+func ArenaOf[T any](r *Registry) (*AtomicArena[T], bool) {
+	r.mu.RLock()
+	e, ok := r.entries[registryKey[T]{}]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return e.arena.(*AtomicArena[T]), true
+}
+
+// ResetAll resets every registered arena, zeroing storage if release is set.
+// It attempts every arena and returns the joined errors of those that failed.
+func (r *Registry) ResetAll(release bool) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var errs []error
+	for _, e := range r.entries {
+		if err := e.arena.Reset(release); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.typ, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StatsAll returns the stats of every registered arena keyed by element type.
+func (r *Registry) StatsAll() map[reflect.Type]Stats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[reflect.Type]Stats, len(r.entries))
+	for _, e := range r.entries {
+		out[e.typ] = e.arena.Stats()
+	}
+	return out
+}
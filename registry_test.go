@@ -0,0 +1,79 @@
+package atomicarena
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type position struct{ X, Y float64 }
+type velocity struct{ DX, DY float64 }
+type health int
+
+// TestRegistry registers several types and allocates through the generic accessor
+func TestRegistry(t *testing.T) {
+	var r Registry
+	if _, err := RegisterArena[position](&r, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RegisterArena[velocity](&r, 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RegisterArena[health](&r, 30); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RegisterArena[health](&r, 30); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("expected ErrAlreadyRegistered, got %v", err)
+	}
+	if _, ok := ArenaOf[string](&r); ok {
+		t.Fatal("found an arena for an unregistered type")
+	}
+
+	pos, _ := ArenaOf[position](&r)
+	vel, _ := ArenaOf[velocity](&r)
+	hp, _ := ArenaOf[health](&r)
+	pos.Alloc(position{1, 2})
+	vel.Alloc(velocity{3, 4})
+	vel.Alloc(velocity{5, 6})
+	hp.Alloc(100)
+
+	stats := r.StatsAll()
+	if len(stats) != 3 || stats[reflect.TypeFor[velocity]()].Len != 2 || stats[reflect.TypeFor[health]()].Cap != 30 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if err := r.ResetAll(true); err != nil {
+		t.Fatal(err)
+	}
+	for typ, s := range r.StatsAll() {
+		if s.Len != 0 || s.Epoch != 1 {
+			t.Fatalf("%s not reset: %+v", typ, s)
+		}
+	}
+
+	hp.Freeze()
+	if err := r.ResetAll(false); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected the frozen arena's error, got %v", err)
+	}
+}
+
+// TestRegistryConcurrent registers types while other goroutines look them up
+func TestRegistryConcurrent(t *testing.T) {
+	var r Registry
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if a, ok := ArenaOf[position](&r); ok {
+					a.Alloc(position{})
+				}
+				r.StatsAll()
+			}
+		}()
+	}
+	RegisterArena[position](&r, 1<<16)
+	RegisterArena[velocity](&r, 1)
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrGap is returned by AllocAt for an index past the first free slot,
+// which would leave unallocated slots below it.
+var ErrGap = errors.New("atomicarena: index leaves unallocated slots below it")
+
+// errAllocated stops AllocAt's reservation when its slot is allocated
+// already, so the value is replaced in place.
+var errAllocated = errors.New("atomicarena: slot already allocated")
+
+// AllocAt stores v in slot i, for rebuilding an arena to a known layout such
+// as one recorded by a snapshot or an operation log. If i is the first free
+// slot, AllocAt allocates it as Alloc would, publishing it and extending the
+// count to i+1. If i is already allocated, v replaces the value in place,
+// without running a destructor on the old one, and the slot is published
+// and brought back to life if it was tombstoned; this must not race with
+// other reads or writes of the slot. An i past the first free slot fails
+// with ErrGap, since the slots in between would count as allocated without
+// holding values, and an i at or beyond the capacity with ErrOutOfRange. A
+// free slot at or above the soft cap fails like Alloc on a full arena.
+func (a *AtomicArena[T]) AllocAt(i uintptr, v T) error {
+	start, err := a.reserveWithin(1, a.softCap.Load(), func(start uintptr) (uintptr, error) {
+		switch limit := a.maxElems.Load(); {
+		case i >= limit:
+			return 0, fmt.Errorf("%w: slot %d, capacity %d", ErrOutOfRange, i, limit)
+		case i > start:
+			return 0, fmt.Errorf("%w: slot %d, len %d", ErrGap, i, start)
+		case i < start:
+			return 0, errAllocated
+		}
+		return 0, nil
+	}, false)
+	switch {
+	case err == errAllocated:
+		a.raw[i] = v
+		a.publish(i, i+1)
+		a.clearDead(i, i+1)
+		return nil
+	case errors.Is(err, ErrOutOfRange), errors.Is(err, ErrGap):
+		return err
+	case err != nil:
+		return a.allocErr(err, start, 1)
+	}
+	a.store(start, v)
+	return nil
+}
+
+// Restore replaces the arena's contents with a copy of values, which end up
+// in slots 0 to len(values)-1, published and committed, as if the arena had
+// been reset and the values appended. Like Reset it destroys the live
+// elements under WithDestructor, returning any Close errors once the arena
+// has been restored, clears tombstones and starts a new Epoch; slots above
+// the restored ones are zeroed. values may fill the arena's whole capacity,
+// regardless of a soft cap; more than that fails with ErrArenaFull and
+// leaves the arena alone. Restore needs a quiescent arena: it fails with
+// ErrNotQuiescent if writes are in flight or another exclusive operation is
+// running, with a *RefsError while WithRefCounting references are
+// outstanding, and with ErrFrozen on a frozen arena.
+func (a *AtomicArena[T]) Restore(values []T) error {
+	c := a.count.Load()
+	n, k := c&countMask, uintptr(len(values))
+	switch {
+	case c&frozenBit != 0:
+		return a.frozenErr()
+	case c&busyBit != 0 || a.done.Load() != n:
+		return ErrNotQuiescent
+	case k > a.maxElems.Load():
+		return a.allocErr(ErrArenaFull, 0, k)
+	}
+	if !a.count.CompareAndSwap(c, c|busyBit) {
+		return ErrNotQuiescent
+	}
+	if a.refs != nil {
+		if refs := sumStripes(a.refs); refs != 0 {
+			a.count.Store(c)
+			return &RefsError{Name: a.opts.name, Refs: refs}
+		}
+	}
+	var err error
+	if a.dtor != nil {
+		err = a.destroyLive(0, n)
+	}
+	a.clearTombstones(n)
+	a.unpoisonSlots(n, k)
+	copy(a.raw, values)
+	a.publish(0, k)
+	if a.ttl != nil {
+		a.ttl.stamp(0, k)
+	}
+	if k < n {
+		a.zeroRange(k, n)
+		a.poisonSlots(k, n)
+		a.countDropped(n, k)
+	} else {
+		a.crossed(k)
+	}
+	a.done.Store(k)
+	a.unpub.Store(0)
+	a.epoch.Add(1)
+	a.count.Store(k)
+	if a.lenc != nil {
+		a.lenc.refresh()
+	}
+	if a.waiters.wantAt.Load() != ^uintptr(0) {
+		a.wakeCommitted()
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// checkRestored fails t unless a holds exactly want, published and committed,
+// with every slot above it zeroed and unpublished.
+func checkRestored(t *testing.T, a *AtomicArena[int], want []int) {
+	t.Helper()
+	n := uintptr(len(want))
+	if a.Len() != n || a.Committed() != n {
+		t.Fatalf("expected %d slots allocated and committed, got %d and %d", n, a.Len(), a.Committed())
+	}
+	for i, v := range want {
+		p, ok := a.Get(uintptr(i))
+		if !ok || *p != v || a.ptrs[i].Load() != &a.raw[i] {
+			t.Fatalf("expected slot %d to hold %d and be published, got %v %v", i, v, p, ok)
+		}
+	}
+	inspectReleased(a)
+	for i := n; i < a.Cap(); i++ {
+		if a.raw[i] != 0 || a.ptrs[i].Load() != nil {
+			t.Fatalf("expected slot %d above the restored ones to be cleared, got %d", i, a.raw[i])
+		}
+	}
+}
+
+// TestRestore replaces the contents wholesale, shrinking and growing
+func TestRestore(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	a.AppendSlice([]int{1, 2, 3, 4, 5})
+	a.Tombstone(1)
+	if err := a.Restore([]int{7, 8}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	checkRestored(t, a, []int{7, 8})
+	if a.Epoch() != 1 {
+		t.Fatalf("expected Restore to start a new epoch, got %d", a.Epoch())
+	}
+	full := []int{10, 11, 12, 13, 14, 15, 16, 17}
+	if err := a.Restore(full); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	checkRestored(t, a, full)
+	if p, err := a.Alloc(9); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected a restored full arena to be full, got %v, %v", p, err)
+	}
+	if err := a.Restore(nil); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	checkRestored(t, a, nil)
+}
+
+// TestRestoreRefused leaves the arena alone when Restore cannot run
+func TestRestoreRefused(t *testing.T) {
+	a := NewAtomicArena[int](4)
+	a.AppendSlice([]int{1, 2})
+	var capErr *CapacityError
+	if err := a.Restore(make([]int, 5)); !errors.As(err, &capErr) || capErr.Requested != 5 || capErr.Capacity != 4 {
+		t.Fatalf("expected a *CapacityError for 5 of 4 slots, got %v", err)
+	}
+	// a reservation whose write has not completed
+	if _, err := a.reserve(1); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if err := a.Restore([]int{9}); !errors.Is(err, ErrNotQuiescent) {
+		t.Fatalf("expected ErrNotQuiescent, got %v", err)
+	}
+	a.commit(1)
+	a.Freeze()
+	if err := a.Restore([]int{9}); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+	if got := a.Snapshot(); !slices.Equal(got, []int{1, 2, 0}) {
+		t.Fatalf("expected the contents to be untouched, got %v", got)
+	}
+}
+
+// TestAllocAt appends at the first free slot and overwrites allocated ones
+func TestAllocAt(t *testing.T) {
+	a := NewAtomicArena[int](4, WithSoftCap(3))
+	for i := range 3 {
+		if err := a.AllocAt(uintptr(i), i+1); err != nil {
+			t.Fatalf("AllocAt(%d) failed: %v", i, err)
+		}
+	}
+	a.Tombstone(1)
+	if err := a.AllocAt(1, 20); err != nil {
+		t.Fatalf("AllocAt over a tombstone failed: %v", err)
+	}
+	checkRestored(t, a, []int{1, 20, 3})
+	if err := a.AllocAt(3, 4); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected the soft cap to refuse slot 3, got %v", err)
+	}
+	if err := a.AllocAt(4, 5); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange at the capacity, got %v", err)
+	}
+
+	b := NewAtomicArena[int](4)
+	b.Alloc(1)
+	if err := b.AllocAt(2, 3); !errors.Is(err, ErrGap) {
+		t.Fatalf("expected ErrGap past the first free slot, got %v", err)
+	}
+	if b.Len() != 1 {
+		t.Fatalf("expected a refused AllocAt to allocate nothing, got len %d", b.Len())
+	}
+	b.Freeze()
+	if err := b.AllocAt(0, 2); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+}
+
+// TestAllocAtRebuild rebuilds an arena slot by slot from another's contents
+func TestAllocAtRebuild(t *testing.T) {
+	src := NewAtomicArena[int](16)
+	src.AppendSlice([]int{5, 4, 3, 2, 1})
+	dst := NewAtomicArena[int](16)
+	// placing the values back to front first hits the gaps
+	vals := src.Snapshot()
+	for i := len(vals) - 1; i >= 0; i-- {
+		err := dst.AllocAt(uintptr(i), vals[i])
+		if (i != 0) != errors.Is(err, ErrGap) {
+			t.Fatalf("AllocAt(%d): expected a gap only above len 0, got %v", i, err)
+		}
+	}
+	for i, v := range vals {
+		if err := dst.AllocAt(uintptr(i), v); err != nil {
+			t.Fatalf("AllocAt(%d) failed: %v", i, err)
+		}
+	}
+	checkRestored(t, dst, vals)
+}
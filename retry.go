@@ -0,0 +1,60 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// ErrTimeout is wrapped, together with the last capacity error, by the error
+// AllocWithRetry returns when the arena stayed full for the whole wait.
+var ErrTimeout = errors.New("atomicarena: timed out waiting for capacity")
+
+// Backoff schedule for AllocWithRetry: retrySpins yields first, then sleeps
+// that start at retryMinSleep and double up to retryMaxSleep.
+const (
+	retrySpins    = 4
+	retryMinSleep = 10 * time.Microsecond
+	retryMaxSleep = time.Millisecond
+)
+
+// AllocWithRetry is Alloc for producers that can briefly outrun whoever
+// drains the arena: while the arena is full it retries, yielding a few times
+// and then sleeping with exponential backoff from 10µs up to 1ms, until
+// maxWait has elapsed. On the default Clock retrying allocates nothing. If
+// no slot frees up in time it returns an error wrapping both ErrTimeout and
+// the last *CapacityError, so errors.Is matches ErrTimeout and ErrArenaFull.
+// Other failures, such as ErrFrozen, are returned at once. A maxWait of zero
+// or less makes a single attempt. Time is measured on the arena's Clock.
+func (a *AtomicArena[T]) AllocWithRetry(obj T, maxWait time.Duration) (*T, error) {
+	clock := a.opts.timeSource()
+	var deadline time.Time
+	wait := retryMinSleep
+	for try := 0; ; try++ {
+		idx, err := a.reserve(1)
+		if err == nil {
+			p := a.store(idx, obj)
+			if a.prof != nil {
+				a.prof.sample(1)
+			}
+			return p, nil
+		}
+		if err != ErrArenaFull {
+			return nil, a.allocErr(err, idx, 1)
+		}
+		if try == 0 {
+			deadline = clock.Now().Add(maxWait)
+		}
+		left := deadline.Sub(clock.Now())
+		if left <= 0 {
+			return nil, fmt.Errorf("%w after %v: %w", ErrTimeout, maxWait, a.allocErr(err, idx, 1))
+		}
+		if try < retrySpins {
+			runtime.Gosched()
+			continue
+		}
+		sleep(clock, min(wait, left))
+		wait = min(2*wait, retryMaxSleep)
+	}
+}
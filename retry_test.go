@@ -0,0 +1,103 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stepClock is a single-goroutine virtual clock: each After advances it by
+// the requested duration, records it and returns an expired channel at once.
+type stepClock struct {
+	now    time.Time
+	sleeps []time.Duration
+	onWake func(n int) // called after each wait with the count so far
+	fired  chan time.Time
+}
+
+func newStepClock() *stepClock {
+	return &stepClock{now: time.Unix(0, 0), sleeps: make([]time.Duration, 0, 64), fired: make(chan time.Time, 1)}
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+	if c.onWake != nil {
+		c.onWake(len(c.sleeps))
+	}
+	c.fired <- c.now
+	return c.fired
+}
+
+func (c *stepClock) NewTicker(time.Duration) Ticker {
+	panic("stepClock has no tickers")
+}
+
+// TestAllocWithRetrySchedule times out after the expected backoff
+func TestAllocWithRetrySchedule(t *testing.T) {
+	f := newStepClock()
+	a := NewAtomicArena[int](1, WithName("retry"), WithClock(f))
+	a.Alloc(1)
+	_, err := a.AllocWithRetry(2, 300*time.Microsecond)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrTimeout and ErrArenaFull, got %v", err)
+	}
+	if ce := capacityErr(t, err); ce.Name != "retry" || ce.Available != 0 {
+		t.Fatalf("unexpected capacity error %+v", *ce)
+	}
+	µs := time.Microsecond
+	want := []time.Duration{10 * µs, 20 * µs, 40 * µs, 80 * µs, 150 * µs}
+	if !slices.Equal(f.sleeps, want) {
+		t.Fatalf("expected sleeps %v, got %v", want, f.sleeps)
+	}
+
+	// the sleeps are capped at a millisecond
+	f.sleeps = f.sleeps[:0]
+	a.AllocWithRetry(2, 5*time.Millisecond)
+	if len(f.sleeps) < 8 || f.sleeps[7] != time.Millisecond || slices.Max(f.sleeps) != time.Millisecond {
+		t.Fatalf("expected the backoff to level off at 1ms, got %v", f.sleeps)
+	}
+
+	f.sleeps = f.sleeps[:0]
+	if _, err := a.AllocWithRetry(2, 0); !errors.Is(err, ErrTimeout) || len(f.sleeps) != 0 {
+		t.Fatalf("expected a single attempt for maxWait 0, got %v after %v", err, f.sleeps)
+	}
+	a.Freeze()
+	if _, err := a.AllocWithRetry(2, time.Second); !errors.Is(err, ErrFrozen) || errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrFrozen at once, got %v", err)
+	}
+}
+
+// TestAllocWithRetrySucceeds gets a slot once capacity appears mid-wait,
+// without allocating on the retry path
+func TestAllocWithRetrySucceeds(t *testing.T) {
+	f := newStepClock()
+	a := NewAtomicArena[int](1, WithClock(f))
+	f.onWake = func(n int) {
+		if n == 3 {
+			// the flusher drained the arena
+			a.Reset(false)
+		}
+	}
+	a.Alloc(1)
+	p, err := a.AllocWithRetry(2, time.Second)
+	if err != nil || *p != 2 || len(f.sleeps) != 3 {
+		t.Fatalf("expected success after 3 sleeps, got %v after %v", err, f.sleeps)
+	}
+	if q, _ := a.Get(0); q != p || a.LoadPointer(0) != p {
+		t.Fatalf("expected the slot to be stored and published")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		f.sleeps = f.sleeps[:0]
+		if _, err := a.AllocWithRetry(3, time.Second); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected 0 allocations while retrying, got %v", allocs)
+	}
+}
@@ -0,0 +1,28 @@
+package atomicarena
+
+import "unsafe"
+
+// Under -asan and -msan the arena tells the sanitizer which slots are
+// released: Reset, Compact and the rollbacks poison the slots they give up,
+// and every reservation unpoisons its slots again, so reading a slot after
+// it was released is reported like a use after free. Without the sanitizers
+// sanitized is false and the hooks compile away.
+
+// poisonSlots marks slots [lo, hi) as released. Reservations must be held
+// off, or a concurrent reservation could lose its slots to the poison.
+func (a *AtomicArena[T]) poisonSlots(lo, hi uintptr) {
+	if sanitized && hi > lo {
+		if size := unsafe.Sizeof(a.raw[0]); size > 0 {
+			poison(unsafe.Pointer(&a.raw[lo]), (hi-lo)*size)
+		}
+	}
+}
+
+// unpoisonSlots marks slots [lo, hi) as allocated.
+func (a *AtomicArena[T]) unpoisonSlots(lo, hi uintptr) {
+	if sanitized && hi > lo {
+		if size := unsafe.Sizeof(a.raw[0]); size > 0 {
+			unpoison(unsafe.Pointer(&a.raw[lo]), (hi-lo)*size)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build asan
+
+package atomicarena
+
+/*
+#include <stdint.h>
+#include <sanitizer/asan_interface.h>
+
+static void arena_poison(uintptr_t p, size_t n) { __asan_poison_memory_region((void *)p, n); }
+static void arena_unpoison(uintptr_t p, size_t n) { __asan_unpoison_memory_region((void *)p, n); }
+*/
+import "C"
+
+import "unsafe"
+
+// sanitized reports whether released slots are poisoned.
+const sanitized = true
+
+// The addresses cross as integers: cgo would reject element types holding
+// Go pointers.
+
+func poison(p unsafe.Pointer, n uintptr) {
+	C.arena_poison(C.uintptr_t(uintptr(p)), C.size_t(n))
+}
+
+func unpoison(p unsafe.Pointer, n uintptr) {
+	C.arena_unpoison(C.uintptr_t(uintptr(p)), C.size_t(n))
+}
@@ -0,0 +1,34 @@
+//go:build msan
+
+package atomicarena
+
+/*
+#include <stddef.h>
+#include <stdint.h>
+
+// from sanitizer/msan_interface.h, which only ships with clang
+void __msan_poison(const volatile void *a, size_t size);
+void __msan_unpoison(const volatile void *a, size_t size);
+
+static void arena_poison(uintptr_t p, size_t n) { __msan_poison((const volatile void *)p, n); }
+static void arena_unpoison(uintptr_t p, size_t n) { __msan_unpoison((const volatile void *)p, n); }
+*/
+import "C"
+
+import "unsafe"
+
+// sanitized reports whether released slots are poisoned. MemorySanitizer
+// has no notion of unaddressable memory, so released slots read as
+// uninitialized instead.
+const sanitized = true
+
+// The addresses cross as integers: cgo would reject element types holding
+// Go pointers.
+
+func poison(p unsafe.Pointer, n uintptr) {
+	C.arena_poison(C.uintptr_t(uintptr(p)), C.size_t(n))
+}
+
+func unpoison(p unsafe.Pointer, n uintptr) {
+	C.arena_unpoison(C.uintptr_t(uintptr(p)), C.size_t(n))
+}
@@ -0,0 +1,41 @@
+package atomicarena
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestSanitizerReadAfterReset reads a slot released by Reset in a child process and expects the sanitizer to abort it
+func TestSanitizerReadAfterReset(t *testing.T) {
+	if !sanitized {
+		t.Skip("built without -asan or -msan")
+	}
+	if os.Getenv("ATOMICARENA_SANITIZER_CHILD") != "" {
+		a := NewAtomicArena[int64](16)
+		p, _ := a.Alloc(42)
+		q, _ := a.Alloc(43)
+		a.Reset(false)
+		if r, _ := a.Alloc(44); r != p || *r != 44 {
+			os.Exit(3)
+		}
+		println(*q)
+		os.Exit(0)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSanitizerReadAfterReset$")
+	cmd.Env = append(os.Environ(), "ATOMICARENA_SANITIZER_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the sanitizer to abort the read of a released slot, got:\n%s", out)
+	}
+	if !strings.Contains(string(out), "use-after-poison") && !strings.Contains(string(out), "use-of-uninitialized-value") {
+		t.Fatalf("expected a sanitizer report, got %v:\n%s", err, out)
+	}
+}
+
+// inspectReleased lifts the sanitizer poison from every slot of a, so a test
+// can check what Reset and the rollbacks left in released slots.
+func inspectReleased[T any](a *AtomicArena[T]) {
+	a.unpoisonSlots(0, uintptr(len(a.raw)))
+}
@@ -0,0 +1,177 @@
+package atomicarena
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+)
+
+// maxSchemaSize bounds the schema ReadArenaFrom accepts, so a corrupted
+// length can't force a huge allocation.
+const maxSchemaSize = 1 << 20
+
+// FieldLayout describes one field of a snapshot's element type: its path
+// from the element, such as "Pos.X" or "Tags[]", its offset and size in
+// bytes and its kind. The element itself has an empty name.
+type FieldLayout struct {
+	Name   string
+	Offset uintptr
+	Size   uintptr
+	Kind   reflect.Kind
+}
+
+func (f FieldLayout) String() string {
+	return fmt.Sprintf("%s at offset %d, size %d", f.Kind, f.Offset, f.Size)
+}
+
+// SchemaMismatchError is returned by ReadArenaFrom when the snapshot was
+// written for an element type whose layout differs from T. Snapshot and
+// Loaded hold the first differing field as recorded in the snapshot and as
+// found in T; one of them is nil if the field exists only on the other side.
+// It wraps ErrSnapshotFormat.
+type SchemaMismatchError struct {
+	Type     string // T, the type being loaded
+	Field    string // path of the first differing field; empty for the element itself
+	Snapshot *FieldLayout
+	Loaded   *FieldLayout
+}
+
+// Error renders the mismatch like `atomicarena: snapshot schema mismatch
+// for pkg.T: field "Pos.Y" is float64 at offset 8, size 8 in the snapshot
+// but int32 at offset 8, size 4 in pkg.T`.
+func (e *SchemaMismatchError) Error() string {
+	what := "element type"
+	if e.Field != "" {
+		what = "field " + strconv.Quote(e.Field)
+	}
+	switch {
+	case e.Snapshot == nil:
+		return fmt.Sprintf("atomicarena: snapshot schema mismatch for %s: %s is not in the snapshot", e.Type, what)
+	case e.Loaded == nil:
+		return fmt.Sprintf("atomicarena: snapshot schema mismatch for %s: %s is missing from %s", e.Type, what, e.Type)
+	}
+	return fmt.Sprintf("atomicarena: snapshot schema mismatch for %s: %s is %v in the snapshot but %v in %s", e.Type, what, e.Snapshot, e.Loaded, e.Type)
+}
+
+// Unwrap returns ErrSnapshotFormat, so errors.Is(err, ErrSnapshotFormat) holds.
+func (e *SchemaMismatchError) Unwrap() error { return ErrSnapshotFormat }
+
+// typeLayout flattens t into the fields a snapshot records: the element
+// itself, then every field of nested structs and arrays in memory order.
+// Array elements are described once, relative to the array, with a "[]"
+// suffix.
+func typeLayout(t reflect.Type) []FieldLayout {
+	var out []FieldLayout
+	var walk func(t reflect.Type, name string, off uintptr)
+	walk = func(t reflect.Type, name string, off uintptr) {
+		out = append(out, FieldLayout{Name: name, Offset: off, Size: t.Size(), Kind: t.Kind()})
+		switch t.Kind() {
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				path := f.Name
+				if name != "" {
+					path = name + "." + f.Name
+				}
+				walk(f.Type, path, off+f.Offset)
+			}
+		case reflect.Array:
+			if t.Len() > 0 {
+				walk(t.Elem(), name+"[]", off)
+			}
+		}
+	}
+	walk(t, "", 0)
+	return out
+}
+
+// marshalSchema encodes the alignment and layout of t.
+func marshalSchema(t reflect.Type) []byte {
+	b := binary.AppendUvarint(nil, uint64(t.Align()))
+	for _, f := range typeLayout(t) {
+		b = binary.AppendUvarint(b, uint64(len(f.Name)))
+		b = append(b, f.Name...)
+		b = binary.AppendUvarint(b, uint64(f.Offset))
+		b = binary.AppendUvarint(b, uint64(f.Size))
+		b = append(b, byte(f.Kind))
+	}
+	return b
+}
+
+// unmarshalSchema decodes a schema written by marshalSchema.
+func unmarshalSchema(b []byte) (align uint64, fields []FieldLayout, err error) {
+	bad := fmt.Errorf("%w: malformed schema", ErrSnapshotFormat)
+	uvarint := func() uint64 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			err = bad
+			return 0
+		}
+		b = b[n:]
+		return v
+	}
+	align = uvarint()
+	for err == nil && len(b) > 0 {
+		k := uvarint()
+		if err != nil || k > uint64(len(b)) {
+			return 0, nil, bad
+		}
+		f := FieldLayout{Name: string(b[:k])}
+		b = b[k:]
+		f.Offset, f.Size = uintptr(uvarint()), uintptr(uvarint())
+		if err != nil || len(b) == 0 {
+			return 0, nil, bad
+		}
+		f.Kind = reflect.Kind(b[0])
+		b = b[1:]
+		fields = append(fields, f)
+	}
+	if err != nil || len(fields) == 0 {
+		return 0, nil, bad
+	}
+	return align, fields, nil
+}
+
+// schemaFingerprint hashes an encoded schema for the snapshot header.
+func schemaFingerprint(schema []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(schema)
+	return h.Sum64()
+}
+
+// checkSchema compares a snapshot's schema with t and describes the first
+// difference, in the snapshot's field order. Fields are compared before the
+// element itself, so an added or resized field is named rather than only
+// the changed element size.
+func checkSchema(t reflect.Type, schema []byte) error {
+	align, got, err := unmarshalSchema(schema)
+	if err != nil {
+		return err
+	}
+	layout := typeLayout(t)
+	want := make(map[string]*FieldLayout, len(layout))
+	for i := range layout {
+		want[layout[i].Name] = &layout[i]
+	}
+	for i := 1; i < len(got); i++ {
+		w := want[got[i].Name]
+		if w == nil || *w != got[i] {
+			return &SchemaMismatchError{Type: t.String(), Field: got[i].Name, Snapshot: &got[i], Loaded: w}
+		}
+		delete(want, got[i].Name)
+	}
+	for i := 1; i < len(layout); i++ {
+		if want[layout[i].Name] != nil {
+			return &SchemaMismatchError{Type: t.String(), Field: layout[i].Name, Loaded: &layout[i]}
+		}
+	}
+	if got[0] != layout[0] {
+		return &SchemaMismatchError{Type: t.String(), Snapshot: &got[0], Loaded: &layout[0]}
+	}
+	if align != uint64(t.Align()) {
+		return fmt.Errorf("%w: snapshot alignment %d, %s has alignment %d", ErrSnapshotFormat, align, t, t.Align())
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package atomicarena
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNotQuiescent is returned by operations that need exclusive access to the
+// arena when writes are still in flight.
+var ErrNotQuiescent = errors.New("atomicarena: arena has in-flight writes")
+
+// prefixSorter adapts the first n slots of an arena to sort.Interface. A
+// swap moves each element's tombstone bit and WithTTL stamp along with it.
+type prefixSorter[T any] struct {
+	a    *AtomicArena[T]
+	n    int
+	less func(a, b *T) bool
+}
+
+func (s prefixSorter[T]) Len() int           { return s.n }
+func (s prefixSorter[T]) Less(i, j int) bool { return s.less(&s.a.raw[i], &s.a.raw[j]) }
+
+func (s prefixSorter[T]) Swap(i, j int) {
+	a := s.a
+	a.raw[i], a.raw[j] = a.raw[j], a.raw[i]
+	a.swapDead(uintptr(i), uintptr(j))
+	if a.ttl != nil {
+		ti, tj := a.ttl.at[i].Load(), a.ttl.at[j].Load()
+		a.ttl.at[i].Store(tj)
+		a.ttl.at[j].Store(ti)
+	}
+}
+
+// SortFunc sorts the allocated elements in place using less.
+// The arena must be frozen, or at least quiescent: it returns ErrNotQuiescent
+// if an allocation is still writing its slot. The caller must also ensure no
+// goroutine reads the arena while it is being sorted.
+// Sorting moves values between slots, so the pointer mirror is rebuilt:
+// afterwards every slot in the allocated prefix is published. Tombstoned and
+// expired elements are sorted too and stay dead in their new slots.
+func (a *AtomicArena[T]) SortFunc(less func(a, b *T) bool) error {
+	n := a.Len()
+	if !a.Frozen() && a.done.Load() != n {
+		return ErrNotQuiescent
+	}
+	sort.Sort(prefixSorter[T]{a: a, n: int(n), less: less})
+	for i := uintptr(0); i < n && a.ptrs != nil; i++ {
+		a.ptrs[i].Store(&a.raw[i])
+	}
+	return nil
+}
+
+// SearchFunc binary-searches the allocated elements, which must be sorted so
+// that pred is false for some prefix and true for the rest. It returns the
+// index of the first live element for which pred is true, skipping
+// tombstoned and expired ones, and false if there is none.
+func (a *AtomicArena[T]) SearchFunc(pred func(*T) bool) (uintptr, bool) {
+	n := a.Len()
+	i := uintptr(sort.Search(int(n), func(i int) bool { return pred(&a.raw[i]) }))
+	for i < n && a.gone(i) {
+		i++
+	}
+	return i, i < n
+}
+
+// Find returns the first live allocated element for which pred is true,
+// skipping tombstoned and expired ones.
+func (a *AtomicArena[T]) Find(pred func(*T) bool) (*T, bool) {
+	n := a.Len()
+	for i := uintptr(0); i < n; i++ {
+		if !a.gone(i) && pred(&a.raw[i]) {
+			return &a.raw[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,109 @@
+package atomicarena
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestSortAndSearch sorts a million random elements and checks the results
+// against the standard library.
+func TestSortAndSearch(t *testing.T) {
+	const n = 1 << 20
+	rng := rand.New(rand.NewSource(42))
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = rng.Intn(n * 4)
+	}
+	arena := NewAtomicArena[int](n)
+	if _, err := arena.AppendSlice(vals); err != nil {
+		t.Fatalf("AppendSlice failed: %v", err)
+	}
+	arena.Freeze()
+	if err := arena.SortFunc(func(a, b *int) bool { return *a < *b }); err != nil {
+		t.Fatalf("SortFunc failed: %v", err)
+	}
+
+	sort.Ints(vals)
+	for i, v := range vals {
+		if arena.raw[i] != v {
+			t.Fatalf("index %d: expected %d, got %d", i, v, arena.raw[i])
+		}
+		if arena.ptrs[i].Load() != &arena.raw[i] {
+			t.Fatalf("index %d: pointer mirror not rebuilt", i)
+		}
+	}
+
+	for k := 0; k < 1000; k++ {
+		target := rng.Intn(n * 4)
+		want := sort.SearchInts(vals, target)
+		got, ok := arena.SearchFunc(func(v *int) bool { return *v >= target })
+		if int(got) != want || ok != (want < n) {
+			t.Fatalf("SearchFunc(%d): expected %d, got %d (ok=%v)", target, want, got, ok)
+		}
+	}
+}
+
+// TestSortFuncNotQuiescent ensures sorting is refused while a write is in flight
+func TestSortFuncNotQuiescent(t *testing.T) {
+	arena := NewAtomicArena[int](4)
+	_, _ = arena.AppendSlice([]int{3, 1, 2})
+	// simulate a reservation whose write has not completed
+	if _, err := arena.reserve(1); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if err := arena.SortFunc(func(a, b *int) bool { return *a < *b }); err != ErrNotQuiescent {
+		t.Fatalf("expected ErrNotQuiescent, got %v", err)
+	}
+}
+
+// TestFind ensures Find returns the first matching element
+func TestFind(t *testing.T) {
+	arena := NewAtomicArena[int](5)
+	_, _ = arena.AppendSlice([]int{5, 8, 13, 8})
+	p, ok := arena.Find(func(v *int) bool { return *v == 8 })
+	if !ok || p != &arena.raw[1] {
+		t.Fatalf("expected pointer to index 1, got %v, %v", p, ok)
+	}
+	if _, ok := arena.Find(func(v *int) bool { return *v == 99 }); ok {
+		t.Fatal("expected no match")
+	}
+}
+
+// TestSortFuncTombstones keeps tombstones and TTL stamps with their elements
+func TestSortFuncTombstones(t *testing.T) {
+	c := newStepClock()
+	arena := NewAtomicArena[int](8, WithTTL(time.Minute), WithClock(c))
+	_, _ = arena.AppendSlice([]int{3, 1})
+	c.now = c.now.Add(30 * time.Second)
+	_, _ = arena.AppendSlice([]int{2, 4})
+	if err := arena.Tombstone(0); err != nil {
+		t.Fatalf("Tombstone failed: %v", err)
+	}
+	if err := arena.SortFunc(func(a, b *int) bool { return *a < *b }); err != nil {
+		t.Fatalf("SortFunc failed: %v", err)
+	}
+	var live []int
+	arena.Range(func(_ uintptr, v *int) bool { live = append(live, *v); return true })
+	if len(live) != 3 || live[0] != 1 || live[1] != 2 || live[2] != 4 {
+		t.Fatalf("expected live values [1 2 4], got %v", live)
+	}
+	if i, ok := arena.SearchFunc(func(v *int) bool { return *v >= 3 }); !ok || arena.raw[i] != 4 {
+		t.Fatalf("expected SearchFunc to skip the tombstoned 3, got %d (ok=%v)", i, ok)
+	}
+	if _, ok := arena.Find(func(v *int) bool { return *v == 3 }); ok {
+		t.Fatal("expected Find to skip the tombstoned 3")
+	}
+	// 1 was stamped first, so it expires first wherever it was sorted to
+	c.now = c.now.Add(45 * time.Second)
+	if _, ok := arena.Find(func(v *int) bool { return *v == 1 }); ok {
+		t.Fatal("expected Find to skip the expired 1")
+	}
+	if p, ok := arena.Find(func(v *int) bool { return *v == 2 }); !ok || *p != 2 {
+		t.Fatalf("expected Find to return 2, got %v, %v", p, ok)
+	}
+	if i, ok := arena.SearchFunc(func(v *int) bool { return *v >= 0 }); !ok || arena.raw[i] != 2 {
+		t.Fatalf("expected SearchFunc to skip the expired 1, got %d (ok=%v)", i, ok)
+	}
+}
@@ -0,0 +1,104 @@
+package atomicarena
+
+// Allocator is the method set shared by AtomicArena and Arena, so library
+// code can accept either the concurrent or the single-goroutine arena.
+type Allocator[T any] interface {
+	Alloc(obj T) (*T, error)
+	Reserve(n uintptr) ([]T, error)
+	AppendSlice(objs []T) ([]T, error)
+	Reset(release bool) error
+	Free() error
+	Get(i uintptr) (*T, bool)
+	Len() uintptr
+	Cap() uintptr
+}
+
+var (
+	_ Allocator[int] = (*AtomicArena[int])(nil)
+	_ Allocator[int] = (*Arena[int])(nil)
+)
+
+// Arena is a fixed-capacity arena with plain integer bookkeeping for hot
+// loops that allocate from a single goroutine.
+//
+// Arena is not safe for concurrent use: every method, including Get and Len,
+// must be called from one goroutine at a time, or under external
+// synchronization. Use AtomicArena when goroutines share the arena.
+type Arena[T any] struct {
+	raw []T
+	n   uintptr
+}
+
+// NewArena creates an Arena that can hold up to maxElems elements of type T.
+func NewArena[T any](maxElems uintptr) *Arena[T] {
+	return &Arena[T]{raw: make([]T, maxElems)}
+}
+
+// Alloc stores obj in the next free slot and returns a pointer to it.
+func (a *Arena[T]) Alloc(obj T) (*T, error) {
+	if a.n == uintptr(len(a.raw)) {
+		return nil, a.capacityError(1)
+	}
+	p := &a.raw[a.n]
+	*p = obj
+	a.n++
+	return p, nil
+}
+
+func (a *Arena[T]) capacityError(n uintptr) error {
+	return &CapacityError{Requested: n, Available: uintptr(len(a.raw)) - a.n, Capacity: uintptr(len(a.raw))}
+}
+
+// Reserve claims n slots and returns them as a slice the caller may write into.
+func (a *Arena[T]) Reserve(n uintptr) ([]T, error) {
+	if n > uintptr(len(a.raw))-a.n {
+		return nil, a.capacityError(n)
+	}
+	seg := a.raw[a.n : a.n+n]
+	a.n += n
+	return seg, nil
+}
+
+// AppendSlice copies objs into newly reserved slots and returns the segment.
+func (a *Arena[T]) AppendSlice(objs []T) ([]T, error) {
+	seg, err := a.Reserve(uintptr(len(objs)))
+	if err != nil {
+		return nil, err
+	}
+	copy(seg, objs)
+	return seg, nil
+}
+
+// Reset rewinds the arena so its slots can be reused, zeroing them first if
+// release is set. It never fails; the error is there to satisfy Allocator.
+func (a *Arena[T]) Reset(release bool) error {
+	if release {
+		clear(a.raw[:a.n])
+	}
+	a.n = 0
+	return nil
+}
+
+// Free zeroes the allocated storage without rewinding the arena.
+func (a *Arena[T]) Free() error {
+	clear(a.raw[:a.n])
+	return nil
+}
+
+// Get returns a pointer to the element at index i, or false if i has not been allocated.
+func (a *Arena[T]) Get(i uintptr) (*T, bool) {
+	if i >= a.n {
+		return nil, false
+	}
+	return &a.raw[i], true
+}
+
+// Len returns the number of slots currently allocated in the arena.
+func (a *Arena[T]) Len() uintptr {
+	return a.n
+}
+
+// Cap returns the maximum number of elements the arena can hold.
+func (a *Arena[T]) Cap() uintptr {
+	return uintptr(len(a.raw))
+}
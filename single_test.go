@@ -0,0 +1,95 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+// allocators lists every Allocator implementation run through the shared suite
+var allocators = []struct {
+	name string
+	new  func(n uintptr) Allocator[int]
+}{
+	{"AtomicArena", func(n uintptr) Allocator[int] { return NewAtomicArena[int](n) }},
+	{"Arena", func(n uintptr) Allocator[int] { return NewArena[int](n) }},
+	{"MutexArena", func(n uintptr) Allocator[int] { return NewMutexArena[int](n) }},
+}
+
+// TestAllocatorSuite runs the same behavioral checks against each implementation
+func TestAllocatorSuite(t *testing.T) {
+	for _, impl := range allocators {
+		t.Run(impl.name, func(t *testing.T) {
+			a := impl.new(6)
+			if a.Cap() != 6 || a.Len() != 0 {
+				t.Fatalf("unexpected cap/len %d/%d", a.Cap(), a.Len())
+			}
+			p, err := a.Alloc(1)
+			if err != nil || *p != 1 {
+				t.Fatalf("Alloc failed: %v", err)
+			}
+			seg, err := a.Reserve(2)
+			if err != nil || len(seg) != 2 {
+				t.Fatalf("Reserve failed: %v", err)
+			}
+			seg[0], seg[1] = 2, 3
+			if _, err := a.AppendSlice([]int{4, 5, 6}); err != nil {
+				t.Fatalf("AppendSlice failed: %v", err)
+			}
+			for i := uintptr(0); i < 6; i++ {
+				if v, ok := a.Get(i); !ok || *v != int(i)+1 {
+					t.Fatalf("Get(%d) = %v, %v", i, v, ok)
+				}
+			}
+			if _, ok := a.Get(6); ok {
+				t.Fatal("Get past Len succeeded")
+			}
+			if _, err := a.Alloc(7); err == nil {
+				t.Fatal("expected Alloc to fail on a full arena")
+			}
+			if _, err := a.Reserve(1); !errors.Is(err, ErrArenaFull) {
+				t.Fatalf("expected ErrArenaFull, got %v", err)
+			}
+			if err := a.Free(); err != nil || a.Len() != 6 {
+				t.Fatalf("Free: %v, len %d", err, a.Len())
+			}
+			if v, _ := a.Get(0); *v != 0 {
+				t.Fatalf("expected zeroed slot after Free, got %d", *v)
+			}
+			if err := a.Reset(false); err != nil || a.Len() != 0 {
+				t.Fatalf("Reset: %v, len %d", err, a.Len())
+			}
+			if _, err := a.AppendSlice([]int{9}); err != nil {
+				t.Fatalf("AppendSlice after Reset failed: %v", err)
+			}
+			if err := a.Reset(true); err != nil {
+				t.Fatal(err)
+			}
+			if seg, _ := a.Reserve(1); seg[0] != 0 {
+				t.Fatalf("expected released slot to be zeroed, got %d", seg[0])
+			}
+		})
+	}
+}
+
+// BenchmarkArenaAlloc compares Alloc on Arena and AtomicArena at the BenchmarkAlloc sizes
+func BenchmarkArenaAlloc(b *testing.B) {
+	for _, impl := range allocators {
+		for _, s := range benchSizes {
+			b.Run(impl.name+"/"+s.name, func(b *testing.B) {
+				pointerSize := unsafe.Sizeof(atomic.Pointer[int]{})
+				a := impl.new(s.totalBytes / pointerSize)
+
+				b.ResetTimer()
+				for i := 0; i < b.N; {
+					if _, err := a.Alloc(i); err != nil {
+						a.Reset(true)
+						continue
+					}
+					i++
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package atomicarena
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// slotBytes is the storage one slot costs: the element plus its pointer mirror.
+// Arenas of a zero-sized T never carry a mirror, so their slots cost nothing.
+func slotBytes[T any]() uintptr {
+	if zeroSized[T]() {
+		return 0
+	}
+	var zero T
+	return unsafe.Sizeof(zero) + unsafe.Sizeof(atomic.Pointer[T]{})
+}
+
+// zeroSized reports whether T occupies no memory, like struct{} or [0]int.
+func zeroSized[T any]() bool {
+	var zero T
+	return unsafe.Sizeof(zero) == 0
+}
+
+// NewArenaForBytes creates an arena holding as many elements of type T as fit
+// in maxBytes, counting both the element storage and its pointer mirror.
+// The tombstone bitmap (one bit per slot) is not charged against maxBytes,
+// except for a zero-sized T: its slots occupy no storage, so the bitmap is
+// the only cost and the capacity is maxBytes*8.
+func NewArenaForBytes[T any](maxBytes uintptr, opts ...Option) *AtomicArena[T] {
+	per := slotBytes[T]()
+	if per == 0 {
+		return NewAtomicArena[T](min(maxBytes, countMask/8)*8, opts...)
+	}
+	return NewAtomicArena[T](maxBytes/per, opts...)
+}
+
+// SizeBytes reports the arena's total memory footprint: element storage,
+// pointer mirror, tombstone bitmap, WithTTL stamps and the arena header
+// itself.
+func (a *AtomicArena[T]) SizeBytes() uintptr {
+	var zero T
+	size := uintptr(cap(a.raw))*unsafe.Sizeof(zero) +
+		uintptr(cap(a.ptrs))*unsafe.Sizeof(atomic.Pointer[T]{}) +
+		uintptr(cap(a.dead))*unsafe.Sizeof(atomic.Uint64{}) +
+		unsafe.Sizeof(*a)
+	if a.ttl != nil {
+		size += uintptr(cap(a.ttl.at))*unsafe.Sizeof(atomic.Uint32{}) + unsafe.Sizeof(*a.ttl)
+	}
+	return size
+}
+
+// alignedSlice returns a zeroed slice of n elements whose first element sits
+// on an align-byte boundary. It over-allocates and skips the k leading
+// elements that bring the address onto the boundary: k*size must make up
+// the base's misalignment, so at most align/gcd(size, align) extra elements
+// are needed. That fails only when the runtime places a small pointerful
+// object behind an allocation header that shifts it off the gcd; the retry
+// then allocates past the small-object limit, where objects are page-aligned.
+func alignedSlice[T any](n, align uintptr) ([]T, error) {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if align <= 1 || size == 0 {
+		return make([]T, n), nil
+	}
+	extra := align / gcd(size, align)
+	for _, length := range []uintptr{n + extra, max(n+extra, maxSmallAlloc/size+1)} {
+		full := make([]T, length)
+		base := uintptr(unsafe.Pointer(unsafe.SliceData(full)))
+		for k := uintptr(0); k <= extra; k++ {
+			if (base+k*size)%align == 0 {
+				return full[k : k+n : k+n], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: cannot align %s to %d bytes", ErrInvalidOptions, reflect.TypeFor[T](), align)
+}
+
+// maxSmallAlloc is the runtime's largest size-classed allocation; larger
+// objects get pages of their own.
+const maxSmallAlloc = 32 << 10
+
+func gcd(a, b uintptr) uintptr {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
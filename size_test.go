@@ -0,0 +1,52 @@
+package atomicarena
+
+import (
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+type padded struct {
+	A byte
+	B int64
+	C byte
+}
+
+// TestNewArenaForBytes checks the computed capacity against unsafe.Sizeof
+func TestNewArenaForBytes(t *testing.T) {
+	const budget = 64 << 20
+	ptr := unsafe.Sizeof(atomic.Pointer[int]{})
+	check := func(name string, got, elem uintptr) {
+		t.Helper()
+		if want := budget / (elem + ptr); got != want {
+			t.Errorf("%s: expected cap %d, got %d", name, want, got)
+		}
+	}
+	check("byte", NewArenaForBytes[byte](budget).Cap(), unsafe.Sizeof(byte(0)))
+	check("int64", NewArenaForBytes[int64](budget).Cap(), unsafe.Sizeof(int64(0)))
+	check("padded", NewArenaForBytes[padded](budget).Cap(), unsafe.Sizeof(padded{}))
+	check("[3]byte", NewArenaForBytes[[3]byte](budget).Cap(), unsafe.Sizeof([3]byte{}))
+	if c := NewArenaForBytes[struct{}](budget).Cap(); c != budget*8 {
+		t.Errorf("struct{}: expected cap %d, got %d", budget*8, c)
+	}
+
+	if unsafe.Sizeof(padded{}) <= 10 {
+		t.Fatalf("expected padded to carry padding, got %d bytes", unsafe.Sizeof(padded{}))
+	}
+	if c := NewArenaForBytes[padded](ptr).Cap(); c != 0 {
+		t.Fatalf("expected zero capacity for a budget below one slot, got %d", c)
+	}
+}
+
+// TestSizeBytes checks that the footprint covers all of the arena's storage
+func TestSizeBytes(t *testing.T) {
+	a := NewArenaForBytes[padded](1 << 20)
+	slots := a.Cap() * (unsafe.Sizeof(padded{}) + unsafe.Sizeof(atomic.Pointer[padded]{}))
+	got := a.SizeBytes()
+	if got < slots || got > slots+a.Cap()/8+8+unsafe.Sizeof(*a) {
+		t.Fatalf("unexpected footprint %d for %d bytes of slots", got, slots)
+	}
+	if slots > 1<<20 {
+		t.Fatalf("slots exceed budget: %d", slots)
+	}
+}
@@ -0,0 +1,269 @@
+package atomicarena
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+var (
+	// ErrPointerType is returned by operations that only support pointer-free element types.
+	ErrPointerType = errors.New("atomicarena: element type contains pointers")
+	// ErrSnapshotFormat is returned by ReadArenaFrom for malformed or mismatched snapshots.
+	ErrSnapshotFormat = errors.New("atomicarena: invalid snapshot")
+)
+
+const (
+	snapshotMagic = "AARN"
+	// snapshotVersion 2 follows the header with the element type's schema;
+	// version 1 snapshots, fingerprinted by type name, are still read.
+	snapshotVersion = 2
+	// header: magic, version, flags, element size, count, type fingerprint,
+	// then in version 2 the schema length and the schema
+	snapshotHeaderSize = 4 + 2 + 2 + 8 + 8 + 8
+	// snapshotChunk is the number of elements ReadArenaFrom reads at a time.
+	snapshotChunk = 4096
+	// snapshotBlock is the number of payload bytes WriteSnapshot writes at a time.
+	snapshotBlock = 64 << 10
+)
+
+type snapshotHeader struct {
+	version     uint16
+	flags       uint16
+	elemSize    uint64
+	count       uint64
+	fingerprint uint64
+}
+
+func (h *snapshotHeader) marshal() []byte {
+	b := make([]byte, snapshotHeaderSize)
+	copy(b, snapshotMagic)
+	binary.LittleEndian.PutUint16(b[4:], h.version)
+	binary.LittleEndian.PutUint16(b[6:], h.flags)
+	binary.LittleEndian.PutUint64(b[8:], h.elemSize)
+	binary.LittleEndian.PutUint64(b[16:], h.count)
+	binary.LittleEndian.PutUint64(b[24:], h.fingerprint)
+	return b
+}
+
+func (h *snapshotHeader) unmarshal(b []byte) error {
+	if string(b[:4]) != snapshotMagic {
+		return fmt.Errorf("%w: bad magic %q", ErrSnapshotFormat, b[:4])
+	}
+	h.version = binary.LittleEndian.Uint16(b[4:])
+	h.flags = binary.LittleEndian.Uint16(b[6:])
+	h.elemSize = binary.LittleEndian.Uint64(b[8:])
+	h.count = binary.LittleEndian.Uint64(b[16:])
+	h.fingerprint = binary.LittleEndian.Uint64(b[24:])
+	if h.version != 1 && h.version != snapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrSnapshotFormat, h.version)
+	}
+	return nil
+}
+
+// typeFingerprint identifies the element type recorded in a version 1 snapshot.
+func typeFingerprint(t reflect.Type) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%d/%d", t.String(), t.Size(), t.Align())
+	return h.Sum64()
+}
+
+// elemBytes returns the first n elements of raw viewed as bytes.
+func elemBytes[T any](raw []T, n uintptr) []byte {
+	if n == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&raw[0])), n*unsafe.Sizeof(raw[0]))
+}
+
+// WriteTo writes a binary snapshot of the allocated elements to w: a versioned
+// header (magic, element size, count, type fingerprint), the layout of T's
+// fields, and then the raw element bytes in native byte order. T must be
+// pointer-free. WriteTo implements io.WriterTo; it is WriteSnapshot with
+// the zero SnapshotOptions.
+func (a *AtomicArena[T]) WriteTo(w io.Writer) (int64, error) {
+	return a.WriteSnapshot(w, SnapshotOptions{})
+}
+
+// WriteSnapshot is WriteTo with the element bytes optionally compressed and
+// followed by a checksum; the header records both choices. The payload is
+// streamed in fixed-size blocks, so memory use does not grow with the arena.
+func (a *AtomicArena[T]) WriteSnapshot(w io.Writer, opts SnapshotOptions) (int64, error) {
+	t := reflect.TypeFor[T]()
+	if a.pointers {
+		return 0, fmt.Errorf("%w: cannot snapshot %s", ErrPointerType, t)
+	}
+	if _, err := snapshotOptionsFrom(opts.flags()); err != nil {
+		return 0, err
+	}
+	var payload io.WriteCloser
+	cw := &countingWriter{w: w}
+	if opts.Compress == CompressNone {
+		payload = nopWriteCloser{cw}
+	} else {
+		codec, err := codecFor(opts.Compress)
+		if err != nil {
+			return 0, err
+		}
+		if payload, err = codec.writer(cw); err != nil {
+			return 0, err
+		}
+	}
+	n := a.Len()
+	schema := marshalSchema(t)
+	hdr := snapshotHeader{
+		version:     snapshotVersion,
+		flags:       opts.flags(),
+		elemSize:    uint64(t.Size()),
+		count:       uint64(n),
+		fingerprint: schemaFingerprint(schema),
+	}
+	b := binary.LittleEndian.AppendUint32(hdr.marshal(), uint32(len(schema)))
+	if _, err := cw.Write(append(b, schema...)); err != nil {
+		return cw.n, err
+	}
+	sum := opts.newHash()
+	data := elemBytes(a.raw, n)
+	for len(data) > 0 {
+		block := data[:min(len(data), snapshotBlock)]
+		if _, err := payload.Write(block); err != nil {
+			return cw.n, err
+		}
+		if sum != nil {
+			sum.Write(block)
+		}
+		data = data[len(block):]
+	}
+	if sum != nil {
+		if _, err := payload.Write(sum.Sum(nil)); err != nil {
+			return cw.n, err
+		}
+	}
+	err := payload.Close()
+	return cw.n, err
+}
+
+// ReadArenaFrom reads a snapshot written by WriteTo and returns an arena whose
+// capacity equals the number of stored elements, with every pointer republished.
+// It fails with a *SchemaMismatchError naming the first differing field if
+// the snapshot was written for a type with a different layout, and with
+// ErrSnapshotFormat if the snapshot is malformed. Compressed and
+// checksummed snapshots are decoded as their header describes; a payload
+// that fails its checksum is rejected with ErrChecksum. Decompressors may
+// read past the end of a compressed snapshot unless r is an io.ByteReader.
+func ReadArenaFrom[T any](r io.Reader) (*AtomicArena[T], error) {
+	return readArenaFrom[T](r, true)
+}
+
+// ReadArenaFromUnchecked is ReadArenaFrom without the schema check: a
+// snapshot of any type with T's size is loaded, reinterpreting its bytes as
+// T. It is meant for callers that migrate layouts themselves.
+func ReadArenaFromUnchecked[T any](r io.Reader) (*AtomicArena[T], error) {
+	return readArenaFrom[T](r, false)
+}
+
+func readArenaFrom[T any](r io.Reader, checked bool) (*AtomicArena[T], error) {
+	t := reflect.TypeFor[T]()
+	if hasPointers[T]() {
+		return nil, fmt.Errorf("%w: cannot load %s", ErrPointerType, t)
+	}
+	buf := make([]byte, snapshotHeaderSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("%w: reading header: %v", ErrSnapshotFormat, err)
+	}
+	var hdr snapshotHeader
+	if err := hdr.unmarshal(buf); err != nil {
+		return nil, err
+	}
+	if hdr.version == 1 {
+		if checked && hdr.fingerprint != typeFingerprint(t) {
+			return nil, fmt.Errorf("%w: snapshot was written for a different type than %s", ErrSnapshotFormat, t)
+		}
+	} else if err := readSchema(r, t, hdr.fingerprint, checked); err != nil {
+		return nil, err
+	}
+	if hdr.elemSize != uint64(t.Size()) {
+		return nil, fmt.Errorf("%w: element size %d, %s has size %d", ErrSnapshotFormat, hdr.elemSize, t, t.Size())
+	}
+	if t.Size() > 0 && hdr.count > uint64(^uintptr(0)/t.Size()) {
+		return nil, fmt.Errorf("%w: element count %d too large", ErrSnapshotFormat, hdr.count)
+	}
+	opts, err := snapshotOptionsFrom(hdr.flags)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Compress != CompressNone {
+		codec, err := codecFor(opts.Compress)
+		if err != nil {
+			return nil, err
+		}
+		zr, err := codec.reader(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: opening payload: %v", ErrSnapshotFormat, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	payload := r
+	sum := opts.newHash()
+	if sum != nil {
+		payload = io.TeeReader(r, sum)
+	}
+	n := uintptr(hdr.count)
+	// read in bounded chunks so a corrupted count can't force a huge allocation
+	// before the payload proves it exists
+	vals := make([]T, 0, min(n, snapshotChunk))
+	for uintptr(len(vals)) < n {
+		k := min(n-uintptr(len(vals)), snapshotChunk)
+		vals = slices.Grow(vals, int(k))
+		chunk := vals[len(vals) : len(vals)+int(k)]
+		if _, err := io.ReadFull(payload, elemBytes(chunk, k)); err != nil {
+			return nil, fmt.Errorf("%w: reading elements: %v", ErrSnapshotFormat, err)
+		}
+		vals = vals[:len(vals)+int(k)]
+	}
+	if sum != nil {
+		var want [4]byte
+		if _, err := io.ReadFull(r, want[:]); err != nil {
+			return nil, fmt.Errorf("%w: reading checksum: %v", ErrSnapshotFormat, err)
+		}
+		if got := sum.Sum(nil); string(got) != string(want[:]) {
+			return nil, fmt.Errorf("%w: payload crc32c %x, recorded %x", ErrChecksum, got, want)
+		}
+	}
+	a := NewAtomicArena[T](n)
+	copy(a.raw, vals)
+	a.publish(0, n)
+	a.count.Store(n)
+	a.done.Store(n)
+	return a, nil
+}
+
+// readSchema reads the schema that follows a version 2 header, verifies it
+// against the header's fingerprint and, if checked, compares it with t.
+func readSchema(r io.Reader, t reflect.Type, fingerprint uint64, checked bool) error {
+	var lb [4]byte
+	if _, err := io.ReadFull(r, lb[:]); err != nil {
+		return fmt.Errorf("%w: reading schema: %v", ErrSnapshotFormat, err)
+	}
+	size := binary.LittleEndian.Uint32(lb[:])
+	if size > maxSchemaSize {
+		return fmt.Errorf("%w: schema of %d bytes too large", ErrSnapshotFormat, size)
+	}
+	schema := make([]byte, size)
+	if _, err := io.ReadFull(r, schema); err != nil {
+		return fmt.Errorf("%w: reading schema: %v", ErrSnapshotFormat, err)
+	}
+	if schemaFingerprint(schema) != fingerprint {
+		return fmt.Errorf("%w: schema does not match its fingerprint", ErrSnapshotFormat)
+	}
+	if !checked {
+		return nil
+	}
+	return checkSchema(t, schema)
+}
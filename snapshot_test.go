@@ -0,0 +1,333 @@
+package atomicarena
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+type snapPoint struct{ X, Y float64 }
+
+type snapPadded struct {
+	A byte
+	B int64
+	C [3]uint16
+}
+
+type snapNested struct {
+	ID    uint32
+	Point snapPoint
+	Flags [4]bool
+}
+
+func roundTrip[T comparable](t *testing.T, vals []T) {
+	t.Helper()
+	arena := NewAtomicArena[T](uintptr(len(vals)) + 3)
+	if _, err := arena.AppendSlice(vals); err != nil {
+		t.Fatalf("AppendSlice failed: %v", err)
+	}
+	var buf bytes.Buffer
+	n, err := arena.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo reported %d bytes, wrote %d", n, buf.Len())
+	}
+	got, err := ReadArenaFrom[T](&buf)
+	if err != nil {
+		t.Fatalf("ReadArenaFrom failed: %v", err)
+	}
+	if got.Len() != uintptr(len(vals)) {
+		t.Fatalf("expected %d elements, got %d", len(vals), got.Len())
+	}
+	for i, v := range vals {
+		if got.raw[i] != v {
+			t.Fatalf("index %d: expected %v, got %v", i, v, got.raw[i])
+		}
+		if got.ptrs != nil && got.ptrs[i].Load() != &got.raw[i] {
+			t.Fatalf("index %d: pointer not republished", i)
+		}
+	}
+}
+
+// TestSnapshotRoundTrip round-trips several struct shapes
+func TestSnapshotRoundTrip(t *testing.T) {
+	roundTrip(t, []int32{1, -2, 3})
+	roundTrip(t, []snapPoint{{1, 2}, {3, 4}})
+	roundTrip(t, []snapPadded{{1, 2, [3]uint16{3, 4, 5}}, {6, 7, [3]uint16{8, 9, 10}}})
+	roundTrip(t, []snapNested{{1, snapPoint{2, 3}, [4]bool{true, false, true, false}}})
+	roundTrip(t, []uint64{})
+	// zero-sized elements have no pointer mirror to republish
+	roundTrip(t, make([]struct{}, 5))
+	large := make([]uint16, 3*snapshotChunk+17)
+	for i := range large {
+		large[i] = uint16(i)
+	}
+	roundTrip(t, large)
+}
+
+// TestSnapshotPointerType ensures pointer-containing types are rejected
+func TestSnapshotPointerType(t *testing.T) {
+	if _, err := NewAtomicArena[string](1).WriteTo(&bytes.Buffer{}); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("expected ErrPointerType, got %v", err)
+	}
+	if _, err := ReadArenaFrom[*int](&bytes.Buffer{}); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("expected ErrPointerType, got %v", err)
+	}
+}
+
+// TestSnapshotCorruptHeader ensures malformed headers error out
+func TestSnapshotCorruptHeader(t *testing.T) {
+	arena := NewAtomicArena[snapPoint](2)
+	_, _ = arena.AppendSlice([]snapPoint{{1, 2}, {3, 4}})
+	var buf bytes.Buffer
+	_, _ = arena.WriteTo(&buf)
+	good := buf.Bytes()
+
+	corrupt := func(f func(b []byte) []byte) error {
+		b := f(append([]byte(nil), good...))
+		_, err := ReadArenaFrom[snapPoint](bytes.NewReader(b))
+		return err
+	}
+	cases := map[string]func(b []byte) []byte{
+		"magic":    func(b []byte) []byte { b[0] = 'X'; return b },
+		"version":  func(b []byte) []byte { b[4] = 99; return b },
+		"elemsize": func(b []byte) []byte { binary.LittleEndian.PutUint64(b[8:], 8); return b },
+		"count":    func(b []byte) []byte { binary.LittleEndian.PutUint64(b[16:], 1<<40); return b },
+		"type":     func(b []byte) []byte { b[24] ^= 0xff; return b },
+		"short":    func(b []byte) []byte { return b[:len(b)-1] },
+		"header":   func(b []byte) []byte { return b[:10] },
+	}
+	for name, f := range cases {
+		if err := corrupt(f); !errors.Is(err, ErrSnapshotFormat) {
+			t.Errorf("%s: expected ErrSnapshotFormat, got %v", name, err)
+		}
+	}
+
+	// a snapshot of a same-sized but different type is rejected
+	if _, err := ReadArenaFrom[[2]float64](bytes.NewReader(good)); !errors.Is(err, ErrSnapshotFormat) {
+		t.Errorf("expected type mismatch error, got %v", err)
+	}
+}
+
+// snapRecord is written to a snapshot; the snapRecord* variants below are
+// what a later version of the program might load it as.
+type snapRecord struct {
+	ID  uint32
+	Pos snapPoint
+}
+
+type snapRecordRetyped struct {
+	ID  uint32
+	Pos struct {
+		X float64
+		Y int64
+	}
+}
+
+type snapRecordReordered struct {
+	Pos snapPoint
+	ID  uint32
+}
+
+type snapRecordGrown struct {
+	ID    uint32
+	Pos   snapPoint
+	Extra uint32
+}
+
+type snapRecordRenamed struct {
+	Key uint32
+	Pos snapPoint
+}
+
+// TestSnapshotSchemaMismatch reads a snapshot back with modified types and
+// checks the error names the first differing field
+func TestSnapshotSchemaMismatch(t *testing.T) {
+	a := NewAtomicArena[snapRecord](2)
+	a.Alloc(snapRecord{ID: 1, Pos: snapPoint{2, 3}})
+	var buf bytes.Buffer
+	if _, err := a.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	snap := buf.Bytes()
+	mismatch := func(err error) *SchemaMismatchError {
+		t.Helper()
+		var se *SchemaMismatchError
+		if !errors.As(err, &se) || !errors.Is(err, ErrSnapshotFormat) {
+			t.Fatalf("expected a *SchemaMismatchError wrapping ErrSnapshotFormat, got %v", err)
+		}
+		return se
+	}
+
+	_, err := ReadArenaFrom[snapRecordRetyped](bytes.NewReader(snap))
+	if se := mismatch(err); se.Field != "Pos.Y" || se.Snapshot.Kind != reflect.Float64 || se.Loaded.Kind != reflect.Int64 {
+		t.Fatalf("expected Pos.Y to differ in kind, got %v", err)
+	}
+	off := unsafe.Offsetof(snapRecord{}.Pos) + unsafe.Offsetof(snapPoint{}.Y)
+	if want := fmt.Sprintf(`field "Pos.Y" is float64 at offset %d, size 8 in the snapshot but int64 at offset %d, size 8 in`, off, off); !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in %q", want, err)
+	}
+
+	_, err = ReadArenaFrom[snapRecordReordered](bytes.NewReader(snap))
+	if se := mismatch(err); se.Field != "ID" || se.Snapshot.Offset != 0 || se.Loaded.Offset != unsafe.Offsetof(snapRecordReordered{}.ID) {
+		t.Fatalf("expected ID to have moved, got %v", err)
+	}
+
+	_, err = ReadArenaFrom[snapRecordGrown](bytes.NewReader(snap))
+	if se := mismatch(err); se.Field != "Extra" || se.Snapshot != nil || !strings.Contains(err.Error(), "not in the snapshot") {
+		t.Fatalf("expected the added field to be named, got %v", err)
+	}
+
+	_, err = ReadArenaFrom[snapRecordRenamed](bytes.NewReader(snap))
+	if se := mismatch(err); se.Field != "ID" || se.Loaded != nil || !strings.Contains(err.Error(), "missing from") {
+		t.Fatalf("expected the renamed field to be reported missing, got %v", err)
+	}
+
+	// the escape hatch loads a same-sized type regardless
+	got, err := ReadArenaFromUnchecked[snapRecordRenamed](bytes.NewReader(snap))
+	if err != nil || got.Len() != 1 || got.raw[0].Key != 1 || got.raw[0].Pos.Y != 3 {
+		t.Fatalf("ReadArenaFromUnchecked: %v", err)
+	}
+	if _, err := ReadArenaFromUnchecked[snapRecordGrown](bytes.NewReader(snap)); !errors.Is(err, ErrSnapshotFormat) {
+		t.Fatalf("expected a size mismatch to fail even unchecked, got %v", err)
+	}
+}
+
+// TestSnapshotVersion1 keeps reading snapshots written before schemas were
+// recorded
+func TestSnapshotVersion1(t *testing.T) {
+	typ := reflect.TypeFor[snapPoint]()
+	hdr := snapshotHeader{version: 1, elemSize: uint64(typ.Size()), count: 1, fingerprint: typeFingerprint(typ)}
+	b := hdr.marshal()
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(1.5))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(2.5))
+	a, err := ReadArenaFrom[snapPoint](bytes.NewReader(b))
+	if err != nil || a.Len() != 1 || a.raw[0] != (snapPoint{1.5, 2.5}) {
+		t.Fatalf("expected the version 1 snapshot to load, got %v", err)
+	}
+	if _, err := ReadArenaFrom[[2]float64](bytes.NewReader(b)); !errors.Is(err, ErrSnapshotFormat) {
+		t.Fatalf("expected the version 1 fingerprint to be checked, got %v", err)
+	}
+}
+
+// maxWriter records the largest single write it receives.
+type maxWriter struct {
+	bytes.Buffer
+	max int
+}
+
+func (w *maxWriter) Write(p []byte) (int, error) {
+	w.max = max(w.max, len(p))
+	return w.Buffer.Write(p)
+}
+
+// TestSnapshotOptionsRoundTrip round-trips every compression and checksum
+// combination
+func TestSnapshotOptionsRoundTrip(t *testing.T) {
+	vals := make([]snapNested, 3*snapshotBlock/int(unsafe.Sizeof(snapNested{}))+5)
+	for i := range vals {
+		vals[i] = snapNested{ID: uint32(i), Point: snapPoint{float64(i), 1}, Flags: [4]bool{i%2 == 0}}
+	}
+	a := NewAtomicArena[snapNested](uintptr(len(vals)))
+	a.AppendSlice(vals)
+	for _, c := range []SnapshotCompression{CompressNone, CompressGzip} {
+		for _, sum := range []SnapshotChecksum{ChecksumNone, ChecksumCRC32C} {
+			opts := SnapshotOptions{Compress: c, Checksum: sum}
+			var w maxWriter
+			n, err := a.WriteSnapshot(&w, opts)
+			if err != nil || n != int64(w.Len()) {
+				t.Fatalf("%+v: WriteSnapshot wrote %d of %d bytes: %v", opts, n, w.Len(), err)
+			}
+			if c == CompressNone && w.max > snapshotBlock {
+				t.Fatalf("%+v: expected writes of at most %d bytes, got %d", opts, snapshotBlock, w.max)
+			}
+			got, err := ReadArenaFrom[snapNested](&w)
+			if err != nil {
+				t.Fatalf("%+v: ReadArenaFrom failed: %v", opts, err)
+			}
+			if !slices.Equal(got.Snapshot(), vals) {
+				t.Fatalf("%+v: round trip changed the contents", opts)
+			}
+		}
+	}
+}
+
+// TestSnapshotChecksum corrupts the payload of checksummed snapshots
+func TestSnapshotChecksum(t *testing.T) {
+	a := NewAtomicArena[int64](64)
+	for i := range int64(64) {
+		a.Alloc(i * i)
+	}
+	var buf bytes.Buffer
+	if _, err := a.WriteSnapshot(&buf, SnapshotOptions{Checksum: ChecksumCRC32C}); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	b[len(b)-100] ^= 0x10
+	if _, err := ReadArenaFrom[int64](bytes.NewReader(b)); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("expected ErrChecksum, got %v", err)
+	}
+	b[len(b)-100] ^= 0x10
+	b[len(b)-1] ^= 0x01
+	if _, err := ReadArenaFrom[int64](bytes.NewReader(b)); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("expected a corrupted checksum to fail, got %v", err)
+	}
+
+	// a damaged compressed payload fails in the decompressor or the checksum
+	buf.Reset()
+	a.WriteSnapshot(&buf, SnapshotOptions{Compress: CompressGzip, Checksum: ChecksumCRC32C})
+	b = buf.Bytes()
+	b[len(b)-12] ^= 0x10
+	if _, err := ReadArenaFrom[int64](bytes.NewReader(b)); !errors.Is(err, ErrChecksum) && !errors.Is(err, ErrSnapshotFormat) {
+		t.Fatalf("expected a corrupted gzip payload to fail, got %v", err)
+	}
+
+	// without a checksum the same corruption goes unnoticed
+	buf.Reset()
+	a.WriteTo(&buf)
+	b = buf.Bytes()
+	b[len(b)-100] ^= 0x10
+	if _, err := ReadArenaFrom[int64](bytes.NewReader(b)); err != nil {
+		t.Fatalf("expected an unchecksummed snapshot to load, got %v", err)
+	}
+}
+
+// TestSnapshotCompressionRegistry covers codecs outside the standard library
+func TestSnapshotCompressionRegistry(t *testing.T) {
+	a := NewAtomicArena[int32](4)
+	a.AppendSlice([]int32{1, 2, 3})
+	opts := SnapshotOptions{Compress: CompressZstd, Checksum: ChecksumCRC32C}
+	if _, err := a.WriteSnapshot(&bytes.Buffer{}, opts); !errors.Is(err, ErrCompressionUnavailable) {
+		t.Fatalf("expected ErrCompressionUnavailable, got %v", err)
+	}
+	if _, err := a.WriteSnapshot(&bytes.Buffer{}, SnapshotOptions{Compress: 9}); !errors.Is(err, ErrSnapshotFormat) {
+		t.Fatalf("expected unknown compressions to be rejected, got %v", err)
+	}
+	// a stand-in codec that stores the payload verbatim
+	RegisterSnapshotCompression(CompressZstd,
+		func(w io.Writer) (io.WriteCloser, error) { return nopWriteCloser{w}, nil },
+		func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(r), nil })
+	t.Cleanup(func() {
+		codecsMu.Lock()
+		delete(snapshotCodecs, CompressZstd)
+		codecsMu.Unlock()
+	})
+	var buf bytes.Buffer
+	if _, err := a.WriteSnapshot(&buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadArenaFrom[int32](&buf)
+	if err != nil || !slices.Equal(got.Snapshot(), []int32{1, 2, 3}) {
+		t.Fatalf("expected the registered codec to round-trip, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package atomicarena
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrChecksum is returned by ReadArenaFrom when a snapshot's payload does
+	// not match its recorded checksum.
+	ErrChecksum = errors.New("atomicarena: snapshot checksum mismatch")
+	// ErrCompressionUnavailable is returned for a snapshot compression with no
+	// registered codec.
+	ErrCompressionUnavailable = errors.New("atomicarena: snapshot compression unavailable")
+)
+
+// SnapshotCompression selects how WriteSnapshot compresses the element bytes.
+type SnapshotCompression uint8
+
+const (
+	// CompressNone stores the element bytes as they are.
+	CompressNone SnapshotCompression = iota
+	// CompressGzip compresses them with compress/gzip.
+	CompressGzip
+	// CompressZstd needs a codec from RegisterSnapshotCompression; the
+	// standard library has none.
+	CompressZstd
+)
+
+// SnapshotChecksum selects the checksum WriteSnapshot appends to the payload.
+type SnapshotChecksum uint8
+
+const (
+	// ChecksumNone appends no checksum.
+	ChecksumNone SnapshotChecksum = iota
+	// ChecksumCRC32C is CRC-32 with the Castagnoli polynomial.
+	ChecksumCRC32C
+)
+
+// SnapshotOptions configures WriteSnapshot. The zero value writes the same
+// uncompressed, unchecksummed snapshot as WriteTo. The options are recorded
+// in the header, so ReadArenaFrom needs none.
+type SnapshotOptions struct {
+	Compress SnapshotCompression
+	Checksum SnapshotChecksum
+}
+
+// flags encodes o into the header's flags field.
+func (o SnapshotOptions) flags() uint16 {
+	return uint16(o.Compress) | uint16(o.Checksum)<<8
+}
+
+// snapshotOptionsFrom decodes the header's flags field.
+func snapshotOptionsFrom(flags uint16) (SnapshotOptions, error) {
+	o := SnapshotOptions{Compress: SnapshotCompression(flags), Checksum: SnapshotChecksum(flags >> 8)}
+	if o.Compress > CompressZstd || o.Checksum > ChecksumCRC32C {
+		return o, fmt.Errorf("%w: unknown flags %#x", ErrSnapshotFormat, flags)
+	}
+	return o, nil
+}
+
+// newHash returns the running checksum for o, or nil for none.
+func (o SnapshotOptions) newHash() hash.Hash32 {
+	if o.Checksum == ChecksumCRC32C {
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	}
+	return nil
+}
+
+// snapshotCodec compresses and decompresses snapshot payloads.
+type snapshotCodec struct {
+	writer func(io.Writer) (io.WriteCloser, error)
+	reader func(io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	codecsMu       sync.RWMutex
+	snapshotCodecs = map[SnapshotCompression]snapshotCodec{
+		CompressGzip: {
+			writer: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			reader: func(r io.Reader) (io.ReadCloser, error) {
+				zr, err := gzip.NewReader(r)
+				if err == nil {
+					zr.Multistream(false)
+				}
+				return zr, err
+			},
+		},
+	}
+)
+
+// RegisterSnapshotCompression installs the codec for c, typically to provide
+// CompressZstd from a third-party package without this module depending on
+// it. It replaces any codec already registered for c.
+func RegisterSnapshotCompression(c SnapshotCompression, writer func(io.Writer) (io.WriteCloser, error), reader func(io.Reader) (io.ReadCloser, error)) {
+	codecsMu.Lock()
+	snapshotCodecs[c] = snapshotCodec{writer: writer, reader: reader}
+	codecsMu.Unlock()
+}
+
+// codecFor looks up the codec for c.
+func codecFor(c SnapshotCompression) (snapshotCodec, error) {
+	codecsMu.RLock()
+	codec, ok := snapshotCodecs[c]
+	codecsMu.RUnlock()
+	if !ok {
+		return codec, fmt.Errorf("%w: compression %d", ErrCompressionUnavailable, c)
+	}
+	return codec, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// nopWriteCloser adds a no-op Close to an uncompressed payload writer.
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,44 @@
+package atomicarena
+
+// WithSoftCap limits Alloc, Reserve, AppendSlice and the other ordinary
+// allocation paths to the first n slots, keeping the rest of the capacity
+// as a reserve that only AllocPriority may use. Both share the arena's
+// single counter, so the limit costs nothing on the fast path. A soft cap
+// at or above the capacity has no effect.
+func WithSoftCap(n uintptr) Option {
+	return func(o *options) { o.softCap, o.softCapped = n, true }
+}
+
+// AllocPriority is Alloc for privileged callers such as flush or error
+// reporting paths: it ignores the soft cap set by WithSoftCap and fails with
+// a *CapacityError only when the arena is full.
+func (a *AtomicArena[T]) AllocPriority(obj T) (*T, error) {
+	_, p, err := a.allocWithin(obj, a.maxElems.Load())
+	if err == nil && a.prof != nil {
+		a.prof.sample(1)
+	}
+	return p, err
+}
+
+// SoftCap returns the number of slots ordinary allocations may use: the
+// limit set by WithSoftCap, or Cap if there is none.
+func (a *AtomicArena[T]) SoftCap() uintptr {
+	return a.softCap.Load()
+}
+
+// room returns how many slots ordinary allocations can still claim. It is
+// zero once priority allocations have taken the count past the soft cap.
+func (a *AtomicArena[T]) room() uintptr {
+	if n, limit := a.Len(), a.softCap.Load(); n < limit {
+		return limit - n
+	}
+	return 0
+}
+
+// softCapFor returns the soft cap of an arena of maxElems slots.
+func (o *options) softCapFor(maxElems uintptr) uintptr {
+	if o.softCapped {
+		return min(o.softCap, maxElems)
+	}
+	return maxElems
+}
@@ -0,0 +1,88 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestSoftCap fills to the soft cap, then uses the reserve with AllocPriority
+func TestSoftCap(t *testing.T) {
+	a := NewAtomicArena[int](10, WithSoftCap(6))
+	for i := 0; i < 6; i++ {
+		if _, err := a.Alloc(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := a.Alloc(6)
+	ce := capacityErr(t, err)
+	if want := (CapacityError{Requested: 1, Available: 0, Capacity: 6, Soft: true}); *ce != want {
+		t.Fatalf("expected %+v, got %+v", want, *ce)
+	}
+	if got := err.Error(); got != "atomicarena: need 1 slot, 0 available of 6 below the soft cap" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if _, err := a.Reserve(1); err == nil {
+		t.Fatalf("expected Reserve to respect the soft cap")
+	}
+	for i := 6; i < 10; i++ {
+		if p, err := a.AllocPriority(i); err != nil || *p != i {
+			t.Fatalf("expected priority Alloc %d to succeed, got %v", i, err)
+		}
+	}
+	// past the soft cap, ordinary allocations still fail without underflow
+	if _, err := a.AppendSlice([]int{1}); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	// the hard cap is absolute
+	_, err = a.AllocPriority(10)
+	if ce := capacityErr(t, err); ce.Soft || ce.Capacity != 10 {
+		t.Fatalf("expected a hard capacity error, got %+v", *ce)
+	}
+	s := a.Stats()
+	if s.SoftCap != 6 || s.SoftRejected != 2 || s.Len != 10 || s.Cap != 10 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if err := a.Reset(false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Alloc(1); err != nil {
+		t.Fatalf("expected Reset to reopen the soft cap, got %v", err)
+	}
+	if b := NewAtomicArena[int](4, WithSoftCap(8)); b.SoftCap() != 4 {
+		t.Fatalf("expected a soft cap above the capacity to be clipped, got %d", b.SoftCap())
+	}
+}
+
+// TestSoftCapConcurrent races ordinary and priority allocations; run with -race
+func TestSoftCapConcurrent(t *testing.T) {
+	const capacity, soft = 4000, 3000
+	a := NewAtomicArena[int](capacity, WithSoftCap(soft))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var normal, priority int
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, p := 0, 0
+			for i := 0; i < 1000; i++ {
+				if g%2 == 0 {
+					if _, err := a.Alloc(i); err == nil {
+						n++
+					}
+				} else if _, err := a.AllocPriority(i); err == nil {
+					p++
+				}
+			}
+			mu.Lock()
+			normal += n
+			priority += p
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if a.Len() != capacity || normal+priority != capacity || normal > soft {
+		t.Fatalf("expected %d allocations with at most %d ordinary, got %d ordinary and %d priority", capacity, soft, normal, priority)
+	}
+}
@@ -0,0 +1,138 @@
+package atomicarena
+
+import (
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+)
+
+// SparseArena is a fixed-capacity arena whose slots can be freed and reused
+// individually. Occupancy is tracked in an atomic bitmap: Alloc claims the
+// first clear bit with a CAS on its word, starting from a rotating hint so
+// concurrent allocators don't all contend on bit zero.
+type SparseArena[T any] struct {
+	raw     []T
+	claimed []atomic.Uint64 // slots owned by an allocation, set before the write
+	ready   []atomic.Uint64 // slots whose value has been written and is visible
+	hint    atomic.Uintptr  // word index where the next scan starts
+	n       uintptr
+}
+
+// NewSparseArena creates a SparseArena with n slots.
+func NewSparseArena[T any](n uintptr) *SparseArena[T] {
+	words := (n + 63) / 64
+	s := &SparseArena[T]{
+		raw:     make([]T, n),
+		claimed: make([]atomic.Uint64, words),
+		ready:   make([]atomic.Uint64, words),
+		n:       n,
+	}
+	if rem := n % 64; rem != 0 {
+		// bits past the end are permanently claimed
+		s.claimed[words-1].Store(^uint64(0) << rem)
+	}
+	return s
+}
+
+// Alloc claims a free slot, stores obj in it and returns its index and address.
+func (s *SparseArena[T]) Alloc(obj T) (uintptr, *T, error) {
+	words := uintptr(len(s.claimed))
+	start := s.hint.Load()
+	for k := uintptr(0); k < words; k++ {
+		w := (start + k) % words
+		for {
+			old := s.claimed[w].Load()
+			if old == ^uint64(0) {
+				break // word full, try the next one
+			}
+			bit := uintptr(bits.TrailingZeros64(^old))
+			if !s.claimed[w].CompareAndSwap(old, old|uint64(1)<<bit) {
+				continue
+			}
+			if w != start {
+				s.hint.Store(w)
+			}
+			i := w*64 + bit
+			s.raw[i] = obj
+			setBit(&s.ready[w], uint64(1)<<bit)
+			return i, &s.raw[i], nil
+		}
+	}
+	return 0, nil, ErrArenaFull
+}
+
+// Free releases slot i, zeroing its value so it can be reused by Alloc.
+// The caller must not use pointers into the slot afterwards.
+func (s *SparseArena[T]) Free(i uintptr) error {
+	if i >= s.n {
+		return fmt.Errorf("%w: free %d, cap %d", ErrOutOfRange, i, s.n)
+	}
+	w, bit := i/64, uint64(1)<<(i%64)
+	if !clearBit(&s.ready[w], bit) {
+		return fmt.Errorf("%w: slot %d is not allocated", ErrOutOfRange, i)
+	}
+	var zero T
+	s.raw[i] = zero
+	clearBit(&s.claimed[w], bit)
+	return nil
+}
+
+// Get returns the value in slot i, or false if the slot is not occupied.
+func (s *SparseArena[T]) Get(i uintptr) (*T, bool) {
+	if i >= s.n || s.ready[i/64].Load()&(uint64(1)<<(i%64)) == 0 {
+		return nil, false
+	}
+	return &s.raw[i], true
+}
+
+// Len returns the number of occupied slots by counting set bits.
+func (s *SparseArena[T]) Len() uintptr {
+	var n int
+	for w := range s.ready {
+		n += bits.OnesCount64(s.ready[w].Load())
+	}
+	return uintptr(n)
+}
+
+// Cap returns the number of slots in the arena.
+func (s *SparseArena[T]) Cap() uintptr {
+	return s.n
+}
+
+// Range calls fn for each occupied slot in index order until fn returns false.
+func (s *SparseArena[T]) Range(fn func(i uintptr, v *T) bool) {
+	for w := range s.ready {
+		word := s.ready[w].Load()
+		for word != 0 {
+			bit := uintptr(bits.TrailingZeros64(word))
+			word &^= uint64(1) << bit
+			i := uintptr(w)*64 + bit
+			if !fn(i, &s.raw[i]) {
+				return
+			}
+		}
+	}
+}
+
+// setBit atomically sets mask in w.
+func setBit(w *atomic.Uint64, mask uint64) {
+	for {
+		old := w.Load()
+		if w.CompareAndSwap(old, old|mask) {
+			return
+		}
+	}
+}
+
+// clearBit atomically clears mask in w and reports whether it was set.
+func clearBit(w *atomic.Uint64, mask uint64) bool {
+	for {
+		old := w.Load()
+		if old&mask == 0 {
+			return false
+		}
+		if w.CompareAndSwap(old, old&^mask) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package atomicarena
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// TestSparseAllocFree ensures freed slots are reused and Len counts occupancy
+func TestSparseAllocFree(t *testing.T) {
+	s := NewSparseArena[int](70)
+	for i := 0; i < 70; i++ {
+		if _, _, err := s.Alloc(i); err != nil {
+			t.Fatalf("Alloc %d failed: %v", i, err)
+		}
+	}
+	if _, _, err := s.Alloc(99); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if err := s.Free(65); err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	if err := s.Free(65); err == nil {
+		t.Fatal("expected error on double free")
+	}
+	if s.Len() != 69 {
+		t.Fatalf("expected len 69, got %d", s.Len())
+	}
+	if _, ok := s.Get(65); ok {
+		t.Fatal("Get returned a freed slot")
+	}
+	i, p, err := s.Alloc(100)
+	if err != nil || i != 65 || *p != 100 {
+		t.Fatalf("expected reuse of slot 65, got %d, %v", i, err)
+	}
+	count := 0
+	s.Range(func(uintptr, *int) bool { count++; return true })
+	if count != 70 {
+		t.Fatalf("Range visited %d slots, want 70", count)
+	}
+}
+
+// TestSparseChurn allocates and frees randomly at ~90% occupancy and checks
+// no slot is ever handed to two owners.
+func TestSparseChurn(t *testing.T) {
+	const (
+		capacity = 4096
+		workers  = 8
+	)
+	ops := 1_000_000
+	if testing.Short() {
+		ops = 50_000
+	}
+	s := NewSparseArena[int](capacity)
+	target := capacity * 9 / 10 / workers
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(w)))
+			var owned []uintptr
+			for op := 0; op < ops/workers; op++ {
+				if len(owned) < target || (len(owned) < 2*target && rng.Intn(2) == 0) {
+					i, _, err := s.Alloc(w)
+					if err != nil {
+						continue
+					}
+					owned = append(owned, i)
+					continue
+				}
+				k := rng.Intn(len(owned))
+				i := owned[k]
+				owned[k] = owned[len(owned)-1]
+				owned = owned[:len(owned)-1]
+				if p, ok := s.Get(i); !ok || *p != w {
+					t.Errorf("slot %d stolen from worker %d", i, w)
+					return
+				}
+				if err := s.Free(i); err != nil {
+					t.Errorf("Free(%d) failed: %v", i, err)
+					return
+				}
+			}
+			for _, i := range owned {
+				if p, ok := s.Get(i); !ok || *p != w {
+					t.Errorf("slot %d stolen from worker %d", i, w)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// freeListArena is a mutex-guarded free-list baseline for BenchmarkSparseChurn.
+type freeListArena struct {
+	mu   sync.Mutex
+	raw  []int
+	free []uintptr
+}
+
+func (f *freeListArena) alloc(v int) (uintptr, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.free) == 0 {
+		return 0, false
+	}
+	i := f.free[len(f.free)-1]
+	f.free = f.free[:len(f.free)-1]
+	f.raw[i] = v
+	return i, true
+}
+
+func (f *freeListArena) release(i uintptr) {
+	f.mu.Lock()
+	f.raw[i] = 0
+	f.free = append(f.free, i)
+	f.mu.Unlock()
+}
+
+// BenchmarkSparseChurn compares the bitmap arena with a mutex-guarded free list.
+func BenchmarkSparseChurn(b *testing.B) {
+	const capacity = 1 << 16
+	b.Run("Bitmap", func(b *testing.B) {
+		s := NewSparseArena[int](capacity)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if i, _, err := s.Alloc(1); err == nil {
+					_ = s.Free(i)
+				}
+			}
+		})
+	})
+	b.Run("FreeList", func(b *testing.B) {
+		f := &freeListArena{raw: make([]int, capacity)}
+		for i := uintptr(0); i < capacity; i++ {
+			f.free = append(f.free, i)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if i, ok := f.alloc(1); ok {
+					f.release(i)
+				}
+			}
+		})
+	})
+}
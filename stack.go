@@ -0,0 +1,116 @@
+package atomicarena
+
+import (
+	"errors"
+	"math"
+	"sync/atomic"
+)
+
+// ErrStackFull is returned by Push when every slot of the stack is in use.
+var ErrStackFull = errors.New("atomicarena: stack full")
+
+// Stack is a bounded lock-free LIFO (a Treiber stack) over arena slots.
+// Values live in an arena's storage and are linked by slot index through a
+// parallel next array. Two such lists are kept: the stack itself and the
+// free slots. Each head packs a 1-based slot index with a change counter
+// into one 64-bit word, so a head that was popped and pushed back between a
+// load and a CAS does not compare equal (the ABA problem).
+type Stack[T any] struct {
+	vals []T
+	next []atomic.Uint32 // 1-based index of the slot below, 0 at the bottom
+
+	head atomic.Uint64 // top of the stack
+	free atomic.Uint64 // top of the free list
+	n    atomic.Int64
+}
+
+// NewStack creates a stack holding up to capacity values. capacity is
+// clamped to the range [1, math.MaxUint32-1].
+func NewStack[T any](capacity uintptr) *Stack[T] {
+	capacity = min(max(capacity, 1), math.MaxUint32-1)
+	vals, _ := NewAtomicArena[T](capacity).Reserve(capacity)
+	s := &Stack[T]{vals: vals, next: make([]atomic.Uint32, capacity)}
+	s.initFree()
+	return s
+}
+
+// initFree links every slot into the free list.
+func (s *Stack[T]) initFree() {
+	for i := range s.next {
+		s.next[i].Store(uint32(i)) // slot i sits on slot i-1
+	}
+	s.free.Store(uint64(len(s.next)))
+	s.head.Store(0)
+}
+
+// pop removes the top slot of the list at h and returns its 0-based index.
+func (s *Stack[T]) pop(h *atomic.Uint64) (uint32, bool) {
+	for {
+		old := h.Load()
+		top := uint32(old)
+		if top == 0 {
+			return 0, false
+		}
+		below := s.next[top-1].Load()
+		if h.CompareAndSwap(old, (old>>32+1)<<32|uint64(below)) {
+			return top - 1, true
+		}
+	}
+}
+
+// push puts slot i on top of the list at h.
+func (s *Stack[T]) push(h *atomic.Uint64, i uint32) {
+	for {
+		old := h.Load()
+		s.next[i].Store(uint32(old))
+		if h.CompareAndSwap(old, (old>>32+1)<<32|uint64(i+1)) {
+			return
+		}
+	}
+}
+
+// Push stores v on top of the stack, or returns ErrStackFull.
+func (s *Stack[T]) Push(v T) error {
+	i, ok := s.pop(&s.free)
+	if !ok {
+		return ErrStackFull
+	}
+	s.vals[i] = v
+	s.push(&s.head, i)
+	s.n.Add(1)
+	return nil
+}
+
+// Pop removes and returns the most recently pushed value, or false if the
+// stack is empty.
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	i, ok := s.pop(&s.head)
+	if !ok {
+		return zero, false
+	}
+	v := s.vals[i]
+	s.vals[i] = zero
+	s.n.Add(-1)
+	s.push(&s.free, i)
+	return v, true
+}
+
+// Len returns the number of values on the stack. Under concurrent use it is
+// a snapshot that may already be out of date.
+func (s *Stack[T]) Len() uintptr {
+	return uintptr(max(s.n.Load(), 0))
+}
+
+// Cap returns the maximum number of values the stack can hold.
+func (s *Stack[T]) Cap() uintptr {
+	return uintptr(len(s.vals))
+}
+
+// Reset empties the stack and zeroes its slots. It must not run concurrently
+// with Push or Pop.
+func (s *Stack[T]) Reset() {
+	clear(s.vals)
+	s.initFree()
+	s.n.Store(0)
+}
@@ -0,0 +1,151 @@
+package atomicarena
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestStackLIFO covers ordering, full and empty conditions and Reset
+func TestStackLIFO(t *testing.T) {
+	s := NewStack[int](3)
+	for i := 1; i <= 3; i++ {
+		if err := s.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Push(4); err != ErrStackFull {
+		t.Fatalf("expected ErrStackFull, got %v", err)
+	}
+	for want := 3; want >= 1; want-- {
+		if v, ok := s.Pop(); !ok || v != want {
+			t.Fatalf("Pop = %d, %v; want %d", v, ok, want)
+		}
+	}
+	if _, ok := s.Pop(); ok {
+		t.Fatal("expected an empty stack")
+	}
+	s.Push(7)
+	s.Reset()
+	if s.Len() != 0 {
+		t.Fatalf("expected empty stack after Reset, len %d", s.Len())
+	}
+	for i := 0; i < 3; i++ {
+		if err := s.Push(i); err != nil {
+			t.Fatalf("Push after Reset failed: %v", err)
+		}
+	}
+}
+
+// TestStackConcurrent checks exactly-once delivery under concurrent push and pop
+func TestStackConcurrent(t *testing.T) {
+	const workers, per = 8, 20_000
+	s := NewStack[int](256)
+	seen := make([]atomic.Uint32, workers*per)
+	var popped atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < per; i++ {
+				for s.Push(w*per+i) != nil {
+					runtime.Gosched()
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for popped.Load() < workers*per {
+				v, ok := s.Pop()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				if seen[v].Add(1) != 1 {
+					t.Errorf("value %d popped twice", v)
+				}
+				popped.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	for v := range seen {
+		if seen[v].Load() != 1 {
+			t.Fatalf("value %d popped %d times", v, seen[v].Load())
+		}
+	}
+}
+
+// TestStackABA rapidly pops and re-pushes the same few slots from many
+// goroutines, which corrupts an untagged Treiber stack
+func TestStackABA(t *testing.T) {
+	s := NewStack[int](4)
+	for i := 0; i < 4; i++ {
+		s.Push(i)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20_000; i++ {
+				if v, ok := s.Pop(); ok {
+					if err := s.Push(v); err != nil {
+						t.Errorf("re-push failed: %v", err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	var got []int
+	for {
+		v, ok := s.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, v)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("stack corrupted: %v", got)
+	}
+	// the free list must still hold every slot
+	for i := 0; i < 4; i++ {
+		if err := s.Push(i); err != nil {
+			t.Fatalf("free list corrupted: %v", err)
+		}
+	}
+}
+
+type stackObj struct{ buf [64]byte }
+
+// BenchmarkStack compares Stack against sync.Pool for a fixed-size struct
+func BenchmarkStack(b *testing.B) {
+	b.Run("Stack", func(b *testing.B) {
+		s := NewStack[stackObj](1024)
+		for i := 0; i < 512; i++ {
+			s.Push(stackObj{})
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if v, ok := s.Pop(); ok {
+					s.Push(v)
+				}
+			}
+		})
+	})
+	b.Run("sync.Pool", func(b *testing.B) {
+		p := sync.Pool{New: func() any { return new(stackObj) }}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				v := p.Get().(*stackObj)
+				p.Put(v)
+			}
+		})
+	})
+}
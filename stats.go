@@ -0,0 +1,91 @@
+package atomicarena
+
+// Stats is a point-in-time summary of an arena's state.
+type Stats struct {
+	Len    uintptr // allocated slots
+	Cap    uintptr // maximum number of slots
+	Bytes  uintptr // total memory footprint, as reported by SizeBytes
+	Epoch  uint64  // number of resets
+	Frozen bool
+	Name   string // set by WithName; empty for unnamed arenas
+
+	SoftCap      uintptr // slots ordinary allocations may use; Cap unless WithSoftCap
+	SoftRejected uint64  // allocations refused at the soft cap that would have fit the capacity
+
+	HugePages bool // storage is backed by huge pages, as requested by WithHugePages or WithHugeTLB
+
+	ZeroCleared uint64 // stale slots cleared before a zeroing allocation returned them
+	ZeroSkipped uint64 // pristine slots a zeroing allocation returned without clearing
+
+	Allocated uint64  // slots reserved since creation, including those since reset or compacted away
+	Rejected  uint64  // allocations refused with ErrArenaFull, SoftRejected included
+	PeakLen   uintptr // highest Len since creation
+
+	ReservationRetries uint64 // reservation attempts repeated because another reservation won the race
+}
+
+// Stats returns a summary of the arena's current state. Under concurrent
+// allocation the fields are sampled one after another.
+func (a *AtomicArena[T]) Stats() Stats {
+	n := a.Len()
+	return Stats{
+		Len:    n,
+		Cap:    a.Cap(),
+		Bytes:  a.SizeBytes(),
+		Epoch:  a.Epoch(),
+		Frozen: a.Frozen(),
+		Name:   a.opts.name,
+
+		SoftCap:      a.softCap.Load(),
+		SoftRejected: a.softFull.Load(),
+
+		HugePages: a.huge,
+
+		ZeroCleared: a.cleared.Load(),
+		ZeroSkipped: a.pristine.Load(),
+
+		Allocated: a.retired.Load() + uint64(n),
+		Rejected:  a.full.Load(),
+		PeakLen:   max(a.peak.Load(), n),
+
+		ReservationRetries: a.retries.Load(),
+	}
+}
+
+// sumStats combines the Stats of the arenas behind an ArenaGroup or a
+// PartitionedArena, as ArenaGroup.Stats describes. Name and HugePages are
+// left for the caller.
+func sumStats(parts ...Stats) Stats {
+	s := Stats{Frozen: len(parts) > 0}
+	for _, p := range parts {
+		s.Len += p.Len
+		s.Cap += p.Cap
+		s.Bytes += p.Bytes
+		s.Epoch += p.Epoch
+		s.Frozen = s.Frozen && p.Frozen
+		s.SoftCap += p.SoftCap
+		s.SoftRejected += p.SoftRejected
+		s.ZeroCleared += p.ZeroCleared
+		s.ZeroSkipped += p.ZeroSkipped
+		s.Allocated += p.Allocated
+		s.Rejected += p.Rejected
+		s.PeakLen += p.PeakLen
+		s.ReservationRetries += p.ReservationRetries
+	}
+	return s
+}
+
+// Sub returns the change from prev to s, for two Stats of the same arena
+// taken one after the other: the counters Epoch, SoftRejected, ZeroCleared,
+// ZeroSkipped, Allocated, Rejected and ReservationRetries hold how much they
+// grew in between, and every other field is s's.
+func (s Stats) Sub(prev Stats) Stats {
+	s.Epoch -= prev.Epoch
+	s.SoftRejected -= prev.SoftRejected
+	s.ZeroCleared -= prev.ZeroCleared
+	s.ZeroSkipped -= prev.ZeroSkipped
+	s.Allocated -= prev.Allocated
+	s.Rejected -= prev.Rejected
+	s.ReservationRetries -= prev.ReservationRetries
+	return s
+}
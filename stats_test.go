@@ -0,0 +1,57 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStatsTotals counts allocations, refusals and the peak across Reset, Compact and TryShrinkTo
+func TestStatsTotals(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	if _, err := a.Reserve(6); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if _, err := a.Reserve(3); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if err := a.TryShrinkTo(4); err != nil {
+		t.Fatalf("TryShrinkTo failed: %v", err)
+	}
+	if err := a.Reset(false); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		a.Alloc(i)
+	}
+	s := a.Stats()
+	if s.Allocated != 9 || s.Rejected != 1 || s.PeakLen != 6 || s.Len != 3 {
+		t.Fatalf("expected 9 allocated, 1 rejected, peak 6 and 3 in use, got %d, %d, %d and %d", s.Allocated, s.Rejected, s.PeakLen, s.Len)
+	}
+	a.Reserve(5)
+	if s := a.Stats(); s.PeakLen != 8 || s.Allocated != 14 {
+		t.Fatalf("expected the peak to follow Len to 8 with 14 allocated, got %d and %d", s.PeakLen, s.Allocated)
+	}
+	a.Tombstone(0)
+	a.Compact()
+	if s := a.Stats(); s.Len != 7 || s.PeakLen != 8 || s.Allocated != 14 {
+		t.Fatalf("expected Compact to leave 7 of 14 allocated with peak 8, got %d of %d with peak %d", s.Len, s.Allocated, s.PeakLen)
+	}
+}
+
+// TestStatsSub diffs the counters and keeps the gauges
+func TestStatsSub(t *testing.T) {
+	a := NewAtomicArena[int](4, WithSoftCap(2))
+	a.Alloc(1)
+	before := a.Stats()
+	a.Alloc(2)
+	a.Alloc(3)
+	a.Reset(false)
+	a.Alloc(4)
+	d := a.Stats().Sub(before)
+	if d.Allocated != 2 || d.Rejected != 1 || d.SoftRejected != 1 || d.Epoch != 1 {
+		t.Fatalf("expected 2 allocated, 1 rejected at the soft cap and 1 reset, got %d, %d, %d and %d", d.Allocated, d.Rejected, d.SoftRejected, d.Epoch)
+	}
+	if d.Len != 1 || d.Cap != 4 || d.PeakLen != 2 {
+		t.Fatalf("expected Len, Cap and PeakLen of the later Stats, got %d, %d and %d", d.Len, d.Cap, d.PeakLen)
+	}
+}
@@ -0,0 +1,51 @@
+package atomicarena
+
+import (
+	"context"
+	"errors"
+)
+
+// Subscribe streams newly committed slots as half-open index ranges
+// [lo, hi), in order and without gaps, starting at the committed count at
+// the time of the call. The channel has the given buffer. The returned
+// func unsubscribes and closes the channel; it is idempotent and returns
+// once the channel is closed.
+//
+// A subscription is served by its own goroutine, which waits with
+// WaitForCommitted, so producers never block on it. A slow subscriber
+// loses nothing either: while its channel is full, newly committed slots
+// are coalesced into the next range it receives, so fewer and larger
+// ranges replace per-element sends. The channel is also closed when the
+// arena is reset, compacted, frozen or closed, after the slots committed
+// before a freeze have been delivered; ranges delivered before a Reset or
+// Compact referred to the previous epoch.
+func (a *AtomicArena[T]) Subscribe(buffer int) (<-chan [2]uintptr, func()) {
+	ch := make(chan [2]uintptr, buffer)
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+	e, next := a.Epoch(), a.Committed()
+	go func() {
+		defer close(exited)
+		defer close(ch)
+		for {
+			err := a.WaitForCommitted(ctx, next+1)
+			if err != nil && !errors.Is(err, ErrFrozen) && !errors.Is(err, ErrClosed) {
+				return
+			}
+			n := a.Committed()
+			if a.Epoch() != e || n <= next {
+				return
+			}
+			select {
+			case ch <- [2]uintptr{next, n}:
+				next = n
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, func() {
+		cancel()
+		<-exited
+	}
+}
@@ -0,0 +1,102 @@
+package atomicarena
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// collectRanges drains a subscription and checks that its ranges are
+// gapless from start; it returns the end of the last range.
+func collectRanges(t *testing.T, ch <-chan [2]uintptr, start uintptr, delay time.Duration) (end uintptr, ranges int) {
+	end = start
+	for r := range ch {
+		if r[0] != end || r[1] <= r[0] {
+			t.Errorf("expected a range starting at %d, got %v", end, r)
+			return end, ranges
+		}
+		end = r[1]
+		ranges++
+		time.Sleep(delay)
+	}
+	return end, ranges
+}
+
+// TestSubscribe runs fast and slow subscribers against concurrent producers;
+// run with -race
+func TestSubscribe(t *testing.T) {
+	const producers, perProducer = 4, 1000
+	const total = producers * perProducer
+	a := NewAtomicArena[int](total, WithUnfreeze())
+	a.Alloc(-1) // committed before subscribing, so never delivered
+	type result struct{ end, ranges uintptr }
+	results := make([]result, 3)
+	var subs sync.WaitGroup
+	for i := range results {
+		ch, _ := a.Subscribe(4)
+		delay := time.Duration(0)
+		if i == 2 {
+			delay = 2 * time.Millisecond // the slow subscriber
+		}
+		subs.Add(1)
+		go func() {
+			defer subs.Done()
+			end, n := collectRanges(t, ch, 1, delay)
+			results[i] = result{end, uintptr(n)}
+		}()
+	}
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer-1; i++ {
+				a.Alloc(i)
+			}
+			a.AppendSlice([]int{p})
+		}()
+	}
+	wg.Wait()
+	// freezing delivers what is committed and then ends the subscriptions
+	a.Freeze()
+	subs.Wait()
+	for i, r := range results {
+		if r.end != total {
+			t.Fatalf("subscriber %d: expected ranges up to %d, got %d", i, total, r.end)
+		}
+	}
+	if results[2].ranges >= total-1 {
+		t.Fatalf("expected the slow subscriber's ranges to be coalesced, got %d", results[2].ranges)
+	}
+}
+
+// TestSubscribeReset ensures Reset and cancel close subscriptions
+func TestSubscribeReset(t *testing.T) {
+	a := NewAtomicArena[int](16)
+	ch, cancel := a.Subscribe(0)
+	defer cancel()
+	a.Alloc(1)
+	if r := <-ch; r != [2]uintptr{0, 1} {
+		t.Fatalf("expected [0 1], got %v", r)
+	}
+	a.Reset(false)
+	select {
+	case r, ok := <-ch:
+		if ok {
+			t.Fatalf("expected the channel closed by Reset, got %v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Reset did not close the subscription")
+	}
+
+	// cancel closes a subscription that is blocked on a full channel
+	ch, cancel = a.Subscribe(0)
+	a.Alloc(2)
+	cancel()
+	cancel()
+	for range ch {
+	}
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected cancel to close the channel")
+	}
+}
@@ -0,0 +1,131 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOutOfRange is returned when an index does not refer to an allocated slot.
+var ErrOutOfRange = errors.New("atomicarena: index out of range")
+
+// Tombstone marks the allocated slot i as dead. Dead slots are skipped by Get,
+// Range and Snapshot but keep their index until Compact is called.
+// Tombstone is safe to call concurrently with Alive, iteration and allocation.
+// If the arena has a destructor, it runs when a live slot is tombstoned and
+// its error, if any, is returned.
+func (a *AtomicArena[T]) Tombstone(i uintptr) error {
+	if a.Frozen() {
+		return a.frozenErr()
+	}
+	if i >= a.Len() {
+		return fmt.Errorf("%w: tombstone %d, len %d", ErrOutOfRange, i, a.Len())
+	}
+	if a.markDead(i) && a.dtor != nil {
+		return a.dtor(&a.raw[i])
+	}
+	return nil
+}
+
+// markDead sets the tombstone bit of slot i and reports whether it was clear.
+func (a *AtomicArena[T]) markDead(i uintptr) bool {
+	w, bit := &a.dead[i/64], uint64(1)<<(i%64)
+	for {
+		old := w.Load()
+		if old&bit != 0 {
+			return false
+		}
+		if w.CompareAndSwap(old, old|bit) {
+			return true
+		}
+	}
+}
+
+// Alive reports whether i is an allocated slot that has not been tombstoned.
+func (a *AtomicArena[T]) Alive(i uintptr) bool {
+	return i < a.Len() && !a.tombstoned(i)
+}
+
+func (a *AtomicArena[T]) tombstoned(i uintptr) bool {
+	return a.dead[i/64].Load()&(uint64(1)<<(i%64)) != 0
+}
+
+// gone reports whether slot i is tombstoned or has expired under WithTTL,
+// the slots Get refuses.
+func (a *AtomicArena[T]) gone(i uintptr) bool {
+	return a.tombstoned(i) || a.expired(i)
+}
+
+// swapDead exchanges the tombstone bits of slots i and j. Callers hold the
+// arena exclusively.
+func (a *AtomicArena[T]) swapDead(i, j uintptr) {
+	if a.tombstoned(i) == a.tombstoned(j) {
+		return
+	}
+	for _, k := range [2]uintptr{i, j} {
+		a.dead[k/64].Store(a.dead[k/64].Load() ^ uint64(1)<<(k%64))
+	}
+}
+
+// clearTombstones clears the tombstone bits of the first n slots.
+func (a *AtomicArena[T]) clearTombstones(n uintptr) {
+	for w := uintptr(0); w < (n+63)/64; w++ {
+		a.dead[w].Store(0)
+	}
+}
+
+// Compact slides live elements down over tombstoned slots, preserving their
+// order, and makes the freed tail available to Alloc again. It returns the
+// old-to-new index of every element that moved. Under WithDestructor the
+// elements it drops were destroyed when they were tombstoned. Compacting any
+// away advances the Epoch, since indices taken before it no longer name the
+// same values.
+// Compact requires exclusive access: no other goroutine may allocate, read or
+// tombstone while it runs. It does nothing on a frozen arena.
+func (a *AtomicArena[T]) Compact() (moved map[uintptr]uintptr) {
+	if a.Frozen() {
+		return nil
+	}
+	n := a.Len()
+	moved = make(map[uintptr]uintptr)
+	live, dropped := uintptr(0), uintptr(0)
+	for i := uintptr(0); i < n; i++ {
+		if a.tombstoned(i) {
+			dropped += a.unpublished(i, i+1)
+			continue
+		}
+		if i != live {
+			a.raw[live] = a.raw[i]
+			if a.ttl != nil {
+				a.ttl.at[live].Store(a.ttl.at[i].Load())
+			}
+			if a.ptrs != nil {
+				var p *T
+				if a.ptrs[i].Load() != nil {
+					p = &a.raw[live]
+				}
+				a.ptrs[live].Store(p)
+			}
+			moved[i] = live
+		}
+		live++
+	}
+	// zero the freed tail
+	a.zero.clear(a.raw[live:n])
+	for i := live; i < n && a.ptrs != nil; i++ {
+		a.ptrs[i].Store(nil)
+	}
+	a.clearTombstones(n)
+	a.dropUnpublished(dropped, live)
+	a.poisonSlots(live, n)
+	a.countDropped(n, live)
+	if live != n {
+		a.epoch.Add(1)
+	}
+	a.count.Store(live)
+	a.done.Store(live)
+	if live != n && a.waiters.wantAt.Load() != ^uintptr(0) {
+		// let waiters see the new epoch
+		a.wakeCommitted()
+	}
+	return moved
+}
@@ -0,0 +1,118 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestTombstone ensures tombstoned slots are hidden but keep indices stable
+func TestTombstone(t *testing.T) {
+	arena := NewAtomicArena[int](4)
+	_, _ = arena.AppendSlice([]int{10, 20, 30})
+	if err := arena.Tombstone(1); err != nil {
+		t.Fatalf("Tombstone failed: %v", err)
+	}
+	if arena.Alive(1) || !arena.Alive(0) || !arena.Alive(2) {
+		t.Fatal("unexpected liveness after tombstone")
+	}
+	if _, ok := arena.Get(1); ok {
+		t.Error("Get returned a tombstoned slot")
+	}
+	if v, ok := arena.Get(2); !ok || *v != 30 {
+		t.Errorf("index 2 changed after tombstone: %v, %v", v, ok)
+	}
+	var seen []int
+	arena.Range(func(_ uintptr, v *int) bool { seen = append(seen, *v); return true })
+	if len(seen) != 2 || seen[0] != 10 || seen[1] != 30 {
+		t.Errorf("Range did not skip tombstone: %v", seen)
+	}
+	if err := arena.Tombstone(3); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+// TestCompact ensures Compact preserves every live value exactly once
+func TestCompact(t *testing.T) {
+	const n = 1000
+	arena := NewAtomicArena[int](n)
+	for i := 0; i < n; i++ {
+		if _, err := arena.Alloc(i); err != nil {
+			t.Fatalf("Alloc failed: %v", err)
+		}
+	}
+
+	// tombstone every third element while readers iterate
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := uintptr(0); i < n; i += 3 {
+			if err := arena.Tombstone(i); err != nil {
+				t.Errorf("Tombstone(%d) failed: %v", i, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 10; k++ {
+			arena.Range(func(i uintptr, v *int) bool {
+				if uintptr(*v) != i {
+					t.Errorf("Range index %d holds %d", i, *v)
+				}
+				return true
+			})
+		}
+	}()
+	wg.Wait()
+
+	moved := arena.Compact()
+	seen := make(map[int]int)
+	arena.Range(func(i uintptr, v *int) bool { seen[*v]++; return true })
+	for v := 0; v < n; v++ {
+		want := 1
+		if v%3 == 0 {
+			want = 0
+		}
+		if seen[v] != want {
+			t.Fatalf("value %d seen %d times, want %d", v, seen[v], want)
+		}
+	}
+	for from, to := range moved {
+		if arena.raw[to] != int(from) {
+			t.Fatalf("moved[%d]=%d but slot holds %d", from, to, arena.raw[to])
+		}
+	}
+	live := uintptr(len(seen))
+	if arena.Len() != live {
+		t.Fatalf("expected len %d after compact, got %d", live, arena.Len())
+	}
+	// freed tail is reusable
+	for i := live; i < n; i++ {
+		if _, err := arena.Alloc(-1); err != nil {
+			t.Fatalf("Alloc into freed tail failed: %v", err)
+		}
+	}
+}
+
+// TestCompactEpoch advances the epoch when Compact drops slots, staling indices
+func TestCompactEpoch(t *testing.T) {
+	arena := NewAtomicArena[int](4)
+	_, _ = arena.AppendSlice([]int{1, 2, 3})
+	x, err := BuildIndex(arena, func(v *int) int { return *v }, LastWriterWins)
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	arena.Compact()
+	if arena.Epoch() != 0 || x.Stale() {
+		t.Fatalf("expected a Compact with nothing to drop to keep epoch 0, got %d", arena.Epoch())
+	}
+	_ = arena.Tombstone(0)
+	arena.Compact()
+	if arena.Epoch() != 1 || !x.Stale() {
+		t.Fatalf("expected Compact to advance the epoch to 1, got %d", arena.Epoch())
+	}
+	if _, ok := x.Get(3); ok {
+		t.Fatal("expected the stale index to miss")
+	}
+}
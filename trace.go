@@ -0,0 +1,56 @@
+package atomicarena
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/trace"
+)
+
+// Region names and the log category used in execution traces.
+const (
+	regionReset = "atomicarena.Reset"
+	regionFree  = "atomicarena.Free"
+	regionDrain = "atomicarena.Drain"
+	traceCat    = "atomicarena"
+)
+
+// WithTracing annotates the execution trace with the arena's lifecycle:
+// Reset, Free and BatchedArena.Drain run inside trace regions and log the
+// arena's name and the number of elements released, and allocations that
+// fail for lack of capacity log an event. Nothing is recorded, and nothing
+// beyond a flag check is paid, unless a trace is being collected.
+func WithTracing() Option {
+	return func(o *options) { o.tracing = true }
+}
+
+// traceLabel identifies the arena in trace logs: its name, or its element
+// type if it is unnamed.
+func (a *AtomicArena[T]) traceLabel() string {
+	if a.opts.name != "" {
+		return a.opts.name
+	}
+	return "AtomicArena[" + reflect.TypeFor[T]().String() + "]"
+}
+
+func (a *AtomicArena[T]) tracedReset(release bool) error {
+	ctx := context.Background()
+	defer trace.StartRegion(ctx, regionReset).End()
+	n, ok, err := a.rewind(release, nil)
+	if ok {
+		trace.Log(ctx, traceCat, fmt.Sprintf("%s: reset released %d", a.traceLabel(), n))
+	}
+	return err
+}
+
+func (a *AtomicArena[T]) tracedFree() error {
+	ctx := context.Background()
+	defer trace.StartRegion(ctx, regionFree).End()
+	n, err := a.free()
+	trace.Log(ctx, traceCat, fmt.Sprintf("%s: free released %d", a.traceLabel(), n))
+	return err
+}
+
+func (a *AtomicArena[T]) traceFull() {
+	trace.Log(context.Background(), traceCat, fmt.Sprintf("%s: full at capacity %d", a.traceLabel(), a.maxElems.Load()))
+}
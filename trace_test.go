@@ -0,0 +1,64 @@
+package atomicarena
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+)
+
+// TestTracing records a trace and checks the regions and logs appear in it.
+// Without a trace parser in the standard library it searches the encoded
+// trace, whose string table holds region names and log messages verbatim.
+func TestTracing(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("cannot start trace: %v", err)
+	}
+	a := NewAtomicArena[int](2, WithTracing(), WithName("traced"))
+	a.AppendSlice([]int{1, 2})
+	a.Alloc(3)
+	a.Free()
+	a.Reset(false)
+	b := NewBatchedArena[int](16, 4, WithTracing())
+	b.NewBatch().Alloc(1)
+	b.Drain()
+	untraced := NewAtomicArena[int](1, WithName("untraced"))
+	untraced.Alloc(1)
+	untraced.Alloc(2)
+	untraced.Reset(false)
+	trace.Stop()
+
+	out := buf.Bytes()
+	for _, want := range []string{
+		regionReset, regionFree, regionDrain, traceCat,
+		"traced: free released 2",
+		"traced: reset released 2",
+		"traced: full at capacity 2",
+		"AtomicArena[int]: reset released 1", // Flush gives back the unused tail of the batch
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("expected %q in the trace", want)
+		}
+	}
+	if bytes.Contains(out, []byte("untraced")) {
+		t.Errorf("expected arenas without WithTracing to stay out of the trace")
+	}
+}
+
+// TestTracingIdle ensures traced arenas behave normally when no trace runs
+func TestTracingIdle(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("a trace is already running")
+	}
+	a := NewAtomicArena[int](1, WithTracing())
+	a.Alloc(1)
+	if _, err := a.Alloc(2); err == nil {
+		t.Fatalf("expected the arena to be full")
+	}
+	if err := a.Free(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Reset(true); err != nil || a.Len() != 0 {
+		t.Fatalf("expected an empty arena after Reset, got len %d, %v", a.Len(), err)
+	}
+}
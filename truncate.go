@@ -0,0 +1,127 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrTruncateInUse is returned by Truncate when more slots are allocated
+// than the requested capacity.
+var ErrTruncateInUse = errors.New("atomicarena: slots beyond the new capacity are in use")
+
+// Truncate lowers the arena's capacity to newMax, for arenas provisioned
+// larger than their working set turned out to be. Allocated slots must all
+// lie below newMax, or it fails with ErrTruncateInUse; raising the capacity
+// fails with ErrOutOfRange.
+//
+// With allowMove set, the allocated elements are copied to a new buffer of
+// newMax slots so the old one can be collected. Every pointer and slice
+// previously returned by the arena then refers to the old buffer: it must
+// not be used again, and writes through it are lost. Without allowMove the
+// storage stays where it is and only the capacity drops, so pointers stay
+// valid but no memory is returned. Indices are unaffected either way.
+//
+// Truncate needs exclusive access: no other goroutine may read the arena
+// while it runs. It fails with ErrNotQuiescent if writes are still in flight
+// and ErrFrozen on a frozen arena. Watermarks are recomputed for the new
+// capacity and a soft cap above it is lowered to it.
+func (a *AtomicArena[T]) Truncate(newMax uintptr, allowMove bool) error {
+	for {
+		c := a.count.Load()
+		if c&frozenBit != 0 {
+			return a.frozenErr()
+		}
+		if c&busyBit != 0 {
+			runtime.Gosched()
+			continue
+		}
+		n := c & countMask
+		switch limit := a.maxElems.Load(); {
+		case newMax > limit:
+			return fmt.Errorf("%w: cannot raise the capacity from %d to %d", ErrOutOfRange, limit, newMax)
+		case n > newMax:
+			return fmt.Errorf("%w: %d slots are allocated, capacity %d requested", ErrTruncateInUse, n, newMax)
+		case a.done.Load() != n:
+			return ErrNotQuiescent
+		}
+		if !a.count.CompareAndSwap(c, c|busyBit) {
+			continue
+		}
+		if allowMove {
+			if err := a.moveTo(newMax, n); err != nil {
+				a.count.Store(c)
+				return err
+			}
+		} else {
+			a.resliceTo(newMax)
+		}
+		a.setCap(newMax, n)
+		a.count.Store(c)
+		return nil
+	}
+}
+
+// resliceTo cuts the arena's storage down to newMax slots in place.
+func (a *AtomicArena[T]) resliceTo(newMax uintptr) {
+	a.raw = a.raw[:newMax:newMax]
+	if a.ptrs != nil {
+		a.ptrs = a.ptrs[:newMax:newMax]
+	}
+	words := (newMax + 63) / 64
+	a.dead = a.dead[:words:words]
+	if a.ttl != nil {
+		a.ttl.at = a.ttl.at[:newMax:newMax]
+	}
+	a.dirty.Store(min(a.dirty.Load(), newMax))
+}
+
+// moveTo copies the first n slots and their bookkeeping into fresh storage
+// of newMax slots and releases the difference from the arena's budget.
+func (a *AtomicArena[T]) moveTo(newMax, n uintptr) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: requested %s: %v", ErrTooLarge, describeRequest[T](newMax), r)
+		}
+	}()
+	raw, err := alignedSlice[T](newMax, a.opts.baseAlign)
+	if err != nil {
+		return err
+	}
+	copy(raw, a.raw[:n])
+	var ptrs []atomic.Pointer[T]
+	if a.ptrs != nil {
+		ptrs = make([]atomic.Pointer[T], newMax)
+		for i := uintptr(0); i < n; i++ {
+			if a.ptrs[i].Load() != nil {
+				ptrs[i].Store(&raw[i])
+			}
+		}
+	}
+	dead := make([]atomic.Uint64, (newMax+63)/64)
+	for w := range dead {
+		dead[w].Store(a.dead[w].Load())
+	}
+	var at []atomic.Uint32
+	if a.ttl != nil {
+		at = make([]atomic.Uint32, newMax)
+		for i := uintptr(0); i < n; i++ {
+			at[i].Store(a.ttl.at[i].Load())
+		}
+	}
+	// nothing can fail from here on
+	a.raw, a.ptrs, a.dead = raw, ptrs, dead
+	if a.ttl != nil {
+		a.ttl.at = at
+	}
+	if a.budget != nil {
+		kept := newMax * unsafe.Sizeof(*new(T))
+		a.budget.release(a.budgetBytes - kept)
+		a.budgetBytes = kept
+	}
+	// the new buffer is zero past the allocated slots
+	a.dirty.Store(n)
+	return nil
+}
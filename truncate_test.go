@@ -0,0 +1,111 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestTruncateInPlace lowers the capacity and keeps existing pointers valid
+func TestTruncateInPlace(t *testing.T) {
+	a := NewAtomicArena[int](100)
+	p, _ := a.Alloc(7)
+	if err := a.Truncate(10, false); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	if a.Cap() != 10 || a.SoftCap() != 10 {
+		t.Fatalf("expected capacity 10, got %d (soft cap %d)", a.Cap(), a.SoftCap())
+	}
+	if got, _ := a.Get(0); got != p || *p != 7 {
+		t.Fatalf("expected the pointer to stay valid")
+	}
+	if _, err := a.Reserve(9); err != nil {
+		t.Fatalf("expected 9 free slots, got %v", err)
+	}
+	if _, err := a.Alloc(1); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull at the new capacity, got %v", err)
+	}
+}
+
+// TestTruncateMove copies the elements to a smaller buffer and invalidates old pointers
+func TestTruncateMove(t *testing.T) {
+	b := NewBudget(1 << 20)
+	a, err := NewAtomicArenaWithBudget[int64](1000, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := make([]*int64, 4)
+	for i := range old {
+		old[i], _ = a.Alloc(int64(i + 1))
+	}
+	a.Tombstone(2)
+	if err := a.Truncate(8, true); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	if b.Used() != 8*8 {
+		t.Fatalf("expected the budget to keep 64 bytes, got %d", b.Used())
+	}
+	for i, p := range old {
+		got, ok := a.Get(uintptr(i))
+		if i == 2 {
+			if ok {
+				t.Fatalf("expected the tombstone to survive the move")
+			}
+			continue
+		}
+		if !ok || got == p || *got != int64(i+1) {
+			t.Fatalf("expected slot %d copied to new storage, got %v", i, got)
+		}
+		if a.ptrs[i].Load() != got {
+			t.Fatalf("expected the mirror to point into the new storage")
+		}
+	}
+	// writes through an old pointer no longer reach the arena
+	*old[0] = 99
+	if got, _ := a.Get(0); *got != 1 {
+		t.Fatalf("expected the old buffer to be detached, got %d", *got)
+	}
+	if _, err := a.AppendSlice(make([]int64, 4)); err != nil {
+		t.Fatalf("expected 4 free slots, got %v", err)
+	}
+	if _, err := a.Alloc(0); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull at the new capacity, got %v", err)
+	}
+}
+
+// TestTruncateErrors rejects truncation below the count, growth, in-flight writes and frozen arenas
+func TestTruncateErrors(t *testing.T) {
+	a := NewAtomicArena[int](10)
+	a.AppendSlice([]int{1, 2, 3})
+	if err := a.Truncate(2, true); !errors.Is(err, ErrTruncateInUse) {
+		t.Fatalf("expected ErrTruncateInUse, got %v", err)
+	}
+	if err := a.Truncate(11, false); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	if a.Cap() != 10 || a.Len() != 3 {
+		t.Fatalf("expected a failed Truncate to change nothing")
+	}
+	// a reservation whose write has not completed
+	if _, err := a.reserve(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Truncate(5, false); !errors.Is(err, ErrNotQuiescent) {
+		t.Fatalf("expected ErrNotQuiescent, got %v", err)
+	}
+	a.commit(1)
+	a.Freeze()
+	if err := a.Truncate(5, false); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+}
+
+// TestTruncateWatermarks recomputes thresholds for the new capacity
+func TestTruncateWatermarks(t *testing.T) {
+	var fired []uintptr
+	a := NewAtomicArena[int](100, WithHighWatermark(0.5, func(n, c uintptr) { fired = append(fired, n, c) }))
+	a.Truncate(10, false)
+	a.AppendSlice(make([]int, 5))
+	if len(fired) != 2 || fired[0] != 5 || fired[1] != 10 {
+		t.Fatalf("expected the 50%% mark at 5 of 10, got %v", fired)
+	}
+}
@@ -0,0 +1,29 @@
+package atomicarena
+
+import "errors"
+
+// ErrContended is returned by TryAlloc when another reservation changed the
+// arena between reading its count and claiming a slot, or a Reset or other
+// exclusive operation was in progress.
+var ErrContended = errors.New("atomicarena: reservation contended")
+
+// TryAlloc is Alloc that does not spin: it makes exactly one attempt to
+// claim a slot and fails fast with ErrContended if that attempt loses a race,
+// for callers that prefer to back off, or go elsewhere, at a higher level.
+// A TryAlloc with no concurrent reservation never fails with ErrContended,
+// and under contention each ErrContended means the count moved in between,
+// almost always because another reservation succeeded, so the arena as a
+// whole keeps making progress. Lost attempts are not counted in
+// Stats.ReservationRetries, which only counts the retries of the spinning
+// paths. Other errors are those of Alloc.
+func (a *AtomicArena[T]) TryAlloc(obj T) (*T, error) {
+	start, err := a.reserveWithin(1, a.softCap.Load(), nil, true)
+	if err != nil {
+		return nil, a.allocErr(err, start, 1)
+	}
+	p := a.store(start, obj)
+	if a.prof != nil {
+		a.prof.sample(1)
+	}
+	return p, nil
+}
@@ -0,0 +1,107 @@
+package atomicarena
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// contend runs workers goroutines that call try until it reports the arena
+// full, then resets the arena, for rounds rounds or until stop reports true.
+func contend(a *AtomicArena[int], workers, rounds int, try func() bool, stop func() bool) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(max(workers, 4)))
+	for r := 0; r < rounds && !stop(); r++ {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for try() {
+				}
+			}()
+		}
+		wg.Wait()
+		a.Reset(false)
+	}
+}
+
+// TestReservationRetries forces lost CASes with many goroutines on a small arena and checks they are counted
+func TestReservationRetries(t *testing.T) {
+	a := NewAtomicArena[int](64)
+	deadline := time.Now().Add(10 * time.Second)
+	contend(a, 16, 1<<20, func() bool {
+		_, err := a.Alloc(1)
+		return err == nil
+	}, func() bool { return a.Stats().ReservationRetries > 0 || time.Now().After(deadline) })
+	if a.Stats().ReservationRetries == 0 {
+		t.Fatalf("expected contended reservations to be counted")
+	}
+
+	b := NewAtomicArena[int](64)
+	for i := 0; i < 64; i++ {
+		b.Alloc(i)
+	}
+	if n := b.Stats().ReservationRetries; n != 0 {
+		t.Fatalf("expected no retries without contention, got %d", n)
+	}
+}
+
+// TestTryAlloc never reports contention alone, and under it loses no slot and fails only with ErrContended or ErrArenaFull
+func TestTryAlloc(t *testing.T) {
+	a := NewAtomicArena[int](4, WithName("try"))
+	for i := 0; i < 4; i++ {
+		if _, err := a.TryAlloc(i); err != nil {
+			t.Fatalf("expected TryAlloc to succeed without contention, got %v", err)
+		}
+	}
+	if _, err := a.TryAlloc(4); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	a.Freeze()
+	if _, err := a.TryAlloc(5); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+
+	const size, workers = 64, 16
+	c := NewAtomicArena[int](size)
+	var ok, contended, other atomic.Int64
+	var rounds int
+	deadline := time.Now().Add(10 * time.Second)
+	contend(c, workers, 1<<20, func() bool {
+		_, err := c.TryAlloc(1)
+		switch {
+		case err == nil:
+			ok.Add(1)
+		case errors.Is(err, ErrContended):
+			contended.Add(1)
+		case errors.Is(err, ErrArenaFull):
+			return false
+		default:
+			other.Add(1)
+			return false
+		}
+		return true
+	}, func() bool {
+		rounds++
+		return contended.Load() > 0 || time.Now().After(deadline)
+	})
+	if other.Load() != 0 {
+		t.Fatalf("expected only ErrContended and ErrArenaFull, got %d other errors", other.Load())
+	}
+	if got, want := ok.Load(), int64(size*(rounds-1)); got != want {
+		t.Fatalf("expected every round to fill all %d slots, got %d successes in %d rounds", size, got, rounds-1)
+	}
+	if contended.Load() == 0 {
+		t.Fatalf("expected some attempts to lose the race")
+	}
+	// each success can make at most every other worker's attempt lose
+	if contended.Load() > (workers-1)*ok.Load() {
+		t.Fatalf("expected at most %d lost attempts per success, got %d against %d", workers-1, contended.Load(), ok.Load())
+	}
+	if s := c.Stats(); s.ReservationRetries != 0 {
+		t.Fatalf("expected TryAlloc not to count retries, got %d", s.ReservationRetries)
+	}
+}
@@ -0,0 +1,112 @@
+package atomicarena
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// WithTTL gives every element a time to live of d. The arena records when
+// each slot was reserved, in whole seconds since the arena was created, which
+// costs four bytes and one clock read per reservation. Get and Resolve treat
+// an element whose time is up as missing, and ExpireOlderThan tombstones
+// such elements so the next Compact or Reset reclaims their slots. A
+// janitor started with StartJanitor also sweeps expired elements on every
+// tick. Time is read from the arena's Clock. d must be at least a second.
+func WithTTL(d time.Duration) Option {
+	return func(o *options) { o.ttl = d }
+}
+
+// validateTTL checks a WithTTL duration.
+func validateTTL(d time.Duration) error {
+	if d != 0 && d < time.Second {
+		return fmt.Errorf("%w: WithTTL needs at least a second, got %v", ErrInvalidOptions, d)
+	}
+	return nil
+}
+
+// ttlStamps records the reservation time of every slot.
+type ttlStamps struct {
+	ttl   time.Duration
+	clock Clock
+	base  time.Time       // time zero of the stamps
+	at    []atomic.Uint32 // seconds after base at which each slot was reserved
+}
+
+func newTTLStamps(o options, n uintptr) *ttlStamps {
+	if o.ttl == 0 {
+		return nil
+	}
+	c := o.timeSource()
+	return &ttlStamps{ttl: o.ttl, clock: c, base: c.Now(), at: make([]atomic.Uint32, n)}
+}
+
+// since converts t to whole seconds after base, clamped to the uint32 range.
+func (s *ttlStamps) since(t time.Time) uint32 {
+	d := t.Sub(s.base) / time.Second
+	return uint32(min(max(d, 0), math.MaxUint32))
+}
+
+// stamp records now as the reservation time of slots [start, start+n).
+func (s *ttlStamps) stamp(start, n uintptr) {
+	now := s.since(s.clock.Now())
+	for i := start; i < start+n; i++ {
+		s.at[i].Store(now)
+	}
+}
+
+// before reports whether slot i was reserved before second sec.
+func (s *ttlStamps) before(i uintptr, sec uint32) bool {
+	return s.at[i].Load() < sec
+}
+
+// expiredAt returns the first stamp that is still alive now: slots stamped
+// below it have outlived the TTL.
+func (s *ttlStamps) expiredAt(now time.Time) uint32 {
+	cutoff := now.Add(-s.ttl)
+	if cutoff.Before(s.base) {
+		return 0
+	}
+	return s.since(cutoff) + 1
+}
+
+// expired reports whether slot i has outlived the TTL.
+func (a *AtomicArena[T]) expired(i uintptr) bool {
+	return a.ttl != nil && a.ttl.before(i, a.ttl.expiredAt(a.ttl.clock.Now()))
+}
+
+// ExpireOlderThan tombstones every committed element reserved before cutoff,
+// to the second, running the destructor on each, and returns how many it
+// tombstoned. Their slots are reclaimed by the next Compact or Reset.
+// Destructor errors are discarded. It returns 0 on a frozen arena or one
+// built without WithTTL.
+func (a *AtomicArena[T]) ExpireOlderThan(cutoff time.Time) int {
+	if a.ttl == nil || a.Frozen() {
+		return 0
+	}
+	return a.expireBefore(a.ttl.since(cutoff))
+}
+
+// expireBefore tombstones the committed elements stamped before second sec.
+func (a *AtomicArena[T]) expireBefore(sec uint32) int {
+	expired := 0
+	n := a.Committed()
+	for i := uintptr(0); i < n; i++ {
+		if a.ttl.before(i, sec) && a.markDead(i) {
+			if a.dtor != nil {
+				_ = a.dtor(&a.raw[i])
+			}
+			expired++
+		}
+	}
+	return expired
+}
+
+// expireStale is the janitor's sweep: it tombstones every element that has
+// outlived the TTL.
+func (a *AtomicArena[T]) expireStale() {
+	if a.ttl != nil && !a.Frozen() {
+		a.expireBefore(a.ttl.expiredAt(a.ttl.clock.Now()))
+	}
+}
@@ -0,0 +1,124 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTTLBoundary hides an element from Get and Resolve once its TTL is up
+func TestTTLBoundary(t *testing.T) {
+	f := newStepClock()
+	a := NewAtomicArena[int](4, WithClock(f), WithTTL(10*time.Second))
+	idx, err := a.AllocIdx(1)
+	if err != nil {
+		t.Fatalf("AllocIdx failed: %v", err)
+	}
+	f.now = f.now.Add(9*time.Second + 999*time.Millisecond)
+	if p, ok := a.Get(0); !ok || *p != 1 {
+		t.Fatalf("expected the element to be alive just before its TTL")
+	}
+	f.now = f.now.Add(time.Millisecond)
+	if _, ok := a.Get(0); ok {
+		t.Fatalf("expected the element to be expired at its TTL")
+	}
+	if p := a.Resolve(idx); p != nil {
+		t.Fatalf("expected Resolve to miss an expired element, got %d", *p)
+	}
+	if !a.Alive(0) {
+		t.Fatalf("expected expiry alone not to tombstone the slot")
+	}
+}
+
+// TestExpireOlderThan tombstones elements stamped before the cutoff second
+func TestExpireOlderThan(t *testing.T) {
+	f := newStepClock()
+	destroyed := 0
+	a := NewAtomicArena[int](8, WithClock(f), WithTTL(time.Minute),
+		WithDestructor(func(*int) { destroyed++ }))
+	for i := 0; i < 6; i++ {
+		a.Alloc(i)
+		f.now = f.now.Add(time.Second)
+	}
+	if n := a.ExpireOlderThan(f.now.Add(-6 * time.Second)); n != 0 {
+		t.Fatalf("expected nothing older than the first element, got %d", n)
+	}
+	if n := a.ExpireOlderThan(f.now.Add(-3 * time.Second)); n != 3 {
+		t.Fatalf("expected 3 expired elements, got %d", n)
+	}
+	if n := a.ExpireOlderThan(f.now); n != 3 {
+		t.Fatalf("expected the other 3 to expire without repeating the first, got %d", n)
+	}
+	if destroyed != 6 || a.Alive(5) {
+		t.Fatalf("expected all 6 destroyed and tombstoned, got %d destroyed", destroyed)
+	}
+	if n := NewAtomicArena[int](2).ExpireOlderThan(f.now); n != 0 {
+		t.Fatalf("expected 0 without WithTTL, got %d", n)
+	}
+}
+
+// TestTTLReuse reclaims expired slots for new elements, which get new stamps
+func TestTTLReuse(t *testing.T) {
+	f := newStepClock()
+	a := NewAtomicArena[int](4, WithClock(f), WithTTL(5*time.Second))
+	for i := 0; i < 4; i++ {
+		a.Alloc(i)
+	}
+	if _, err := a.Alloc(4); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	f.now = f.now.Add(3 * time.Second)
+	a.Tombstone(3)
+	a.Compact()
+	late, _, err := a.AllocIndexed(30)
+	if err != nil {
+		t.Fatalf("expected the compacted slot to be reused, got %v", err)
+	}
+	f.now = f.now.Add(2 * time.Second)
+	if n := a.ExpireOlderThan(f.now.Add(-4 * time.Second)); n != 3 {
+		t.Fatalf("expected the 3 old elements to expire, got %d", n)
+	}
+	moved := a.Compact()
+	if a.Len() != 1 || len(moved) != 1 || moved[late] != 0 {
+		t.Fatalf("expected the late element to move to slot 0, got len %d, moves %v", a.Len(), moved)
+	}
+	if p, ok := a.Get(0); !ok || *p != 30 {
+		t.Fatalf("expected the moved element to keep its stamp and stay alive")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := a.Alloc(i); err != nil {
+			t.Fatalf("expected reclaimed slots to be reused, got %v", err)
+		}
+	}
+	f.now = f.now.Add(3 * time.Second)
+	if _, ok := a.Get(0); ok {
+		t.Fatalf("expected the moved element to expire on its own stamp")
+	}
+	if _, ok := a.Get(1); !ok {
+		t.Fatalf("expected a reused slot to carry its new stamp")
+	}
+}
+
+// TestTTLOptionValidation rejects TTLs below a second
+func TestTTLOptionValidation(t *testing.T) {
+	if _, err := New[int](4, WithTTL(500*time.Millisecond)); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions, got %v", err)
+	}
+	if _, err := New[int](4, WithTTL(time.Second)); err != nil {
+		t.Fatalf("expected a one-second TTL to be accepted, got %v", err)
+	}
+}
+
+// TestTTLSweep tombstones exactly the elements past their TTL, as the janitor does on each tick
+func TestTTLSweep(t *testing.T) {
+	f := newStepClock()
+	a := NewAtomicArena[int](4, WithClock(f), WithTTL(2*time.Second))
+	a.Alloc(1)
+	f.now = f.now.Add(time.Second)
+	a.Alloc(2)
+	f.now = f.now.Add(time.Second)
+	a.expireStale()
+	if a.Alive(0) || !a.Alive(1) {
+		t.Fatalf("expected only the first element to be swept")
+	}
+}
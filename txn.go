@@ -0,0 +1,149 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrOutOfOrder is returned by Txn.Rollback when slots allocated after
+	// the transaction's range are still live, and by operations on a
+	// transaction while a nested one is open.
+	ErrOutOfOrder = errors.New("atomicarena: transaction out of order")
+	// ErrTxnDone is returned by operations on a committed or rolled back
+	// transaction.
+	ErrTxnDone = errors.New("atomicarena: transaction already finished")
+)
+
+// Txn is a transaction on an arena: it owns the contiguous range of slots
+// allocated through it, which Commit keeps and Rollback releases.
+// Transactions nest: a transaction begun from another owns its slots until
+// it commits, and then hands them to its parent, so rolling back the parent
+// also releases them.
+//
+// A transaction assumes a single writer. While it is open, the arena must
+// only be allocated into through it or through the transactions nested in
+// it, all from one goroutine; that is what keeps its range contiguous.
+// Rollback is only legal for the most recent slots: it fails with
+// ErrOutOfOrder once a later transaction has committed past it. A Reset of
+// the arena discards every open transaction, which then return ErrStale.
+type Txn[T any] struct {
+	a      *AtomicArena[T]
+	parent *Txn[T]
+	child  *Txn[T] // open nested transaction, if any
+	lo, hi uintptr // owned slots [lo, hi)
+	epoch  uint64
+	done   bool
+}
+
+// Begin starts a transaction at the arena's current length.
+func (a *AtomicArena[T]) Begin() *Txn[T] {
+	n := a.Len()
+	return &Txn[T]{a: a, lo: n, hi: n, epoch: a.Epoch()}
+}
+
+// Begin starts a transaction nested in t. Until the nested transaction
+// finishes, t itself can only be read.
+func (t *Txn[T]) Begin() (*Txn[T], error) {
+	if err := t.usable(); err != nil {
+		return nil, err
+	}
+	t.child = &Txn[T]{a: t.a, parent: t, lo: t.hi, hi: t.hi, epoch: t.epoch}
+	return t.child, nil
+}
+
+// usable reports why t cannot be used right now, if it can't.
+func (t *Txn[T]) usable() error {
+	switch {
+	case t.done:
+		return ErrTxnDone
+	case t.a.Epoch() != t.epoch:
+		return ErrStale
+	case t.child != nil:
+		return fmt.Errorf("%w: a nested transaction is open", ErrOutOfOrder)
+	}
+	return nil
+}
+
+// Range returns the slots [lo, hi) the transaction owns, including those of
+// committed nested transactions.
+func (t *Txn[T]) Range() (lo, hi uintptr) {
+	return t.lo, t.hi
+}
+
+// Alloc stores obj in the arena as part of the transaction.
+func (t *Txn[T]) Alloc(obj T) (*T, error) {
+	if err := t.usable(); err != nil {
+		return nil, err
+	}
+	i, p, err := t.a.AllocIndexed(obj)
+	if err != nil {
+		return nil, err
+	}
+	t.hi = i + 1
+	return p, nil
+}
+
+// AppendSlice copies objs into the arena as part of the transaction.
+func (t *Txn[T]) AppendSlice(objs []T) ([]T, error) {
+	if err := t.usable(); err != nil {
+		return nil, err
+	}
+	start, seg, err := t.a.appendIndexed(objs)
+	if err != nil {
+		return nil, err
+	}
+	t.hi = start + uintptr(len(seg))
+	return seg, nil
+}
+
+// Commit makes the transaction's slots permanent. A nested transaction
+// passes them to its parent instead, which may still roll them back.
+func (t *Txn[T]) Commit() error {
+	if err := t.usable(); err != nil {
+		return err
+	}
+	t.finish()
+	if p := t.parent; p != nil {
+		p.hi = max(p.hi, t.hi)
+	}
+	return nil
+}
+
+// Rollback releases the transaction's slots, zeroing them and rolling the
+// arena's count back to where the transaction began. It fails with
+// ErrOutOfOrder, leaving everything in place, if slots beyond the
+// transaction's range are allocated, for example by a later transaction
+// that has committed.
+func (t *Txn[T]) Rollback() error {
+	if err := t.usable(); err != nil {
+		return err
+	}
+	if t.lo == t.hi {
+		t.finish()
+		return nil
+	}
+	err := t.a.rollback(t.lo, func(n uintptr) error {
+		if t.a.Epoch() != t.epoch {
+			return ErrStale
+		}
+		if n != t.hi {
+			return fmt.Errorf("%w: transaction owns [%d, %d) but the count is %d", ErrOutOfOrder, t.lo, t.hi, n)
+		}
+		return nil
+	})
+	// anything else comes from a destructor, after the slots were released
+	if errors.Is(err, ErrOutOfOrder) || errors.Is(err, ErrStale) || errors.Is(err, ErrFrozen) || errors.Is(err, ErrClosed) {
+		return err
+	}
+	t.finish()
+	return err
+}
+
+// finish closes t and detaches it from its parent.
+func (t *Txn[T]) finish() {
+	t.done = true
+	if p := t.parent; p != nil && p.child == t {
+		p.child = nil
+	}
+}
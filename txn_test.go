@@ -0,0 +1,113 @@
+package atomicarena
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestTxnNested commits and rolls back nested transactions
+func TestTxnNested(t *testing.T) {
+	a := NewAtomicArena[int](16)
+	a.Alloc(0)
+	outer := a.Begin()
+	outer.Alloc(1)
+	inner, err := outer.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := outer.Alloc(9); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("expected the parent to be locked while a nested txn is open, got %v", err)
+	}
+	inner.AppendSlice([]int{2, 3})
+	if err := inner.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if lo, hi := outer.Range(); lo != 1 || hi != 4 {
+		t.Fatalf("expected the parent to own [1, 4), got [%d, %d)", lo, hi)
+	}
+	if err := inner.Rollback(); !errors.Is(err, ErrTxnDone) {
+		t.Fatalf("expected ErrTxnDone, got %v", err)
+	}
+
+	// a nested rollback releases only its own slots
+	inner, _ = outer.Begin()
+	inner.Alloc(4)
+	if err := inner.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	outer.Alloc(5)
+	if got := a.Snapshot(); !slices.Equal(got, []int{0, 1, 2, 3, 5}) {
+		t.Fatalf("after nested rollback: %v", got)
+	}
+
+	// rolling back the parent releases the committed child too
+	if err := outer.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Snapshot(); !slices.Equal(got, []int{0}) {
+		t.Fatalf("after outer rollback: %v", got)
+	}
+	inspectReleased(a)
+	if a.raw[1] != 0 || a.raw[4] != 0 {
+		t.Fatalf("expected rolled back slots to be zeroed")
+	}
+
+	committed := a.Begin()
+	committed.Alloc(6)
+	if err := committed.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Snapshot(); !slices.Equal(got, []int{0, 6}) {
+		t.Fatalf("after commit: %v", got)
+	}
+}
+
+// TestTxnOutOfOrder rejects rolling back a transaction a later one has
+// committed past
+func TestTxnOutOfOrder(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	first := a.Begin()
+	first.Alloc(1)
+	first.Commit()
+	// an uncommitted transaction followed by a committed one
+	second := a.Begin()
+	second.Alloc(2)
+	third := a.Begin()
+	third.Alloc(3)
+	third.Commit()
+	if err := second.Rollback(); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("expected ErrOutOfOrder, got %v", err)
+	}
+	if got := a.Snapshot(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("rejected rollback changed the arena: %v", got)
+	}
+	// second is still open and may commit instead
+	if err := second.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	empty := a.Begin()
+	a.Alloc(4)
+	if err := empty.Rollback(); err != nil {
+		t.Fatalf("expected an empty rollback to succeed, got %v", err)
+	}
+}
+
+// TestTxnReset makes open transactions stale
+func TestTxnReset(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	txn := a.Begin()
+	txn.Alloc(1)
+	nested, _ := txn.Begin()
+	a.Reset(true)
+	a.AppendSlice([]int{7, 8})
+	if _, err := nested.Alloc(2); !errors.Is(err, ErrStale) {
+		t.Fatalf("expected ErrStale after Reset, got %v", err)
+	}
+	if err := txn.Rollback(); !errors.Is(err, ErrStale) {
+		t.Fatalf("expected ErrStale after Reset, got %v", err)
+	}
+	if got := a.Snapshot(); !slices.Equal(got, []int{7, 8}) {
+		t.Fatalf("stale transaction touched the arena: %v", got)
+	}
+}
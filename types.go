@@ -0,0 +1,33 @@
+package atomicarena
+
+import "reflect"
+
+// hasPointers reports whether T contains pointers; see typeHasPointers.
+// Arenas evaluate it once at construction and keep the result.
+func hasPointers[T any]() bool {
+	return typeHasPointers(reflect.TypeFor[T]())
+}
+
+// typeHasPointers reports whether values of type t contain any pointers the
+// garbage collector would need to see, which rules out byte-level tricks such
+// as raw serialization or storage outside the Go heap.
+func typeHasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return t.Len() > 0 && typeHasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if typeHasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	default:
+		// pointers, strings, slices, maps, chans, funcs, interfaces, unsafe.Pointer
+		return true
+	}
+}
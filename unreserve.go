@@ -0,0 +1,126 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+var (
+	// ErrNotMostRecent is returned by Unreserve for a segment that is not the
+	// arena's most recent reservation.
+	ErrNotMostRecent = errors.New("atomicarena: segment is not the most recent reservation")
+	// ErrForeignSegment is returned by Unreserve for a slice that was not
+	// reserved from the arena.
+	ErrForeignSegment = errors.New("atomicarena: segment does not belong to the arena")
+)
+
+// Unreserve gives back seg, which must be the arena's most recent reservation
+// as returned by Reserve, ReserveIndexed or AppendSlice: it must end exactly
+// at the current count. The segment is zeroed and the count rolled back in
+// one step, so the slots are immediately available again. If another
+// allocation has happened since, the segment stays allocated and Unreserve
+// returns ErrNotMostRecent; it returns ErrForeignSegment if seg is not part
+// of the arena and ErrFrozen on a frozen arena. An empty seg is a no-op.
+func (a *AtomicArena[T]) Unreserve(seg []T) error {
+	if len(seg) == 0 {
+		return nil
+	}
+	start, ok := a.segmentStart(seg)
+	if !ok {
+		return ErrForeignSegment
+	}
+	end := start + uintptr(len(seg))
+	return a.rollback(start, func(n uintptr) error {
+		if n != end {
+			return fmt.Errorf("%w: it covers slots [%d, %d) but the count is %d", ErrNotMostRecent, start, end, n)
+		}
+		return nil
+	})
+}
+
+// TryShrinkTo rolls the count back to n, zeroing and freeing every slot from
+// n on. It is meant for a caller that knows it is the only writer, and fails
+// with ErrNotQuiescent if writes are still in flight. Shrinking to more than
+// the current count fails; ErrFrozen is returned on a frozen arena.
+func (a *AtomicArena[T]) TryShrinkTo(n uintptr) error {
+	return a.rollback(n, func(cur uintptr) error {
+		if n > cur {
+			return fmt.Errorf("atomicarena: cannot shrink to %d, only %d slots are allocated", n, cur)
+		}
+		if a.done.Load() != cur {
+			return ErrNotQuiescent
+		}
+		return nil
+	})
+}
+
+// segmentStart returns the index of seg's first slot, reporting false if seg
+// does not lie within the arena's storage. Reserved segments extend to the
+// end of the buffer's capacity, which locates them even for zero-sized T.
+func (a *AtomicArena[T]) segmentStart(seg []T) (uintptr, bool) {
+	if cap(seg) > cap(a.raw) {
+		return 0, false
+	}
+	start := uintptr(cap(a.raw) - cap(seg))
+	if start+uintptr(len(seg)) > a.maxElems.Load() {
+		return 0, false
+	}
+	if unsafe.Sizeof(seg[0]) != 0 && &a.raw[start] != &seg[0] {
+		return 0, false
+	}
+	return start, true
+}
+
+// rollback moves the count back to to once check accepts the current count.
+// The busy bit holds off reservations while the released slots are zeroed,
+// as during a reset.
+func (a *AtomicArena[T]) rollback(to uintptr, check func(n uintptr) error) error {
+	for {
+		c := a.count.Load()
+		if c&frozenBit != 0 {
+			return a.frozenErr()
+		}
+		if c&busyBit != 0 {
+			runtime.Gosched()
+			continue
+		}
+		n := c & countMask
+		if err := check(n); err != nil {
+			return err
+		}
+		if !a.count.CompareAndSwap(c, c|busyBit) {
+			continue
+		}
+		var err error
+		if a.dtor != nil {
+			err = a.destroyLive(to, n)
+		}
+		a.dropUnpublished(a.unpublished(to, n), to)
+		a.zeroRange(to, n)
+		a.clearDead(to, n)
+		a.done.Add(^(n - to) + 1)
+		a.poisonSlots(to, n)
+		a.countDropped(n, to)
+		a.count.Store(to)
+		return err
+	}
+}
+
+// clearDead clears the tombstone bits of slots [lo, hi).
+func (a *AtomicArena[T]) clearDead(lo, hi uintptr) {
+	for i := lo; i < hi; {
+		w := &a.dead[i/64]
+		// mask covers the bits of this word that fall in the range
+		bits := min(hi-i, 64-i%64)
+		mask := ^uint64(0) >> (64 - bits) << (i % 64)
+		for {
+			old := w.Load()
+			if old&mask == 0 || w.CompareAndSwap(old, old&^mask) {
+				break
+			}
+		}
+		i += bits
+	}
+}
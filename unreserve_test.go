@@ -0,0 +1,136 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestUnreserve gives back the most recent reservation and reuses it
+func TestUnreserve(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	a.Alloc(1)
+	seg, _ := a.Reserve(4)
+	for i := range seg {
+		seg[i] = 9
+	}
+	a.Tombstone(3)
+	if err := a.Unreserve(seg); err != nil {
+		t.Fatalf("Unreserve failed: %v", err)
+	}
+	if a.Len() != 1 {
+		t.Fatalf("expected len 1 after Unreserve, got %d", a.Len())
+	}
+	inspectReleased(a)
+	for i := 1; i < 5; i++ {
+		if a.raw[i] != 0 || a.tombstoned(uintptr(i)) {
+			t.Fatalf("slot %d not reset: %d, dead=%v", i, a.raw[i], a.tombstoned(uintptr(i)))
+		}
+	}
+	idx, _, _ := a.AllocIndexed(2)
+	if idx != 1 {
+		t.Fatalf("expected the freed slot to be reused, got index %d", idx)
+	}
+	if err := a.Unreserve(nil); err != nil {
+		t.Fatalf("expected an empty segment to be a no-op, got %v", err)
+	}
+
+	// AppendSlice segments and zero-sized elements can be given back too
+	seg2, _ := a.AppendSlice([]int{5, 6})
+	if err := a.Unreserve(seg2); err != nil || a.Len() != 2 {
+		t.Fatalf("expected AppendSlice segment to be given back, len %d, %v", a.Len(), err)
+	}
+	z := NewAtomicArena[struct{}](4)
+	zs, _ := z.Reserve(3)
+	if err := z.Unreserve(zs); err != nil || z.Len() != 0 {
+		t.Fatalf("expected zero-sized segment to be given back, len %d, %v", z.Len(), err)
+	}
+}
+
+// TestUnreserveErrors covers stale, foreign and frozen segments
+func TestUnreserveErrors(t *testing.T) {
+	a := NewAtomicArena[int](8)
+	seg, _ := a.Reserve(2)
+	a.Alloc(3)
+	if err := a.Unreserve(seg); !errors.Is(err, ErrNotMostRecent) {
+		t.Fatalf("expected ErrNotMostRecent, got %v", err)
+	}
+	if a.Len() != 3 {
+		t.Fatalf("a failed Unreserve must not change the count, got %d", a.Len())
+	}
+	if err := a.Unreserve(make([]int, 2)); !errors.Is(err, ErrForeignSegment) {
+		t.Fatalf("expected ErrForeignSegment, got %v", err)
+	}
+	b := NewAtomicArena[int](8)
+	bs, _ := b.Reserve(8)
+	if err := a.Unreserve(bs); !errors.Is(err, ErrForeignSegment) {
+		t.Fatalf("expected ErrForeignSegment for another arena's segment, got %v", err)
+	}
+	last, _ := a.Reserve(1)
+	a.Freeze()
+	if err := a.Unreserve(last); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+}
+
+// TestUnreserveContended has a goroutine allocate between Reserve and Unreserve
+func TestUnreserveContended(t *testing.T) {
+	for round := 0; round < 200; round++ {
+		a := NewAtomicArena[int](64)
+		seg, _ := a.Reserve(4)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Alloc(7)
+		}()
+		err := a.Unreserve(seg)
+		wg.Wait()
+		switch {
+		case err == nil:
+			// Unreserve won the race; the Alloc landed in the freed space
+			if a.Len() != 1 || a.raw[0] != 7 {
+				t.Fatalf("expected only the Alloc to remain, len %d", a.Len())
+			}
+		case errors.Is(err, ErrNotMostRecent):
+			if a.Len() != 5 || a.raw[4] != 7 {
+				t.Fatalf("expected the segment and the Alloc to remain, len %d", a.Len())
+			}
+		default:
+			t.Fatalf("unexpected error %v", err)
+		}
+		if a.done.Load() != a.Len() {
+			t.Fatalf("done %d out of step with len %d", a.done.Load(), a.Len())
+		}
+	}
+}
+
+// TestTryShrinkTo rolls back to an absolute count
+func TestTryShrinkTo(t *testing.T) {
+	a := NewAtomicArena[int](130)
+	for i := 0; i < 130; i++ {
+		a.Alloc(i + 1)
+	}
+	for _, i := range []uintptr{10, 63, 64, 129} {
+		a.Tombstone(i)
+	}
+	if err := a.TryShrinkTo(200); err == nil {
+		t.Fatalf("expected shrinking past the count to fail")
+	}
+	if err := a.TryShrinkTo(60); err != nil {
+		t.Fatalf("TryShrinkTo failed: %v", err)
+	}
+	inspectReleased(a)
+	if a.Len() != 60 || a.raw[60] != 0 || a.raw[129] != 0 || a.ptrs[100].Load() != nil {
+		t.Fatalf("expected slots from 60 on to be freed, len %d", a.Len())
+	}
+	if !a.tombstoned(10) || a.tombstoned(63) || a.tombstoned(64) || a.tombstoned(129) {
+		t.Fatalf("expected only tombstones below the new count to remain")
+	}
+	if err := a.TryShrinkTo(60); err != nil {
+		t.Fatalf("shrinking to the current count should be a no-op, got %v", err)
+	}
+	if err := a.TryShrinkTo(0); err != nil || a.Len() != 0 {
+		t.Fatalf("expected an empty arena, len %d, %v", a.Len(), err)
+	}
+}
@@ -0,0 +1,47 @@
+package atomicarena
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// ErrMisaligned is returned when a view would start at an address that is
+// not a multiple of the viewed type's alignment.
+var ErrMisaligned = errors.New("atomicarena: misaligned view")
+
+// ViewAs returns a pointer to the U stored at byte offset off of the
+// allocated region of a, without copying. U must be pointer-free, the value
+// must lie entirely within the allocated bytes, and its address must be
+// suitably aligned for U; otherwise ErrPointerType, ErrOutOfRange or
+// ErrMisaligned is returned. The view dies at Reset like any buffer.
+func ViewAs[U any](a *ByteArena, off uintptr) (*U, error) {
+	s, err := SliceAs[U](a, off, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &s[0], nil
+}
+
+// SliceAs returns n consecutive values of type U starting at byte offset off
+// of the allocated region of a, under the same rules as ViewAs.
+func SliceAs[U any](a *ByteArena, off, n uintptr) ([]U, error) {
+	t := reflect.TypeFor[U]()
+	if typeHasPointers(t) {
+		return nil, fmt.Errorf("%w: cannot view bytes as %s", ErrPointerType, t)
+	}
+	size := t.Size()
+	used := a.Len()
+	if off > used || (size > 0 && n > (used-off)/size) {
+		return nil, fmt.Errorf("%w: %d %s at offset %d, %d bytes allocated", ErrOutOfRange, n, t, off, used)
+	}
+	if n == 0 {
+		return []U{}, nil
+	}
+	p := unsafe.Add(unsafe.Pointer(unsafe.SliceData(a.arena.raw)), off)
+	if align := uintptr(t.Align()); uintptr(p)%align != 0 {
+		return nil, fmt.Errorf("%w: %s at offset %d needs %d-byte alignment", ErrMisaligned, t, off, align)
+	}
+	return unsafe.Slice((*U)(p), n), nil
+}
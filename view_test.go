@@ -0,0 +1,91 @@
+package atomicarena
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+// frameHeader is laid out without padding: 4+2+2+8 bytes
+type frameHeader struct {
+	Magic   uint32
+	Version uint16
+	Flags   uint16
+	Length  uint64
+}
+
+// TestViewAsRoundTrip writes a header through a view and reads it back from bytes
+func TestViewAsRoundTrip(t *testing.T) {
+	if unsafe.Sizeof(frameHeader{}) != 16 {
+		t.Fatalf("frameHeader is padded: %d bytes", unsafe.Sizeof(frameHeader{}))
+	}
+	b := NewByteArena(256)
+	buf, _ := b.AllocBytes(64)
+	h, err := ViewAs[frameHeader](b, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*h = frameHeader{Magic: 0xCAFEBABE, Version: 3, Flags: 0x8001, Length: 1 << 40}
+	raw := buf[16:32]
+	if binary.NativeEndian.Uint32(raw[0:]) != 0xCAFEBABE || binary.NativeEndian.Uint16(raw[4:]) != 3 ||
+		binary.NativeEndian.Uint16(raw[6:]) != 0x8001 || binary.NativeEndian.Uint64(raw[8:]) != 1<<40 {
+		t.Fatalf("header bytes do not match fields: %x", raw)
+	}
+	again, _ := ViewAs[frameHeader](b, 16)
+	if *again != *h {
+		t.Fatalf("round trip mismatch: %+v vs %+v", *again, *h)
+	}
+	words, err := SliceAs[uint64](b, 8, 7)
+	if err != nil || len(words) != 7 || words[2] != 1<<40 {
+		t.Fatalf("SliceAs = %v, %v", words, err)
+	}
+}
+
+// TestViewAsErrors covers bounds, alignment and pointer checks
+func TestViewAsErrors(t *testing.T) {
+	b := NewByteArena(64)
+	b.AllocBytes(32)
+	if _, err := ViewAs[frameHeader](b, 24); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange past the allocated bytes, got %v", err)
+	}
+	if _, err := SliceAs[uint64](b, 0, ^uintptr(0)); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange for a huge count, got %v", err)
+	}
+	if _, err := ViewAs[uint64](b, 3); !errors.Is(err, ErrMisaligned) {
+		t.Fatalf("expected ErrMisaligned, got %v", err)
+	}
+	if _, err := ViewAs[*int](b, 0); !errors.Is(err, ErrPointerType) {
+		t.Fatalf("expected ErrPointerType, got %v", err)
+	}
+}
+
+// FuzzViewAs checks that every offset either errors or yields an in-bounds, aligned view
+func FuzzViewAs(f *testing.F) {
+	f.Add(uint64(0), uint64(1))
+	f.Add(uint64(7), uint64(3))
+	f.Add(uint64(1000), uint64(0))
+	f.Add(^uint64(0), ^uint64(0))
+	b := NewByteArena(512)
+	b.AllocBytes(200)
+	base := uintptr(unsafe.Pointer(&b.arena.raw[0]))
+	f.Fuzz(func(t *testing.T, off, n uint64) {
+		s, err := SliceAs[uint32](b, uintptr(off), uintptr(n))
+		if err != nil {
+			if !errors.Is(err, ErrOutOfRange) && !errors.Is(err, ErrMisaligned) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			return
+		}
+		if len(s) == 0 {
+			return
+		}
+		start := uintptr(unsafe.Pointer(&s[0]))
+		if start%4 != 0 || start < base || start-base+uintptr(len(s))*4 > 200 {
+			t.Fatalf("wild view at offset %d len %d", start-base, len(s))
+		}
+		for i := range s {
+			s[i] = 0
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package atomicarena
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// Watermark is a utilization threshold for WithHighWatermarks: Fn is called
+// when the arena's count rises to Fraction of its capacity.
+type Watermark struct {
+	Fraction float64
+	Fn       func(len, cap uintptr)
+}
+
+// WithHighWatermark calls fn once each time the arena's count crosses
+// fraction of its capacity going up. fn receives the count that crossed
+// the threshold and the capacity. It is re-armed when the count drops back
+// below the threshold, by Reset, Drain, Compact or a rollback. The option can
+// be repeated, e.g. for 75% and 90%.
+//
+// fn runs on the goroutine whose allocation crossed the threshold, before
+// that allocation returns and while it still holds off Reset, so it should be
+// quick and must not allocate from or reset the arena. The count includes
+// reservations that are still being written. fraction must be in (0, 1] and
+// fn must not be nil, or the constructor fails with ErrInvalidOptions.
+func WithHighWatermark(fraction float64, fn func(len, cap uintptr)) Option {
+	return WithHighWatermarks([]Watermark{{Fraction: fraction, Fn: fn}})
+}
+
+// WithHighWatermarks is WithHighWatermark for several thresholds at once.
+func WithHighWatermarks(marks []Watermark) Option {
+	marks = slices.Clone(marks)
+	return func(o *options) { o.watermarks = append(o.watermarks, marks...) }
+}
+
+// validateWatermarks checks the thresholds passed to WithHighWatermarks.
+func validateWatermarks(marks []Watermark) error {
+	for _, m := range marks {
+		if !(m.Fraction > 0 && m.Fraction <= 1) {
+			return fmt.Errorf("%w: watermark fraction %v is outside (0, 1]", ErrInvalidOptions, m.Fraction)
+		}
+		if m.Fn == nil {
+			return fmt.Errorf("%w: watermark %v has no callback", ErrInvalidOptions, m.Fraction)
+		}
+	}
+	return nil
+}
+
+// watermark is an armed threshold, held as an absolute count.
+type watermark struct {
+	at    uintptr
+	fn    func(len, cap uintptr)
+	armed atomic.Bool
+}
+
+// newWatermarks converts the thresholds to counts for an arena of maxElems
+// slots, lowest first, all armed.
+func newWatermarks(marks []Watermark, maxElems uintptr) []watermark {
+	if len(marks) == 0 {
+		return nil
+	}
+	marks = slices.Clone(marks)
+	slices.SortStableFunc(marks, func(x, y Watermark) int { return cmp.Compare(x.Fraction, y.Fraction) })
+	out := make([]watermark, len(marks))
+	for i, m := range marks {
+		// round up so a threshold is never reached early, and never sits at zero
+		out[i].at = max(uintptr(math.Ceil(m.Fraction*float64(maxElems))), 1)
+		out[i].fn = m.Fn
+		out[i].armed.Store(true)
+	}
+	return out
+}
+
+// crossed is the allocation fast path: a single comparison of the new count
+// against the lowest armed threshold, markAt, which is ^0 when none is.
+func (a *AtomicArena[T]) crossed(end uintptr) {
+	if end >= a.markAt.Load() {
+		a.fireMarks(end)
+	}
+}
+
+// fireMarks calls every armed threshold at or below end, each exactly once,
+// and moves markAt to the lowest threshold still armed.
+func (a *AtomicArena[T]) fireMarks(end uintptr) {
+	for i := range a.marks {
+		m := &a.marks[i]
+		if m.at <= end && m.armed.CompareAndSwap(true, false) {
+			m.fn(end, a.maxElems.Load())
+		}
+	}
+	a.markAt.Store(a.lowestArmed())
+}
+
+// armMarks sets the count to n for the thresholds: those above n are armed
+// and the rest disarmed, as if the count had just dropped or risen to n.
+// Callers hold the arena exclusively, so no threshold fires concurrently.
+func (a *AtomicArena[T]) armMarks(n uintptr) {
+	if a.marks == nil {
+		return
+	}
+	for i := range a.marks {
+		a.marks[i].armed.Store(a.marks[i].at > n)
+	}
+	a.markAt.Store(a.lowestArmed())
+}
+
+func (a *AtomicArena[T]) lowestArmed() uintptr {
+	for i := range a.marks {
+		if a.marks[i].armed.Load() {
+			return a.marks[i].at
+		}
+	}
+	return ^uintptr(0)
+}
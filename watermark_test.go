@@ -0,0 +1,131 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// markCounter records how often a watermark fired and the counts it saw.
+type markCounter struct {
+	fired atomic.Int64
+	mu    sync.Mutex
+	lens  []uintptr
+}
+
+func (m *markCounter) fn(n, c uintptr) {
+	m.fired.Add(1)
+	m.mu.Lock()
+	m.lens = append(m.lens, n)
+	m.mu.Unlock()
+}
+
+// TestHighWatermarkConcurrent crosses two thresholds from many goroutines
+// at once and re-arms them with Reset; run with -race
+func TestHighWatermarkConcurrent(t *testing.T) {
+	const capacity, writers = 1000, 8
+	var low, high markCounter
+	a := NewAtomicArena[int](capacity, WithHighWatermarks([]Watermark{
+		{Fraction: 0.9, Fn: high.fn},
+		{Fraction: 0.75, Fn: low.fn},
+	}))
+	for round := int64(1); round <= 3; round++ {
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for g := 0; g < writers; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				for {
+					if _, err := a.Alloc(g); err != nil {
+						return
+					}
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		if low.fired.Load() != round || high.fired.Load() != round {
+			t.Fatalf("round %d: expected each watermark to fire once, got %d and %d", round, low.fired.Load(), high.fired.Load())
+		}
+		if err := a.Reset(round%2 == 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, n := range low.lens {
+		if n < 750 || n >= 900 {
+			t.Fatalf("expected the 75%% watermark to see a count in [750, 900), got %v", low.lens)
+		}
+	}
+	for _, n := range high.lens {
+		if n < 900 {
+			t.Fatalf("expected the 90%% watermark to see at least 900, got %v", high.lens)
+		}
+	}
+}
+
+// TestHighWatermarkRearm covers re-arming by rollback and Reserve crossing
+// several thresholds in one step
+func TestHighWatermarkRearm(t *testing.T) {
+	var half, full markCounter
+	a := NewAtomicArena[int](10, WithHighWatermark(0.5, half.fn), WithHighWatermark(1, full.fn))
+	for i := 0; i < 4; i++ {
+		a.Alloc(i)
+	}
+	if half.fired.Load() != 0 {
+		t.Fatalf("expected no watermark below 5")
+	}
+	a.Alloc(4)
+	a.Alloc(5)
+	if half.fired.Load() != 1 || half.lens[0] != 5 {
+		t.Fatalf("expected the 50%% watermark at 5, got %v", half.lens)
+	}
+	// dropping back to the threshold or above keeps it disarmed
+	if err := a.TryShrinkTo(5); err != nil {
+		t.Fatal(err)
+	}
+	a.Alloc(5)
+	if err := a.TryShrinkTo(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Reserve(7); err != nil {
+		t.Fatal(err)
+	}
+	if half.fired.Load() != 2 || full.fired.Load() != 1 || full.lens[0] != 10 {
+		t.Fatalf("expected both watermarks from a single Reserve, got %v and %v", half.lens, full.lens)
+	}
+	// a clone already sits above its thresholds
+	c := a.Clone()
+	if err := c.TryShrinkTo(9); err != nil {
+		t.Fatal(err)
+	}
+	c.Alloc(1)
+	if half.fired.Load() != 2 || full.fired.Load() != 2 {
+		t.Fatalf("expected only the clone's full watermark to re-arm, got %d and %d", half.fired.Load(), full.fired.Load())
+	}
+}
+
+// TestHighWatermarkInvalid rejects bad thresholds
+func TestHighWatermarkInvalid(t *testing.T) {
+	fn := func(uintptr, uintptr) {}
+	for _, opt := range []Option{
+		WithHighWatermark(0, fn),
+		WithHighWatermark(1.5, fn),
+		WithHighWatermark(0.5, nil),
+	} {
+		if _, err := New[int](8, opt); !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected ErrInvalidOptions, got %v", err)
+		}
+	}
+}
+
+// BenchmarkAllocWatermark measures Alloc with an armed watermark that never fires
+func BenchmarkAllocWatermark(b *testing.B) {
+	a := NewAtomicArena[int](uintptr(b.N)+1, WithHighWatermark(1, func(uintptr, uintptr) {}))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Alloc(i)
+	}
+}
@@ -0,0 +1,45 @@
+package atomicarena
+
+import "unsafe"
+
+// wordSized reports whether T fits in a machine word and can be stored with
+// a single move: at most 8 bytes, an alignment that divides 8, and no
+// pointers for the garbage collector to see.
+func wordSized[T any]() bool {
+	var zero T
+	size, align := unsafe.Sizeof(zero), unsafe.Alignof(zero)
+	return size > 0 && size <= 8 && 8%align == 0 && !hasPointers[T]()
+}
+
+// allocWord is allocWithin for word arenas: with no mirror to publish to, an
+// allocation is the reservation, one plain store and the commit.
+func (a *AtomicArena[T]) allocWord(obj T, limit uintptr) (uintptr, *T, error) {
+	idx, err := a.reserveWithin(1, limit, nil, false)
+	if err != nil {
+		return 0, nil, a.allocErr(err, idx, 1)
+	}
+	p := (*T)(unsafe.Add(unsafe.Pointer(unsafe.SliceData(a.raw)), idx*unsafe.Sizeof(obj)))
+	*p = obj
+	if a.ops != nil {
+		a.ops.record(OpAlloc, idx, 1, false)
+	}
+	a.commit(1)
+	return idx, p, nil
+}
+
+// appendWord is AppendSlice for word arenas: the reservation and one memmove
+// of the input, with no slot to publish.
+func (a *AtomicArena[T]) appendWord(objs []T) (uintptr, []T, error) {
+	n := uintptr(len(objs))
+	start, err := a.reserve(n)
+	if err != nil {
+		return 0, nil, a.allocErr(err, start, n)
+	}
+	seg := a.raw[start : start+n]
+	copy(seg, objs)
+	if a.ops != nil {
+		a.ops.record(OpReserve, start, n, false)
+	}
+	a.commit(n)
+	return start, seg, nil
+}
@@ -0,0 +1,149 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestWordSized detects element types that fit a machine word
+func TestWordSized(t *testing.T) {
+	type pair struct{ A, B int16 }
+	cases := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"byte", wordSized[byte](), true},
+		{"int32", wordSized[int32](), true},
+		{"int64", wordSized[int64](), true},
+		{"pair", wordSized[pair](), true},
+		{"[3]byte", wordSized[[3]byte](), true},
+		{"[16]byte", wordSized[[16]byte](), false},
+		{"*int", wordSized[*int](), false},
+		{"struct{}", wordSized[struct{}](), false},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("expected wordSized[%s] to be %v, got %v", c.name, c.want, c.got)
+		}
+	}
+	if NewAtomicArena[int64](1).word || !NewAtomicArena[int64](1, WithoutPointerMirror()).word {
+		t.Fatalf("expected only the arena without a mirror to take the word path")
+	}
+}
+
+// TestWordPaths runs the shared suite over the word path and the generic one
+func TestWordPaths(t *testing.T) {
+	for _, mirror := range []bool{true, false} {
+		var opts []Option
+		name := "generic"
+		if !mirror {
+			opts, name = append(opts, WithoutPointerMirror()), "word"
+		}
+		t.Run(name+"/byte", func(t *testing.T) { allocSuite(t, opts, func(i int) byte { return byte(i) }) })
+		t.Run(name+"/int32", func(t *testing.T) { allocSuite(t, opts, func(i int) int32 { return int32(i) }) })
+		t.Run(name+"/int64", func(t *testing.T) { allocSuite(t, opts, func(i int) int64 { return int64(i) << 33 }) })
+	}
+}
+
+// allocSuite checks Alloc, AllocIndexed, AppendSlice, Reset and concurrent
+// allocation on an arena of T built with opts, where val(i) is the i-th test
+// value; the values of 0 to 255 must be distinct.
+func allocSuite[T comparable](t *testing.T, opts []Option, val func(int) T) {
+	const size = 256
+	a := NewAtomicArena[T](size, opts...)
+	p, err := a.Alloc(val(1))
+	if err != nil || *p != val(1) {
+		t.Fatalf("expected Alloc to store its value, got %v", err)
+	}
+	idx, q, _ := a.AllocIndexed(val(2))
+	if g, _ := a.Get(idx); idx != 1 || g != q || *g != val(2) {
+		t.Fatalf("expected AllocIndexed to return slot 1 holding its value")
+	}
+	in := []T{val(3), val(4), val(5)}
+	seg, err := a.AppendSlice(in)
+	if err != nil || len(seg) != 3 {
+		t.Fatalf("expected a segment of 3, got %d and %v", len(seg), err)
+	}
+	in[0] = val(9)
+	if g, _ := a.Get(2); g != &seg[0] || *g != val(3) {
+		t.Fatalf("expected AppendSlice to copy into the arena")
+	}
+	if _, err := a.AppendSlice(make([]T, size)); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if a.Len() != 5 {
+		t.Fatalf("expected a failed AppendSlice to reserve nothing, got len %d", a.Len())
+	}
+	if err := a.Reset(false); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+
+	const workers = 4
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < size; i += workers {
+				if _, err := a.Alloc(val(i)); err != nil {
+					t.Errorf("Alloc failed: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if _, err := a.Alloc(val(0)); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull on a full arena, got %v", err)
+	}
+	seen := make(map[T]bool, size)
+	for _, v := range a.Snapshot() {
+		seen[v] = true
+	}
+	if len(seen) != size || a.Committed() != size {
+		t.Fatalf("expected %d distinct committed values, got %d of %d", size, len(seen), a.Committed())
+	}
+}
+
+// BenchmarkWordAlloc compares Alloc and AppendSlice on word-sized elements
+// with the mirror, which takes the generic path, and without it
+func BenchmarkWordAlloc(b *testing.B) {
+	for _, mirror := range []bool{true, false} {
+		var opts []Option
+		name := "generic"
+		if !mirror {
+			opts, name = append(opts, WithoutPointerMirror()), "word"
+		}
+		b.Run(name+"/byte", func(b *testing.B) { benchmarkWord[byte](b, opts) })
+		b.Run(name+"/int32", func(b *testing.B) { benchmarkWord[int32](b, opts) })
+		b.Run(name+"/int64", func(b *testing.B) { benchmarkWord[int64](b, opts) })
+	}
+}
+
+// benchmarkWord runs the Alloc and AppendSlice benchmarks for an arena of T
+// built with opts, resetting it whenever it fills.
+func benchmarkWord[T any](b *testing.B, opts []Option) {
+	const size = 1 << 16
+	b.Run("Alloc", func(b *testing.B) {
+		a := NewAtomicArena[T](size, opts...)
+		var v T
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := a.Alloc(v); err != nil {
+				a.Reset(false)
+			}
+		}
+	})
+	b.Run("AppendSlice", func(b *testing.B) {
+		a := NewAtomicArena[T](size, opts...)
+		in := make([]T, 64)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := a.AppendSlice(in); err != nil {
+				a.Reset(false)
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package atomicarena_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Raezil/atomicarena"
+	"github.com/Raezil/atomicarena/atomicarenatest"
+)
+
+// runs is enough slots for a zero-allocation check: its warm-up call plus
+// every measured one.
+const runs = atomicarenatest.AllocRuns + 1
+
+type point struct {
+	X, Y float64
+	Tag  string
+}
+
+// These guard the allocation-free hot paths. A change that boxes a value into
+// an interface, lets a pointer escape or wraps a nil error fails them.
+
+// TestZeroAllocsAlloc covers Alloc, AllocIndexed and TryAlloc
+func TestZeroAllocsAlloc(t *testing.T) {
+	a := atomicarena.NewAtomicArena[point](3 * runs)
+	atomicarenatest.AssertZeroAllocs(t, func() { a.Alloc(point{1, 2, "p"}) })
+	atomicarenatest.AssertZeroAllocs(t, func() { a.AllocIndexed(point{3, 4, "q"}) })
+	atomicarenatest.AssertZeroAllocs(t, func() { a.TryAlloc(point{5, 6, "r"}) })
+}
+
+// TestZeroAllocsReserve covers Reserve and AppendSlice with pre-sized input
+func TestZeroAllocsReserve(t *testing.T) {
+	a := atomicarena.NewAtomicArena[point](16 * runs)
+	atomicarenatest.AssertZeroAllocs(t, func() { a.Reserve(8) })
+	in := make([]point, 8)
+	atomicarenatest.AssertZeroAllocs(t, func() { a.AppendSlice(in) })
+}
+
+// stringer is a pointer-receiver fmt.Stringer for TestZeroAllocsAllocAs.
+type stringer struct{ n int }
+
+func (s *stringer) String() string { return "stringer" }
+
+// TestZeroAllocsAllocAs covers AllocAs, which must not box the value it stores
+func TestZeroAllocsAllocAs(t *testing.T) {
+	a := atomicarena.NewAtomicArena[stringer](runs)
+	atomicarenatest.AssertZeroAllocs(t, func() { atomicarena.AllocAs[fmt.Stringer](a, stringer{1}) })
+}
+
+// TestZeroAllocsRead covers Get, Len and Committed
+func TestZeroAllocsRead(t *testing.T) {
+	a := atomicarena.NewAtomicArena[point](4)
+	a.Alloc(point{})
+	atomicarenatest.AssertZeroAllocs(t, func() { a.Get(0) })
+	atomicarenatest.AssertZeroAllocs(t, func() { a.Get(3) })
+	atomicarenatest.AssertZeroAllocs(t, func() { a.Len() })
+	atomicarenatest.AssertZeroAllocs(t, func() { a.Committed() })
+}
+
+// TestZeroAllocsReset covers Reset with and without release
+func TestZeroAllocsReset(t *testing.T) {
+	a := atomicarena.NewAtomicArena[point](8)
+	atomicarenatest.AssertZeroAllocs(t, func() {
+		a.Alloc(point{})
+		a.Reset(false)
+	})
+	atomicarenatest.AssertZeroAllocs(t, func() {
+		a.Alloc(point{})
+		a.Reset(true)
+	})
+}
+
+// TestAllocsFull documents the one allowed allocation on the error path: the
+// *CapacityError that reports the sizes
+func TestAllocsFull(t *testing.T) {
+	a := atomicarena.NewAtomicArena[point](0)
+	atomicarenatest.AssertAllocsAtMost(t, 1, func() { a.Alloc(point{}) })
+	atomicarenatest.AssertAllocsAtMost(t, 1, func() { a.Reserve(1) })
+}
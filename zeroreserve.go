@@ -0,0 +1,92 @@
+package atomicarena
+
+// WithZeroOnReserve makes Reserve and ReserveIndexed behave like
+// ReserveZeroed, so no reserved segment ever exposes values left behind by
+// an earlier Reset(false).
+func WithZeroOnReserve() Option {
+	return func(o *options) { o.zeroOnReserve = true }
+}
+
+// WithZeroOnAlloc guarantees that no allocation hands out a slot holding
+// data left behind by an earlier Reset(false), for arenas whose slots are
+// reused across tenants. Reserve, ReserveIndexed, AllocManyFunc and
+// ByteArena's allocations clear the slots they return, as ReserveZeroed
+// does; Alloc and the other allocations that store a value overwrite the
+// whole slot and need no clear. Slots that are still pristine, because
+// nothing was allocated there since construction or since Free or
+// Reset(true) last cleared them, are not cleared again, so the cost is only
+// paid on reuse. Stats counts the slots
+// cleared in ZeroCleared and those skipped in ZeroSkipped.
+func WithZeroOnAlloc() Option {
+	return func(o *options) { o.zeroOnAlloc, o.zeroOnReserve = true, true }
+}
+
+// AllocZero reserves one slot and returns a pointer to it holding the zero
+// value of T, published in the pointer mirror like Alloc. The slot is only
+// cleared if it may hold a stale value, as with ReserveZeroed, so it is
+// cheaper than Alloc with a zero T for large elements on pristine storage.
+func (a *AtomicArena[T]) AllocZero() (*T, error) {
+	idx, err := a.reserve(1)
+	if err != nil {
+		return nil, a.allocErr(err, idx, 1)
+	}
+	a.zeroStale(idx, a.raw[idx:idx+1])
+	p := &a.raw[idx]
+	if a.ptrs != nil {
+		a.ptrs[idx].Store(p)
+	}
+	a.commit(1)
+	if a.prof != nil {
+		a.prof.sample(1)
+	}
+	return p, nil
+}
+
+// ReserveZeroed is Reserve, except that the segment is cleared before it is
+// returned. Reset(false) leaves old values in the storage; ReserveZeroed
+// guarantees they are not handed out again. Slots the arena knows are still
+// pristine, because nothing was allocated that far since the storage was last
+// cleared, are not cleared a second time.
+func (a *AtomicArena[T]) ReserveZeroed(n uintptr) ([]T, error) {
+	_, seg, err := a.reserveSeg(n, true)
+	if err == nil && a.prof != nil {
+		a.prof.sample(n)
+	}
+	return seg, err
+}
+
+// zeroStale clears the slots of a freshly reserved segment that starts at
+// index start and may hold stale values: those below the dirty mark. It
+// counts the slots it cleared and those it skipped for Stats.
+func (a *AtomicArena[T]) zeroStale(start uintptr, seg []T) {
+	var stale uintptr
+	if d := a.dirty.Load(); start < d {
+		stale = min(uintptr(len(seg)), d-start)
+		a.zero.clear(seg[:stale])
+		a.cleared.Add(uint64(stale))
+	}
+	if rest := uintptr(len(seg)) - stale; rest > 0 {
+		a.pristine.Add(uint64(rest))
+	}
+}
+
+// markStale records that slots [0, n) keep their values past a rewind.
+func (a *AtomicArena[T]) markStale(n uintptr) {
+	for {
+		d := a.dirty.Load()
+		if d >= n || a.dirty.CompareAndSwap(d, n) {
+			return
+		}
+	}
+}
+
+// markZeroed records that slots [lo, hi) have been cleared. When that covers
+// the top of the stale region, the dirty mark drops to lo.
+func (a *AtomicArena[T]) markZeroed(lo, hi uintptr) {
+	for {
+		d := a.dirty.Load()
+		if d <= lo || d > hi || a.dirty.CompareAndSwap(d, lo) {
+			return
+		}
+	}
+}
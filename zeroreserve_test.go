@@ -0,0 +1,223 @@
+package atomicarena
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+const sentinel = -1
+
+// fillSentinels fills every free slot with the sentinel and resets without zeroing
+func fillSentinels(t *testing.T, a *AtomicArena[int]) {
+	t.Helper()
+	seg, err := a.Reserve(a.Cap() - a.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range seg {
+		seg[i] = sentinel
+	}
+	if err := a.Reset(false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReserveZeroed asserts stale sentinels never leak through ReserveZeroed
+func TestReserveZeroed(t *testing.T) {
+	a := NewAtomicArena[int](64)
+	fillSentinels(t, a)
+	if seg, _ := a.Reserve(4); seg[0] != sentinel {
+		t.Fatalf("expected plain Reserve to expose old values, got %v", seg)
+	}
+	for _, n := range []uintptr{1, 7, 20, 32} {
+		seg, err := a.ReserveZeroed(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range seg {
+			if v != 0 {
+				t.Fatalf("ReserveZeroed(%d) exposed %d at %d", n, v, i)
+			}
+		}
+	}
+
+	// a release Reset or Free wipes the stale region, after which nothing needs clearing
+	a.Reset(true)
+	if a.dirty.Load() != 0 {
+		t.Fatalf("expected Reset(true) to clear the dirty mark, got %d", a.dirty.Load())
+	}
+	fillSentinels(t, a)
+	a.Reserve(10)
+	a.Free()
+	if a.dirty.Load() != 64 {
+		t.Fatalf("expected Free of 10 slots to leave slots up to 64 stale, got %d", a.dirty.Load())
+	}
+	seg, _ := a.ReserveZeroed(54)
+	for i, v := range seg {
+		if v != 0 {
+			t.Fatalf("ReserveZeroed exposed %d at %d after a partial Free", v, i)
+		}
+	}
+}
+
+// TestZeroOnReserve applies the option to Reserve and ReserveIndexed
+func TestZeroOnReserve(t *testing.T) {
+	a := NewAtomicArena[int](32, WithZeroOnReserve())
+	fillSentinels(t, a)
+	seg, _ := a.Reserve(16)
+	_, seg2, _ := a.ReserveIndexed(16)
+	for i, v := range append(seg, seg2...) {
+		if v != 0 {
+			t.Fatalf("Reserve exposed %d at %d with WithZeroOnReserve", v, i)
+		}
+	}
+}
+
+// TestReserveZeroedPristine ensures slots beyond the dirty mark are left alone
+func TestReserveZeroedPristine(t *testing.T) {
+	a := NewAtomicArena[int](16)
+	a.AppendSlice([]int{1, 2, 3, 4})
+	a.Reset(false)
+	if a.dirty.Load() != 4 {
+		t.Fatalf("expected dirty mark 4, got %d", a.dirty.Load())
+	}
+	// plant a marker past the mark; a pristine slot must not be cleared again
+	a.raw[10] = 42
+	seg, _ := a.ReserveZeroed(12)
+	if seg[0] != 0 || seg[3] != 0 || seg[10] != 42 {
+		t.Fatalf("expected only the stale prefix to be cleared, got %v", seg)
+	}
+	// rolling back zeroes the slots and lowers the mark
+	a.TryShrinkTo(0)
+	if a.dirty.Load() != 0 {
+		t.Fatalf("expected TryShrinkTo to clear the dirty mark, got %d", a.dirty.Load())
+	}
+}
+
+// TestReserveZeroedConcurrent resets and reserves concurrently; run with -race
+func TestReserveZeroedConcurrent(t *testing.T) {
+	a := NewAtomicArena[int](256)
+	var mu sync.RWMutex // readers reserve and fill, the writer resets
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				mu.RLock()
+				seg, err := a.ReserveZeroed(3)
+				if err == nil {
+					for j, v := range seg {
+						if v != 0 {
+							t.Errorf("exposed %d at %d", v, j)
+						}
+						seg[j] = sentinel
+					}
+				}
+				mu.RUnlock()
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		mu.Lock()
+		a.Reset(false)
+		mu.Unlock()
+	}
+	wg.Wait()
+}
+
+// TestZeroOnAlloc re-allocates over sentinels after Reset(false) and counts the clears
+func TestZeroOnAlloc(t *testing.T) {
+	a := NewAtomicArena[int](64, WithZeroOnAlloc())
+	fillSentinels(t, a)
+	if s := a.Stats(); s.ZeroCleared != 0 || s.ZeroSkipped != 64 {
+		t.Fatalf("expected the first fill to skip 64 pristine slots, got %d cleared and %d skipped", s.ZeroCleared, s.ZeroSkipped)
+	}
+	var got []int
+	p, _ := a.AllocZero()
+	got = append(got, *p)
+	seg, _ := a.Reserve(7)
+	got = append(got, seg...)
+	_, seg, _ = a.ReserveIndexed(8)
+	got = append(got, seg...)
+	seg, _ = a.AllocManyFunc(8, func(_ uintptr, p *int) { got = append(got, *p) })
+	q, _ := a.Alloc(5)
+	for i, v := range got {
+		if v != 0 {
+			t.Fatalf("expected every returned slot to read zero, got %d at %d", v, i)
+		}
+	}
+	if *q != 5 {
+		t.Fatalf("expected Alloc to store its value, got %d", *q)
+	}
+	// 24 stale slots were handed out zeroed; Alloc overwrote its slot
+	if s := a.Stats(); s.ZeroCleared != 24 || s.ZeroSkipped != 64 {
+		t.Fatalf("expected 24 cleared and 64 skipped, got %d and %d", s.ZeroCleared, s.ZeroSkipped)
+	}
+	// once the whole stale region has been allocated, a release wipes it
+	a.Reserve(39)
+	a.Reset(true)
+	a.Reserve(64)
+	if s := a.Stats(); s.ZeroCleared != 63 || s.ZeroSkipped != 128 {
+		t.Fatalf("expected slots wiped by Reset(true) to be skipped, got %d cleared and %d skipped", s.ZeroCleared, s.ZeroSkipped)
+	}
+}
+
+// TestZeroOnAllocByteArena clears reused bytes for both byte allocations
+func TestZeroOnAllocByteArena(t *testing.T) {
+	b := NewByteArena(256, WithZeroOnAlloc())
+	buf, _ := b.AllocBytes(256)
+	for i := range buf {
+		buf[i] = 0xAA
+	}
+	b.Reset()
+	plain, _ := b.AllocBytes(3)
+	aligned, _ := b.AllocBytesAligned(64, 32)
+	for i, v := range append(plain, aligned...) {
+		if v != 0 {
+			t.Fatalf("expected reused bytes to read zero, got %#x at %d", v, i)
+		}
+	}
+}
+
+// TestAllocZero clears a stale slot without the option and publishes it
+func TestAllocZero(t *testing.T) {
+	a := NewAtomicArena[int](4)
+	fillSentinels(t, a)
+	p, err := a.AllocZero()
+	if err != nil || *p != 0 {
+		t.Fatalf("expected a zeroed slot, got %v (%v)", p, err)
+	}
+	if a.LoadPointer(0) != p {
+		t.Fatal("expected AllocZero to publish the slot")
+	}
+	a.Reserve(3)
+	if _, err := a.AllocZero(); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+}
+
+type page4K [4096]byte
+
+// BenchmarkZeroOnAlloc measures reserving 4KB elements on reused storage with and without clearing
+func BenchmarkZeroOnAlloc(b *testing.B) {
+	const slots = 256
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{{"plain", nil}, {"zeroed", []Option{WithZeroOnAlloc()}}} {
+		b.Run(bc.name, func(b *testing.B) {
+			a := NewAtomicArena[page4K](slots, bc.opts...)
+			b.SetBytes(int64(unsafe.Sizeof(page4K{})))
+			for i := 0; i < b.N; i++ {
+				if i%slots == 0 {
+					a.Reset(false)
+				}
+				seg, _ := a.Reserve(1)
+				seg[0][0] = 1
+			}
+		})
+	}
+}
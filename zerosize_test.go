@@ -0,0 +1,112 @@
+package atomicarena
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCheckedZeroSized ensures the checked constructor rejects zero-sized types
+func TestCheckedZeroSized(t *testing.T) {
+	if _, err := NewAtomicArenaChecked[struct{}](8); !errors.Is(err, ErrZeroSizedType) {
+		t.Fatalf("expected ErrZeroSizedType, got %v", err)
+	}
+	if _, err := NewAtomicArenaChecked[[0]int](8); !errors.Is(err, ErrZeroSizedType) {
+		t.Fatalf("expected ErrZeroSizedType for [0]int, got %v", err)
+	}
+}
+
+// TestZeroSizedAlloc covers the specialized arena: counting, a shared address and no mirror
+func TestZeroSizedAlloc(t *testing.T) {
+	a := NewAtomicArena[struct{}](4)
+	if a.ptrs != nil {
+		t.Fatalf("expected no pointer mirror for a zero-sized type")
+	}
+	p, err := a.Alloc(struct{}{})
+	if err != nil {
+		t.Fatalf("Alloc failed: %v", err)
+	}
+	q, err := a.Alloc(struct{}{})
+	if err != nil {
+		t.Fatalf("Alloc failed: %v", err)
+	}
+	if p != q {
+		t.Fatalf("expected zero-sized elements to share one address")
+	}
+	if g, ok := a.Get(1); !ok || g != p {
+		t.Fatalf("expected Get(1) to return the shared address, got %p %v", g, ok)
+	}
+	if _, ok := a.Get(2); ok {
+		t.Fatalf("expected Get beyond Len to fail")
+	}
+	if _, err := a.Reserve(3); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull past capacity, got %v", err)
+	}
+	if a.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", a.Len())
+	}
+}
+
+// TestZeroSizedAppendSlice checks that segments of zero-sized elements keep their length
+func TestZeroSizedAppendSlice(t *testing.T) {
+	a := NewAtomicArena[struct{}](10)
+	seg, err := a.AppendSlice(make([]struct{}, 3))
+	if err != nil {
+		t.Fatalf("AppendSlice failed: %v", err)
+	}
+	if len(seg) != 3 || cap(seg) < 3 {
+		t.Fatalf("expected a segment of length 3, got len %d cap %d", len(seg), cap(seg))
+	}
+	seg, err = a.AppendSlice(nil)
+	if err != nil || len(seg) != 0 {
+		t.Fatalf("expected an empty segment, got %d, %v", len(seg), err)
+	}
+	res, err := a.Reserve(7)
+	if err != nil || len(res) != 7 {
+		t.Fatalf("expected a reserved segment of length 7, got %d, %v", len(res), err)
+	}
+	if _, err := a.AppendSlice(make([]struct{}, 1)); !errors.Is(err, ErrArenaFull) {
+		t.Fatalf("expected ErrArenaFull, got %v", err)
+	}
+	if n := len(a.Snapshot()); n != 10 {
+		t.Fatalf("expected 10 elements in the snapshot, got %d", n)
+	}
+}
+
+// TestZeroSizedReset ensures Free and Reset rewind the count for zero-sized types
+func TestZeroSizedReset(t *testing.T) {
+	a := NewAtomicArena[struct{}](1 << 20)
+	if _, err := a.Reserve(1 << 20); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	a.Tombstone(5)
+	if err := a.Free(); err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	if err := a.Reset(true); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if a.Len() != 0 {
+		t.Fatalf("expected empty arena after Reset, got %d", a.Len())
+	}
+	if _, err := a.Reserve(6); err != nil {
+		t.Fatalf("Reserve after Reset failed: %v", err)
+	}
+	if _, ok := a.Get(5); !ok {
+		t.Fatalf("expected Reset to clear tombstones")
+	}
+}
+
+// TestZeroSizedMmap checks that mapped arenas of zero-sized types also drop the mirror
+func TestZeroSizedMmap(t *testing.T) {
+	m, err := NewMmapArena[struct{}](16)
+	if err != nil {
+		t.Fatalf("NewMmapArena failed: %v", err)
+	}
+	defer m.Close()
+	if m.mem != nil || m.arena.ptrs != nil {
+		t.Fatalf("expected no mapping for zero-sized elements")
+	}
+	if _, err := m.Alloc(struct{}{}); err != nil {
+		t.Fatalf("Alloc failed: %v", err)
+	}
+}